@@ -1,8 +1,10 @@
 from __future__ import annotations
 
+import os
+import time
 from dataclasses import asdict
 from pathlib import Path
-from threading import Lock
+from threading import Lock, Thread
 from typing import Any
 
 
@@ -32,12 +34,144 @@ def expand_input_paths(paths: list[str]) -> dict:
     return expand_paths(paths)
 
 
+def is_archive_path(path: str) -> bool:
+    from backend.infrastructure.archives import is_archive_path as check_archive
+
+    return check_archive(path)
+
+
+def expand_archive(archive_path: str) -> dict:
+    from backend.infrastructure.archives import expand_archive as expand_zip
+
+    return expand_zip(archive_path)
+
+
+def resolve_member_path(path: str) -> str:
+    from backend.infrastructure.archives import resolve_member_path as extract_on_demand
+
+    return extract_on_demand(path)
+
+
+def archive_member_listed(path: str) -> bool:
+    from backend.infrastructure.archives import member_listed
+
+    return member_listed(path)
+
+
+def write_zip(entries: list[tuple[str, str]], output_path: str) -> dict:
+    from backend.infrastructure.archives import write_zip as pack_zip
+
+    return pack_zip(entries, output_path)
+
+
 def list_system_fonts() -> list[str]:
     from backend.domain.paths import list_system_fonts as load_fonts
 
     return load_fonts()
 
 
+def message(key: str, lang: str | None = None, **params) -> str:
+    from backend.contracts.messages import message as resolve_message
+
+    return resolve_message(key, lang, **params)
+
+
+def format_capabilities() -> dict:
+    from backend.domain.formats import format_capabilities as load_capabilities
+
+    return load_capabilities()
+
+
+def clamp_request(module_name: str, payload: dict) -> tuple[dict, list[str]]:
+    from backend.domain.bounds import clamp_request as clamp_payload
+
+    return clamp_payload(module_name, payload)
+
+
+def convert_target_error(format_name: str) -> str:
+    from backend.domain.formats import convert_target_error as target_error
+
+    return target_error(format_name)
+
+
+def hex_color_error(value, field: str) -> str:
+    from backend.domain.formats import hex_color_error as color_error
+
+    return color_error(value, field)
+
+
+def subsampling_error(value) -> str:
+    from backend.domain.formats import subsampling_error as chroma_error
+
+    return chroma_error(value)
+
+
+def tiff_compression_error(value) -> str:
+    from backend.domain.formats import tiff_compression_error as compression_error
+
+    return compression_error(value)
+
+
+def ico_sizes_error(value) -> str:
+    from backend.domain.formats import ico_sizes_error as icon_sizes_error
+
+    return icon_sizes_error(value)
+
+
+def normalize_ico_sizes(value) -> list[int]:
+    from backend.domain.formats import normalize_ico_sizes as normalize_icon_sizes
+
+    return normalize_icon_sizes(value)
+
+
+def hamming_distance(a: str, b: str) -> int:
+    from backend.domain.perceptual_hash import hamming_distance as hash_distance
+
+    return hash_distance(a, b)
+
+
+def max_megapixels_error(value) -> str:
+    from backend.domain.formats import max_megapixels_error as pixel_limit_error
+
+    return pixel_limit_error(value)
+
+
+def dpi_error(value) -> str:
+    from backend.domain.formats import dpi_error as resolution_error
+
+    return resolution_error(value)
+
+
+def orientation_error(value) -> str:
+    from backend.domain.formats import orientation_error as exif_orientation_error
+
+    return exif_orientation_error(value)
+
+
+def trim_error(trim_borders, trim_tolerance) -> str:
+    from backend.domain.formats import trim_error as border_trim_error
+
+    return border_trim_error(trim_borders, trim_tolerance)
+
+
+def input_file_error(path: str) -> str:
+    from backend.domain.formats import input_file_error as header_error
+
+    return header_error(path)
+
+
+def color_profile_error(keep_color_profile, keep_metadata) -> str:
+    from backend.domain.formats import color_profile_error as profile_error
+
+    return profile_error(keep_color_profile, keep_metadata)
+
+
+def canonical_format(format_name: str) -> str:
+    from backend.domain.formats import canonical_format as resolve_format
+
+    return resolve_format(format_name)
+
+
 def normalize_optional_user_supplied_path(value: str) -> str:
     from backend.domain.paths import normalize_optional_user_supplied_path as normalize_optional_path
 
@@ -86,6 +220,24 @@ def settings_from_dict(payload: dict):
     return build_settings(payload)
 
 
+def reset_settings():
+    from backend.infrastructure.settings_store import reset_settings as write_default_settings
+
+    return write_default_settings()
+
+
+def settings_to_json(settings) -> str:
+    from backend.infrastructure.settings_store import settings_to_json as dump_settings
+
+    return dump_settings(settings)
+
+
+def settings_from_json(data: str):
+    from backend.infrastructure.settings_store import settings_from_json as parse_settings
+
+    return parse_settings(data)
+
+
 def runtime_quit() -> None:
     from backend.infrastructure.window_ops import runtime_quit as quit_window
 
@@ -104,6 +256,143 @@ def runtime_window_toggle_maximise() -> None:
     toggle_window_maximise()
 
 
+def notify_long_operation(detail: dict) -> None:
+    from backend.application.long_operation import LONG_OPERATION_EVENT
+    from backend.infrastructure.window_ops import dispatch_window_event
+
+    dispatch_window_event(LONG_OPERATION_EVENT, detail)
+
+
+def notify_batch_done(detail: dict) -> None:
+    from backend.application.completion import BATCH_DONE_EVENT
+    from backend.infrastructure.window_ops import dispatch_window_event
+
+    dispatch_window_event(BATCH_DONE_EVENT, detail)
+
+
+def notify_batch_progress(detail: dict) -> None:
+    from backend.application.progress import BATCH_PROGRESS_EVENT
+    from backend.infrastructure.window_ops import dispatch_window_event
+
+    dispatch_window_event(BATCH_PROGRESS_EVENT, detail)
+
+
+def notify_conflict_prompt(detail: dict) -> None:
+    from backend.application.conflicts import CONFLICT_PROMPT_EVENT
+    from backend.infrastructure.window_ops import dispatch_window_event
+
+    dispatch_window_event(CONFLICT_PROMPT_EVENT, detail)
+
+
+def notify_runtime_progress(detail: dict) -> None:
+    from backend.application.runtime_status import RUNTIME_PROGRESS_EVENT
+    from backend.infrastructure.window_ops import dispatch_window_event
+
+    dispatch_window_event(RUNTIME_PROGRESS_EVENT, detail)
+
+
+def capabilities() -> dict:
+    from backend.application.capabilities import capabilities as build_report
+
+    return build_report()
+
+
+def pdf_support() -> dict:
+    from backend.application.capabilities import pdf_support as probe
+
+    return probe()
+
+
+def active_operation_log() -> Any:
+    from backend.infrastructure.operation_log import active_operation_log as current_log
+
+    return current_log()
+
+
+def leftover_temp_files(record: dict) -> list[str]:
+    from backend.infrastructure.operation_log import leftover_temp_files as find_leftovers
+
+    return find_leftovers(record)
+
+
+def _interrupted_summary(record: dict) -> dict:
+    payload = record.get("payload") or {}
+    return {
+        "id": str(record.get("id") or ""),
+        "module": str(record.get("module") or ""),
+        "input_path": str(payload.get("input_path") or ""),
+        "output_path": str(payload.get("output_path") or ""),
+        "started_at": float(record.get("started_at") or 0),
+    }
+
+
+def runtime_status() -> dict:
+    from backend.application.runtime_status import runtime_preparation
+
+    return runtime_preparation().status()
+
+
+def startup_error() -> dict:
+    from backend.application.startup_status import startup_status
+
+    return startup_status().status()
+
+
+def startup_diagnostics() -> dict:
+    from backend.application.startup_status import startup_diagnostics as diagnostics
+
+    return diagnostics()
+
+
+def edit_sessions():
+    from backend.application.edit_session import edit_sessions as sessions
+
+    return sessions()
+
+
+def repair_runtime_preparation() -> dict:
+    from backend.application.runtime_status import runtime_preparation
+
+    return runtime_preparation().repair(notify_runtime_progress)
+
+
+def notify_desktop(title: str, body: str) -> None:
+    from backend.infrastructure.notifications import notify
+
+    # Native notifiers may spawn a helper process; never hold the batch result on it.
+    Thread(target=notify, args=(title, body), daemon=True).start()
+
+
+def long_operation_seconds() -> float:
+    from backend.application.long_operation import long_operation_seconds as threshold
+
+    return threshold()
+
+
+def reveal_path(path_value: str) -> bool:
+    from backend.infrastructure.shell_ops import reveal_path as reveal
+
+    return reveal(path_value)
+
+
+def watch_long_operation(operation: str, task_id: int | None, item_count: int = 1):
+    from backend.application.long_operation import watch_long_operation as watch
+
+    return watch(operation, task_id, notify_long_operation, item_count=item_count)
+
+
+def order_batch_indexes(indexes: list[int], payloads: list[dict], strategy: str) -> list[int]:
+    from backend.application.batch_order import order_batch_indexes as order_indexes
+
+    return order_indexes(indexes, payloads, strategy)
+
+
+def up_to_date_result(payload: dict) -> dict | None:
+    from backend.application.image_ops import _up_to_date_result as skipped_result
+
+    return skipped_result(payload)
+
+
 def execute_engine(module_name: str, payload: dict, task_manager: Any, task_id: int | None = None) -> dict:
     from backend.application.image_ops import execute_engine as run_engine
 
@@ -112,8 +401,13 @@ def execute_engine(module_name: str, payload: dict, task_manager: Any, task_id:
 
 def execute_engine_batch(module_name: str, payloads: list[dict], settings: Any, task_manager: Any) -> list[dict]:
     from backend.application.image_ops import execute_engine_batch as run_engine_batch
+    from backend.application.progress import BatchProgress
 
-    return run_engine_batch(module_name, payloads, settings, task_manager)
+    progress = BatchProgress(module_name, len(payloads), task_manager.current_task_id, notify_batch_progress)
+    try:
+        return run_engine_batch(module_name, payloads, settings, task_manager, on_result=progress.report)
+    finally:
+        progress.finish()
 
 
 def _normalize_recent_path(value: str) -> str:
@@ -153,24 +447,404 @@ def _merge_recent_paths(current: list[str], next_value: str) -> list[str]:
     return merged
 
 
-def _normalize_payload_paths(payload: Any) -> Any:
+def _normalize_payload_paths(payload: Any, extract_members: bool = True) -> Any:
+    """Normalize user-supplied paths; with `extract_members=False` zip member references are kept as-is."""
     if isinstance(payload, list):
-        return [_normalize_payload_paths(item) for item in payload]
+        return [_normalize_payload_paths(item, extract_members) for item in payload]
     if not isinstance(payload, dict):
         return payload
 
+    resolve = resolve_member_path if extract_members else (lambda path: path)
     normalized = dict(payload)
-    for field in ("input_path", "output_path", "output_dir", "watermark_path", "image_path"):
+    for field in ("input_path", "output_path", "output_dir", "watermark_path", "image_path", "append_to_path"):
         if field in normalized:
             normalized[field] = normalize_optional_user_supplied_path(str(normalized.get(field) or ""))
+    # Files listed from a dropped zip are only extracted once a request reads them.
+    for field in ("input_path", "image_path"):
+        if normalized.get(field):
+            normalized[field] = resolve(normalized[field])
     for field in ("input_paths", "image_paths"):
         if field in normalized and isinstance(normalized[field], list):
-            normalized[field] = [normalize_user_supplied_path(str(item)) for item in normalized[field] if str(item).strip()]
+            normalized[field] = [
+                resolve(normalize_user_supplied_path(str(item))) for item in normalized[field] if str(item).strip()
+            ]
     if isinstance(normalized.get("images"), list):
-        normalized["images"] = [_normalize_payload_paths(item) for item in normalized["images"]]
+        normalized["images"] = [_normalize_payload_paths(item, extract_members) for item in normalized["images"]]
     return normalized
 
 
+def _apply_quality_default(payload: dict, format_name: str, defaults: dict[str, int]) -> dict:
+    """Fill a zero/missing quality from the per-format settings; explicit values always win.
+
+    A compression `level` is an explicit choice too: the level sets the quality, so the
+    per-format default only applies to requests that carry neither.
+    """
+    if payload.get("level") not in (None, ""):
+        return payload
+    try:
+        requested = int(payload.get("quality") or 0)
+    except (TypeError, ValueError):
+        requested = 0
+    if requested > 0:
+        return payload
+    default = (defaults or {}).get(canonical_format(format_name))
+    if not default:
+        return payload
+    return {**payload, "quality": int(default)}
+
+
+def _reserve_batch_outputs(payloads: list[dict]) -> tuple[list[dict], list[list[str]]]:
+    """Rename outputs that collide with an earlier item of the same batch (photo.jpg -> photo_01.jpg)."""
+    reserved: list[str] = []
+    seen: set[str] = set()
+    resolved: list[dict] = []
+    warnings: list[list[str]] = []
+    for payload in payloads:
+        output_path = str(payload.get("output_path") or "") if isinstance(payload, dict) else ""
+        if not output_path:
+            resolved.append(payload)
+            warnings.append([])
+            continue
+        item_warnings: list[str] = []
+        if output_path.replace("\\", "/").casefold() in seen:
+            renamed = resolve_output_path(output_path, reserved)
+            item_warnings.append(f"输出路径与同批次文件冲突，已重命名为 {Path(renamed).name}")
+            payload = {**payload, "output_path": renamed}
+            output_path = renamed
+        seen.add(output_path.replace("\\", "/").casefold())
+        reserved.append(output_path)
+        resolved.append(payload)
+        warnings.append(item_warnings)
+    return resolved, warnings
+
+
+# Batch operations PlanBatch understands, keyed by UI name and engine module name.
+PLAN_OPERATIONS = {
+    "convert": "converter",
+    "compress": "compressor",
+    "watermark": "watermark",
+    "adjust": "adjuster",
+    "filter": "filter",
+}
+
+
+def _precheck_inputs(module_name: str, items: list[Any]) -> dict[int, dict]:
+    """Fail empty or truncated inputs before they reach the engine, keyed by batch index."""
+    if module_name not in PLAN_OPERATIONS.values():
+        return {}
+    rejected: dict[int, dict] = {}
+    for index, item in enumerate(items):
+        input_path = str(item.get("input_path") or "") if isinstance(item, dict) else ""
+        error = input_file_error(input_path) if input_path else ""
+        if error:
+            rejected[index] = {"success": False, "error": error, "input_path": input_path}
+    return rejected
+
+
+def _up_to_date_indexes(items: list[Any]) -> set[int]:
+    """Batch indexes the engine will skip because `skip_up_to_date` finds their output newer."""
+    return {index for index, item in enumerate(items) if up_to_date_result(item) is not None}
+
+
+def _merge_warnings(result: Any, warnings: list[str]) -> Any:
+    if not warnings or not isinstance(result, dict):
+        return result
+    existing = str(result.get("warning") or "").strip()
+    return {**result, "warning": "；".join([*warnings, existing] if existing else warnings)}
+
+
+def _with_working_copy(items: list[Any], settings: Any) -> list[Any]:
+    """Apply the `use_working_copy` setting unless a request chose for itself."""
+    if not getattr(settings, "use_working_copy", False):
+        return items
+    return [
+        {"working_copy": True, **item} if isinstance(item, dict) and "working_copy" not in item else item
+        for item in items
+    ]
+
+
+def resolve_ram_temp_dir(configured: str) -> str:
+    from backend.infrastructure.ram_temp import resolve_ram_temp_dir as resolve_dir
+
+    return resolve_dir(configured)
+
+
+def _with_temp_dir(items: list[Any], settings: Any) -> list[Any]:
+    """Route scratch files to the RAM-backed directory when `use_ram_temp` is on and it is usable."""
+    if not getattr(settings, "use_ram_temp", False):
+        return items
+    temp_dir = resolve_ram_temp_dir(getattr(settings, "ram_temp_dir", ""))
+    if not temp_dir:
+        return items
+    return [{"temp_dir": temp_dir, **item} if isinstance(item, dict) and "temp_dir" not in item else item for item in items]
+
+
+def _with_max_megapixels(payload: dict, settings: Any) -> dict:
+    """Apply the `max_megapixels` setting unless the request set its own limit."""
+    if payload.get("max_megapixels"):
+        return payload
+    return {**payload, "max_megapixels": getattr(settings, "max_megapixels", 0) or 256}
+
+
+def _with_gif_limits(payload: dict, settings: Any) -> dict:
+    """Apply the gif_max_* settings to the limits a GIF request did not set itself."""
+    limits = {
+        "max_frames": getattr(settings, "gif_max_frames", 0) or 10_000,
+        "max_total_megapixels": getattr(settings, "gif_max_megapixels", 0) or 2_048,
+        "max_export_files": getattr(settings, "gif_max_export_files", 0) or 2_000,
+    }
+    return {**{key: value for key, value in limits.items() if not payload.get(key)}, **payload}
+
+
+def _with_contact_sheet_sampling(payload: Any) -> Any:
+    """On `contact_sheet`, the request's `max_frames` is how many frames to sample (0 = all).
+
+    gif_splitter reads `max_frames` as its decode guard on every action, so the count travels
+    as `sheet_max_frames` and the guard comes from `gif_max_frames` like a request without it.
+    """
+    if not isinstance(payload, dict) or "max_frames" not in payload:
+        return payload
+    if str(payload.get("action") or "").strip().lower() not in ("contact_sheet", "montage"):
+        return payload
+    updated = {key: value for key, value in payload.items() if key != "max_frames"}
+    updated["sheet_max_frames"] = payload["max_frames"]
+    return updated
+
+
+# `convert_animation` targets and the spellings gif_splitter accepts for them.
+_ANIMATION_OUTPUT_FORMATS = ("apng", "png", "webp", "gif")
+_ANIMATION_CONVERT_ACTIONS = ("convert", "convert_animation", "transcode", "convert_animated", "convert_anim")
+
+
+def _gif_crop_error(payload: dict) -> str:
+    """Crop fields must be whole pixels; gif_splitter checks the rectangle against the canvas."""
+    for key, minimum in (("crop_x", 0), ("crop_y", 0), ("crop_width", 1), ("crop_height", 1)):
+        value = payload.get(key)
+        if value is None and minimum == 0:
+            continue
+        if isinstance(value, bool) or not isinstance(value, int) or value < minimum:
+            return f"[BAD_INPUT] {key} must be an integer of at least {minimum}"
+    return ""
+
+
+def _gif_request_error(payload: Any) -> str:
+    if not isinstance(payload, dict):
+        return ""
+    action = str(payload.get("action") or "").strip().lower()
+    if action in ("", "split", "export", "export_frames"):
+        timestamp = payload.get("timestamp_ms")
+        if timestamp is not None and (isinstance(timestamp, bool) or not isinstance(timestamp, int) or timestamp < 0):
+            return "[BAD_INPUT] timestamp_ms must be a non-negative integer"
+        return ""
+    if action in ("crop", "crop_gif"):
+        return _gif_crop_error(payload)
+    if action in ("contact_sheet", "montage"):
+        for key in ("grid_columns", "max_frames"):
+            value = payload.get(key)
+            if value is not None and (isinstance(value, bool) or not isinstance(value, int) or value < 0):
+                return f"[BAD_INPUT] {key} must be a non-negative integer"
+        return ""
+    if action in ("compress", "compress_gif"):
+        max_colors = payload.get("max_colors")
+        if max_colors is not None and (isinstance(max_colors, bool) or not isinstance(max_colors, int) or not 2 <= max_colors <= 256):
+            return "[BAD_INPUT] max_colors must be an integer between 2 and 256"
+        return ""
+    if action not in _ANIMATION_CONVERT_ACTIONS:
+        return ""
+    output_format = str(payload.get("output_format") or payload.get("format") or "").strip().lower()
+    if output_format not in _ANIMATION_OUTPUT_FORMATS:
+        return "[BAD_INPUT] output_format must be apng, webp or gif for convert_animation"
+    return ""
+
+
+def _info_request_error(payload: Any) -> str:
+    palette_size = payload.get("palette_size") if isinstance(payload, dict) else None
+    if palette_size in (None, 0):
+        return ""
+    if isinstance(palette_size, bool) or not isinstance(palette_size, int) or not 1 <= palette_size <= 16:
+        return "[BAD_INPUT] palette_size must be an integer between 1 and 16"
+    return ""
+
+
+def _convert_output_format(payload: dict) -> str:
+    return str(payload.get("format") or "jpg")
+
+
+def _convert_request_error(payload: dict) -> str:
+    return (
+        convert_target_error(_convert_output_format(payload))
+        or hex_color_error(payload.get("background_color"), "background_color")
+        or hex_color_error(payload.get("pad_color"), "pad_color")
+        or subsampling_error(payload.get("subsampling"))
+        or tiff_compression_error(payload.get("tiff_compression"))
+        or trim_error(payload.get("trim_borders"), payload.get("trim_tolerance"))
+        or ico_sizes_error(_convert_ico_sizes(payload))
+        or max_megapixels_error(payload.get("max_megapixels"))
+        or dpi_error(payload.get("dpi"))
+        or color_profile_error(payload.get("keep_color_profile"), payload.get("keep_metadata"))
+    )
+
+
+def _convert_ico_sizes(payload: dict):
+    return payload.get("ico_sizes") or payload.get("icoSizes")
+
+
+def _normalize_convert_ico_sizes(payload: dict) -> dict:
+    sizes = _convert_ico_sizes(payload)
+    if not sizes:
+        return payload
+    updated = {key: value for key, value in payload.items() if key != "icoSizes"}
+    updated["ico_sizes"] = normalize_ico_sizes(sizes)
+    return updated
+
+
+def _target_ssim_error(value: Any) -> str:
+    """`[BAD_INPUT]` unless `value` is a number in (0, 1]; 0 would accept any quality."""
+    if isinstance(value, bool) or not isinstance(value, (int, float)) or not 0.0 < float(value) <= 1.0:
+        return f"[BAD_INPUT] target_ssim must be within (0, 1], got {value!r}"
+    return ""
+
+
+def _target_size_kb_error(value: Any) -> str:
+    """`[BAD_INPUT]` unless `value` is unset (None, "" or 0) or a positive whole number of KB."""
+    if value is None or value == "" or (value == 0 and not isinstance(value, bool)):
+        return ""
+    if isinstance(value, int) and not isinstance(value, bool) and value > 0:
+        return ""
+    return f"[BAD_INPUT] target_size_kb must be a positive integer, got {value!r}"
+
+
+# Longest password AES-256 PDF encryption (revision 5/6) accepts, in UTF-8 bytes.
+PDF_PASSWORD_MAX_BYTES = 127
+# Mirrors pdf_generator.PDF_PERMISSIONS.
+_PDF_PERMISSIONS = ("print", "modify", "copy", "annotate")
+
+
+def _pdf_encryption_error(payload: Any) -> str:
+    """`[BAD_INPUT]` for passwords that are not strings or too long, or unknown permissions.
+
+    Passwords without pyaes fail with `[PDF_ENCRYPTION_FAILED]`: the PDF is never written weaker.
+    """
+    if not isinstance(payload, dict):
+        return ""
+    for field in ("user_password", "owner_password"):
+        value = payload.get(field)
+        if value is None:
+            continue
+        if not isinstance(value, str):
+            return f"[BAD_INPUT] {field} must be a string"
+        if len(value.encode("utf-8")) > PDF_PASSWORD_MAX_BYTES:
+            return f"[BAD_INPUT] {field} must be at most {PDF_PASSWORD_MAX_BYTES} bytes"
+    permissions = payload.get("permissions")
+    if permissions is None:
+        return ""
+    if not isinstance(permissions, list) or not all(isinstance(item, str) for item in permissions):
+        return "[BAD_INPUT] permissions must be a list of strings"
+    unknown = sorted({item.strip().lower() for item in permissions} - set(_PDF_PERMISSIONS))
+    if unknown:
+        return f"[BAD_INPUT] Unknown permissions: {', '.join(unknown)} (expected {', '.join(_PDF_PERMISSIONS)})"
+    if (payload.get("user_password") or payload.get("owner_password")) and not pdf_support()["aes256"]:
+        return "[PDF_ENCRYPTION_FAILED] AES-256 encryption needs the pyaes package, which is not installed"
+    return ""
+
+
+def _pdf_bookmarks_error(payload: Any) -> str:
+    """`[BAD_INPUT]` unless `bookmarks` is empty or one string label per requested image."""
+    if not isinstance(payload, dict):
+        return ""
+    bookmarks = payload.get("bookmarks")
+    if not bookmarks:
+        return ""
+    if not isinstance(bookmarks, list) or not all(isinstance(label, str) for label in bookmarks):
+        return "[BAD_INPUT] bookmarks must be a list of strings"
+    images = payload.get("images") or payload.get("image_paths") or []
+    count = len(images) if isinstance(images, list) else 0
+    if len(bookmarks) != count:
+        return f"[BAD_INPUT] bookmarks must have one label per image ({count}), got {len(bookmarks)}"
+    return ""
+
+
+def _pdf_image_dpi_error(value: Any) -> str:
+    """`[BAD_INPUT]` unless `value` is unset or a non-negative whole number (0 keeps full resolution)."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, int) and not isinstance(value, bool) and value >= 0:
+        return ""
+    return f"[BAD_INPUT] image_dpi must be a non-negative integer, got {value!r}"
+
+
+def _pdf_append_error(payload: Any) -> str:
+    """An error unless `append_to_path` is unset, or a readable PDF with no passwords and pypdf installed."""
+    if not isinstance(payload, dict) or not payload.get("append_to_path"):
+        return ""
+    path = Path(str(payload["append_to_path"]))
+    if not path.is_file():
+        return f"[NOT_FOUND] PDF to append to not found: {path}"
+    try:
+        with open(path, "rb") as handle:
+            header = handle.read(1024)
+    except OSError as exc:
+        return f"[IO_ERROR] Cannot read {path}: {exc}"
+    if b"%PDF-" not in header:
+        return f"[BAD_INPUT] append_to_path is not a PDF: {path}"
+    if payload.get("user_password") or payload.get("owner_password"):
+        return "[BAD_INPUT] Passwords cannot be combined with append_to_path"
+    if not pdf_support()["append"]:
+        return "[PDF_APPEND_FAILED] appending to an existing PDF needs the pypdf package, which is not installed"
+    return ""
+
+
+# Mirrors compressor.STRIP_MODES; the host does not import engines.
+_STRIP_MODES = ("all", "exif_only", "keep_icc")
+
+
+def _strip_mode_error(value: Any) -> str:
+    """`[BAD_INPUT]` unless `value` is unset or one of the compressor's strip modes."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, str) and value.strip().lower() in _STRIP_MODES:
+        return ""
+    return f"[BAD_INPUT] strip_mode must be one of {', '.join(_STRIP_MODES)}, got {value!r}"
+
+
+# Compressor engines that only encode lossily; `lossless` cannot be honoured through them.
+_LOSSY_COMPRESSION_ENGINES = ("mozjpeg", "pngquant", "imagequant")
+
+
+def _compress_request_error(item: Any) -> str:
+    if not isinstance(item, dict):
+        return ""
+    engine = str(item.get("engine") or "").strip().lower()
+    if item.get("lossless") is True and engine in _LOSSY_COMPRESSION_ENGINES:
+        return f"[BAD_INPUT] lossless compression is not available with the {engine} engine (use auto, oxipng or pillow)"
+    error = _strip_mode_error(item.get("strip_mode"))
+    if error:
+        return error
+    if item.get("target_ssim") not in (None, ""):
+        error = _target_ssim_error(item["target_ssim"])
+        if error:
+            return error
+    return _target_size_kb_error(item.get("target_size_kb"))
+
+
+def _batch_request_error(module_name: str, item: Any) -> str:
+    """Host-side request validation a batch of `module_name` runs before anything is queued.
+
+    `plan_batch` reports the same errors, so validation added to a batch belongs here.
+    """
+    if not isinstance(item, dict):
+        return ""
+    if module_name == "converter":
+        return _convert_request_error(item)
+    if module_name == "compressor":
+        return _compress_request_error(item)
+    return ""
+
+
+def _compress_output_format(payload: dict) -> str:
+    return Path(str(payload.get("output_path") or payload.get("input_path") or "")).suffix
+
+
 def _extract_runtime_file_path(file_ref: Any) -> str:
     candidates: list[str] = []
     if isinstance(file_ref, dict):
@@ -271,6 +945,7 @@ class DesktopAPI:
         self._info_task_lock = Lock()
         self._settings_lock = Lock()
         self._active_info_task_id: int | None = None
+        self._conflict_prompter = None
 
     @property
     def _task_manager(self):
@@ -283,27 +958,46 @@ class DesktopAPI:
     def _settings(self):
         return load_settings()
 
-    def _run_operation(self, handler):
+    @property
+    def _conflicts(self):
+        if self._conflict_prompter is None:
+            from backend.application.conflicts import ConflictPrompter
+
+            with self._task_manager_lock:
+                if self._conflict_prompter is None:
+                    self._conflict_prompter = ConflictPrompter(lambda detail: notify_conflict_prompt(detail))
+        return self._conflict_prompter
+
+    def _message(self, key: str, **params) -> str:
+        try:
+            lang = self._settings().language
+        except Exception:
+            lang = None
+        return message(key, lang, **params)
+
+    def _run_operation(self, handler, operation: str = "operation"):
         task_id = self._task_manager.begin_task("operation")
         try:
-            return handler()
+            with watch_long_operation(operation, task_id):
+                return handler()
         except Exception as exc:
             return {"success": False, "error": str(exc)}
         finally:
             self._task_manager.finish_task(task_id)
 
-    def _run_batch_operation(self, payloads: list[dict], handler):
+    def _run_batch_operation(self, payloads: list[dict], handler, operation: str = "operation"):
         """Always return list[dict] so frontend never mistakes an error envelope for success."""
         items = list(payloads or [])
         if not items:
             return []
         task_id = self._task_manager.begin_task("operation")
         try:
-            result = handler()
+            with watch_long_operation(operation, task_id, item_count=len(items)):
+                result = handler()
             if isinstance(result, list):
                 return result
             if isinstance(result, dict):
-                error = str(result.get("error") or "批处理失败")
+                error = str(result.get("error") or self._message("batch_failed"))
                 return [
                     {
                         "success": False,
@@ -315,7 +1009,7 @@ class DesktopAPI:
             return [
                 {
                     "success": False,
-                    "error": "批处理返回格式异常",
+                    "error": self._message("batch_bad_result"),
                     "input_path": str(item.get("input_path") or ""),
                 }
                 for item in items
@@ -333,6 +1027,207 @@ class DesktopAPI:
         finally:
             self._task_manager.finish_task(task_id)
 
+    def _run_engine(self, module_name: str, payload: dict) -> dict:
+        settings = self._settings()
+        items: list[dict] = _with_temp_dir(_with_working_copy([payload], settings), settings)
+        conflict_warnings: list[list[str]] = [[]]
+        rejected = _precheck_inputs(module_name, items)
+        if rejected:
+            return rejected[0]
+        if getattr(settings, "conflict_strategy", "") == "ask":
+            decided = self._ask_output_conflicts(module_name, items, conflict_warnings, skip=_up_to_date_indexes(items))
+            if decided:
+                return decided[0]
+        clamped, warnings = clamp_request(module_name, items[0])
+        result = self._run_operation(lambda: execute_engine(module_name, clamped, self._task_manager), module_name)
+        return _merge_warnings(result, [*conflict_warnings[0], *warnings])
+
+    def _run_engine_batch(self, module_name: str, payloads: list[dict], settings: Any | None = None) -> list[dict]:
+        settings = settings or self._settings()
+        reserved_items, reserve_warnings = _reserve_batch_outputs(
+            _with_temp_dir(_with_working_copy(list(payloads or []), settings), settings)
+        )
+        decided = _precheck_inputs(module_name, reserved_items)
+        if getattr(settings, "conflict_strategy", "") == "ask":
+            # Outputs that `skip_up_to_date` will leave alone are never written, so never asked about.
+            skip = {*decided, *_up_to_date_indexes(reserved_items)}
+            asked = self._ask_output_conflicts(module_name, reserved_items, reserve_warnings, skip=skip)
+            decided = {**asked, **decided}
+        indexes = order_batch_indexes(
+            [index for index in range(len(reserved_items)) if index not in decided],
+            reserved_items,
+            getattr(settings, "batch_order", ""),
+        )
+        clamped_items = [clamp_request(module_name, reserved_items[index]) for index in indexes]
+        clamped = [item for item, _warnings in clamped_items]
+        item_warnings = [
+            [*reserve_warnings[index], *bounds] for index, (_item, bounds) in zip(indexes, clamped_items)
+        ]
+        started = time.monotonic()
+        results = self._run_batch_operation(
+            clamped,
+            lambda: execute_engine_batch(module_name, clamped, settings, self._task_manager),
+            module_name,
+        )
+        if len(results) == len(clamped):
+            results = [_merge_warnings(result, warnings) for result, warnings in zip(results, item_warnings)]
+            # Enqueue order may differ from the request (batch_order) and decided items never ran.
+            ordered = {**dict(zip(indexes, results)), **decided}
+            results = [ordered[index] for index in range(len(reserved_items))]
+        self._finish_batch(module_name, results, settings, time.monotonic() - started)
+        return results
+
+    def _ask_output_conflicts(
+        self,
+        module_name: str,
+        items: list[dict],
+        warnings: list[list[str]],
+        skip: Any = (),
+    ) -> dict[int, dict]:
+        """Prompt once per output that already exists on disk (the "ask" conflict strategy).
+
+        Renamed items are updated in place; skipped or cancelled ones come back as ready-made
+        results keyed by their batch index so they never reach the engine.
+        """
+        from backend.application.conflicts import CANCELLED_DECISION
+
+        prompter = self._conflicts
+        operation_id = prompter.new_operation_id(module_name)
+        reserved = [str(item.get("output_path") or "") for item in items if isinstance(item, dict)]
+        decided: dict[int, dict] = {}
+        for index, item in enumerate(items):
+            output_path = str(item.get("output_path") or "") if isinstance(item, dict) else ""
+            if index in skip or not output_path or not os.path.exists(output_path):
+                continue
+            decision = prompter.ask(operation_id, module_name, output_path)
+            if decision == CANCELLED_DECISION:
+                cancelled = {"success": False, "error": "[PY_CANCELLED] operation cancelled"}
+                return {
+                    position: {**cancelled, "input_path": str(entry.get("input_path") or "")}
+                    for position, entry in enumerate(items)
+                    if isinstance(entry, dict)
+                }
+            if decision == "skip":
+                decided[index] = {
+                    "success": True,
+                    "skipped": True,
+                    "input_path": str(item.get("input_path") or ""),
+                    "output_path": output_path,
+                    "warning": self._message("conflict_skipped", name=Path(output_path).name),
+                }
+            elif decision == "rename":
+                renamed = resolve_output_path(output_path, reserved)
+                reserved.append(renamed)
+                items[index] = {**item, "output_path": renamed}
+                warnings[index] = [*warnings[index], self._message("conflict_renamed", name=Path(renamed).name)]
+        return decided
+
+    def get_runtime_status(self) -> dict:
+        return runtime_status()
+
+    def repair_runtime(self) -> dict:
+        return repair_runtime_preparation()
+
+    def get_startup_error(self) -> dict:
+        """The first launch step that failed (`ok` false) and the safe-mode decisions taken."""
+        return startup_error()
+
+    def get_startup_diagnostics(self) -> dict:
+        """Every launch and runtime preparation step with its state, and the first one that failed."""
+        return startup_diagnostics()
+
+    def get_interrupted_operations(self) -> list[dict]:
+        """Items a killed session started but never finished, oldest first."""
+        log = active_operation_log()
+        if log is None:
+            return []
+        return [_interrupted_summary(record) for record in log.interrupted()]
+
+    def _resolve_interrupted(self, operation_ids: list[str] | None) -> list[dict]:
+        log = active_operation_log()
+        if log is None:
+            return []
+        pending = log.interrupted()
+        if operation_ids is not None:
+            wanted = {str(op_id) for op_id in operation_ids}
+            pending = [record for record in pending if record.get("id") in wanted]
+        return log.resolve([record["id"] for record in pending])
+
+    def resume_interrupted_operations(self, operation_ids: list[str] | None = None) -> dict:
+        """Run interrupted items again with their original requests; None means all of them."""
+        from backend.infrastructure.engine_loader import ALLOWED_ENGINES
+
+        records = self._resolve_interrupted(operation_ids)
+        by_module: dict[str, list[dict]] = {}
+        results: list[dict] = []
+        for record in records:
+            if record.get("redacted"):
+                # Rerunning without the passwords would write an unprotected file.
+                fields = ", ".join(record["redacted"])
+                results.append(
+                    {"success": False, "id": record["id"], "error": f"[BAD_INPUT] {fields} not kept in the operation log; start it again"}
+                )
+            elif str(record.get("module") or "") in ALLOWED_ENGINES:
+                by_module.setdefault(record["module"], []).append(record)
+        for module_name, items in by_module.items():
+            processed = self._run_engine_batch(module_name, [item["payload"] for item in items])
+            results.extend({**result, "id": item["id"]} for item, result in zip(items, processed))
+        return {"success": True, "results": results}
+
+    def discard_interrupted_operations(self, operation_ids: list[str] | None = None) -> dict:
+        """Forget interrupted items and delete temp files they left beside their outputs."""
+        removed: list[str] = []
+        records = self._resolve_interrupted(operation_ids)
+        for record in records:
+            for path in leftover_temp_files(record):
+                try:
+                    os.remove(path)
+                    removed.append(path)
+                except OSError:
+                    continue
+        return {"success": True, "count": len(records), "removed": removed}
+
+    def resolve_conflict(self, operation_id: str, path: str, decision: str) -> dict:
+        from backend.application.conflicts import CONFLICT_DECISIONS
+
+        decision = str(decision or "").strip().lower()
+        if decision not in CONFLICT_DECISIONS:
+            return {
+                "success": False,
+                "error": f"[BAD_INPUT] Unknown conflict decision: {decision or '(empty)'} "
+                f"(expected one of {', '.join(CONFLICT_DECISIONS)})",
+            }
+        if not self._conflicts.resolve(str(operation_id or ""), str(path or ""), decision):
+            return {"success": False, "error": "[NOT_FOUND] No pending conflict prompt for this path"}
+        return {"success": True}
+
+    def _finish_batch(self, operation: str, results: list[dict], settings: Any, elapsed: float = 0.0) -> None:
+        """End-of-job glue: announce where the outputs went and optionally reveal the folder."""
+        from backend.application.completion import batch_done_detail
+
+        detail = batch_done_detail(operation, results)
+        if settings.notify_on_completion and results and elapsed >= long_operation_seconds():
+            notify_desktop(
+                message("completion_title", settings.language),
+                message(
+                    "completion_body",
+                    settings.language,
+                    succeeded=detail["succeeded"],
+                    failed=detail["failed"],
+                ),
+            )
+        if not detail["succeeded"]:
+            return
+        detail["message"] = message(
+            "batch_done", settings.language, count=detail["succeeded"], folder=detail["output_dir"]
+        )
+        try:
+            notify_batch_done(detail)
+        except Exception:
+            pass
+        if settings.open_folder_when_done and detail["output_dir"]:
+            reveal_path(detail["output_dir"])
+
     def ping(self) -> str:
         return "pong"
 
@@ -344,6 +1239,24 @@ class DesktopAPI:
             saved = save_settings(settings_from_dict(payload))
         return asdict(saved)
 
+    def export_settings(self) -> str:
+        return settings_to_json(self._settings())
+
+    def import_settings(self, data: str) -> dict:
+        try:
+            imported = settings_from_json(data)
+        except ValueError as exc:
+            # The parser's messages already carry the [BAD_INPUT] code.
+            return {"success": False, "error": str(exc)}
+        with self._settings_lock:
+            saved = save_settings(imported)
+        return asdict(saved)
+
+    def reset_settings(self) -> dict:
+        with self._settings_lock:
+            saved = reset_settings()
+        return asdict(saved)
+
     def update_recent_paths(self, payload: dict) -> dict:
         with self._settings_lock:
             current = self._settings()
@@ -354,7 +1267,7 @@ class DesktopAPI:
 
     def select_input_files(self, options: dict | None = None) -> list[str]:
         dialog_options = dict(options) if isinstance(options, dict) else {}
-        dialog_options.setdefault("title", "选择文件")
+        dialog_options.setdefault("title", self._message("select_files"))
         dialog_options["allowsMultipleSelection"] = bool(
             dialog_options.get("allowsMultipleSelection", True)
         )
@@ -366,16 +1279,65 @@ class DesktopAPI:
         return []
 
     def select_input_directory(self) -> str:
-        return str(open_directory_dialog({"title": "选择文件夹"}) or "")
+        return str(open_directory_dialog({"title": self._message("select_folder")}) or "")
 
     def select_output_directory(self) -> str:
-        return str(open_directory_dialog({"title": "选择输出文件夹"}) or "")
+        return str(open_directory_dialog({"title": self._message("select_output_folder")}) or "")
 
     def expand_dropped_paths(self, paths: list[str]) -> dict:
         filtered = [str(path).strip() for path in paths if str(path).strip()]
-        return expand_input_paths(filtered)
+        archives = [path for path in filtered if is_archive_path(path)]
+        result = expand_input_paths([path for path in filtered if not is_archive_path(path)])
+        if not archives:
+            return result
+        files = list(result.get("files") or [])
+        warnings = list(result.get("warnings") or [])
+        for archive_path in archives:
+            expanded = self.expand_archive(archive_path)
+            files.extend(expanded.get("files") or [])
+            warnings.extend(expanded.get("warnings") or [])
+        merged = {**result, "files": files, "has_directory": True}
+        if warnings:
+            merged["warnings"] = warnings
+        return merged
+
+    def expand_archive(self, archive_path: str) -> dict:
+        try:
+            normalized = normalize_user_supplied_path(str(archive_path or ""))
+        except ValueError as exc:
+            return {"files": [], "warnings": [str(exc)]}
+        if not is_archive_path(normalized):
+            return {"files": [], "warnings": [f"不支持的压缩包格式: {Path(normalized).name}"]}
+        return expand_archive(normalized)
+
+    def repack_archive(self, payload: dict) -> dict:
+        """Zip processed outputs back under the member paths they were expanded from."""
+        items = payload.get("items") if isinstance(payload, dict) else None
+        if not isinstance(items, list) or not items or not all(isinstance(item, dict) for item in items):
+            return {"success": False, "error": "[BAD_INPUT] Missing items list"}
+        entries: list[tuple[str, str]] = []
+        for item in items:
+            produced = normalize_optional_user_supplied_path(str(item.get("output_path") or ""))
+            relative_dir = Path(str(item.get("relative_path") or "").replace("\\", "/")).parent.as_posix()
+            arcname = Path(produced).name if relative_dir in ("", ".") else f"{relative_dir}/{Path(produced).name}"
+            entries.append((produced, arcname))
+        return self._write_zip(entries, str(payload.get("output_path") or ""))
+
+    def _write_zip(self, entries: list[tuple[str, str]], output_path: str) -> dict:
+        """Write (source_path, arcname) pairs to one zip; `RepackArchive` and `PackResultsZip` both end here."""
+        target = normalize_optional_user_supplied_path(output_path)
+        if not target:
+            return {"success": False, "error": self._message("output_path_empty")}
+        try:
+            return {"success": True, **write_zip(entries, target)}
+        except FileNotFoundError as exc:
+            return {"success": False, "error": f"[NOT_FOUND] {exc}"}
+        except Exception as exc:
+            return {"success": False, "error": str(exc)}
 
     def resolve_output_path(self, payload: dict) -> dict:
+        if not str(payload.get("base_path") or "").strip():
+            return {"success": False, "error": self._message("output_path_empty")}
         try:
             base = normalize_user_supplied_path(str(payload.get("base_path") or ""))
             reserved = [str(item) for item in payload.get("reserved") or []]
@@ -401,9 +1363,104 @@ class DesktopAPI:
         except Exception as exc:
             return {"success": False, "error": str(exc), "paths": []}
 
+    def expand_template_tokens(self, payload: dict) -> dict:
+        """Expand per-file EXIF tokens of an output template; other tokens are left to the UI."""
+        from backend.application.output_template import expand_for_results, has_metadata_tokens
+
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] Invalid payload", "templates": [], "warnings": []}
+        template = str(payload.get("template") or "")
+        try:
+            input_paths = [
+                normalize_user_supplied_path(str(item)) for item in (payload.get("input_paths") or []) if str(item).strip()
+            ]
+        except ValueError as exc:
+            return {"success": False, "error": f"[BAD_INPUT] {exc}", "templates": [], "warnings": []}
+        if not has_metadata_tokens(template):
+            return {"success": True, "templates": [template for _ in input_paths], "warnings": [[] for _ in input_paths]}
+
+        requests = [{"action": "get_info", "input_path": resolve_member_path(path)} for path in input_paths]
+        settings = self._settings()
+        infos = self._run_batch_operation(
+            requests,
+            lambda: execute_engine_batch("info_viewer", requests, settings, self._task_manager),
+            "info_viewer",
+        )
+        return expand_for_results(template, input_paths, infos)
+
+    def pack_results_zip(self, payload: dict) -> dict:
+        """Zip produced files (explicit paths or a whole directory) into one archive."""
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] Invalid payload"}
+        output_path = normalize_optional_user_supplied_path(str(payload.get("output_path") or ""))
+        try:
+            preserve = bool(payload.get("preserve_structure", True))
+            directory = str(payload.get("directory") or "").strip()
+            if directory:
+                root = Path(normalize_user_supplied_path(directory))
+                if not root.is_dir():
+                    return {"success": False, "error": f"[NOT_FOUND] Directory not found: {root}"}
+                sources = sorted(
+                    (path for path in root.rglob("*") if path.is_file() and str(path) != output_path),
+                    key=lambda path: str(path).lower(),
+                )
+            else:
+                sources = [
+                    Path(normalize_user_supplied_path(str(item)))
+                    for item in (payload.get("paths") or [])
+                    if str(item).strip()
+                ]
+                root_value = str(payload.get("root") or "").strip()
+                if root_value:
+                    root = Path(normalize_user_supplied_path(root_value))
+                elif len(sources) > 1:
+                    root = Path(os.path.commonpath([str(path.parent) for path in sources]))
+                else:
+                    root = sources[0].parent if sources else Path(output_path).parent
+            if not sources:
+                return {"success": False, "error": "[BAD_INPUT] No files to pack"}
+
+            entries: list[tuple[str, str]] = []
+            for source in sources:
+                arcname = source.name
+                if preserve:
+                    try:
+                        arcname = source.relative_to(root).as_posix()
+                    except ValueError:
+                        arcname = source.name
+                entries.append((str(source), arcname))
+        except (OSError, ValueError) as exc:
+            return {"success": False, "error": str(exc)}
+        return self._write_zip(entries, output_path)
+
+    def reveal_path(self, path_value: str) -> dict:
+        normalized = normalize_optional_user_supplied_path(str(path_value or ""))
+        return {"success": bool(normalized) and reveal_path(normalized)}
+
     def list_system_fonts(self) -> list[str]:
         return list_system_fonts()
 
+    def get_format_capabilities(self) -> dict:
+        return format_capabilities()
+
+    def get_capabilities(self) -> dict:
+        """Version, Python, optional engines/codecs/models and formats, computed once at launch."""
+        return capabilities()
+
+    def get_export_presets(self) -> list[dict]:
+        from backend.application.export_presets import export_presets
+
+        return export_presets()
+
+    def apply_preset(self, preset: str, payload: dict | None = None) -> dict:
+        """Fill a convert request with a named preset's settings; see `export_presets.EXPORT_PRESETS`."""
+        from backend.application.export_presets import apply_preset
+
+        request, error = apply_preset(preset, payload)
+        if error:
+            return {"success": False, "error": error}
+        return {"success": True, "request": request}
+
     def get_image_preview(self, payload: dict) -> dict:
         from backend.application.preview import build_image_preview_smart
 
@@ -411,9 +1468,21 @@ class DesktopAPI:
         input_path = normalized.get("input_path")
         if not input_path:
             return {"success": False, "error": "Missing input_path in payload"}
-        return build_image_preview_smart(str(input_path))
+        return build_image_preview_smart(str(input_path), self._settings().preview_mode)
+
+    def jpeg_size_curve(self, payload: dict) -> dict:
+        from backend.application.size_curve import build_jpeg_size_curve
+
+        normalized = _normalize_payload_paths(payload)
+        return self._run_operation(
+            lambda: build_jpeg_size_curve(str(normalized.get("input_path") or ""), normalized.get("qualities")),
+            "jpeg_size_curve",
+        )
 
     def get_info(self, payload: dict) -> dict:
+        error = _info_request_error(payload)
+        if error:
+            return {"success": False, "error": error}
         normalized = _normalize_payload_paths(payload)
         with self._info_task_lock:
             previous_task_id = self._active_info_task_id
@@ -431,45 +1500,298 @@ class DesktopAPI:
                 if self._active_info_task_id == task_id:
                     self._active_info_task_id = None
 
+    def get_info_batch(self, payloads: list[dict]) -> list[dict]:
+        """Info for many files on the engine pool, in request order.
+
+        Reads are quick and side-effect free, so the batch is not tied to the current
+        operation: cancelling that task neither stops it nor is replaced by it.
+        """
+        from backend.application.image_ops import execute_engine_batch as run_engine_batch
+
+        requests: list[dict] = []
+        errors: list[str] = []
+        for item in payloads or []:
+            if not isinstance(item, dict):
+                item = {}
+            try:
+                request = {**_normalize_payload_paths(item), "action": "get_info"}
+            except ValueError as exc:
+                # One bad path fails its own slot, not the whole batch.
+                requests.append({"action": "get_info", "input_path": str(item.get("input_path") or "")})
+                errors.append(f"[BAD_INPUT] {exc}")
+                continue
+            requests.append(request)
+            errors.append(_info_request_error(request))
+        runnable = [item for item, error in zip(requests, errors) if not error]
+        results: list = []
+        if runnable:
+            try:
+                results = run_engine_batch("info_viewer", runnable, self._settings(), self._task_manager, cancellable=False)
+            except Exception as exc:
+                results = [{"success": False, "error": f"[INTERNAL] {exc}"} for _ in runnable]
+        ran = iter(results)
+        ordered = []
+        for item, error in zip(requests, errors):
+            result = {"success": False, "error": error} if error else next(ran, None)
+            if not isinstance(result, dict):
+                result = {"success": False, "error": self._message("batch_bad_result")}
+            if not result.get("success"):
+                result.setdefault("input_path", str(item.get("input_path") or ""))
+            ordered.append(result)
+        return ordered
+
+    def export_info_report(self, payload: dict) -> dict:
+        """Read info for `input_paths` on the pool and write one CSV/JSON row per file to `output_path`."""
+        from backend.application.info_report import info_report_format, info_report_row, write_info_report
+
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] Invalid payload"}
+        try:
+            output_path = normalize_user_supplied_path(str(payload.get("output_path") or ""))
+            input_paths = [
+                normalize_user_supplied_path(str(item)) for item in (payload.get("input_paths") or []) if str(item).strip()
+            ]
+        except ValueError as exc:
+            return {"success": False, "error": f"[BAD_INPUT] {exc}"}
+        if not input_paths:
+            return {"success": False, "error": "[BAD_INPUT] No input_paths to report"}
+        fmt = info_report_format(payload.get("format"), output_path)
+        if not fmt:
+            return {"success": False, "error": "[BAD_INPUT] format must be csv or json"}
+
+        infos = self.get_info_batch([{"input_path": path} for path in input_paths])
+        rows = [info_report_row(path, info) for path, info in zip(input_paths, infos)]
+        try:
+            written = write_info_report(rows, output_path, fmt)
+        except OSError as exc:
+            return {"success": False, "error": f"[IO_ERROR] {exc}"}
+        failed = sum(1 for row in rows if row["error"])
+        return {"success": True, **written, "failed_count": failed}
+
+    def hash_distance(self, a: str, b: str) -> dict:
+        """Bits that differ between two `perceptual_hash` values; a handful or fewer means near-duplicates."""
+        try:
+            return {"success": True, "distance": hamming_distance(a, b)}
+        except ValueError as exc:
+            return {"success": False, "error": f"[BAD_INPUT] {exc}"}
+
     def edit_metadata(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
         normalized["action"] = "edit_exif"
-        return self._run_operation(lambda: execute_engine("info_viewer", normalized, self._task_manager))
+        return self._run_operation(lambda: execute_engine("info_viewer", normalized, self._task_manager), "info_viewer")
 
     def strip_metadata(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
         normalized["action"] = "strip_metadata"
-        return self._run_operation(lambda: execute_engine("metadata_tool", normalized, self._task_manager))
+        return self._run_operation(lambda: execute_engine("metadata_tool", normalized, self._task_manager), "metadata_tool")
+
+    def set_orientation(self, payload: dict) -> dict:
+        """Rewrite a JPEG's EXIF Orientation tag without decoding or re-encoding its pixels."""
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] Invalid payload"}
+        normalized = _normalize_payload_paths(payload)
+        error = orientation_error(normalized.get("orientation"))
+        if error:
+            return {"success": False, "error": error, "input_path": str(normalized.get("input_path") or "")}
+        normalized["action"] = "set_orientation"
+        return self._run_operation(lambda: execute_engine("metadata_tool", normalized, self._task_manager), "metadata_tool")
 
     def convert(self, payload: dict) -> dict:
+        settings = self._settings()
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("converter", normalized, self._task_manager))
+        error = _convert_request_error(normalized)
+        if error:
+            return {"success": False, "error": error, "input_path": str(normalized.get("input_path") or "")}
+        normalized = _apply_quality_default(normalized, _convert_output_format(normalized), settings.format_quality_defaults)
+        return self._run_engine("converter", _with_max_megapixels(_normalize_convert_ico_sizes(normalized), settings))
 
     def convert_batch(self, payloads: list[dict]) -> list[dict]:
-        normalized = [_normalize_payload_paths(item) for item in payloads]
-        return self._run_batch_operation(
-            normalized,
-            lambda: execute_engine_batch("converter", normalized, self._settings(), self._task_manager),
-        )
+        settings = self._settings()
+        normalized = [
+            _apply_quality_default(item, _convert_output_format(item), settings.format_quality_defaults)
+            for item in (_normalize_payload_paths(payload) for payload in payloads)
+        ]
+        errors = [_batch_request_error("converter", item) for item in normalized]
+        normalized = [
+            item if error else _with_max_megapixels(_normalize_convert_ico_sizes(item), settings)
+            for item, error in zip(normalized, errors)
+        ]
+        if not any(errors):
+            return self._run_engine_batch("converter", normalized, settings)
+        # Reject bad targets and colors up front and keep result order aligned with the request.
+        valid = [item for item, error in zip(normalized, errors) if not error]
+        processed = iter(self._run_engine_batch("converter", valid, settings) if valid else [])
+        return [
+            {"success": False, "error": error, "input_path": str(item.get("input_path") or "")}
+            if error
+            else next(processed, {"success": False, "error": self._message("batch_bad_result")})
+            for item, error in zip(normalized, errors)
+        ]
+
+    def convert_by_rules(self, payload: dict) -> dict:
+        """Convert each request with the first rule matching its source format; copy the rest unchanged."""
+        from backend.application.convert_rules import apply_rule, copy_unmatched, match_rule, normalize_rules
+
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] Invalid payload", "results": []}
+        rules, error = normalize_rules(payload.get("rules"))
+        if error:
+            return {"success": False, "error": error, "results": []}
+        requests = payload.get("requests")
+        if not isinstance(requests, list) or not all(isinstance(item, dict) for item in requests):
+            return {"success": False, "error": "[BAD_INPUT] requests must be a list of request objects", "results": []}
+
+        normalized = [_normalize_payload_paths(item) for item in requests]
+        matches = [match_rule(str(item.get("input_path") or ""), rules) for item in normalized]
+        matched = [apply_rule(item, rule) for item, rule in zip(normalized, matches) if rule is not None]
+        converted = iter(self.convert_batch(matched) if matched else [])
+        results: list[dict] = []
+        for item, rule in zip(normalized, matches):
+            if rule is None:
+                results.append(copy_unmatched(item))
+                continue
+            result = next(converted, {"success": False, "error": self._message("batch_bad_result")})
+            results.append({**result, "copied": False, "rule": rule} if isinstance(result, dict) else result)
+        return {"success": True, "results": results}
+
+    def convert_batch_by_rule(self, payload: dict) -> list[dict]:
+        """Build one convert request per input from a source-extension -> target map and run them as a batch.
+
+        Output paths are `<output_dir>/<output_prefix>_<name>.<ext>`, made unique with the same
+        rules as `resolve_output_paths`; `options` holds request fields shared by every item.
+        Inputs whose extension has no entry fail with `[UNSUPPORTED_FORMAT]` and never run.
+        """
+        from backend.application.convert_rules import match_rule, rule_output_path, rules_from_format_map
+
+        if not isinstance(payload, dict):
+            return [{"success": False, "error": "[BAD_INPUT] Invalid payload"}]
+        rules, error = rules_from_format_map(payload.get("formats"))
+        input_paths = payload.get("input_paths")
+        if not error and not isinstance(input_paths, list):
+            error = "[BAD_INPUT] input_paths must be a list"
+        if error:
+            return [{"success": False, "error": error}]
+        options = payload.get("options") if isinstance(payload.get("options"), dict) else {}
+        settings = self._settings()
+        output_dir = str(payload.get("output_dir") or settings.default_output_dir or "").strip()
+        try:
+            output_dir = normalize_optional_user_supplied_path(output_dir) if output_dir else ""
+            inputs = [normalize_user_supplied_path(str(item or "")) for item in input_paths]
+        except ValueError as exc:
+            return [{"success": False, "error": f"[BAD_INPUT] {exc}"}]
+
+        reserved: list[str] = []
+        requests: list[dict] = []
+        results: list[dict | None] = []
+        for input_path in inputs:
+            rule = match_rule(input_path, rules)
+            if rule is None:
+                suffix = Path(input_path).suffix or input_path
+                results.append(
+                    {"success": False, "error": f"[UNSUPPORTED_FORMAT] No target format for {suffix}", "input_path": input_path}
+                )
+                continue
+            output_path = resolve_output_path(
+                rule_output_path(input_path, rule, output_dir, settings.output_prefix), reserved
+            )
+            reserved.append(output_path)
+            request = {**options, "input_path": input_path, "output_path": output_path, "format": rule["format"]}
+            if "quality" in rule:
+                request["quality"] = rule["quality"]
+            requests.append(request)
+            results.append(None)
+        converted = iter(self.convert_batch(requests) if requests else [])
+        return [
+            result if result is not None else next(converted, {"success": False, "error": self._message("batch_bad_result")})
+            for result in results
+        ]
 
     def compress(self, payload: dict) -> dict:
+        defaults = self._settings().format_quality_defaults
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("compressor", normalized, self._task_manager))
+        error = _compress_request_error(normalized)
+        if error:
+            return {"success": False, "error": error, "input_path": str(normalized.get("input_path") or "")}
+        normalized = _apply_quality_default(normalized, _compress_output_format(normalized), defaults)
+        return self._run_engine("compressor", normalized)
 
     def compress_batch(self, payloads: list[dict]) -> list[dict]:
-        normalized = [_normalize_payload_paths(item) for item in payloads]
-        return self._run_batch_operation(
-            normalized,
-            lambda: execute_engine_batch("compressor", normalized, self._settings(), self._task_manager),
-        )
+        """Compress as a batch; `target_ssim` items run their quality search on the pool too."""
+        settings = self._settings()
+        normalized = [
+            _apply_quality_default(item, _compress_output_format(item), settings.format_quality_defaults)
+            for item in (_normalize_payload_paths(payload) for payload in payloads)
+        ]
+        errors = [_batch_request_error("compressor", item) for item in normalized]
+        if not any(errors):
+            return self._run_engine_batch("compressor", normalized, settings)
+        valid = [item for item, error in zip(normalized, errors) if not error]
+        processed = iter(self._run_engine_batch("compressor", valid, settings) if valid else [])
+        return [
+            {"success": False, "error": error, "input_path": str(item.get("input_path") or "")}
+            if error
+            else next(processed, {"success": False, "error": self._message("batch_bad_result")})
+            for item, error in zip(normalized, errors)
+        ]
+
+    def estimate_compression(self, payload: dict) -> dict:
+        """Dry-run `compress`: the engine encodes to a temp file, reports the sizes and deletes it."""
+        if not isinstance(payload, dict):
+            return {"success": False, "error": "[BAD_INPUT] request must be an object"}
+        # No output is written, so there is nothing to name, reserve or compare freshness against.
+        return self.compress({**payload, "output_path": "", "skip_up_to_date": False, "estimate": True})
+
+    def compress_to_quality(self, payload: dict) -> dict:
+        """Compress at the lowest quality whose SSIM reaches `target_ssim`; the quality-driven `target_size_kb`."""
+        normalized = _normalize_payload_paths(payload)
+        request = {**normalized, "target_ssim": normalized.get("target_ssim", 0.98)}
+        # Same checks as `Compress` with a `target_ssim`, except that here the target is required.
+        error = _target_ssim_error(request["target_ssim"]) or _compress_request_error(request)
+        if error:
+            return {"success": False, "error": error}
+
+        # The compressor searches quality against the full-resolution output itself.
+        return self._run_engine("compressor", {**request, "target_ssim": float(request["target_ssim"])})
+
+    def assign_color_profile(self, payload: dict) -> dict:
+        normalized = _normalize_payload_paths(payload)
+        mode = str(normalized.get("mode") or "assign").strip().lower()
+        if mode not in ("assign", "convert"):
+            return {"success": False, "error": f"[BAD_INPUT] mode must be assign or convert, got {mode}"}
+        profile = str(normalized.get("profile") or normalized.get("profile_path") or "").strip()
+        if not profile:
+            return {"success": False, "error": "[BAD_INPUT] Missing profile"}
+        if Path(profile).suffix.lower() in (".icc", ".icm") and not Path(profile).is_file():
+            return {"success": False, "error": f"[NOT_FOUND] Profile file not found: {profile}"}
+        request = {**normalized, "action": "color_profile", "mode": mode, "profile": profile}
+        return self._run_engine("converter", request)
 
     def generate_pdf(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("pdf_generator", normalized, self._task_manager))
+        error = (
+            _pdf_image_dpi_error(normalized.get("image_dpi"))
+            or _pdf_encryption_error(normalized)
+            or _pdf_bookmarks_error(normalized)
+            or _pdf_append_error(normalized)
+        )
+        if error:
+            return {"success": False, "error": error}
+        return self._run_engine("pdf_generator", normalized)
 
     def split_gif(self, payload: dict) -> dict:
+        error = _gif_request_error(payload)
+        if error:
+            return {"success": False, "error": error}
+        normalized = _with_gif_limits(_with_contact_sheet_sampling(_normalize_payload_paths(payload)), self._settings())
+        return self._run_operation(lambda: execute_engine("gif_splitter", normalized, self._task_manager), "gif_splitter")
+
+    def get_gif_details(self, payload: dict) -> dict:
+        """Per-frame delay, disposal, placement and encoded size plus loop and palette of a GIF."""
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("gif_splitter", normalized, self._task_manager))
+        if not isinstance(normalized, dict) or not normalized.get("input_path"):
+            return {"success": False, "error": "[BAD_INPUT] Missing input_path"}
+        request = {"action": "inspect", "input_path": normalized["input_path"]}
+        return self._run_operation(lambda: execute_engine("gif_splitter", request, self._task_manager), "gif_splitter")
 
     def probe_animated_paths(self, paths: list[str]) -> list[dict]:
         normalized_paths = [str(path) for path in paths if str(path).strip()]
@@ -477,42 +1799,237 @@ class DesktopAPI:
 
     def generate_subtitle_long_image(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("subtitle_stitcher", normalized, self._task_manager))
+        return self._run_operation(
+            lambda: execute_engine("subtitle_stitcher", normalized, self._task_manager),
+            "subtitle_stitcher",
+        )
 
     def add_watermark(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("watermark", normalized, self._task_manager))
+        return self._run_engine("watermark", normalized)
 
     def add_watermark_batch(self, payloads: list[dict]) -> list[dict]:
         normalized = [_normalize_payload_paths(item) for item in payloads]
-        return self._run_batch_operation(
-            normalized,
-            lambda: execute_engine_batch("watermark", normalized, self._settings(), self._task_manager),
-        )
+        return self._run_engine_batch("watermark", normalized)
+
+    def preview_watermark_grid(self, payload: dict) -> dict:
+        from backend.application.watermark_preview import build_watermark_grid
+
+        normalized, warnings = clamp_request("watermark", _normalize_payload_paths(payload))
+        [normalized] = _with_temp_dir([normalized], self._settings())
+        result = self._run_operation(lambda: build_watermark_grid(normalized), "watermark_grid")
+        return _merge_warnings(result, warnings)
+
+    def overlay_guides(self, payload: dict) -> dict:
+        normalized = _normalize_payload_paths(payload)
+        normalized["type"] = "guides"
+        return self._run_engine("watermark", normalized)
 
     def adjust(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("adjuster", normalized, self._task_manager))
+        return self._run_engine("adjuster", normalized)
 
     def adjust_batch(self, payloads: list[dict]) -> list[dict]:
         normalized = [_normalize_payload_paths(item) for item in payloads]
-        return self._run_batch_operation(
-            normalized,
-            lambda: execute_engine_batch("adjuster", normalized, self._settings(), self._task_manager),
-        )
+        return self._run_engine_batch("adjuster", normalized)
 
     def apply_filter(self, payload: dict) -> dict:
         normalized = _normalize_payload_paths(payload)
-        return self._run_operation(lambda: execute_engine("filter", normalized, self._task_manager))
+        return self._run_engine("filter", normalized)
 
     def apply_filter_batch(self, payloads: list[dict]) -> list[dict]:
         normalized = [_normalize_payload_paths(item) for item in payloads]
-        return self._run_batch_operation(
-            normalized,
-            lambda: execute_engine_batch("filter", normalized, self._settings(), self._task_manager),
+        return self._run_engine_batch("filter", normalized)
+
+    def begin_edit_session(self, path: str) -> dict:
+        """Start iterative editing of `path`; later edits build on the previous result, not the original."""
+        normalized = normalize_optional_user_supplied_path(str(path or ""))
+        return edit_sessions().begin(resolve_member_path(normalized) if normalized else normalized)
+
+    def apply_edit(self, session_id: str, payload: dict) -> dict:
+        """Run an adjust (default) or `operation: "filter"` request on the session's current state."""
+        return edit_sessions().apply(
+            session_id,
+            _normalize_payload_paths(payload),
+            self._run_engine,
+            depth=self._settings().edit_history_depth,
         )
 
+    def undo_edit(self, session_id: str) -> dict:
+        return edit_sessions().undo(session_id)
+
+    def commit_edit(self, session_id: str, output_path: str) -> dict:
+        return edit_sessions().commit(session_id, normalize_optional_user_supplied_path(str(output_path or "")))
+
+    def cancel_edit(self, session_id: str) -> dict:
+        return edit_sessions().cancel(session_id)
+
+    def run_manifest(self, manifest_path: str) -> dict:
+        """Run every operation of a JSON/JSONL/CSV manifest through the regular batch handlers.
+
+        Each consecutive run of one operation becomes a pooled, cancellable batch, so entries
+        that read an earlier entry's output run after it; results come back in manifest order
+        tagged with their source line.
+        """
+        from backend.application.manifest import load_manifest, manifest_groups
+
+        try:
+            normalized = normalize_user_supplied_path(str(manifest_path or ""))
+        except ValueError as exc:
+            return {"success": False, "error": f"[BAD_INPUT] {exc}", "results": []}
+        entries, errors = load_manifest(normalized)
+        if errors:
+            return {"success": False, "error": errors[0], "errors": errors, "results": []}
+
+        handlers = {
+            "convert": self.convert_batch,
+            "compress": self.compress_batch,
+            "watermark": self.add_watermark_batch,
+            "adjust": self.adjust_batch,
+            "filter": self.apply_filter_batch,
+        }
+        results: list[dict] = [{} for _ in entries]
+        cancelled = False
+        for operation, indexes in manifest_groups(entries):
+            payloads = [entries[index]["payload"] for index in indexes]
+            if cancelled:
+                batch = [{"success": False, "error": "[PY_CANCELLED] operation cancelled"} for _ in payloads]
+            else:
+                try:
+                    batch = handlers[operation](payloads)
+                except Exception as exc:
+                    batch = [{"success": False, "error": str(exc)} for _ in payloads]
+            processed = iter(batch if isinstance(batch, list) else [])
+            for index in indexes:
+                result = next(processed, None)
+                if not isinstance(result, dict):
+                    result = {"success": False, "error": self._message("batch_bad_result")}
+                entry = entries[index]
+                results[index] = {
+                    "input_path": entry["payload"]["input_path"],
+                    **result,
+                    "line": entry["line"],
+                    "operation": operation,
+                }
+            # A cancel only reaches the running batch; stop the groups that have not started yet.
+            cancelled = cancelled or any(
+                str(results[index].get("error") or "").startswith("[PY_CANCELLED]") for index in indexes
+            )
+
+        succeeded = sum(1 for item in results if item.get("success"))
+        return {
+            "success": True,
+            "manifest_path": normalized,
+            "total": len(results),
+            "succeeded": succeeded,
+            "failed": len(results) - succeeded,
+            "results": results,
+        }
+
+    def plan_batch(self, operation: str, requests: Any) -> dict:
+        """Dry-run a batch: resolve outputs and validate inputs exactly as the batch would, without work.
+
+        Uses the batch's own checks (`_batch_request_error`, `_precheck_inputs`, the
+        `skip_up_to_date` freshness test and the `conflict_strategy` setting), so an item the
+        batch would reject, skip or ask about is reported that way here. Zip members are looked
+        up in their archive's name list, not extracted.
+        """
+        import json
+
+        op = str(operation or "").strip().lower()
+        module_name = PLAN_OPERATIONS.get(op) or (op if op in PLAN_OPERATIONS.values() else "")
+        if not module_name:
+            return {"success": False, "error": f"[BAD_INPUT] Unknown batch operation: {operation}"}
+        try:
+            items = json.loads(requests or "[]") if isinstance(requests, (str, bytes)) else requests
+        except json.JSONDecodeError as exc:
+            return {"success": False, "error": f"[BAD_INPUT] Invalid requests JSON: {exc.msg}"}
+        if not isinstance(items, list) or not all(isinstance(item, dict) for item in items):
+            return {"success": False, "error": "[BAD_INPUT] requests must be a list of request objects"}
+
+        settings = self._settings()
+        capabilities = format_capabilities()["formats"]
+        prepared: list[dict] = []
+        errors: list[str] = []
+        for item in items:
+            try:
+                normalized = _normalize_payload_paths(item, extract_members=False)
+                errors.append("")
+            except ValueError as exc:
+                normalized = dict(item)
+                errors.append(f"[BAD_INPUT] {exc}")
+            defaults = settings.format_quality_defaults
+            if module_name == "converter":
+                normalized = _apply_quality_default(normalized, _convert_output_format(normalized), defaults)
+            elif module_name == "compressor":
+                normalized = _apply_quality_default(normalized, _compress_output_format(normalized), defaults)
+            prepared.append(normalized)
+
+        reserved_items, reserve_warnings = _reserve_batch_outputs(prepared)
+        rejected = _precheck_inputs(module_name, reserved_items)
+        ask = getattr(settings, "conflict_strategy", "") == "ask"
+        planned: list[dict] = []
+        for index, payload in enumerate(reserved_items):
+            clamped, bounds_warnings = clamp_request(module_name, payload)
+            warnings = [*reserve_warnings[index], *bounds_warnings]
+            input_path = str(clamped.get("input_path") or "")
+            output_path = str(clamped.get("output_path") or "")
+            if module_name == "converter":
+                target_format = canonical_format(_convert_output_format(clamped))
+            else:
+                target_format = canonical_format(Path(output_path or input_path).suffix)
+
+            error = errors[index] or _batch_request_error(module_name, payload)
+            if not error and not input_path:
+                error = "[BAD_INPUT] Missing input_path"
+            elif not error and not (Path(input_path).is_file() or archive_member_listed(input_path)):
+                error = f"[NOT_FOUND] Input file not found: {input_path}"
+            elif not error and index in rejected:
+                error = rejected[index]["error"]
+            elif not error and not output_path:
+                error = "[BAD_INPUT] Missing output_path"
+            elif not error and target_format not in capabilities:
+                error = f"[UNSUPPORTED_FORMAT] Unsupported output format: {target_format or '?'}"
+            up_to_date = not error and up_to_date_result(clamped) is not None
+            if up_to_date:
+                warnings.append(message("plan_up_to_date", settings.language, name=Path(output_path).name))
+            elif not error and Path(output_path).exists() and Path(output_path) != Path(input_path):
+                key = "plan_conflict_ask" if ask else "plan_overwrite"
+                warnings.append(message(key, settings.language, name=Path(output_path).name))
+
+            entry = {
+                "index": index,
+                "input_path": input_path,
+                "output_path": output_path,
+                "format": target_format,
+                "skip": bool(error) or up_to_date,
+                "warnings": warnings,
+            }
+            if error:
+                entry["error"] = error
+            if up_to_date:
+                entry["up_to_date"] = True
+            planned.append(entry)
+
+        return {
+            "success": True,
+            "operation": module_name,
+            "total": len(planned),
+            "skipped": sum(1 for entry in planned if entry["skip"]),
+            "items": planned,
+        }
+
+    def summarize_results(self, operation: str, results: Any, elapsed_ms: float | None = None) -> dict:
+        from backend.application.batch_summary import summarize_results
+
+        try:
+            return {"success": True, **summarize_results(operation, results, elapsed_ms)}
+        except (TypeError, ValueError) as exc:
+            return {"success": False, "error": f"[BAD_INPUT] {exc}"}
+
     def cancel_processing(self) -> bool:
+        if self._conflict_prompter is not None:
+            self._conflict_prompter.cancel()
         return self._task_manager.cancel_current_task()
 
     def open_file_dialog(self, options: dict | None = None):
@@ -557,6 +2074,15 @@ class DesktopAPI:
     def SaveSettings(self, payload: dict) -> dict:
         return self.save_settings(payload)
 
+    def ExportSettings(self) -> str:
+        return self.export_settings()
+
+    def ImportSettings(self, data: str) -> dict:
+        return self.import_settings(data)
+
+    def ResetSettings(self) -> dict:
+        return self.reset_settings()
+
     def UpdateRecentPaths(self, payload: dict) -> dict:
         return self.update_recent_paths(payload)
 
@@ -572,42 +2098,126 @@ class DesktopAPI:
     def ExpandDroppedPaths(self, paths: list[str]) -> dict:
         return self.expand_dropped_paths(paths)
 
+    def ExpandArchive(self, archive_path: str) -> dict:
+        return self.expand_archive(archive_path)
+
+    def RepackArchive(self, payload: dict) -> dict:
+        return self.repack_archive(payload)
+
+    def GetRuntimeStatus(self) -> dict:
+        return self.get_runtime_status()
+
+    def GetStartupError(self) -> dict:
+        return self.get_startup_error()
+
+    def GetStartupDiagnostics(self) -> dict:
+        return self.get_startup_diagnostics()
+
+    def RepairRuntime(self) -> dict:
+        return self.repair_runtime()
+
+    def GetInterruptedOperations(self) -> list[dict]:
+        return self.get_interrupted_operations()
+
+    def ResumeInterruptedOperations(self, operation_ids: list[str] | None = None) -> dict:
+        return self.resume_interrupted_operations(operation_ids)
+
+    def DiscardInterruptedOperations(self, operation_ids: list[str] | None = None) -> dict:
+        return self.discard_interrupted_operations(operation_ids)
+
+    def ResolveConflict(self, operation_id: str, path: str, decision: str) -> dict:
+        return self.resolve_conflict(operation_id, path, decision)
+
     def ResolveOutputPath(self, payload: dict) -> dict:
         return self.resolve_output_path(payload)
 
     def ResolveOutputPaths(self, payload: dict) -> dict:
         return self.resolve_output_paths(payload)
 
+    def RevealPath(self, path_value: str) -> dict:
+        return self.reveal_path(path_value)
+
     def ListSystemFonts(self) -> list[str]:
         return self.list_system_fonts()
 
+    def ExpandTemplateTokens(self, payload: dict) -> dict:
+        return self.expand_template_tokens(payload)
+
+    def PackResultsZip(self, payload: dict) -> dict:
+        return self.pack_results_zip(payload)
+
+    def GetFormatCapabilities(self) -> dict:
+        return self.get_format_capabilities()
+
+    def GetCapabilities(self) -> dict:
+        return self.get_capabilities()
+
+    def GetExportPresets(self) -> list[dict]:
+        return self.get_export_presets()
+
+    def ApplyPreset(self, preset: str, payload: dict | None = None) -> dict:
+        return self.apply_preset(preset, payload)
+
     def GetImagePreview(self, payload: dict) -> dict:
         return self.get_image_preview(payload)
 
     def GetInfo(self, payload: dict) -> dict:
         return self.get_info(payload)
 
+    def GetInfoBatch(self, payloads: list[dict]) -> list[dict]:
+        return self.get_info_batch(payloads)
+
+    def HammingDistance(self, a: str, b: str) -> dict:
+        return self.hash_distance(a, b)
+
+    def ExportInfoReport(self, payload: dict) -> dict:
+        return self.export_info_report(payload)
+
+    def JPEGSizeCurve(self, payload: dict) -> dict:
+        return self.jpeg_size_curve(payload)
+
     def EditMetadata(self, payload: dict) -> dict:
         return self.edit_metadata(payload)
 
     def StripMetadata(self, payload: dict) -> dict:
         return self.strip_metadata(payload)
 
+    def SetOrientation(self, payload: dict) -> dict:
+        return self.set_orientation(payload)
+
     def Convert(self, payload: dict) -> dict:
         return self.convert(payload)
 
     def ConvertBatch(self, payloads: list[dict]) -> list[dict]:
         return self.convert_batch(payloads)
 
+    def ConvertBatchByRule(self, payload: dict) -> list[dict]:
+        return self.convert_batch_by_rule(payload)
+
+    def ConvertByRules(self, payload: dict) -> dict:
+        return self.convert_by_rules(payload)
+
     def Compress(self, payload: dict) -> dict:
         return self.compress(payload)
 
     def CompressBatch(self, payloads: list[dict]) -> list[dict]:
         return self.compress_batch(payloads)
 
+    def CompressToQuality(self, payload: dict) -> dict:
+        return self.compress_to_quality(payload)
+
+    def EstimateCompression(self, payload: dict) -> dict:
+        return self.estimate_compression(payload)
+
+    def AssignColorProfile(self, payload: dict) -> dict:
+        return self.assign_color_profile(payload)
+
     def GeneratePDF(self, payload: dict) -> dict:
         return self.generate_pdf(payload)
 
+    def GetGIFDetails(self, payload: dict) -> dict:
+        return self.get_gif_details(payload)
+
     def SplitGIF(self, payload: dict) -> dict:
         return self.split_gif(payload)
 
@@ -623,6 +2233,12 @@ class DesktopAPI:
     def AddWatermarkBatch(self, payloads: list[dict]) -> list[dict]:
         return self.add_watermark_batch(payloads)
 
+    def PreviewWatermarkGrid(self, payload: dict) -> dict:
+        return self.preview_watermark_grid(payload)
+
+    def OverlayGuides(self, payload: dict) -> dict:
+        return self.overlay_guides(payload)
+
     def Adjust(self, payload: dict) -> dict:
         return self.adjust(payload)
 
@@ -635,6 +2251,30 @@ class DesktopAPI:
     def ApplyFilterBatch(self, payloads: list[dict]) -> list[dict]:
         return self.apply_filter_batch(payloads)
 
+    def BeginEditSession(self, path: str) -> dict:
+        return self.begin_edit_session(path)
+
+    def ApplyEdit(self, session_id: str, payload: dict) -> dict:
+        return self.apply_edit(session_id, payload)
+
+    def UndoEdit(self, session_id: str) -> dict:
+        return self.undo_edit(session_id)
+
+    def CommitEdit(self, session_id: str, output_path: str) -> dict:
+        return self.commit_edit(session_id, output_path)
+
+    def CancelEdit(self, session_id: str) -> dict:
+        return self.cancel_edit(session_id)
+
+    def PlanBatch(self, operation: str, requests: Any) -> dict:
+        return self.plan_batch(operation, requests)
+
+    def RunManifest(self, manifest_path: str) -> dict:
+        return self.run_manifest(manifest_path)
+
+    def SummarizeResults(self, operation: str, results: Any, elapsed_ms: float | None = None) -> dict:
+        return self.summarize_results(operation, results, elapsed_ms)
+
     def CancelProcessing(self) -> bool:
         return self.cancel_processing()
 
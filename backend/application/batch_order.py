@@ -0,0 +1,39 @@
+from __future__ import annotations
+
+import os
+from pathlib import Path
+from typing import Any
+
+from backend.contracts.settings import BATCH_ORDERS
+
+DEFAULT_BATCH_ORDER = BATCH_ORDERS[0]
+
+
+def _input_size(payload: Any) -> int:
+    path = str(payload.get("input_path") or "") if isinstance(payload, dict) else ""
+    try:
+        return os.stat(path).st_size if path else 0
+    except OSError:
+        # Unreadable inputs sort as empty; the engine reports the real error.
+        return 0
+
+
+def _input_name(payload: Any) -> tuple[str, str]:
+    path = str(payload.get("input_path") or "") if isinstance(payload, dict) else ""
+    return Path(path.replace("\\", "/")).name.casefold(), path.casefold()
+
+
+def order_batch_indexes(indexes: list[int], payloads: list[Any], strategy: str) -> list[int]:
+    """Return `indexes` in the order their payloads should be enqueued.
+
+    Sorting is stable, so equal sizes or names keep their request order. Callers map the
+    results back through the returned indexes to keep the response in request order.
+    """
+    strategy = str(strategy or "").strip().lower()
+    if strategy == "smallest-first":
+        return sorted(indexes, key=lambda index: _input_size(payloads[index]))
+    if strategy == "largest-first":
+        return sorted(indexes, key=lambda index: -_input_size(payloads[index]))
+    if strategy == "name":
+        return sorted(indexes, key=lambda index: _input_name(payloads[index]))
+    return list(indexes)
@@ -0,0 +1,94 @@
+from __future__ import annotations
+
+import json
+from typing import Any
+
+CANCELLED_ERROR_CODE = "[PY_CANCELLED]"
+
+_INPUT_SIZE_KEYS = ("original_size", "input_size")
+_OUTPUT_SIZE_KEYS = ("compressed_size", "output_size", "file_size")
+
+
+def _first_size(item: dict, keys: tuple[str, ...]) -> int | None:
+    for key in keys:
+        value = item.get(key)
+        if isinstance(value, bool):
+            continue
+        if isinstance(value, (int, float)) and value >= 0:
+            return int(value)
+    return None
+
+
+def _coerce_results(results: Any) -> list[dict]:
+    if isinstance(results, (str, bytes)):
+        results = json.loads(results or "[]")
+    if isinstance(results, dict):
+        results = [results]
+    if not isinstance(results, list):
+        raise ValueError("results must be a list of result objects")
+    return [item for item in results if isinstance(item, dict)]
+
+
+def summarize_results(operation: str, results: Any, elapsed_ms: float | None = None) -> dict[str, Any]:
+    """Aggregate per-item engine results into dashboard totals for any batch operation."""
+    items = _coerce_results(results)
+    succeeded = failed = cancelled = skipped = 0
+    input_bytes = output_bytes = 0
+    paired_input = paired_output = 0
+    item_time_ms = 0.0
+    engines: dict[str, int] = {}
+
+    for item in items:
+        if item.get("success"):
+            succeeded += 1
+            if item.get("skipped"):
+                skipped += 1
+        elif str(item.get("error") or "").startswith(CANCELLED_ERROR_CODE):
+            cancelled += 1
+        else:
+            failed += 1
+
+        in_size = _first_size(item, _INPUT_SIZE_KEYS)
+        out_size = _first_size(item, _OUTPUT_SIZE_KEYS) if item.get("success") else None
+        if in_size is not None:
+            input_bytes += in_size
+        if out_size is not None:
+            output_bytes += out_size
+        if in_size and out_size is not None:
+            paired_input += in_size
+            paired_output += out_size
+
+        engine = item.get("engine_used") if item.get("success") else None
+        if isinstance(engine, str) and engine:
+            engines[engine] = engines.get(engine, 0) + 1
+
+        try:
+            item_time_ms += max(0.0, float(item.get("elapsed_ms") or 0))
+        except (TypeError, ValueError):
+            pass
+
+    try:
+        total_time_ms = max(0.0, float(elapsed_ms)) if elapsed_ms is not None else item_time_ms
+    except (TypeError, ValueError):
+        total_time_ms = item_time_ms
+
+    average_rate = 0.0
+    if paired_input > 0:
+        average_rate = round((1 - paired_output / paired_input) * 100, 2)
+
+    summary = {
+        "operation": str(operation or ""),
+        "total": len(items),
+        "succeeded": succeeded,
+        "failed": failed,
+        "cancelled": cancelled,
+        "skipped": skipped,
+        "input_bytes": input_bytes,
+        "output_bytes": output_bytes,
+        "total_time_ms": round(total_time_ms, 1),
+        "average_compression_rate": average_rate,
+    }
+    # Compress results name the engine that wrote each file; other batches leave this out.
+    if engines:
+        summary["engines"] = dict(sorted(engines.items()))
+    return summary
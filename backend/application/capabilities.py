@@ -0,0 +1,106 @@
+from __future__ import annotations
+
+import copy
+import importlib.util
+import platform
+import threading
+from datetime import datetime, timezone
+from typing import Any
+
+# Optional packages probed by import name without importing them.
+COMPRESSION_ENGINES = {
+    "mozjpeg": "mozjpeg_lossless_optimization",
+    "imagequant": "imagequant",
+    "oxipng": "oxipng",
+}
+# Optional packages behind PDF features; the engine fails those requests without them.
+PDF_FEATURES = {
+    "append": "pypdf",
+    "aes256": "pyaes",
+}
+# This build ships no ML models; the keys are listed so the UI can gate on them uniformly.
+OPTIONAL_MODELS = ("background_removal", "upscaling", "face_detection")
+
+_lock = threading.Lock()
+_cached: dict[str, Any] | None = None
+
+
+def _module_available(name: str) -> bool:
+    try:
+        return importlib.util.find_spec(name) is not None
+    except (ImportError, ValueError):
+        return False
+
+
+def app_version() -> str:
+    """Version stamped into the release manifest, else the source checkout's pyproject.toml."""
+    from backend.infrastructure.runtime_files import runtime_version
+
+    version = runtime_version()
+    if version:
+        return version
+    from backend.packaging.release_config import default_project_root, read_project_version
+
+    try:
+        return read_project_version(default_project_root())
+    except (OSError, KeyError, ValueError):
+        return "unknown"
+
+
+def _pillow_version() -> str:
+    try:
+        import PIL
+    except ImportError:
+        return ""
+    return str(getattr(PIL, "__version__", ""))
+
+
+def _pillow_handles(extension: str) -> bool:
+    try:
+        from PIL import Image
+    except ImportError:
+        return False
+    Image.init()
+    return extension in Image.registered_extensions()
+
+
+def codec_support() -> dict[str, bool]:
+    """Optional codecs, mirroring how the converter enables them (built-in Pillow or a plugin)."""
+    return {
+        "avif": _pillow_handles(".avif") or _module_available("pillow_avif"),
+        "jxl": _module_available("pillow_jxl"),
+        "heic": _module_available("pillow_heif"),
+        "raw": _module_available("rawpy"),
+    }
+
+
+def pdf_support() -> dict[str, bool]:
+    return {name: _module_available(module) for name, module in PDF_FEATURES.items()}
+
+
+def build_capabilities() -> dict[str, Any]:
+    from backend.domain.formats import format_capabilities
+
+    return {
+        "app_version": app_version(),
+        "python": {
+            "version": platform.python_version(),
+            "implementation": platform.python_implementation(),
+            "pillow": _pillow_version(),
+        },
+        "compression_engines": {name: _module_available(module) for name, module in COMPRESSION_ENGINES.items()},
+        "codecs": codec_support(),
+        "pdf": pdf_support(),
+        "models": {name: False for name in OPTIONAL_MODELS},
+        "formats": format_capabilities(),
+        "generated_at": datetime.now(timezone.utc).isoformat(timespec="seconds"),
+    }
+
+
+def capabilities() -> dict[str, Any]:
+    """Build once per process (warmed at launch) and hand out copies of the cached report."""
+    global _cached
+    with _lock:
+        if _cached is None:
+            _cached = build_capabilities()
+        return copy.deepcopy(_cached)
@@ -0,0 +1,35 @@
+from __future__ import annotations
+
+import os
+from typing import Any
+
+BATCH_DONE_EVENT = "__imageflow_batch_done__"
+
+
+def batch_output_dir(results: list[Any]) -> str:
+    """Common directory of every successful output, or "" when nothing was written."""
+    folders = [
+        os.path.dirname(os.path.abspath(str(item.get("output_path"))))
+        for item in results
+        if isinstance(item, dict) and item.get("success") and str(item.get("output_path") or "").strip()
+    ]
+    if not folders:
+        return ""
+    try:
+        return os.path.commonpath(folders)
+    except ValueError:
+        # Outputs on different drives have no common path; fall back to the first.
+        return folders[0]
+
+
+def batch_done_detail(operation: str, results: list[Any], message: str = "") -> dict[str, Any]:
+    succeeded = sum(1 for item in results if isinstance(item, dict) and item.get("success"))
+    return {
+        "type": "batch_done",
+        "operation": str(operation or ""),
+        "total": len(results),
+        "succeeded": succeeded,
+        "failed": len(results) - succeeded,
+        "output_dir": batch_output_dir(results),
+        "message": message,
+    }
@@ -0,0 +1,99 @@
+from __future__ import annotations
+
+import itertools
+import os
+import threading
+from typing import Any, Callable
+
+CONFLICT_PROMPT_EVENT = "__imageflow_conflict_prompt__"
+CONFLICT_DECISIONS = ("overwrite", "skip", "rename")
+# Unanswered prompts fall back to the non-destructive choice the "rename" strategy makes anyway.
+DEFAULT_CONFLICT_DECISION = "rename"
+DEFAULT_CONFLICT_TIMEOUT_SECONDS = 120.0
+# Internal answer when the batch is cancelled while a prompt is open; never sent by the UI.
+CANCELLED_DECISION = "cancel"
+
+
+def conflict_timeout_seconds() -> float:
+    raw_value = str(os.getenv("IMAGEFLOW_CONFLICT_TIMEOUT_SECONDS", "") or "").strip()
+    if not raw_value:
+        return DEFAULT_CONFLICT_TIMEOUT_SECONDS
+    try:
+        parsed = float(raw_value)
+    except ValueError:
+        return DEFAULT_CONFLICT_TIMEOUT_SECONDS
+    return parsed if parsed > 0 else DEFAULT_CONFLICT_TIMEOUT_SECONDS
+
+
+def _path_key(path: str) -> str:
+    return str(path or "").replace("\\", "/").casefold()
+
+
+class _PendingPrompt:
+    __slots__ = ("answered", "decision")
+
+    def __init__(self) -> None:
+        self.answered = threading.Event()
+        self.decision = DEFAULT_CONFLICT_DECISION
+
+
+class ConflictPrompter:
+    """Ask the UI what to do with an existing output and block until it answers or times out.
+
+    Each batch gets its own operation id; answers are matched on (operation id, path) so a
+    late reply to an earlier batch can never decide a conflict in the current one.
+    """
+
+    def __init__(self, notify: Callable[[dict[str, Any]], None], timeout: float | None = None):
+        self._notify = notify
+        self._timeout = timeout
+        self._lock = threading.Lock()
+        self._pending: dict[tuple[str, str], _PendingPrompt] = {}
+        self._ids = itertools.count(1)
+
+    def new_operation_id(self, operation: str) -> str:
+        return f"{operation or 'operation'}-{next(self._ids)}"
+
+    def ask(self, operation_id: str, operation: str, path: str) -> str:
+        key = (operation_id, _path_key(path))
+        pending = _PendingPrompt()
+        with self._lock:
+            self._pending[key] = pending
+        timeout = conflict_timeout_seconds() if self._timeout is None else max(0.0, float(self._timeout))
+        try:
+            self._notify(
+                {
+                    "type": "conflict_prompt",
+                    "operation_id": operation_id,
+                    "operation": str(operation or ""),
+                    "path": path,
+                    "decisions": list(CONFLICT_DECISIONS),
+                    "default": DEFAULT_CONFLICT_DECISION,
+                    "timeout_seconds": timeout,
+                }
+            )
+            pending.answered.wait(timeout)
+        except Exception:
+            pass
+        finally:
+            with self._lock:
+                self._pending.pop(key, None)
+        return pending.decision
+
+    def resolve(self, operation_id: str, path: str, decision: str) -> bool:
+        """Deliver the UI's answer; False when nothing is waiting for it (expired or unknown)."""
+        with self._lock:
+            pending = self._pending.get((str(operation_id or ""), _path_key(path)))
+            if pending is None or pending.answered.is_set():
+                return False
+            pending.decision = decision
+            pending.answered.set()
+        return True
+
+    def cancel(self) -> None:
+        """Release every open prompt so its batch stops instead of waiting out the timeout."""
+        with self._lock:
+            for pending in self._pending.values():
+                if not pending.answered.is_set():
+                    pending.decision = CANCELLED_DECISION
+                    pending.answered.set()
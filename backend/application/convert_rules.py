@@ -0,0 +1,100 @@
+from __future__ import annotations
+
+import os
+import shutil
+from pathlib import Path
+from typing import Any
+
+from backend.domain.formats import FORMAT_CAPABILITIES, canonical_format, convert_target_error
+from backend.domain.paths import sanitize_filename
+
+
+def normalize_rules(raw_rules: Any) -> tuple[list[dict[str, Any]], str]:
+    """Validate `[{source, format, quality?}]` rules; `source` may be one extension or a list.
+
+    Returns the canonical rules and "" or the first `[BAD_INPUT]`/`[UNSUPPORTED_FORMAT]` error.
+    """
+    if not isinstance(raw_rules, list) or not raw_rules:
+        return [], "[BAD_INPUT] rules must be a non-empty list"
+    rules: list[dict[str, Any]] = []
+    for index, raw in enumerate(raw_rules):
+        if not isinstance(raw, dict):
+            return [], f"[BAD_INPUT] rules[{index}] must be an object"
+        sources = raw.get("source")
+        sources = sources if isinstance(sources, list) else [sources]
+        canonical_sources = sorted({canonical_format(str(item)) for item in sources if str(item or "").strip()})
+        if not canonical_sources:
+            return [], f"[BAD_INPUT] rules[{index}].source is required"
+        target = canonical_format(str(raw.get("format") or ""))
+        error = convert_target_error(target)
+        if error:
+            return [], f"{error} (rules[{index}])"
+        rule: dict[str, Any] = {"index": index, "source": canonical_sources, "format": target}
+        quality = raw.get("quality")
+        if quality not in (None, "", 0):
+            if isinstance(quality, bool) or not isinstance(quality, (int, float)) or not 1 <= quality <= 100:
+                return [], f"[BAD_INPUT] rules[{index}].quality must be between 1 and 100"
+            rule["quality"] = int(quality)
+        rules.append(rule)
+    return rules, ""
+
+
+def rules_from_format_map(format_map: Any) -> tuple[list[dict[str, Any]], str]:
+    """Turn `{source_ext: format | {format, quality?}}` into rules, validated like `normalize_rules`."""
+    if not isinstance(format_map, dict) or not format_map:
+        return [], "[BAD_INPUT] formats must be a non-empty map of source extension to target"
+    raw_rules = []
+    for source, target in format_map.items():
+        entry = target if isinstance(target, dict) else {"format": target}
+        raw_rules.append({**entry, "source": source})
+    return normalize_rules(raw_rules)
+
+
+def rule_output_path(input_path: str, rule: dict[str, Any], output_dir: str = "", prefix: str = "") -> str:
+    """`<output_dir or source dir>/<prefix>_<stem><extension>`, as the default template names it."""
+    source = Path(input_path)
+    extension = FORMAT_CAPABILITIES[rule["format"]]["extensions"][0]
+    prefix = sanitize_filename(str(prefix or "").strip())
+    name = f"{prefix}_{source.stem}" if prefix else source.stem
+    return str(Path(output_dir or source.parent) / f"{name}{extension}")
+
+
+def match_rule(input_path: str, rules: list[dict[str, Any]]) -> dict[str, Any] | None:
+    """First rule whose source list contains the input's format wins."""
+    source = canonical_format(Path(str(input_path or "")).suffix)
+    for rule in rules:
+        if source in rule["source"]:
+            return rule
+    return None
+
+
+def apply_rule(payload: dict, rule: dict[str, Any]) -> dict:
+    """Turn a format-less convert request into the one `rule` asks for, fixing the output suffix."""
+    applied = {**payload, "format": rule["format"]}
+    if "quality" in rule:
+        applied["quality"] = rule["quality"]
+    output_path = str(payload.get("output_path") or "")
+    if output_path:
+        extension = FORMAT_CAPABILITIES[rule["format"]]["extensions"][0]
+        applied["output_path"] = str(Path(output_path).with_suffix(extension))
+    return applied
+
+
+def copy_unmatched(payload: dict) -> dict[str, Any]:
+    """Copy a file no rule matched, keeping its own extension; in-place requests are a no-op."""
+    input_path = str(payload.get("input_path") or "")
+    output_path = str(payload.get("output_path") or "")
+    if not input_path or not output_path:
+        return {"success": False, "error": "[BAD_INPUT] Missing input_path or output_path", "input_path": input_path}
+    source = Path(input_path)
+    target = Path(output_path).with_suffix(source.suffix)
+    result = {"input_path": input_path, "output_path": str(target), "copied": True, "rule": None}
+    if not source.is_file():
+        return {**result, "success": False, "error": f"[NOT_FOUND] Input file not found: {input_path}"}
+    try:
+        if os.path.abspath(source) != os.path.abspath(target):
+            target.parent.mkdir(parents=True, exist_ok=True)
+            shutil.copy2(source, target)
+    except OSError as exc:
+        return {**result, "success": False, "error": f"[IO_ERROR] {exc}"}
+    return {**result, "success": True}
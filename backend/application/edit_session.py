@@ -0,0 +1,162 @@
+from __future__ import annotations
+
+import os
+import shutil
+import threading
+import uuid
+from pathlib import Path
+from typing import Any, Callable
+
+from backend.domain.formats import canonical_format
+from backend.infrastructure.engine_loader import engine_temp_registry, staged_output
+
+# Engines an edit can run; each reads `input_path` and writes `output_path` like its batch form.
+EDIT_ENGINES = {"adjust": "adjuster", "adjuster": "adjuster", "filter": "filter"}
+DEFAULT_HISTORY_DEPTH = 20
+
+EngineRunner = Callable[[str, dict], dict]
+
+
+class EditSessions:
+    """Iterative edits on one file: each edit reads the previous edited state instead of the original.
+
+    A session owns a registered temp directory holding the untouched copy (state 0) and one
+    file per applied edit, so undo is dropping the newest file. Commit copies the current
+    state to the chosen output; commit and cancel remove the directory, and anything left
+    at exit is purged with the other engine temp files.
+    """
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self._sessions: dict[str, dict[str, Any]] = {}
+
+    def begin(self, path: str) -> dict[str, Any]:
+        source = Path(str(path or "")).expanduser()
+        if not str(path or "").strip():
+            return {"success": False, "error": "[BAD_INPUT] path is required"}
+        if not source.is_file():
+            return {"success": False, "error": f"[NOT_FOUND] Input file not found: {source}"}
+        registry = engine_temp_registry()
+        directory = registry.create_dir(prefix="imageflow_edit_")
+        base = os.path.join(directory, f"state0{source.suffix}")
+        try:
+            shutil.copy2(source, base)
+        except OSError as exc:
+            registry.discard(directory)
+            return {"success": False, "error": f"[IO_ERROR] {exc}"}
+        session = {"id": uuid.uuid4().hex, "source": str(source), "dir": directory, "states": [base], "next": 1}
+        with self._lock:
+            self._sessions[session["id"]] = session
+        return self._summary(session)
+
+    def apply(self, session_id: str, request: Any, run: EngineRunner, depth: int = DEFAULT_HISTORY_DEPTH) -> dict[str, Any]:
+        """Run one adjust/filter request (`operation` picks the engine) on the current state."""
+        if not isinstance(request, dict):
+            return {"success": False, "error": "[BAD_INPUT] request must be an object"}
+        operation = str(request.get("operation") or "adjust").strip().lower()
+        module_name = EDIT_ENGINES.get(operation)
+        if module_name is None:
+            return {"success": False, "error": f"[BAD_INPUT] Unknown edit operation: {operation} (expected adjust or filter)"}
+        session = self._get(session_id)
+        if session is None:
+            return _unknown_session(session_id)
+        with self._lock:
+            current = session["states"][-1]
+            output = os.path.join(session["dir"], f"state{session['next']}{Path(session['source']).suffix}")
+            session["next"] += 1
+        payload = {key: value for key, value in request.items() if key != "operation"}
+        result = run(module_name, {**payload, "input_path": current, "output_path": output})
+        if not isinstance(result, dict) or not result.get("success") or not os.path.isfile(output):
+            _remove_quietly(output)
+            failed = dict(result) if isinstance(result, dict) else {"success": False}
+            failed.setdefault("error", "[INTERNAL] edit produced no output")
+            return {**failed, "success": False, **self._state(session)}
+        with self._lock:
+            session["states"].append(output)
+            # State 0 is the original copy and always stays; the oldest edits go first.
+            while len(session["states"]) - 1 > max(1, int(depth)):
+                _remove_quietly(session["states"].pop(1))
+        return {**result, **self._summary(session)}
+
+    def undo(self, session_id: str) -> dict[str, Any]:
+        session = self._get(session_id)
+        if session is None:
+            return _unknown_session(session_id)
+        with self._lock:
+            newest = session["states"].pop() if len(session["states"]) > 1 else ""
+        if not newest:
+            return {"success": False, "error": "[BAD_INPUT] Nothing to undo", **self._state(session)}
+        _remove_quietly(newest)
+        return self._summary(session)
+
+    def commit(self, session_id: str, output_path: str) -> dict[str, Any]:
+        """Write the current state to `output_path` (same format as the source) and end the session."""
+        session = self._get(session_id)
+        if session is None:
+            return _unknown_session(session_id)
+        target = Path(str(output_path or "")).expanduser()
+        if not str(output_path or "").strip():
+            return {"success": False, "error": "[BAD_INPUT] output_path is required"}
+        source_format = canonical_format(Path(session["source"]).suffix.lstrip("."))
+        if canonical_format(target.suffix.lstrip(".")) != source_format:
+            return {
+                "success": False,
+                "error": f"[BAD_INPUT] output_path must keep the source format ({source_format}); use Convert to change it",
+            }
+        with self._lock:
+            current = session["states"][-1]
+            edits = len(session["states"]) - 1
+        try:
+            with staged_output(str(target), target.suffix or ".tmp") as staging:
+                shutil.copyfile(current, staging)
+        except OSError as exc:
+            return {"success": False, "error": f"[IO_ERROR] {exc}", **self._state(session)}
+        self.cancel(session_id)
+        return {"success": True, "session_id": session["id"], "input_path": session["source"], "output_path": str(target), "edits": edits}
+
+    def cancel(self, session_id: str) -> dict[str, Any]:
+        with self._lock:
+            session = self._sessions.pop(str(session_id or ""), None)
+        if session is None:
+            return _unknown_session(session_id)
+        engine_temp_registry().discard(session["dir"])
+        return {"success": True, "session_id": session["id"]}
+
+    def active(self) -> list[str]:
+        with self._lock:
+            return list(self._sessions)
+
+    def _get(self, session_id: str) -> dict[str, Any] | None:
+        with self._lock:
+            return self._sessions.get(str(session_id or ""))
+
+    def _state(self, session: dict[str, Any]) -> dict[str, Any]:
+        with self._lock:
+            return {
+                "session_id": session["id"],
+                "input_path": session["source"],
+                "working_path": session["states"][-1],
+                "edits": len(session["states"]) - 1,
+                "can_undo": len(session["states"]) > 1,
+            }
+
+    def _summary(self, session: dict[str, Any]) -> dict[str, Any]:
+        return {"success": True, **self._state(session)}
+
+
+def _unknown_session(session_id: Any) -> dict[str, Any]:
+    return {"success": False, "error": f"[BAD_INPUT] Unknown edit session: {session_id}"}
+
+
+def _remove_quietly(path: str) -> None:
+    try:
+        os.remove(path)
+    except OSError:
+        pass
+
+
+_SESSIONS = EditSessions()
+
+
+def edit_sessions() -> EditSessions:
+    return _SESSIONS
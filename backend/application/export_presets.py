@@ -0,0 +1,96 @@
+from __future__ import annotations
+
+import copy
+from pathlib import Path
+from typing import Any
+
+from backend.domain.formats import FORMAT_CAPABILITIES
+
+# Resize fields a preset resets when it keeps the source dimensions.
+_NO_RESIZE = {"resize_mode": "", "width": 0, "height": 0, "scale_percent": 0, "long_edge": 0}
+
+# Each preset is plain data: `settings` are convert request fields written over the caller's
+# request. A new preset is one more entry here; GetExportPresets/ApplyPreset pick it up as is.
+EXPORT_PRESETS: tuple[dict[str, Any], ...] = (
+    {
+        "id": "web",
+        "label": "Web",
+        "description": "sRGB WebP q80, metadata stripped, long edge 2048 px",
+        "settings": {
+            "format": "webp",
+            "quality": 80,
+            "lossless": False,
+            "keep_metadata": False,
+            "keep_color_profile": False,
+            "auto_orient": True,
+            "preserve_bit_depth": False,
+            "resize_mode": "long_edge",
+            "long_edge": 2048,
+            "width": 0,
+            "height": 0,
+            "scale_percent": 0,
+        },
+    },
+    {
+        "id": "print",
+        "label": "Print",
+        "description": "LZW TIFF at full size, keeping colour profile, metadata and 16-bit depth",
+        "settings": {
+            "format": "tiff",
+            "tiff_compression": "lzw",
+            "keep_metadata": True,
+            "keep_color_profile": True,
+            "auto_orient": True,
+            "preserve_bit_depth": True,
+            **_NO_RESIZE,
+        },
+    },
+    {
+        "id": "archive",
+        "label": "Archive",
+        "description": "Lossless PNG at full size with every pixel, tag and profile left as the source has them",
+        "settings": {
+            "format": "png",
+            "compress_level": 9,
+            "lossless": True,
+            "keep_metadata": True,
+            "keep_color_profile": True,
+            "auto_orient": False,
+            "preserve_bit_depth": True,
+            "trim_borders": False,
+            **_NO_RESIZE,
+        },
+    },
+)
+
+
+def export_presets() -> list[dict[str, Any]]:
+    return copy.deepcopy(list(EXPORT_PRESETS))
+
+
+def find_preset(preset_id: Any) -> dict[str, Any] | None:
+    key = str(preset_id or "").strip().lower()
+    for preset in EXPORT_PRESETS:
+        if preset["id"] == key:
+            return preset
+    return None
+
+
+def apply_preset(preset_id: Any, request: Any) -> tuple[dict[str, Any], str]:
+    """Write a preset's settings over a convert request, fixing the output suffix for its format.
+
+    Returns the new request and "" or a `[BAD_INPUT]` error; paths and fields the preset does not
+    name are kept.
+    """
+    preset = find_preset(preset_id)
+    if preset is None:
+        known = ", ".join(item["id"] for item in EXPORT_PRESETS)
+        return {}, f"[BAD_INPUT] Unknown preset: {preset_id} (expected one of {known})"
+    if request is not None and not isinstance(request, dict):
+        return {}, "[BAD_INPUT] request must be an object"
+    applied = {**(request or {}), **copy.deepcopy(preset["settings"])}
+    output_path = str(applied.get("output_path") or "")
+    if output_path:
+        extension = FORMAT_CAPABILITIES[applied["format"]]["extensions"][0]
+        applied["output_path"] = str(Path(output_path).with_suffix(extension))
+    return applied, ""
@@ -4,16 +4,21 @@ import atexit
 import os
 import threading
 import time
-from concurrent.futures import FIRST_COMPLETED, ProcessPoolExecutor, wait
-from typing import Any
+from concurrent.futures import FIRST_COMPLETED, Future, ProcessPoolExecutor, wait
+from concurrent.futures.process import BrokenProcessPool
+from typing import Any, Callable
 
 from backend.application.task_manager import TaskManager
+from backend.contracts.messages import message
 from backend.contracts.settings import AppSettings
 from backend.infrastructure.engine_loader import invoke_engine_process
+from backend.infrastructure.operation_log import active_operation_log
 
 _pool_lock = threading.Lock()
 _pool: ProcessPoolExecutor | None = None
 _pool_size = 0
+# Engines whose results report `elapsed_ms`.
+TIMED_MODULES = frozenset({"converter", "compressor"})
 _pool_disabled = str(os.getenv("IMAGEFLOW_DISABLE_PROCESS_POOL", "") or "").strip().lower() in {
     "1",
     "true",
@@ -24,10 +29,15 @@ _pool_disabled = str(os.getenv("IMAGEFLOW_DISABLE_PROCESS_POOL", "") or "").stri
 
 def _invoke_engine_job(module_name: str, payload: dict[str, Any]) -> dict[str, Any]:
     """Top-level worker entry so ProcessPoolExecutor can pickle it on Windows."""
+    started = time.perf_counter()
     try:
-        return invoke_engine_process(module_name, payload)
+        result = invoke_engine_process(module_name, payload)
     except Exception as exc:
-        return {"success": False, "error": str(exc)}
+        result = {"success": False, "error": str(exc)}
+    if module_name in TIMED_MODULES and isinstance(result, dict):
+        # Wall time of the engine call alone (SVG rasterization included), not pool queueing.
+        result.setdefault("elapsed_ms", int((time.perf_counter() - started) * 1000))
+    return result
 
 
 def _desired_pool_size(requested: int | None = None) -> int:
@@ -62,11 +72,32 @@ def _shutdown_pool() -> None:
 atexit.register(_shutdown_pool)
 
 
+def _discard_broken_pool(pool: Any) -> None:
+    """Drop `pool` after a worker died so the next batch starts a fresh one instead of failing at submit."""
+    global _pool, _pool_size
+    with _pool_lock:
+        if _pool is not pool:
+            return
+        _pool = None
+        _pool_size = 0
+    try:
+        pool.shutdown(wait=False)
+    except Exception:
+        pass
+
+
 def reset_process_pool_for_tests() -> None:
     """Test helper to drop the global pool between cases."""
     _shutdown_pool()
 
 
+def disable_process_pool() -> None:
+    """Run every later batch in-process, one item at a time, as safe mode does; drops a live pool."""
+    global _pool_disabled
+    _pool_disabled = True
+    _shutdown_pool()
+
+
 def warm_process_pool(min_size: int | None = None) -> int:
     """Pre-create the process pool so first user action avoids cold spawn."""
     if _pool_disabled:
@@ -85,9 +116,8 @@ def _get_pool(min_size: int = 1) -> ProcessPoolExecutor:
         if _pool is not None and _pool_size >= target:
             return _pool
         if _pool is not None:
+            # Only stop new submissions: jobs other batches already queued there still run to completion.
             try:
-                _pool.shutdown(wait=False, cancel_futures=True)
-            except TypeError:
                 _pool.shutdown(wait=False)
             except Exception:
                 pass
@@ -97,39 +127,109 @@ def _get_pool(min_size: int = 1) -> ProcessPoolExecutor:
         return _pool
 
 
+def _up_to_date_result(payload: dict[str, Any]) -> dict[str, Any] | None:
+    """Result for a `skip_up_to_date` item whose output is already newer than its input, else None."""
+    if not isinstance(payload, dict) or not payload.get("skip_up_to_date"):
+        return None
+    input_path = str(payload.get("input_path") or "")
+    output_path = str(payload.get("output_path") or "")
+    if not input_path or not output_path or os.path.abspath(input_path) == os.path.abspath(output_path):
+        return None
+    try:
+        if os.stat(output_path).st_mtime_ns <= os.stat(input_path).st_mtime_ns:
+            return None
+    except OSError:
+        return None
+    return {"success": True, "skipped": True, "input_path": input_path, "output_path": output_path}
+
+
 def _run_jobs(
     module_name: str,
     payloads: list[dict[str, Any]],
     max_workers: int,
     task_manager: TaskManager | None = None,
     task_id: int | None = None,
+    lang: str | None = None,
+    on_result: Callable[[int, dict[str, Any]], None] | None = None,
 ) -> list[dict[str, Any]]:
+    """Run `payloads` on the pool; `on_result(index, result)` fires as each item settles."""
     if not payloads:
         return []
 
+    def settled(index: int, result: dict[str, Any]) -> None:
+        if on_result is not None:
+            on_result(index, result)
+
+    skipped = [_up_to_date_result(payload) for payload in payloads]
+    if any(skipped):
+        remaining_indexes = [index for index, result in enumerate(skipped) if result is None]
+        for index, result in enumerate(skipped):
+            if result is not None:
+                settled(index, result)
+        processed = iter(
+            _run_jobs(
+                module_name,
+                [payloads[index] for index in remaining_indexes],
+                max_workers,
+                task_manager,
+                task_id,
+                lang,
+                on_result=lambda index, result: settled(remaining_indexes[index], result),
+            )
+        )
+        return [result if result is not None else next(processed) for result in skipped]
+
     if _pool_disabled:
         results: list[dict[str, Any]] = []
-        for payload in payloads:
+        for index, payload in enumerate(payloads):
             if task_manager is not None and task_id is not None and task_manager.is_cancelled(task_id):
                 results.append({"success": False, "error": "[PY_CANCELLED] operation cancelled"})
-                continue
-            results.append(_invoke_engine_job(module_name, payload))
+            else:
+                results.append(_invoke_engine_job(module_name, payload))
+            settled(index, results[-1])
         return results
 
     worker_count = max(1, min(int(max_workers), len(payloads)))
     pool = _get_pool(worker_count)
-    futures = [pool.submit(_invoke_engine_job, module_name, payload) for payload in payloads]
-    results: list[dict[str, Any]] = [{"success": False, "error": "处理失败"} for _ in payloads]
-    pending = set(futures)
-    future_to_index = {future: index for index, future in enumerate(futures)}
+    results: list[dict[str, Any]] = [{"success": False, "error": message("operation_failed", lang)} for _ in payloads]
+    queued = iter(range(len(payloads)))
+    pending: set[Future] = set()
+    future_to_index: dict[Future, int] = {}
+    broken = False
+
+    def submit_next() -> None:
+        nonlocal pool
+        index = next(queued, None)
+        if index is None:
+            return
+        try:
+            future = pool.submit(_invoke_engine_job, module_name, payloads[index])
+        except BrokenProcessPool as exc:
+            # A worker died while the batch was running; the rest fail like the jobs it took down.
+            future = Future()
+            future.set_exception(exc)
+        except RuntimeError:
+            # A concurrent batch grew the shared pool and shut this one down; move to its replacement.
+            pool = _get_pool(worker_count)
+            future = pool.submit(_invoke_engine_job, module_name, payloads[index])
+        future_to_index[future] = index
+        pending.add(future)
+
+    # The shared pool may be larger than this batch's `max_workers`; keeping only that many
+    # jobs in flight is what bounds the batch, queueing everything up front would not.
+    for _ in range(worker_count):
+        submit_next()
 
     try:
         while pending:
             if task_manager is not None and task_id is not None and task_manager.is_cancelled(task_id):
-                for future in list(pending):
+                cancelled = []
+                for future in pending:
                     future.cancel()
-                    index = future_to_index[future]
+                    cancelled.append(future_to_index[future])
+                for index in [*cancelled, *queued]:
                     results[index] = {"success": False, "error": "[PY_CANCELLED] operation cancelled"}
+                    settled(index, results[index])
                 break
 
             done, pending = wait(pending, timeout=0.1, return_when=FIRST_COMPLETED)
@@ -142,17 +242,52 @@ def _run_jobs(
                     if isinstance(value, dict):
                         results[index] = value
                     else:
-                        results[index] = {"success": False, "error": "处理返回格式异常"}
+                        results[index] = {"success": False, "error": message("result_bad_format", lang)}
+                except BrokenProcessPool as exc:
+                    broken = True
+                    results[index] = {"success": False, "error": str(exc) or message("operation_failed", lang)}
                 except Exception as exc:
                     results[index] = {"success": False, "error": str(exc)}
+                settled(index, results[index])
+                submit_next()
     finally:
         if task_manager is not None and task_id is not None and task_manager.is_cancelled(task_id):
-            for future in futures:
+            for future in future_to_index:
                 future.cancel()
+        if broken:
+            _discard_broken_pool(pool)
 
     return results
 
 
+def _journaled(
+    module_name: str,
+    payloads: list[dict[str, Any]],
+    on_result: Callable[[int, dict[str, Any]], None] | None,
+) -> Callable[[int, dict[str, Any]], None] | None:
+    """Record an intent per item before it runs and its result once it settles (see operation_log).
+
+    Only items that write an output are journaled; reads such as info lookups have nothing to recover.
+    """
+    log = active_operation_log()
+    if log is None:
+        return on_result
+    operation_ids = [
+        log.begin(module_name, payload) if isinstance(payload, dict) and payload.get("output_path") else None
+        for payload in payloads
+    ]
+    if not any(operation_ids):
+        return on_result
+
+    def settled(index: int, result: dict[str, Any]) -> None:
+        if operation_ids[index]:
+            log.finish(operation_ids[index], result)
+        if on_result is not None:
+            on_result(index, result)
+
+    return settled
+
+
 def execute_engine(
     module_name: str,
     payload: dict[str, Any],
@@ -169,8 +304,9 @@ def execute_engine(
         max_workers=1,
         task_manager=task_manager,
         task_id=effective_task_id,
+        on_result=_journaled(module_name, [payload], None),
     )
-    result = results[0] if results else {"success": False, "error": "处理失败"}
+    result = results[0] if results else {"success": False, "error": message("operation_failed")}
     if task_manager and effective_task_id is not None and task_manager.is_cancelled(effective_task_id):
         return {"success": False, "error": "[PY_CANCELLED] operation cancelled"}
     return result
@@ -181,11 +317,14 @@ def execute_engine_batch(
     payloads: list[dict[str, Any]],
     settings: AppSettings,
     task_manager: TaskManager,
+    on_result: Callable[[int, dict[str, Any]], None] | None = None,
+    cancellable: bool = True,
 ) -> list[dict[str, Any]]:
+    """Run `payloads` on the shared pool; with `cancellable=False` cancelling the current task does not stop them."""
     if not payloads:
         return []
 
-    task_id = task_manager.current_task_id
+    task_id = task_manager.current_task_id if cancellable else None
     max_workers = max(1, min(settings.max_concurrency, len(payloads)))
     return _run_jobs(
         module_name,
@@ -193,4 +332,6 @@ def execute_engine_batch(
         max_workers=max_workers,
         task_manager=task_manager,
         task_id=task_id,
+        lang=settings.language,
+        on_result=_journaled(module_name, payloads, on_result),
     )
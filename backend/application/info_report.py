@@ -0,0 +1,70 @@
+from __future__ import annotations
+
+import csv
+import json
+from pathlib import Path
+from typing import Any
+
+from backend.application.output_template import capture_datetime
+from backend.infrastructure.engine_loader import staged_output
+
+INFO_REPORT_FORMATS = ("csv", "json")
+# One row per input file, in this column order for both formats.
+INFO_REPORT_COLUMNS = (
+    "path",
+    "file_name",
+    "format",
+    "width",
+    "height",
+    "file_size",
+    "has_gps",
+    "latitude",
+    "longitude",
+    "datetime",
+    "error",
+)
+
+
+def info_report_format(value: Any, output_path: str) -> str:
+    """The requested format, else the output extension, else csv; "" when not supported."""
+    text = str(value or "").strip().lower() or Path(output_path).suffix.lstrip(".").lower() or "csv"
+    return text if text in INFO_REPORT_FORMATS else ""
+
+
+def info_report_row(input_path: str, info: Any) -> dict[str, Any]:
+    info = info if isinstance(info, dict) else {}
+    if not info.get("success"):
+        error = str(info.get("error") or "no info")
+        row = {column: "" for column in INFO_REPORT_COLUMNS}
+        return {**row, "path": input_path, "file_name": Path(input_path).name, "error": error}
+    captured = capture_datetime(info)
+    has_gps = info.get("has_gps") is True
+    return {
+        "path": input_path,
+        "file_name": info.get("file_name") or Path(input_path).name,
+        "format": info.get("format") or "",
+        "width": info.get("width") or 0,
+        "height": info.get("height") or 0,
+        "file_size": info.get("file_size") or 0,
+        "has_gps": has_gps,
+        "latitude": info.get("latitude") if has_gps else "",
+        "longitude": info.get("longitude") if has_gps else "",
+        "datetime": captured.isoformat() if captured else "",
+        "error": "",
+    }
+
+
+def write_info_report(rows: list[dict[str, Any]], output_path: str, fmt: str) -> dict[str, Any]:
+    """Write the rows next to `output_path` first, then move them into place."""
+    target = Path(output_path)
+    with staged_output(output_path, f".{fmt}.tmp") as tmp_path:
+        if fmt == "json":
+            with open(tmp_path, "w", encoding="utf-8") as handle:
+                json.dump(rows, handle, ensure_ascii=False, indent=2)
+        else:
+            # The BOM lets spreadsheet apps detect UTF-8 file names.
+            with open(tmp_path, "w", encoding="utf-8-sig", newline="") as handle:
+                writer = csv.DictWriter(handle, fieldnames=INFO_REPORT_COLUMNS)
+                writer.writeheader()
+                writer.writerows(rows)
+    return {"output_path": str(target), "format": fmt, "row_count": len(rows), "size": target.stat().st_size}
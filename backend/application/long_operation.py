@@ -0,0 +1,66 @@
+from __future__ import annotations
+
+import os
+import threading
+from contextlib import contextmanager
+from typing import Any, Callable
+
+DEFAULT_LONG_OPERATION_SECONDS = 15.0
+LONG_OPERATION_EVENT = "__imageflow_long_operation__"
+
+
+def long_operation_seconds() -> float:
+    raw_value = str(os.getenv("IMAGEFLOW_LONG_OPERATION_SECONDS", "") or "").strip()
+    if not raw_value:
+        return DEFAULT_LONG_OPERATION_SECONDS
+    try:
+        parsed = float(raw_value)
+    except ValueError:
+        return DEFAULT_LONG_OPERATION_SECONDS
+    return parsed if parsed > 0 else DEFAULT_LONG_OPERATION_SECONDS
+
+
+@contextmanager
+def watch_long_operation(
+    operation: str,
+    task_id: int | None,
+    notify: Callable[[dict[str, Any]], None],
+    threshold: float | None = None,
+    item_count: int = 1,
+):
+    """Fire one informational notice if the block is still running after `threshold` seconds.
+
+    The notice is not progress and never fails the operation; it only lets the UI
+    say "still processing" instead of looking frozen. When it fired, a second one with
+    `done: true` follows once the block ends so the UI can take the message down.
+    """
+    delay = long_operation_seconds() if threshold is None else max(0.0, float(threshold))
+    detail = {
+        "type": "long_operation",
+        "operation": str(operation or ""),
+        "task_id": task_id,
+        "elapsed_seconds": delay,
+        "item_count": int(item_count),
+        "done": False,
+    }
+    fired = threading.Event()
+
+    def send(notice: dict[str, Any]) -> None:
+        try:
+            notify(notice)
+        except Exception:
+            pass
+
+    def fire() -> None:
+        fired.set()
+        send(dict(detail))
+
+    timer = threading.Timer(delay, fire)
+    timer.daemon = True
+    timer.start()
+    try:
+        yield
+    finally:
+        timer.cancel()
+        if fired.is_set():
+            send({**detail, "done": True})
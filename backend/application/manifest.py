@@ -0,0 +1,168 @@
+from __future__ import annotations
+
+import csv
+import io
+import json
+from pathlib import Path
+from typing import Any
+
+MANIFEST_EXTENSIONS = (".json", ".jsonl", ".ndjson", ".csv")
+# Same names PlanBatch accepts; each maps onto an existing *_batch handler.
+MANIFEST_OPERATIONS = ("convert", "compress", "watermark", "adjust", "filter")
+MAX_MANIFEST_BYTES = 16 * 1024 * 1024
+MAX_MANIFEST_ENTRIES = 10_000
+_CSV_PATH_COLUMNS = {"type", "input", "output", "input_path", "output_path"}
+
+
+def _json_array_entries(text: str) -> list[tuple[int, Any]]:
+    """Decode a top-level JSON array element by element so each entry keeps its starting line."""
+    decoder = json.JSONDecoder()
+    position = text.index("[") + 1
+    entries: list[tuple[int, Any]] = []
+    while True:
+        while position < len(text) and text[position] in " \t\r\n,":
+            position += 1
+        if position >= len(text):
+            raise json.JSONDecodeError("Unterminated array", text, position)
+        if text[position] == "]":
+            break
+        line = text.count("\n", 0, position) + 1
+        value, position = decoder.raw_decode(text, position)
+        entries.append((line, value))
+    return entries
+
+
+def _json_lines_entries(text: str) -> list[tuple[int, Any]]:
+    entries: list[tuple[int, Any]] = []
+    offset = 0
+    for line, raw in enumerate(text.splitlines(keepends=True), start=1):
+        if raw.strip() and not raw.lstrip().startswith("#"):
+            try:
+                entries.append((line, json.loads(raw.rstrip("\r\n"))))
+            except json.JSONDecodeError as exc:
+                # Re-anchor the position so the reported line is the manifest's, not the fragment's.
+                raise json.JSONDecodeError(exc.msg, text, offset + exc.pos) from None
+        offset += len(raw)
+    return entries
+
+
+def _csv_cell(value: str) -> Any:
+    """CSV cells are strings; let numbers and booleans through as JSON scalars."""
+    text = value.strip()
+    try:
+        parsed = json.loads(text)
+    except ValueError:
+        return text
+    return parsed if isinstance(parsed, (int, float, bool, list)) else text
+
+
+def _csv_entries(text: str) -> list[tuple[int, Any]]:
+    reader = csv.DictReader(io.StringIO(text))
+    entries: list[tuple[int, Any]] = []
+    for row in reader:
+        params = {
+            key.strip(): _csv_cell(value)
+            for key, value in row.items()
+            if key and key.strip() not in _CSV_PATH_COLUMNS and value is not None and value.strip()
+        }
+        entry = {key.strip(): (value or "").strip() for key, value in row.items() if key and key.strip() in _CSV_PATH_COLUMNS}
+        entries.append((reader.line_num, {**entry, "params": params}))
+    return entries
+
+
+def _resolve_entry_path(value: str, base_dir: Path) -> str:
+    path = Path(value).expanduser()
+    return str(path if path.is_absolute() else (base_dir / path).resolve())
+
+
+def _payload_for(line: int, raw: Any, base_dir: Path) -> tuple[dict[str, Any] | None, str]:
+    if not isinstance(raw, dict):
+        return None, f"line {line}: entry must be an object"
+    operation = str(raw.get("type") or raw.get("operation") or "").strip().lower()
+    if operation not in MANIFEST_OPERATIONS:
+        expected = ", ".join(MANIFEST_OPERATIONS)
+        return None, f"line {line}: unknown operation {operation or '(missing)'!r} (expected one of {expected})"
+    params = raw.get("params", {})
+    if not isinstance(params, dict):
+        return None, f"line {line}: params must be an object"
+    input_value = str(raw.get("input") or raw.get("input_path") or "").strip()
+    output_value = str(raw.get("output") or raw.get("output_path") or "").strip()
+    if not input_value:
+        return None, f"line {line}: input is required"
+    if not output_value:
+        return None, f"line {line}: output is required"
+
+    payload = {
+        **params,
+        "input_path": _resolve_entry_path(input_value, base_dir),
+        "output_path": _resolve_entry_path(output_value, base_dir),
+    }
+    if operation == "convert" and not payload.get("format"):
+        payload["format"] = Path(output_value).suffix.lower().lstrip(".")
+    return {"line": line, "operation": operation, "payload": payload}, ""
+
+
+def load_manifest(manifest_path: str) -> tuple[list[dict[str, Any]], list[str]]:
+    """Parse a JSON array, JSON Lines or CSV manifest into `{line, operation, payload}` entries.
+
+    Relative input/output paths resolve against the manifest's folder. Every schema problem is
+    reported as `line N: ...` so nothing runs until the whole manifest is valid.
+    """
+    source = Path(manifest_path)
+    suffix = source.suffix.lower()
+    if suffix not in MANIFEST_EXTENSIONS:
+        return [], [f"[UNSUPPORTED_FORMAT] Manifest must be one of {', '.join(MANIFEST_EXTENSIONS)}"]
+    try:
+        if source.stat().st_size > MAX_MANIFEST_BYTES:
+            return [], [f"[BAD_INPUT] Manifest is larger than {MAX_MANIFEST_BYTES // (1024 * 1024)} MB"]
+        text = source.read_text(encoding="utf-8-sig")
+    except FileNotFoundError:
+        return [], [f"[NOT_FOUND] Manifest not found: {source}"]
+    except (OSError, UnicodeDecodeError) as exc:
+        return [], [f"[BAD_INPUT] Cannot read manifest: {exc}"]
+
+    try:
+        if suffix == ".csv":
+            raw_entries = _csv_entries(text)
+        elif suffix == ".json" and text.lstrip().startswith("["):
+            raw_entries = _json_array_entries(text)
+        elif suffix == ".json":
+            return [], ["[BAD_INPUT] line 1: JSON manifest must be an array of operations"]
+        else:
+            raw_entries = _json_lines_entries(text)
+    except json.JSONDecodeError as exc:
+        return [], [f"[BAD_INPUT] line {exc.lineno}: invalid JSON ({exc.msg})"]
+    except csv.Error as exc:
+        return [], [f"[BAD_INPUT] Invalid CSV manifest: {exc}"]
+
+    if not raw_entries:
+        return [], ["[BAD_INPUT] Manifest contains no operations"]
+    if len(raw_entries) > MAX_MANIFEST_ENTRIES:
+        return [], [f"[BAD_INPUT] Manifest has more than {MAX_MANIFEST_ENTRIES} operations"]
+
+    base_dir = source.resolve().parent
+    entries: list[dict[str, Any]] = []
+    errors: list[str] = []
+    for line, raw in raw_entries:
+        entry, error = _payload_for(line, raw, base_dir)
+        if error:
+            errors.append(f"[BAD_INPUT] {error}")
+        else:
+            entries.append(entry)
+    return entries, errors
+
+
+def manifest_groups(entries: list[dict[str, Any]]) -> list[tuple[str, list[int]]]:
+    """Entry indexes per consecutive run of one operation, each run one pooled batch.
+
+    Only neighbours are merged: a later entry may read an earlier one's output, so runs keep
+    manifest order (compress, convert, compress is three batches, not two).
+    """
+    groups: list[tuple[str, list[int]]] = []
+    for index, entry in enumerate(entries):
+        if groups and groups[-1][0] == entry["operation"]:
+            groups[-1][1].append(index)
+        else:
+            groups.append((entry["operation"], [index]))
+    return groups
+
@@ -0,0 +1,115 @@
+from __future__ import annotations
+
+import re
+from datetime import datetime
+from pathlib import Path
+from typing import Any
+
+from backend.domain.paths import sanitize_filename
+
+# Per-file metadata tokens. `{date}`/`{time}` stay the batch start time the UI already
+# expands, so capture time has its own `{taken:FORMAT}` token.
+METADATA_TOKEN_RE = re.compile(r"\{(exif:[^{}]+|taken(?::[^{}]+)?|camera|make|lens|iso)\}", re.IGNORECASE)
+TOKEN_ALIASES = {
+    "camera": "Model",
+    "make": "Make",
+    "lens": "LensModel",
+    "iso": "ISOSpeedRatings",
+}
+CAPTURE_DATE_TAGS = ("DateTimeOriginal", "DateTimeDigitized", "DateTime")
+DEFAULT_TAKEN_FORMAT = "YYYYMMDD"
+MAX_TOKEN_VALUE_LENGTH = 64
+# An expanded value must never be read as another token by the UI pass.
+_TOKEN_BRACES = re.compile(r"[{}]+")
+_EXIF_DATE_FORMATS = ("%Y:%m:%d %H:%M:%S", "%Y-%m-%d %H:%M:%S", "%Y:%m:%d", "%Y-%m-%dT%H:%M:%S")
+
+
+def has_metadata_tokens(template: str) -> bool:
+    return bool(METADATA_TOKEN_RE.search(str(template or "")))
+
+
+def sanitize_token_value(value: Any) -> str:
+    """Make a metadata value safe as part of a file name on every platform."""
+    text = re.sub(r"\s+", " ", _TOKEN_BRACES.sub("_", str(value or "")))
+    return sanitize_filename(text[:MAX_TOKEN_VALUE_LENGTH], windows=True)
+
+
+def exif_tag_value(info: dict, tag: str) -> str:
+    """Look up `tag` in an info_viewer result, matching names like `Image Model` or `0th:Model`."""
+    wanted = str(tag or "").strip().casefold()
+    metadata = info.get("metadata") if isinstance(info.get("metadata"), dict) else {}
+    sources = [metadata.get("exifread"), metadata.get("piexif"), info.get("exif")]
+    for source in sources:
+        if not isinstance(source, dict):
+            continue
+        for key, value in source.items():
+            name = re.split(r"[ :]", str(key))[-1].casefold()
+            if (name == wanted or str(key).casefold() == wanted) and str(value or "").strip():
+                return str(value).strip()
+    return ""
+
+
+def capture_datetime(info: dict) -> datetime | None:
+    for tag in CAPTURE_DATE_TAGS:
+        raw = exif_tag_value(info, tag)
+        for pattern in _EXIF_DATE_FORMATS:
+            try:
+                return datetime.strptime(raw[:19], pattern)
+            except ValueError:
+                continue
+    return None
+
+
+def format_date_pattern(moment: datetime, pattern: str) -> str:
+    """Same YYYY/MM/DD/HH/mm/ss placeholders the UI uses for `{date}`."""
+    return (
+        pattern.replace("YYYY", f"{moment.year:04d}")
+        .replace("MM", f"{moment.month:02d}")
+        .replace("DD", f"{moment.day:02d}")
+        .replace("HH", f"{moment.hour:02d}")
+        .replace("mm", f"{moment.minute:02d}")
+        .replace("ss", f"{moment.second:02d}")
+    )
+
+
+def expand_metadata_tokens(template: str, info: dict | None, file_name: str = "") -> tuple[str, list[str]]:
+    """Replace metadata tokens for one file; missing values become "" and add a warning.
+
+    Other tokens (`{basename}`, `{seq}` ...) are left for the UI's own expander.
+    """
+    warnings: list[str] = []
+    details = info if isinstance(info, dict) else {}
+
+    def replace(match: re.Match) -> str:
+        token = match.group(1)
+        key, _, argument = token.partition(":")
+        key = key.lower()
+        if key == "taken":
+            moment = capture_datetime(details)
+            value = format_date_pattern(moment, argument or DEFAULT_TAKEN_FORMAT) if moment else ""
+        else:
+            value = exif_tag_value(details, argument if key == "exif" else TOKEN_ALIASES[key])
+        cleaned = sanitize_token_value(value)
+        if not cleaned:
+            warnings.append(f"{file_name or '文件'} 没有 {{{token}}} 对应的元数据，已留空")
+        return cleaned
+
+    return METADATA_TOKEN_RE.sub(replace, str(template or "")), warnings
+
+
+def expand_for_results(template: str, input_paths: list[str], infos: list[Any]) -> dict[str, Any]:
+    """Pair each input with its info_viewer result and expand the template once per file."""
+    templates: list[str] = []
+    warnings: list[list[str]] = []
+    for input_path, info in zip(input_paths, infos):
+        name = Path(input_path).name
+        if isinstance(info, dict) and info.get("success") is False:
+            # Unreadable metadata still yields a usable name; one warning beats one per token.
+            expanded, _missing = expand_metadata_tokens(template, {}, name)
+            templates.append(expanded)
+            warnings.append([f"无法读取 {name} 的元数据，模板标记已留空: {info.get('error') or '未知错误'}"])
+            continue
+        expanded, item_warnings = expand_metadata_tokens(template, info, name)
+        templates.append(expanded)
+        warnings.append(item_warnings)
+    return {"success": True, "templates": templates, "warnings": warnings}
@@ -8,6 +8,7 @@ from pathlib import Path
 from queue import Empty
 from typing import Any
 
+from backend.contracts.settings import PREVIEW_MODES
 from backend.infrastructure.engine_loader import load_engine_module
 
 DEFAULT_PREVIEW_MAX_BYTES = 4 * 1024 * 1024
@@ -16,9 +17,13 @@ PREVIEW_JPEG_QUALITY = 80
 PREVIEW_PROCESS_TIMEOUT_SECONDS = 20.0
 PREVIEW_CACHE_MAX_ENTRIES = 64
 _ISOLATE_EXTENSIONS = {".svg"}
+PREVIEW_MODE_AUTO = "auto"
+PREVIEW_MODE_FAST = "always-fast"
+PREVIEW_MODE_FULL = "always-full"
+PREVIEW_MODE_NEVER = "never"
 
 _preview_cache_lock = threading.Lock()
-_preview_cache: dict[tuple[str, int, int], tuple[float, dict[str, Any]]] = {}
+_preview_cache: dict[tuple[str, int, int, bool], tuple[float, dict[str, Any]]] = {}
 
 
 def _resolve_preview_max_bytes() -> int:
@@ -30,15 +35,25 @@ def _resolve_preview_max_bytes() -> int:
     return parsed if parsed > 0 else DEFAULT_PREVIEW_MAX_BYTES
 
 
-def _cache_key(source: Path) -> tuple[str, int, int] | None:
+def _normalize_preview_mode(mode: Any) -> str:
+    normalized = str(mode or "").strip().lower()
+    return normalized if normalized in PREVIEW_MODES else PREVIEW_MODE_AUTO
+
+
+def _cache_key(source: Path, full: bool = False) -> tuple[str, int, int, bool] | None:
     try:
         stat = source.stat()
     except OSError:
         return None
-    return (str(source), int(getattr(stat, "st_mtime_ns", int(stat.st_mtime * 1_000_000_000))), int(stat.st_size))
+    return (
+        str(source),
+        int(getattr(stat, "st_mtime_ns", int(stat.st_mtime * 1_000_000_000))),
+        int(stat.st_size),
+        bool(full),
+    )
 
 
-def _cache_get(key: tuple[str, int, int]) -> dict[str, Any] | None:
+def _cache_get(key: tuple[str, int, int, bool]) -> dict[str, Any] | None:
     with _preview_cache_lock:
         item = _preview_cache.get(key)
         if item is None:
@@ -47,7 +62,7 @@ def _cache_get(key: tuple[str, int, int]) -> dict[str, Any] | None:
         return dict(item[1])
 
 
-def _cache_put(key: tuple[str, int, int], value: dict[str, Any]) -> None:
+def _cache_put(key: tuple[str, int, int, bool], value: dict[str, Any]) -> None:
     if not value.get("success"):
         return
     with _preview_cache_lock:
@@ -66,17 +81,22 @@ def _should_isolate(path: Path) -> bool:
     return path.suffix.lower() in _ISOLATE_EXTENSIONS
 
 
-def build_image_preview(input_path: str) -> dict[str, Any]:
-    from PIL import Image
+def build_image_preview(input_path: str, mode: str = PREVIEW_MODE_AUTO) -> dict[str, Any]:
+    """Encode a JPEG data URL preview.
 
-    # Preview path should never accept decompression bombs even if caller raised the global limit.
-    Image.MAX_IMAGE_PIXELS = min(int(getattr(Image, "MAX_IMAGE_PIXELS", 0) or 64_000_000), 32_000_000)
+    `auto` downscales files within the byte budget, `always-fast` downscales regardless
+    of size, `always-full` keeps full resolution within the budget, and `never` skips.
+    """
+    mode = _normalize_preview_mode(mode)
+    if mode == PREVIEW_MODE_NEVER:
+        return {"success": False, "error": "PREVIEW_SKIPPED"}
+    full = mode == PREVIEW_MODE_FULL
 
     source = Path(input_path)
     if not source.exists():
         return {"success": False, "error": "文件不存在"}
 
-    cache_key = _cache_key(source)
+    cache_key = _cache_key(source, full)
     if cache_key is not None:
         cached = _cache_get(cache_key)
         if cached is not None:
@@ -87,12 +107,21 @@ def build_image_preview(input_path: str) -> dict[str, Any]:
         file_size = source.stat().st_size
     except OSError:
         return {"success": False, "error": "文件不存在"}
-    if file_size > max_bytes:
+    # The budget is decided from stat alone; nothing reads the source before this point, and
+    # past it the decoder streams from the path (header first, then pixels) rather than
+    # taking the file into memory, so an oversized `always-fast` source is never read whole.
+    if file_size > max_bytes and mode != PREVIEW_MODE_FAST:
         return {"success": False, "error": "PREVIEW_SKIPPED"}
 
+    from PIL import Image
+
+    # Preview path should never accept decompression bombs even if caller raised the global limit.
+    Image.MAX_IMAGE_PIXELS = min(int(getattr(Image, "MAX_IMAGE_PIXELS", 0) or 64_000_000), 32_000_000)
+
     converter = load_engine_module("converter")
     open_image = getattr(converter, "open_image_with_svg_support")
     is_svg_path = getattr(converter, "is_svg_path", None)
+    convert_cmyk = getattr(converter, "convert_cmyk_to_srgb", None)
 
     image: Image.Image | None = None
     raw: Image.Image | None = None
@@ -102,12 +131,19 @@ def build_image_preview(input_path: str) -> dict[str, Any]:
         else:
             raw = Image.open(str(source))
             # Prefer decoder draft when available (JPEG) to reduce decode cost before thumbnail.
-            try:
-                raw.draft("RGB", (PREVIEW_MAX_EDGE, PREVIEW_MAX_EDGE))
-            except Exception:
-                pass
-
-        if raw.mode not in ("RGB", "L"):
+            if not full:
+                try:
+                    raw.draft("RGB", (PREVIEW_MAX_EDGE, PREVIEW_MAX_EDGE))
+                except Exception:
+                    pass
+
+        was_cmyk = raw.mode == "CMYK"
+        if was_cmyk and callable(convert_cmyk):
+            # Webviews render raw CMYK JPEGs inverted; always hand back an sRGB transcode.
+            image = convert_cmyk(raw)
+            raw.close()
+            raw = None
+        elif raw.mode not in ("RGB", "L"):
             image = raw.convert("RGB")
             raw.close()
             raw = None
@@ -115,11 +151,12 @@ def build_image_preview(input_path: str) -> dict[str, Any]:
             image = raw
             raw = None
 
-        image.thumbnail((PREVIEW_MAX_EDGE, PREVIEW_MAX_EDGE), Image.Resampling.BILINEAR)
+        if not full:
+            image.thumbnail((PREVIEW_MAX_EDGE, PREVIEW_MAX_EDGE), Image.Resampling.BILINEAR)
         buffer = io.BytesIO()
         image.save(buffer, format="JPEG", quality=PREVIEW_JPEG_QUALITY, optimize=False)
         encoded = base64.b64encode(buffer.getvalue()).decode("ascii")
-        result = {"success": True, "data_url": f"data:image/jpeg;base64,{encoded}"}
+        result = {"success": True, "data_url": f"data:image/jpeg;base64,{encoded}", "was_cmyk": was_cmyk}
         if cache_key is not None:
             _cache_put(cache_key, result)
         return result
@@ -138,17 +175,21 @@ def build_image_preview(input_path: str) -> dict[str, Any]:
                 pass
 
 
-def _preview_worker(input_path: str, queue: Queue) -> None:
+def _preview_worker(input_path: str, mode: str, queue: Queue) -> None:
     try:
-        queue.put(build_image_preview(input_path))
+        queue.put(build_image_preview(input_path, mode))
     except Exception as exc:
         queue.put({"success": False, "error": str(exc)})
 
 
-def build_image_preview_isolated(input_path: str, timeout: float = PREVIEW_PROCESS_TIMEOUT_SECONDS) -> dict[str, Any]:
+def build_image_preview_isolated(
+    input_path: str,
+    timeout: float = PREVIEW_PROCESS_TIMEOUT_SECONDS,
+    mode: str = PREVIEW_MODE_AUTO,
+) -> dict[str, Any]:
     """Generate previews in a child process so SVG/decode bombs cannot freeze the host."""
     queue: Queue = Queue()
-    process = Process(target=_preview_worker, args=(str(input_path), queue))
+    process = Process(target=_preview_worker, args=(str(input_path), _normalize_preview_mode(mode), queue))
     try:
         process.start()
         process.join(timeout)
@@ -176,9 +217,12 @@ def build_image_preview_isolated(input_path: str, timeout: float = PREVIEW_PROCE
             join_thread()
 
 
-def build_image_preview_smart(input_path: str) -> dict[str, Any]:
+def build_image_preview_smart(input_path: str, mode: str = PREVIEW_MODE_AUTO) -> dict[str, Any]:
     """Prefer in-process preview for ordinary bitmaps; isolate only risky inputs."""
+    mode = _normalize_preview_mode(mode)
+    if mode == PREVIEW_MODE_NEVER:
+        return {"success": False, "error": "PREVIEW_SKIPPED"}
     source = Path(str(input_path or ""))
     if _should_isolate(source):
-        return build_image_preview_isolated(str(source))
-    return build_image_preview(str(source))
+        return build_image_preview_isolated(str(source), mode=mode)
+    return build_image_preview(str(source), mode)
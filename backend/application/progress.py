@@ -0,0 +1,60 @@
+from __future__ import annotations
+
+import threading
+from typing import Any, Callable
+
+BATCH_PROGRESS_EVENT = "__imageflow_batch_progress__"
+
+
+class BatchProgress:
+    """Counts finished items of one engine batch and fires a notice for each of them.
+
+    Notices are per file, in completion order (not submission order); the last one has
+    `done: True` so the UI can settle its bar even when the batch was cancelled early.
+    """
+
+    def __init__(
+        self,
+        operation: str,
+        total: int,
+        task_id: int | None,
+        notify: Callable[[dict[str, Any]], None],
+    ):
+        self._operation = str(operation or "")
+        self._total = max(0, int(total))
+        self._task_id = task_id
+        self._notify = notify
+        self._lock = threading.Lock()
+        self._current = 0
+
+    def _detail(self, result: Any, done: bool) -> dict[str, Any]:
+        item = result if isinstance(result, dict) else {}
+        return {
+            "type": "batch_progress",
+            "operation": self._operation,
+            "task_id": self._task_id,
+            "current": self._current,
+            "total": self._total,
+            "percentage": round(self._current * 100.0 / self._total, 1) if self._total else 100.0,
+            "input_path": str(item.get("input_path") or ""),
+            "output_path": str(item.get("output_path") or ""),
+            "success": bool(item.get("success")),
+            "done": done,
+        }
+
+    def _fire(self, detail: dict[str, Any]) -> None:
+        try:
+            self._notify(detail)
+        except Exception:
+            pass
+
+    def report(self, _index: int, result: Any) -> None:
+        with self._lock:
+            self._current = min(self._total, self._current + 1)
+            detail = self._detail(result, False)
+        self._fire(detail)
+
+    def finish(self) -> None:
+        with self._lock:
+            detail = self._detail(None, True)
+        self._fire(detail)
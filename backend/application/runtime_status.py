@@ -0,0 +1,208 @@
+from __future__ import annotations
+
+import logging
+import threading
+from typing import Any, Callable
+
+RUNTIME_PROGRESS_EVENT = "__imageflow_runtime_progress__"
+RUNTIME_STATES = ("idle", "preparing", "ready", "failed", "incomplete")
+# Listed in the status so the UI can show what is broken without flooding the screen.
+MAX_REPORTED_FILES = 20
+
+logger = logging.getLogger(__name__)
+
+Step = tuple[str, Callable[[], Any]]
+
+
+class RuntimeIncomplete(Exception):
+    """Raised by the file check when shipped runtime files are missing or cut short."""
+
+    def __init__(self, files: list[str]):
+        self.files = list(files)
+        super().__init__(
+            f"[RUNTIME_INCOMPLETE] 运行环境文件不完整（{len(self.files)} 个缺失或大小不符），"
+            "请重新解压便携版或重新运行安装程序"
+        )
+
+
+def _verify_runtime_files() -> None:
+    from backend.infrastructure.runtime_files import verify_runtime_files
+
+    problems = verify_runtime_files()
+    if problems:
+        raise RuntimeIncomplete(problems)
+
+
+def _check_engine_script(name: str) -> None:
+    from backend.infrastructure.engine_loader import ensure_engine_scripts_path
+
+    script = ensure_engine_scripts_path() / f"{name}.py"
+    if not script.is_file():
+        raise FileNotFoundError(f"[NOT_FOUND] Engine script missing: {script}")
+
+
+def _warm_pool() -> None:
+    from backend.application.image_ops import warm_process_pool
+
+    warm_process_pool()
+
+
+def _build_capabilities() -> None:
+    from backend.application.capabilities import capabilities
+
+    capabilities()
+
+
+def _load_converter() -> None:
+    from backend.infrastructure.engine_loader import load_engine_module
+
+    load_engine_module("converter")
+
+
+def default_runtime_steps() -> list[Step]:
+    """Check the release manifest and every engine script, cache the capability report, then start the
+    worker pool and import the converter.
+
+    Safe mode leaves out the manifest check and the pool, the two steps a broken packaged
+    runtime fails in.
+    """
+    from backend.application.startup_status import startup_status
+    from backend.infrastructure.engine_loader import ALLOWED_ENGINES, TEMP_REGISTRY_MODULE
+
+    safe_mode = startup_status().safe_mode
+    names = [TEMP_REGISTRY_MODULE, *sorted(ALLOWED_ENGINES)]
+    steps: list[Step] = [] if safe_mode else [("runtime_files", _verify_runtime_files)]
+    steps.extend((f"engine:{name}", lambda name=name: _check_engine_script(name)) for name in names)
+    steps.append(("capabilities", _build_capabilities))
+    if not safe_mode:
+        steps.append(("process_pool", _warm_pool))
+    steps.append(("converter", _load_converter))
+    return steps
+
+
+class RuntimePreparation:
+    """One-time warm-up the host runs off the UI thread at launch.
+
+    The UI polls status() on startup and follows progress events after that, so a slow
+    first launch shows a setup screen and a broken install shows the failing step instead
+    of the first conversion erroring out.
+    """
+
+    def __init__(self, steps: list[Step] | None = None):
+        self._steps = steps
+        self._lock = threading.Lock()
+        self._state = "idle"
+        self._current = 0
+        self._total = len(steps) if steps is not None else 0
+        self._step = ""
+        self._error = ""
+        self._files: list[str] = []
+
+    def status(self) -> dict[str, Any]:
+        with self._lock:
+            return self._snapshot()
+
+    def phases(self) -> list[dict[str, str]]:
+        """Each step of the current (or last) run as {name, state, error}; empty before the first run."""
+        with self._lock:
+            names = [name for name, _action in self._steps or []]
+            failed = self._state in ("failed", "incomplete")
+            phases = []
+            for index, name in enumerate(names):
+                if index < self._current or self._state == "ready":
+                    phases.append({"name": name, "state": "ok", "error": ""})
+                elif index == self._current and failed:
+                    phases.append({"name": name, "state": "failed", "error": self._error})
+                elif index == self._current and self._state == "preparing":
+                    phases.append({"name": name, "state": "running", "error": ""})
+                else:
+                    phases.append({"name": name, "state": "skipped" if failed else "pending", "error": ""})
+            return phases
+
+    def run(self, notify: Callable[[dict[str, Any]], None] | None = None) -> dict[str, Any]:
+        """Run every step in order and stop at the first failure; a failed run may be retried."""
+        with self._lock:
+            if self._state in ("preparing", "ready"):
+                return self._snapshot()
+            if self._steps is None:
+                self._steps = default_runtime_steps()
+            steps = list(self._steps)
+            self._state = "preparing"
+            self._current = 0
+            self._total = len(steps)
+            self._step = ""
+            self._error = ""
+            self._files = []
+        self._emit(notify)
+        for name, action in steps:
+            with self._lock:
+                self._step = name
+            try:
+                action()
+            except RuntimeIncomplete as exc:
+                logger.error("Runtime files incomplete: %s", ", ".join(exc.files[:MAX_REPORTED_FILES]))
+                with self._lock:
+                    self._state = "incomplete"
+                    self._error = str(exc)
+                    self._files = exc.files
+                self._emit(notify)
+                return self.status()
+            except Exception as exc:
+                logger.error("Runtime preparation failed at %s: %s", name, exc)
+                with self._lock:
+                    self._state = "failed"
+                    self._error = str(exc) or exc.__class__.__name__
+                self._emit(notify)
+                return self.status()
+            with self._lock:
+                self._current += 1
+            self._emit(notify)
+        with self._lock:
+            self._state = "ready"
+            self._step = ""
+        self._emit(notify)
+        return self.status()
+
+    def repair(self, notify: Callable[[dict[str, Any]], None] | None = None) -> dict[str, Any]:
+        """Check and warm up again, e.g. after the user re-extracted or reinstalled the app.
+
+        A packaged build cannot restore its own files, so this only re-runs the steps; it
+        is a no-op while a run is already in progress.
+        """
+        with self._lock:
+            if self._state == "preparing":
+                return self._snapshot()
+            self._state = "idle"
+        return self.run(notify)
+
+    def start(self, notify: Callable[[dict[str, Any]], None] | None = None) -> threading.Thread:
+        thread = threading.Thread(target=self.run, args=(notify,), name="imageflow-warmup", daemon=True)
+        thread.start()
+        return thread
+
+    def _snapshot(self) -> dict[str, Any]:
+        return {
+            "type": "runtime_progress",
+            "state": self._state,
+            "current": self._current,
+            "total": self._total,
+            "step": self._step,
+            "error": self._error,
+            "files": self._files[:MAX_REPORTED_FILES],
+            "file_count": len(self._files),
+        }
+
+    def _emit(self, notify: Callable[[dict[str, Any]], None] | None) -> None:
+        if notify is None:
+            return
+        try:
+            notify(self.status())
+        except Exception:
+            pass
+
+
+_PREPARATION = RuntimePreparation()
+
+
+def runtime_preparation() -> RuntimePreparation:
+    return _PREPARATION
@@ -0,0 +1,140 @@
+import io
+from collections import OrderedDict
+from pathlib import Path
+from threading import Lock
+from typing import Any
+
+from backend.infrastructure.engine_loader import load_engine_module
+
+DEFAULT_CURVE_QUALITIES = (60, 70, 80, 90, 95)
+MAX_CURVE_POINTS = 12
+# Encoding happens on this proxy; bytes are extrapolated back by pixel count.
+SIZE_CURVE_PROXY_EDGE = 768
+# SSIM (the compressor's `ssim_score`) compares an even smaller grayscale copy; a curve needs no more.
+SSIM_EDGE = 256
+SIZE_CURVE_CACHE_MAX_ENTRIES = 32
+
+_curve_cache: "OrderedDict[tuple, dict[str, Any]]" = OrderedDict()
+_curve_cache_lock = Lock()
+
+
+def normalize_curve_qualities(values: Any) -> list[int]:
+    if not isinstance(values, (list, tuple)) or not values:
+        return list(DEFAULT_CURVE_QUALITIES)
+    qualities: set[int] = set()
+    for value in values:
+        if isinstance(value, bool):
+            continue
+        try:
+            qualities.add(max(1, min(100, int(value))))
+        except (TypeError, ValueError):
+            continue
+    return sorted(qualities)[:MAX_CURVE_POINTS] or list(DEFAULT_CURVE_QUALITIES)
+
+
+def _cache_key(source: Path, qualities: list[int]) -> tuple | None:
+    try:
+        stat = source.stat()
+    except OSError:
+        return None
+    return (str(source.resolve()), stat.st_mtime_ns, stat.st_size, tuple(qualities))
+
+
+def _load_proxy(source: Path):
+    """Decode `source` once into an RGB proxy plus the grayscale SSIM reference."""
+    from PIL import Image
+
+    converter = load_engine_module("converter")
+    open_image = getattr(converter, "open_image_with_svg_support")
+    convert_cmyk = getattr(converter, "convert_cmyk_to_srgb", None)
+
+    with open_image(str(source), format_type="jpg") as raw:
+        size = raw.size
+        source_format = str(raw.format or "").upper()
+        if raw.mode == "CMYK" and callable(convert_cmyk):
+            proxy = convert_cmyk(raw)
+        else:
+            proxy = raw.convert("RGB")
+    proxy.thumbnail((SIZE_CURVE_PROXY_EDGE, SIZE_CURVE_PROXY_EDGE), Image.Resampling.BILINEAR)
+    reference = proxy.convert("L")
+    reference.thumbnail((SSIM_EDGE, SSIM_EDGE), Image.Resampling.BILINEAR)
+    return proxy, reference, size, source_format
+
+
+def _encode_and_compare(proxy, reference, pillow_format: str, quality: int) -> tuple[int, float]:
+    """Encode the proxy at `quality`; returns (encoded proxy bytes, SSIM against the reference)."""
+    from PIL import Image
+
+    buffer = io.BytesIO()
+    proxy.save(buffer, format=pillow_format, quality=quality, optimize=True)
+    encoded_bytes = buffer.tell()
+    buffer.seek(0)
+    with Image.open(buffer) as decoded, decoded.convert("L") as gray:
+        candidate = gray.resize(reference.size, Image.Resampling.BILINEAR)
+    try:
+        return encoded_bytes, load_engine_module("compressor").ssim_score(reference, candidate)
+    finally:
+        candidate.close()
+
+
+def _validate_source(input_path: str) -> tuple[Path, dict[str, Any] | None]:
+    source = Path(str(input_path or ""))
+    if not str(input_path or "").strip():
+        return source, {"success": False, "error": "[BAD_INPUT] input_path is required"}
+    if not source.exists():
+        return source, {"success": False, "error": f"[NOT_FOUND] {source}"}
+    return source, None
+
+
+def build_jpeg_size_curve(input_path: str, qualities: Any = None) -> dict[str, Any]:
+    """Estimate JPEG size and SSIM at several qualities from a downscaled proxy.
+
+    Byte counts are proxy sizes scaled by the pixel ratio, so they are estimates;
+    results are cached per file path+mtime+size and quality set.
+    """
+    source, error = _validate_source(input_path)
+    if error is not None:
+        return error
+
+    levels = normalize_curve_qualities(qualities)
+    key = _cache_key(source, levels)
+    if key is not None:
+        with _curve_cache_lock:
+            cached = _curve_cache.get(key)
+            if cached is not None:
+                _curve_cache.move_to_end(key)
+                return cached
+
+    proxy, reference, (width, height), _source_format = _load_proxy(source)
+    try:
+        pixel_ratio = (width * height) / max(1, proxy.width * proxy.height)
+        points: list[dict[str, Any]] = []
+        for quality in levels:
+            encoded_bytes, score = _encode_and_compare(proxy, reference, "JPEG", quality)
+            points.append(
+                {
+                    "quality": quality,
+                    "estimated_bytes": int(round(encoded_bytes * pixel_ratio)),
+                    "ssim": round(score, 4),
+                }
+            )
+        result = {
+            "success": True,
+            "input_path": str(source),
+            "width": width,
+            "height": height,
+            "proxy_width": proxy.width,
+            "proxy_height": proxy.height,
+            "points": points,
+        }
+    finally:
+        reference.close()
+        proxy.close()
+
+    if key is not None:
+        with _curve_cache_lock:
+            _curve_cache[key] = result
+            _curve_cache.move_to_end(key)
+            while len(_curve_cache) > SIZE_CURVE_CACHE_MAX_ENTRIES:
+                _curve_cache.popitem(last=False)
+    return result
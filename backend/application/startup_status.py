@@ -0,0 +1,127 @@
+from __future__ import annotations
+
+import logging
+import os
+import threading
+from contextlib import contextmanager
+from typing import Any, Iterator
+
+# `1`/`true`/`yes`/`on` launch in safe mode regardless of the saved `safe_mode` setting.
+SAFE_MODE_ENV = "IMAGEFLOW_SAFE_MODE"
+_TRUTHY = {"1", "true", "yes", "on"}
+
+logger = logging.getLogger(__name__)
+
+
+def safe_mode_requested(settings: Any | None = None) -> str:
+    """What asked for safe mode ("env" or "settings"), or "" for a normal launch."""
+    if str(os.getenv(SAFE_MODE_ENV, "") or "").strip().lower() in _TRUTHY:
+        return "env"
+    if settings is not None and getattr(settings, "safe_mode", False) is True:
+        return "settings"
+    return ""
+
+
+class StartupStatus:
+    """What happened while the host started: the first failed step and every safe-mode decision.
+
+    Launch steps that fail are recorded instead of being skipped silently, so the UI can ask
+    GetStartupError why something is missing once the window is up.
+    """
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self._safe_mode = False
+        self._decisions: list[str] = []
+        self._step = ""
+        self._error = ""
+        self._phases: list[dict[str, str]] = []
+
+    @property
+    def safe_mode(self) -> bool:
+        with self._lock:
+            return self._safe_mode
+
+    def fail(self, step: str, exc: BaseException) -> None:
+        message = str(exc) or exc.__class__.__name__
+        logger.error("Startup step %s failed: %s", step, message)
+        with self._lock:
+            self._phases.append({"name": step, "state": "failed", "error": message})
+            if not self._error:
+                self._step = step
+                self._error = message
+
+    @contextmanager
+    def step(self, name: str) -> Iterator[None]:
+        """Run a launch step, recording (not raising) its failure."""
+        try:
+            yield
+        except Exception as exc:
+            self.fail(name, exc)
+        else:
+            with self._lock:
+                self._phases.append({"name": name, "state": "ok", "error": ""})
+
+    def phases(self) -> list[dict[str, str]]:
+        """Every launch step run so far, in order, as {name, state ("ok"/"failed"), error}."""
+        with self._lock:
+            return [dict(phase) for phase in self._phases]
+
+    def enter_safe_mode(self, reason: str) -> None:
+        """Run engines one at a time in the host process and skip the packaged-runtime checks."""
+        from backend.application.image_ops import disable_process_pool
+
+        with self._lock:
+            self._safe_mode = True
+        self._decide(f"safe mode enabled by {reason}")
+        disable_process_pool()
+        self._decide("process pool disabled: engines run one at a time in the host process")
+        self._decide("packaged runtime file check and pool warm-up skipped")
+
+    def status(self) -> dict[str, Any]:
+        with self._lock:
+            return {
+                "ok": not self._error,
+                "step": self._step,
+                "error": self._error,
+                "safe_mode": self._safe_mode,
+                "decisions": list(self._decisions),
+            }
+
+    def _decide(self, message: str) -> None:
+        logger.warning("Startup: %s", message)
+        with self._lock:
+            self._decisions.append(message)
+
+
+def startup_diagnostics() -> dict[str, Any]:
+    """Launch steps and runtime preparation steps as one list, with the first failure on top.
+
+    A launch step (settings, operation log, ...) that failed is reported before a runtime step
+    (engine scripts, worker warm-up, ...), since the later steps often fail because of it.
+    Runtime steps not reached yet are "pending" (the current one "running"), or "skipped" once
+    an earlier one failed.
+    """
+    from backend.application.runtime_status import runtime_preparation
+
+    status = startup_status()
+    launch = [{**phase, "source": "launch"} for phase in status.phases()]
+    preparation = runtime_preparation()
+    runtime = [{**phase, "source": "runtime"} for phase in preparation.phases()]
+    failed = next((phase for phase in (*launch, *runtime) if phase["state"] == "failed"), None)
+    return {
+        "ok": failed is None,
+        "phase": failed["name"] if failed else "",
+        "error": failed["error"] if failed else "",
+        "safe_mode": status.safe_mode,
+        "runtime_state": preparation.status()["state"],
+        "phases": [*launch, *runtime],
+        "decisions": status.status()["decisions"],
+    }
+
+
+_STATUS = StartupStatus()
+
+
+def startup_status() -> StartupStatus:
+    return _STATUS
@@ -0,0 +1,102 @@
+import base64
+import io
+from typing import Any
+
+from backend.application.preview import PREVIEW_JPEG_QUALITY, PREVIEW_MAX_EDGE
+from backend.infrastructure.engine_loader import engine_temp_registry, load_engine_module
+
+# Row-major so the montage reads like the anchor picker in the UI.
+WATERMARK_GRID_POSITIONS = (
+    "top-left",
+    "top-center",
+    "top-right",
+    "center-left",
+    "center",
+    "center-right",
+    "bottom-left",
+    "bottom-center",
+    "bottom-right",
+)
+# Keep the whole 3x3 montage about the size of a single preview.
+GRID_CELL_EDGE = PREVIEW_MAX_EDGE // 3
+GRID_GAP = 4
+_PIXEL_FIELDS = ("font_size", "offset_x", "offset_y", "line_width")
+
+
+def _scale_pixel_fields(payload: dict, ratio: float) -> dict:
+    """Pixel-sized options must shrink with the proxy or text would dwarf each cell."""
+    scaled = dict(payload)
+    for name in _PIXEL_FIELDS:
+        value = scaled.get(name)
+        if value is None or isinstance(value, bool):
+            continue
+        try:
+            numeric = float(value)
+        except (TypeError, ValueError):
+            continue
+        adjusted = int(round(numeric * ratio))
+        scaled[name] = max(1, adjusted) if name == "font_size" else adjusted
+    return scaled
+
+
+def build_watermark_grid(payload: dict) -> dict[str, Any]:
+    """Render the watermark at all nine anchors on one downscaled proxy and return a JPEG montage."""
+    from PIL import Image
+
+    input_path = str(payload.get("input_path") or "").strip()
+    if not input_path:
+        return {"success": False, "error": "[BAD_INPUT] input_path is required"}
+
+    converter = load_engine_module("converter")
+    watermark = load_engine_module("watermark")
+    open_image = getattr(converter, "open_image_with_svg_support")
+    registry = engine_temp_registry()
+
+    with registry.scope(temp_dir=str(payload.get("temp_dir") or "") or None):
+        with open_image(input_path, format_type="png") as source:
+            original_width = max(1, source.width)
+            proxy = source.convert("RGBA") if source.mode not in ("RGB", "RGBA") else source.copy()
+        try:
+            proxy.thumbnail((GRID_CELL_EDGE, GRID_CELL_EDGE), Image.Resampling.BILINEAR)
+            proxy_path = registry.create(suffix=".png")
+            proxy.save(proxy_path, format="PNG")
+            cell_width, cell_height = proxy.size
+        finally:
+            proxy.close()
+
+        request = _scale_pixel_fields(payload, cell_width / original_width)
+        # Tiling ignores the anchor, so every cell would look the same.
+        request["tiled"] = False
+        request["input_path"] = proxy_path
+
+        montage = Image.new(
+            "RGB",
+            (cell_width * 3 + GRID_GAP * 2, cell_height * 3 + GRID_GAP * 2),
+            (128, 128, 128),
+        )
+        try:
+            for index, position in enumerate(WATERMARK_GRID_POSITIONS):
+                cell_path = registry.create(suffix=".png")
+                result = watermark.process({**request, "position": position, "output_path": cell_path})
+                if not isinstance(result, dict) or not result.get("success"):
+                    error = result.get("error") if isinstance(result, dict) else ""
+                    return {"success": False, "error": str(error or "水印预览失败")}
+                with Image.open(cell_path) as cell:
+                    rendered = cell.convert("RGB")
+                row, col = divmod(index, 3)
+                montage.paste(rendered, (col * (cell_width + GRID_GAP), row * (cell_height + GRID_GAP)))
+                rendered.close()
+
+            buffer = io.BytesIO()
+            montage.save(buffer, format="JPEG", quality=PREVIEW_JPEG_QUALITY, optimize=False)
+        finally:
+            montage.close()
+
+    encoded = base64.b64encode(buffer.getvalue()).decode("ascii")
+    return {
+        "success": True,
+        "data_url": f"data:image/jpeg;base64,{encoded}",
+        "positions": list(WATERMARK_GRID_POSITIONS),
+        "cell_width": cell_width,
+        "cell_height": cell_height,
+    }
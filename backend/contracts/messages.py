@@ -0,0 +1,43 @@
+from __future__ import annotations
+
+DEFAULT_LANGUAGE = "zh"
+SUPPORTED_LANGUAGES = ("zh", "en")
+
+# User-facing host messages keyed by a stable ID. Engine error codes such as
+# `[BAD_INPUT]` stay untranslated; only the prose around them lives here.
+MESSAGES: dict[str, dict[str, str]] = {
+    "service_not_ready": {"zh": "服务未就绪", "en": "Service is not ready"},
+    "operation_failed": {"zh": "处理失败", "en": "Processing failed"},
+    "batch_failed": {"zh": "批处理失败", "en": "Batch processing failed"},
+    "batch_bad_result": {"zh": "批处理返回格式异常", "en": "Batch returned an unexpected result"},
+    "result_bad_format": {"zh": "处理返回格式异常", "en": "Processing returned an unexpected result"},
+    "output_path_empty": {"zh": "输出路径为空", "en": "Output path is empty"},
+    "batch_done": {"zh": "处理完成，{count} 个文件已输出到 {folder}", "en": "Done, {count} files in {folder}"},
+    "completion_title": {"zh": "ImageFlow 处理完成", "en": "ImageFlow finished"},
+    "completion_body": {"zh": "成功 {succeeded} 个，失败 {failed} 个", "en": "{succeeded} succeeded, {failed} failed"},
+    "conflict_skipped": {"zh": "输出文件已存在，已按选择跳过 {name}", "en": "Output already exists, skipped as chosen: {name}"},
+    "conflict_renamed": {"zh": "输出文件已存在，已重命名为 {name}", "en": "Output already exists, renamed to {name}"},
+    "plan_up_to_date": {"zh": "目标文件已是最新，将跳过: {name}", "en": "Output is up to date and will be skipped: {name}"},
+    "plan_conflict_ask": {
+        "zh": "目标文件已存在，运行时将询问如何处理: {name}",
+        "en": "Output already exists; you will be asked what to do: {name}",
+    },
+    "plan_overwrite": {"zh": "目标文件已存在，将被覆盖: {name}", "en": "Output already exists and will be overwritten: {name}"},
+    "select_files": {"zh": "选择文件", "en": "Select files"},
+    "select_folder": {"zh": "选择文件夹", "en": "Select folder"},
+    "select_output_folder": {"zh": "选择输出文件夹", "en": "Select output folder"},
+}
+
+
+def normalize_language(value: str | None) -> str:
+    lang = str(value or "").strip().lower().replace("_", "-").split("-", 1)[0]
+    return lang if lang in SUPPORTED_LANGUAGES else DEFAULT_LANGUAGE
+
+
+def message(key: str, lang: str | None = None, **params) -> str:
+    """Resolve a catalog message, falling back to Chinese and then to the key itself."""
+    entry = MESSAGES.get(key)
+    if entry is None:
+        return key
+    text = entry.get(normalize_language(lang)) or entry.get(DEFAULT_LANGUAGE) or key
+    return text.format(**params) if params else text
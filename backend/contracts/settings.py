@@ -1,6 +1,8 @@
 from dataclasses import dataclass, field
 import os
 
+from backend.contracts.messages import DEFAULT_LANGUAGE
+
 
 def default_max_concurrency() -> int:
     """Prefer a conservative default on Windows where process spawn is expensive."""
@@ -17,6 +19,13 @@ def default_max_concurrency() -> int:
     return max(1, min(8, cpu))
 
 
+PREVIEW_MODES = ("auto", "always-fast", "always-full", "never")
+# "ask" prompts the UI per existing output instead of renaming silently.
+CONFLICT_STRATEGIES = ("rename", "ask")
+# Enqueue order for batch items; results always come back in request order.
+BATCH_ORDERS = ("as-is", "smallest-first", "largest-first", "name")
+
+
 @dataclass(slots=True)
 class AppSettings:
     max_concurrency: int = field(default_factory=default_max_concurrency)
@@ -27,6 +36,28 @@ class AppSettings:
     default_output_dir: str = ""
     recent_input_dirs: list[str] = field(default_factory=list)
     recent_output_dirs: list[str] = field(default_factory=list)
+    preview_mode: str = "auto"
+    format_quality_defaults: dict[str, int] = field(default_factory=dict)
+    language: str = DEFAULT_LANGUAGE
+    open_folder_when_done: bool = False
+    notify_on_completion: bool = False
+    use_working_copy: bool = False
+    batch_order: str = "as-is"
+    use_ram_temp: bool = False
+    # Empty uses /dev/shm on Linux; elsewhere a RAM disk path must be given.
+    ram_temp_dir: str = ""
+    # Conversions refuse inputs above this size before decoding them.
+    max_megapixels: int = 256
+    # GIF operations refuse animations over these before decoding a frame, and frame
+    # export refuses to write more than `gif_max_export_files` files.
+    gif_max_frames: int = 10_000
+    gif_max_megapixels: int = 2_048
+    gif_max_export_files: int = 2_000
+    # Takes effect at the next launch, like IMAGEFLOW_SAFE_MODE=1: no process pool and no
+    # packaged-runtime checks, for recovering from an install that will not start.
+    safe_mode: bool = False
+    # Undo steps an edit session keeps; the oldest edited states are dropped beyond this.
+    edit_history_depth: int = 20
 
 
 def default_app_settings() -> AppSettings:
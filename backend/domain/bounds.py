@@ -0,0 +1,95 @@
+from __future__ import annotations
+
+from typing import Any
+
+# Per-engine numeric bounds applied before a request reaches Python workers.
+# Each field maps to (min, max, kind); None leaves that side open and kind
+# decides whether the clamped value is written back as int or float.
+REQUEST_BOUNDS: dict[str, dict[str, tuple[float | None, float | None, type]]] = {
+    "converter": {
+        # 0 means "use the per-format default" and is resolved by the host or the engine.
+        "quality": (0, 100, int),
+        "width": (0, None, int),
+        "height": (0, None, int),
+        "scale_percent": (0, None, float),
+        "long_edge": (0, None, int),
+        "compress_level": (0, 9, int),
+        "target_size_kb": (0, None, int),
+    },
+    "compressor": {
+        "level": (1, 5, int),
+        "quality": (0, 100, int),
+        "target_size_kb": (0, None, int),
+    },
+    "watermark": {
+        "opacity": (0, 1, float),
+        "watermark_scale": (0.01, None, float),
+        "scale": (0.01, None, float),
+        "font_size": (1, None, int),
+        "line_width": (0, None, int),
+    },
+    "adjuster": {
+        "brightness": (-100, 100, int),
+        "contrast": (-100, 100, int),
+        "saturation": (-100, 100, int),
+        "vibrance": (-100, 100, int),
+        "exposure": (-100, 100, int),
+        "sharpness": (-100, 100, int),
+        "hue": (-180, 180, int),
+    },
+    "filter": {
+        "intensity": (0, 1, float),
+        "grain": (0, 1, float),
+        "vignette": (0, 1, float),
+        "noise_level": (0, 1, float),
+        "vignette_strength": (0, 1, float),
+        "blur_radius": (0, None, float),
+    },
+    "pdf_generator": {
+        "margin": (0, None, int),
+        "compression_level": (0, 3, int),
+        "custom_rows": (1, None, int),
+        "custom_cols": (1, None, int),
+        "images_per_page": (0, 100, int),
+        "grid_columns": (0, 100, int),
+    },
+}
+
+
+def _format_bound(value: float | None) -> str:
+    if value is None:
+        return ""
+    return str(int(value)) if float(value).is_integer() else str(value)
+
+
+def clamp_request(module_name: str, payload: dict) -> tuple[dict, list[str]]:
+    """Clamp out-of-range numeric fields; returns the adjusted copy and one warning per change.
+
+    Missing or non-numeric values are left for the engine to default or reject.
+    """
+    bounds = REQUEST_BOUNDS.get(module_name)
+    if not bounds or not isinstance(payload, dict):
+        return payload, []
+
+    clamped = dict(payload)
+    warnings: list[str] = []
+    for name, (low, high, kind) in bounds.items():
+        raw: Any = clamped.get(name)
+        if raw is None or isinstance(raw, bool):
+            continue
+        try:
+            value = float(raw)
+        except (TypeError, ValueError):
+            continue
+        adjusted = value
+        if low is not None and adjusted < low:
+            adjusted = low
+        if high is not None and adjusted > high:
+            adjusted = high
+        if adjusted == value:
+            continue
+        clamped[name] = kind(adjusted)
+        warnings.append(
+            f"参数 {name} 超出范围 ({_format_bound(low)}~{_format_bound(high)})，已由 {raw} 调整为 {clamped[name]}"
+        )
+    return clamped, warnings
@@ -0,0 +1,357 @@
+from __future__ import annotations
+
+import os
+import re
+
+CAPABILITY_KEYS = (
+    "alpha",
+    "animation",
+    "lossless",
+    "quality",
+    "progressive",
+    "metadata",
+    "multi_page",
+)
+
+# Static per-format knowledge of which options ImageFlow can honor on output.
+# "animation" covers the animated conversion path (GIF/APNG/WebP); "lossless"
+# means the encoder can round-trip pixels exactly.
+FORMAT_CAPABILITIES: dict[str, dict] = {
+    "jpg": {
+        "extensions": [".jpg", ".jpeg"],
+        "alpha": False,
+        "animation": False,
+        "lossless": False,
+        "quality": True,
+        "progressive": True,
+        "metadata": True,
+        "multi_page": False,
+    },
+    "png": {
+        "extensions": [".png"],
+        "alpha": True,
+        "animation": True,
+        "lossless": True,
+        "quality": False,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": False,
+    },
+    "webp": {
+        "extensions": [".webp"],
+        "alpha": True,
+        "animation": True,
+        "lossless": True,
+        "quality": True,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": False,
+    },
+    "avif": {
+        "extensions": [".avif"],
+        "alpha": True,
+        "animation": False,
+        "lossless": True,
+        "quality": True,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": False,
+    },
+    "jxl": {
+        "extensions": [".jxl"],
+        "alpha": True,
+        "animation": False,
+        "lossless": True,
+        "quality": True,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": False,
+    },
+    "gif": {
+        "extensions": [".gif"],
+        "alpha": True,
+        "animation": True,
+        "lossless": False,
+        "quality": False,
+        "progressive": False,
+        "metadata": False,
+        "multi_page": False,
+    },
+    "bmp": {
+        "extensions": [".bmp"],
+        "alpha": False,
+        "animation": False,
+        "lossless": True,
+        "quality": False,
+        "progressive": False,
+        "metadata": False,
+        "multi_page": False,
+    },
+    "tiff": {
+        "extensions": [".tiff", ".tif"],
+        "alpha": True,
+        "animation": False,
+        "lossless": True,
+        "quality": False,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": True,
+    },
+    "ico": {
+        "extensions": [".ico"],
+        "alpha": True,
+        "animation": False,
+        "lossless": True,
+        "quality": False,
+        "progressive": False,
+        "metadata": False,
+        "multi_page": True,
+    },
+    "pdf": {
+        "extensions": [".pdf"],
+        "alpha": False,
+        "animation": False,
+        "lossless": False,
+        "quality": True,
+        "progressive": False,
+        "metadata": True,
+        "multi_page": True,
+    },
+}
+
+# Canonical targets the converter engine can write; PDF output goes through pdf_generator.
+CONVERT_TARGETS = ("jpg", "png", "webp", "avif", "jxl", "bmp", "tiff", "ico")
+
+FORMAT_ALIASES = {
+    "jpeg": "jpg",
+    "tif": "tiff",
+}
+
+
+def canonical_format(format_name: str) -> str:
+    normalized = str(format_name or "").strip().lower().lstrip(".")
+    return FORMAT_ALIASES.get(normalized, normalized)
+
+
+# Leading magic bytes of the raster formats ImageFlow reads; SVG is text and has none.
+IMAGE_SIGNATURES = (
+    (b"\x89PNG\r\n\x1a\n", "png"),
+    (b"\xff\xd8", "jpg"),
+    (b"GIF87a", "gif"),
+    (b"GIF89a", "gif"),
+    (b"BM", "bmp"),
+    (b"II*\x00", "tiff"),
+    (b"MM\x00*", "tiff"),
+    (b"II+\x00", "tiff"),
+    (b"MM\x00+", "tiff"),
+    (b"\x00\x00\x01\x00", "ico"),
+    (b"\xff\x0a", "jxl"),
+    (b"\x00\x00\x00\x0cJXL \r\n\x87\n", "jxl"),
+)
+# Smallest file that still holds the fixed header (plus PNG's IHDR chunk).
+_MIN_HEADER_BYTES = {"png": 33, "gif": 13, "bmp": 26, "ico": 6}
+
+
+def sniff_image_format(header: bytes) -> str:
+    """Canonical format named by a file's first bytes, or "" when no signature matches."""
+    for signature, name in IMAGE_SIGNATURES:
+        if header.startswith(signature):
+            return name
+    if header[:4] == b"RIFF" and header[8:12] == b"WEBP":
+        return "webp"
+    if header[4:8] == b"ftyp":
+        return "avif" if header[8:12].lower() in (b"avif", b"avis") else "heif"
+    return ""
+
+
+def _jpeg_header_complete(handle, size: int) -> bool:
+    """Walk the marker segments up to the first scan; False when the file ends inside them."""
+    position = 2
+    while position + 4 <= size:
+        handle.seek(position)
+        marker = handle.read(4)
+        if marker[0] != 0xFF:
+            return False
+        code = marker[1]
+        if code == 0xFF:
+            position += 1
+            continue
+        if code == 0x01 or 0xD0 <= code <= 0xD7:
+            position += 2
+            continue
+        if code == 0xD9:
+            return False
+        length = int.from_bytes(marker[2:4], "big")
+        if length < 2:
+            return False
+        if code == 0xDA:
+            return position + 2 + length <= size
+        position += 2 + length
+    return False
+
+
+def _header_complete(image_format: str, header: bytes, handle, size: int) -> bool:
+    if image_format == "jpg":
+        return _jpeg_header_complete(handle, size)
+    if image_format == "png":
+        return size >= _MIN_HEADER_BYTES["png"] and header[12:16] == b"IHDR"
+    if image_format == "webp":
+        return size >= 16 and int.from_bytes(header[4:8], "little") + 8 <= size
+    if image_format == "tiff":
+        order = "little" if header[:2] == b"II" else "big"
+        if header[2:4] in (b"+\x00", b"\x00+"):
+            return size >= 16 and int.from_bytes(header[8:16], order) < size
+        return size >= 8 and int.from_bytes(header[4:8], order) < size
+    return size >= _MIN_HEADER_BYTES.get(image_format, 0)
+
+
+def input_file_error(path: str) -> str:
+    """Return `[EMPTY_FILE]` or `[TRUNCATED_OR_CORRUPT]` for inputs that cannot hold an image, else "".
+
+    Only the size and the fixed header are read. Missing/unreadable files and unknown
+    signatures are left to the engine, which reports them with its own codes.
+    """
+    try:
+        with open(path, "rb") as handle:
+            size = os.fstat(handle.fileno()).st_size
+            if size == 0:
+                return f"[EMPTY_FILE] Input file is empty: {path}"
+            header = handle.read(32)
+            image_format = sniff_image_format(header)
+            complete = not image_format or _header_complete(image_format, header, handle, size)
+    except OSError:
+        return ""
+    if not complete:
+        return f"[TRUNCATED_OR_CORRUPT] Input file is truncated or corrupt: {path}"
+    return ""
+
+
+def convert_target_error(format_name: str) -> str:
+    """Return an `[UNSUPPORTED_FORMAT]` message for targets the converter cannot write, else ""."""
+    target = canonical_format(format_name)
+    if target in CONVERT_TARGETS:
+        return ""
+    supported = ", ".join(CONVERT_TARGETS)
+    return f"[UNSUPPORTED_FORMAT] Unsupported output format: {target or '?'} (supported: {supported})"
+
+
+_HEX_COLOR_RE = re.compile(r"^#?(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$")
+
+
+def hex_color_error(value, field: str) -> str:
+    """Return a `[BAD_INPUT]` message naming `field` unless `value` is empty or a `#RGB`/`#RRGGBB` hex color."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, str) and _HEX_COLOR_RE.match(value.strip()):
+        return ""
+    return f"[BAD_INPUT] Invalid {field}: {value!r} (expected #RRGGBB)"
+
+
+def color_profile_error(keep_color_profile, keep_metadata) -> str:
+    """Return a `[BAD_INPUT]` message unless both flags are booleans (or omitted).
+
+    The flags are independent: `keep_metadata` covers EXIF only and `keep_color_profile`
+    decides between embedding the ICC profile and converting to sRGB.
+    """
+    for name, value in (("keep_color_profile", keep_color_profile), ("keep_metadata", keep_metadata)):
+        if value is not None and not isinstance(value, bool):
+            return f"[BAD_INPUT] Invalid {name}: {value!r} (expected true or false)"
+    return ""
+
+
+# Pillow's JPEG `subsampling` values; "" leaves the encoder default.
+JPEG_SUBSAMPLING = {"4:4:4": 0, "4:2:2": 1, "4:2:0": 2}
+
+
+def subsampling_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is empty or a supported chroma subsampling."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, str) and value.strip() in JPEG_SUBSAMPLING:
+        return ""
+    return f"[BAD_INPUT] Invalid subsampling: {value!r} (expected one of {', '.join(JPEG_SUBSAMPLING)} or empty for auto)"
+
+
+# TIFF compression schemes the converter accepts; "" means the default (lzw).
+TIFF_COMPRESSIONS = ("none", "lzw", "deflate", "packbits")
+
+
+def tiff_compression_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is empty or a supported TIFF compression."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, str) and value.strip().lower() in TIFF_COMPRESSIONS:
+        return ""
+    return f"[BAD_INPUT] Invalid tiff_compression: {value!r} (expected one of {', '.join(TIFF_COMPRESSIONS)} or empty for lzw)"
+
+
+def trim_error(trim_borders, trim_tolerance) -> str:
+    """Return a `[BAD_INPUT]` message unless `trim_borders` is a boolean and `trim_tolerance` is 0-255."""
+    if trim_borders is not None and not isinstance(trim_borders, bool):
+        return f"[BAD_INPUT] Invalid trim_borders: {trim_borders!r} (expected true or false)"
+    if trim_tolerance is None or trim_tolerance == "":
+        return ""
+    if isinstance(trim_tolerance, int) and not isinstance(trim_tolerance, bool) and 0 <= trim_tolerance <= 255:
+        return ""
+    return f"[BAD_INPUT] Invalid trim_tolerance: {trim_tolerance!r} (expected an integer 0-255)"
+
+
+MAX_ICO_SIZE = 256
+
+
+def ico_sizes_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is empty or a list of integer edges 1-256."""
+    if value is None or value == "" or value == []:
+        return ""
+    if not isinstance(value, list):
+        return f"[BAD_INPUT] Invalid ico_sizes: {value!r} (expected a list of sizes)"
+    for size in value:
+        if not isinstance(size, int) or isinstance(size, bool):
+            return f"[BAD_INPUT] Invalid ico size: {size!r} (expected an integer 1-{MAX_ICO_SIZE})"
+        if not 1 <= size <= MAX_ICO_SIZE:
+            return f"[BAD_INPUT] ICO size {size} is out of range (ICO frames are at most {MAX_ICO_SIZE}x{MAX_ICO_SIZE})"
+    return ""
+
+
+def normalize_ico_sizes(value) -> list[int]:
+    """Sorted, de-duplicated ICO edges from a list already accepted by `ico_sizes_error`."""
+    return sorted(set(value or []))
+
+
+def max_megapixels_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is empty or a positive integer megapixel limit."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, int) and not isinstance(value, bool) and value > 0:
+        return ""
+    return f"[BAD_INPUT] Invalid max_megapixels: {value!r} (expected a positive integer)"
+
+
+MAX_DPI = 10000
+
+
+def dpi_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is empty or an integer resolution 0-10000."""
+    if value is None or value == "":
+        return ""
+    if isinstance(value, int) and not isinstance(value, bool) and 0 <= value <= MAX_DPI:
+        return ""
+    return f"[BAD_INPUT] Invalid dpi: {value!r} (expected an integer 0-{MAX_DPI}, 0 keeps the source's)"
+
+
+def orientation_error(value) -> str:
+    """Return a `[BAD_INPUT]` message unless `value` is an EXIF Orientation value 1-8."""
+    if isinstance(value, int) and not isinstance(value, bool) and 1 <= value <= 8:
+        return ""
+    return f"[BAD_INPUT] Invalid orientation: {value!r} (expected an integer 1-8)"
+
+
+def format_capabilities() -> dict:
+    formats = {}
+    for name, entry in FORMAT_CAPABILITIES.items():
+        formats[name] = {**entry, "extensions": list(entry["extensions"])}
+    return {
+        "features": list(CAPABILITY_KEYS),
+        "aliases": dict(FORMAT_ALIASES),
+        "formats": formats,
+    }
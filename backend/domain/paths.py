@@ -1,4 +1,5 @@
 import os
+import re
 from pathlib import Path
 
 SUPPORTED_EXTENSIONS = {
@@ -9,6 +10,7 @@ SUPPORTED_EXTENSIONS = {
     ".gif",
     ".bmp",
     ".avif",
+    ".jxl",
     ".ico",
     ".tiff",
     ".tif",
@@ -18,6 +20,38 @@ SUPPORTED_EXTENSIONS = {
 }
 
 
+MAX_FILENAME_LENGTH = 255
+WINDOWS_RESERVED_NAMES = frozenset(
+    {"CON", "PRN", "AUX", "NUL"}
+    | {f"COM{index}" for index in range(1, 10)}
+    | {f"LPT{index}" for index in range(1, 10)}
+)
+_WINDOWS_ILLEGAL_CHARS = re.compile(r'[\\/:*?"<>|\x00-\x1f]+')
+_POSIX_ILLEGAL_CHARS = re.compile(r"[/\x00]+")
+
+
+def sanitize_filename(name: str, windows: bool | None = None) -> str:
+    """Make one path component writable: replace illegal characters with `_`, trim trailing
+    dots/spaces and prefix reserved device names (`CON`, `nul.txt` ...) on Windows.
+
+    `windows` defaults to the host OS; pass True for names that may land on a Windows volume.
+    Returns "" when nothing usable is left so callers can pick their own fallback.
+    """
+    windows = os.name == "nt" if windows is None else windows
+    illegal = _WINDOWS_ILLEGAL_CHARS if windows else _POSIX_ILLEGAL_CHARS
+    cleaned = illegal.sub("_", str(name or "")).strip()
+    if len(cleaned) > MAX_FILENAME_LENGTH:
+        stem, dot, ext = cleaned.rpartition(".")
+        keep_ext = bool(dot) and 0 < len(ext) < 16
+        cleaned = f"{stem[: MAX_FILENAME_LENGTH - len(ext) - 1]}.{ext}" if keep_ext else cleaned[:MAX_FILENAME_LENGTH]
+    if windows:
+        cleaned = cleaned.rstrip(". ")
+        # Windows reserves the device name with any extension too (`NUL.png`).
+        if cleaned.split(".", 1)[0].rstrip(" ").upper() in WINDOWS_RESERVED_NAMES:
+            cleaned = f"_{cleaned}"
+    return "" if cleaned in {".", ".."} else cleaned
+
+
 def _has_leading_parent_traversal(path_value: str) -> bool:
     path = Path(path_value)
     if path.is_absolute():
@@ -0,0 +1,21 @@
+from __future__ import annotations
+
+import re
+
+# info_viewer reports `perceptual_hash` as hex digits of a 64-bit dHash.
+_HEX_HASH = re.compile(r"^[0-9a-fA-F]+$")
+
+
+def hamming_distance(a: str, b: str) -> int:
+    """Number of differing bits between two equal-length hex hashes; 0 means the same picture.
+
+    Raises ValueError for empty, non-hex or mismatched-length hashes, since those come from
+    images whose hash could not be computed or from different hash sizes.
+    """
+    left, right = str(a or "").strip(), str(b or "").strip()
+    for value in (left, right):
+        if not _HEX_HASH.match(value):
+            raise ValueError(f"not a hex hash: {value!r}")
+    if len(left) != len(right):
+        raise ValueError(f"hash lengths differ: {len(left)} and {len(right)} hex digits")
+    return bin(int(left, 16) ^ int(right, 16)).count("1")
@@ -13,12 +13,21 @@ Usage:
 
 import sys
 import json
+import math
 import os
 from pathlib import Path
-from PIL import Image, ImageEnhance, ImageOps
+from PIL import Image, ImageEnhance, ImageOps, ImageStat
 import logging
+from temp_registry import getsize_with_retry
 
-from converter import open_image_with_svg_support
+from converter import (
+    HIGH_BIT_DEPTH_MODES,
+    bit_depth_request,
+    normalize_high_bit_depth,
+    open_image_with_svg_support,
+    prepare_16bit_for_save,
+    read_level_roll_degrees,
+)
 
 # Configure logging
 logger = logging.getLogger(__name__)
@@ -33,7 +42,8 @@ class ImageAdjuster:
     
     def adjust(self, input_path, output_path, rotate=0, flip_h=False, flip_v=False,
                brightness=0, contrast=0, saturation=0, hue=0,
-               exposure=0, vibrance=0, sharpness=0, crop_ratio="", crop_mode=""):
+               exposure=0, vibrance=0, sharpness=0, crop_ratio="", crop_mode="",
+               preserve_16bit=None, auto_level=False):
         """
         Apply adjustments to an image.
         
@@ -47,6 +57,8 @@ class ImageAdjuster:
             contrast (int): Contrast adjustment (-100 to +100)
             saturation (int): Saturation adjustment (-100 to +100)
             hue (int): Hue adjustment (-180 to +180)
+            preserve_16bit (bool | None): Keep 16-bit samples for PNG/TIFF output unless False
+            auto_level (bool): Straighten using the camera roll recorded in XMP
         
         Returns:
             dict: Adjustment result
@@ -59,8 +71,34 @@ class ImageAdjuster:
             
             # Prefer the requested output extension so file content matches file name.
             img_format = self._resolve_output_format(output_path, img.format or 'PNG')
+
+            level_roll = None
+            if auto_level:
+                level_roll = read_level_roll_degrees(
+                    img.info.get('xmp') or img.info.get('XML:com.adobe.xmp')
+                )
+
+            warning = None
+            tone_mapped = False
+            if img.mode in HIGH_BIT_DEPTH_MODES:
+                keep_16bit = preserve_16bit is not False and img_format in ('PNG', 'TIFF')
+                if keep_16bit and sharpness:
+                    # Pillow's kernel filters are 8-bit only.
+                    keep_16bit = False
+                    warning = '锐化仅支持 8 位色深，已转换为 8 位'
+                elif preserve_16bit and not keep_16bit:
+                    warning = f'目标格式 {img_format} 不支持 16 位色深，已转换为 8 位'
+                prev = img
+                img, tone_mapped = normalize_high_bit_depth(img, keep_16bit)
+                if img is not prev:
+                    prev.close()
             
             # Apply adjustments in order, closing intermediate images to free memory
+            if level_roll:
+                prev = img
+                img = self._apply_straighten(img, -level_roll)
+                if img is not prev:
+                    prev.close()
             prev = img
             img = self._apply_rotation(img, rotate)
             if img is not prev:
@@ -113,14 +151,22 @@ class ImageAdjuster:
             img = None
 
             # Get file size
-            file_size = os.path.getsize(output_path)
+            file_size = getsize_with_retry(output_path)
 
-            return {
+            result = {
                 'success': True,
                 'input_path': input_path,
                 'output_path': output_path,
-                'file_size': file_size
+                'file_size': file_size,
+                'tone_mapped': tone_mapped,
             }
+            if auto_level:
+                result['level_roll'] = level_roll
+                result['auto_level_applied'] = bool(level_roll)
+                result['level_data_missing'] = level_roll is None
+            if warning:
+                result['warning'] = warning
+            return result
             
         except FileNotFoundError as e:
             logger.error(f"File not found: {e}")
@@ -158,6 +204,8 @@ class ImageAdjuster:
         save_img = img
         if img_format == 'JPEG' and img.mode not in ('RGB', 'L'):
             save_img = img.convert('RGB')
+        elif img.mode == 'I':
+            save_img = prepare_16bit_for_save(img, img_format)
         save_img.save(output_path, format=img_format)
         if save_img is not img:
             save_img.close()
@@ -181,6 +229,34 @@ class ImageAdjuster:
         # Use expand=True to prevent cropping
         return img.rotate(angle, expand=True, resample=Image.Resampling.BICUBIC)
     
+    def _apply_straighten(self, img, angle):
+        """
+        Rotate by a small correction angle and crop to the largest centered
+        rectangle with the original aspect ratio, so no blank corners remain.
+        """
+        if angle == 0:
+            return img
+
+        logger.debug(f"Applying straighten: {angle} degrees")
+
+        width, height = img.size
+        radians = math.radians(abs(angle))
+        cos_a, sin_a = math.cos(radians), math.sin(radians)
+        scale = min(
+            width / (width * cos_a + height * sin_a),
+            height / (width * sin_a + height * cos_a),
+        )
+        crop_w = max(1, int(width * scale))
+        crop_h = max(1, int(height * scale))
+        left = (width - crop_w) // 2
+        top = (height - crop_h) // 2
+
+        rotated = img.rotate(angle, expand=False, resample=Image.Resampling.BICUBIC)
+        try:
+            return rotated.crop((left, top, left + crop_w, top + crop_h))
+        finally:
+            rotated.close()
+
     def _apply_flip(self, img, flip_h, flip_v):
         """
         Apply flipping to an image.
@@ -226,6 +302,10 @@ class ImageAdjuster:
         # 0 = no change, -100 = 0.0 (black), +100 = 2.0 (double brightness)
         factor = 1.0 + (adjustment / 100.0)
         factor = max(0.0, min(2.0, factor))  # Clamp to valid range
+
+        if img.mode == 'I':
+            # ImageEnhance blends 8-bit bands only; scale 16-bit samples directly.
+            return img.point(lambda v: v * factor)
         
         enhancer = ImageEnhance.Brightness(img)
         return enhancer.enhance(factor)
@@ -249,6 +329,10 @@ class ImageAdjuster:
         # Convert -100 to +100 range to 0.0 to 2.0 factor
         factor = 1.0 + (adjustment / 100.0)
         factor = max(0.0, min(2.0, factor))
+
+        if img.mode == 'I':
+            mean = ImageStat.Stat(img).mean[0]
+            return img.point(lambda v: v * factor + mean * (1.0 - factor))
         
         enhancer = ImageEnhance.Contrast(img)
         return enhancer.enhance(factor)
@@ -267,6 +351,10 @@ class ImageAdjuster:
         if adjustment == 0:
             return img
         
+        if img.mode == 'I':
+            # Single-channel 16-bit data has no chroma to adjust.
+            return img
+
         logger.debug(f"Applying saturation adjustment: {adjustment}")
         
         # Convert -100 to +100 range to 0.0 to 2.0 factor
@@ -284,7 +372,7 @@ class ImageAdjuster:
         """
         Apply vibrance adjustment (boost low-saturation colors more).
         """
-        if adjustment == 0:
+        if adjustment == 0 or img.mode == "I":
             return img
 
         try:
@@ -442,7 +530,7 @@ class ImageAdjuster:
         except Exception:
             return img
 
-        if shift_degrees % 360 == 0:
+        if shift_degrees % 360 == 0 or img.mode == 'I':
             return img
 
         if img.mode not in ('RGB', 'RGBA'):
@@ -503,6 +591,8 @@ def process(input_data):
         sharpness = input_data.get('sharpness', 0)
         crop_ratio = input_data.get('crop_ratio', '')
         crop_mode = input_data.get('crop_mode', '')
+        preserve_16bit = bit_depth_request(input_data)
+        auto_level = bool(input_data.get('auto_level', False))
 
         # Validate required parameters
         if not input_path or not output_path:
@@ -527,7 +617,9 @@ def process(input_data):
             vibrance=vibrance,
             sharpness=sharpness,
             crop_ratio=crop_ratio,
-            crop_mode=crop_mode
+            crop_mode=crop_mode,
+            preserve_16bit=preserve_16bit,
+            auto_level=auto_level
         )
 
         return result
@@ -562,6 +654,8 @@ def main():
         sharpness = input_data.get('sharpness', 0)
         crop_ratio = input_data.get('crop_ratio', '')
         crop_mode = input_data.get('crop_mode', '')
+        preserve_16bit = bit_depth_request(input_data)
+        auto_level = bool(input_data.get('auto_level', False))
         
         # Validate required parameters
         if not input_path or not output_path:
@@ -586,7 +680,9 @@ def main():
                 vibrance=vibrance,
                 sharpness=sharpness,
                 crop_ratio=crop_ratio,
-                crop_mode=crop_mode
+                crop_mode=crop_mode,
+                preserve_16bit=preserve_16bit,
+                auto_level=auto_level
             )
         
         # Write result to stdout
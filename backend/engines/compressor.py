@@ -26,7 +26,8 @@ import os
 import tempfile
 from io import BytesIO
 from pathlib import Path
-from PIL import Image
+from PIL import Image, ImageMath
+from temp_registry import TEMP_REGISTRY, getsize_with_retry, replace_with_retry
 import logging
 
 try:
@@ -56,7 +57,10 @@ except ImportError:
 # Configure logging
 logger = logging.getLogger(__name__)
 
-JPEG_STRIP_MARKERS = {0xE1, 0xED, 0xFE}
+# APP1 holds EXIF (with GPS) and XMP, APP2 the ICC profile, APP13 IPTC; COM is a comment.
+JPEG_METADATA_MARKERS = {0xE1, 0xE2, 0xED, 0xFE}
+# `strip_mode` values: what stripping removes. An empty mode with `strip_metadata` means keep_icc.
+STRIP_MODES = ("all", "exif_only", "keep_icc")
 JPEG_STANDALONE_MARKERS = {0x01, 0xD8, 0xD9, *range(0xD0, 0xD8)}
 COPY_CHUNK_SIZE = 1024 * 1024
 COMPRESSIBLE_EXTENSIONS = {
@@ -71,6 +75,18 @@ COMPRESSIBLE_EXTENSIONS = {
     ".ico",
 }
 UNSUPPORTED_COMPRESSION_EXTENSIONS = {".svg", ".gif", ".apng"}
+# Pillow formats `lossless` can re-encode exactly; JPEG only has the level-1 byte copy.
+LOSSLESS_COMPRESSION_FORMATS = {"PNG", "WEBP", "AVIF"}
+# `target_size_kb` bisects encoder quality from this floor up to the requested quality.
+TARGET_SIZE_MIN_QUALITY = 5
+TARGET_SIZE_MAX_STEPS = 12
+# Pillow formats `target_ssim` can search; compression never changes the format.
+SSIM_SEARCH_FORMATS = ("JPEG", "WEBP")
+SSIM_WINDOW = 8
+# Rows of float images `ssim_score` holds at once; a multiple of the window.
+SSIM_STRIP_ROWS = 512
+_SSIM_C1 = (0.01 * 255) ** 2
+_SSIM_C2 = (0.03 * 255) ** 2
 
 
 def _copy_remaining(src, dst) -> None:
@@ -91,16 +107,25 @@ def _copy_exact(src, dst, size: int) -> None:
         remaining -= len(chunk)
 
 
-def _skip_exact(src, size: int) -> None:
-    remaining = max(0, int(size))
-    while remaining > 0:
-        chunk = src.read(min(COPY_CHUNK_SIZE, remaining))
-        if not chunk:
-            return
-        remaining -= len(chunk)
+def _resolve_strip_mode(strip_mode, strip_metadata) -> str:
+    """The effective strip mode ("" keeps metadata); raises ValueError for an unknown mode."""
+    mode = str(strip_mode or "").strip().lower()
+    if not mode:
+        return "keep_icc" if strip_metadata else ""
+    if mode not in STRIP_MODES:
+        raise ValueError(f"[BAD_INPUT] Invalid strip_mode: {strip_mode!r} (expected one of {', '.join(STRIP_MODES)})")
+    return mode
+
+
+def _jpeg_segment_stripped(marker: int, payload: bytes, mode: str) -> bool:
+    if mode == "exif_only":
+        return marker == 0xE1 and payload.startswith(b"Exif\x00")
+    if marker == 0xE2:
+        return mode == "all"
+    return True
 
 
-def _copy_jpeg_without_metadata(src, dst) -> None:
+def _copy_jpeg_without_metadata(src, dst, mode="keep_icc") -> None:
     header = src.read(2)
     if header != b"\xff\xd8":
         dst.write(header)
@@ -143,8 +168,11 @@ def _copy_jpeg_without_metadata(src, dst) -> None:
             return
 
         payload_length = segment_length - 2
-        if marker in JPEG_STRIP_MARKERS:
-            _skip_exact(src, payload_length)
+        if marker in JPEG_METADATA_MARKERS:
+            # A segment is at most 64 KB, so reading it whole to look at its header is bounded.
+            payload = src.read(payload_length)
+            if not _jpeg_segment_stripped(marker, payload, mode):
+                dst.write(b"\xff" + marker_byte + length_bytes + payload)
         else:
             dst.write(b"\xff" + marker_byte + length_bytes)
             _copy_exact(src, dst, payload_length)
@@ -154,12 +182,12 @@ def _copy_jpeg_without_metadata(src, dst) -> None:
             return
 
 
-def _strip_jpeg_metadata_bytes(data: bytes) -> bytes:
+def _strip_jpeg_metadata_bytes(data: bytes, mode="keep_icc") -> bytes:
     if not data:
         return data
     src = BytesIO(data)
     dst = BytesIO()
-    _copy_jpeg_without_metadata(src, dst)
+    _copy_jpeg_without_metadata(src, dst, mode)
     return dst.getvalue()
 
 
@@ -175,6 +203,92 @@ def _append_warning(existing: str, message: str) -> str:
     return f"{existing}；{message}"
 
 
+def _target_missed_warning(target_bytes: int, output_path: str) -> str:
+    """Warning for a `target_size_kb` no engine setting could reach, naming the size written instead."""
+    message = f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+    try:
+        achieved = getsize_with_retry(output_path)
+    except OSError:
+        return message
+    return f"{message}（{achieved / 1024:.1f}KB）"
+
+
+def search_quality_for_size(encoded_size, target_bytes: int, high: int, low: int = TARGET_SIZE_MIN_QUALITY):
+    """Bisect for the highest quality in [low, high] whose `encoded_size(q)` fits `target_bytes`.
+
+    `encoded_size` returns the byte size at one quality, or None to stop the search. It also
+    stops once the size has not changed for two steps. Returns (quality, met); when nothing
+    fits, quality is the lowest one tried (`high` if none was), the smallest output seen.
+    """
+    best_q = None
+    smallest_q = None
+    last_size = None
+    stable_hits = 0
+    low = min(low, high)
+    for _ in range(TARGET_SIZE_MAX_STEPS):
+        if low > high:
+            break
+        q = (low + high) // 2
+        size = encoded_size(q)
+        if size is None:
+            break
+        smallest_q = q if smallest_q is None else min(smallest_q, q)
+        stable_hits = stable_hits + 1 if size == last_size else 0
+        last_size = size
+        if size <= target_bytes:
+            best_q = q
+            low = q + 1
+        else:
+            high = q - 1
+        if stable_hits >= 2:
+            break
+    if best_q is not None:
+        return best_q, True
+    return (smallest_q if smallest_q is not None else high), False
+
+
+def ssim_score(reference, candidate) -> float:
+    """Mean SSIM over non-overlapping 8x8 windows of two same-sized "L" images.
+
+    Works on full-resolution Pillow float images a strip at a time; the right and bottom
+    pixels that do not fill a window are left out.
+    """
+    window = SSIM_WINDOW
+    width = reference.width // window * window
+    height = reference.height // window * window
+    if not width or not height:
+        return 1.0 if reference.tobytes() == candidate.tobytes() else 0.0
+    total = 0.0
+    for top in range(0, height, SSIM_STRIP_ROWS):
+        box = (0, top, width, min(height, top + SSIM_STRIP_ROWS))
+        x = reference.crop(box).convert("F")
+        y = candidate.crop(box).convert("F")
+        blocks = (x.width // window, x.height // window)
+
+        def window_mean(image):
+            # BOX resampling by an exact factor averages each window.
+            return image.resize(blocks, Image.Resampling.BOX)
+
+        stats = {
+            "mx": window_mean(x),
+            "my": window_mean(y),
+            "xx": window_mean(ImageMath.lambda_eval(lambda a: a["x"] * a["x"], x=x)),
+            "yy": window_mean(ImageMath.lambda_eval(lambda a: a["y"] * a["y"], y=y)),
+            "xy": window_mean(ImageMath.lambda_eval(lambda a: a["x"] * a["y"], x=x, y=y)),
+        }
+        scores = ImageMath.lambda_eval(
+            lambda a: (2 * a["mx"] * a["my"] + _SSIM_C1)
+            * (2 * (a["xy"] - a["mx"] * a["my"]) + _SSIM_C2)
+            / (
+                (a["mx"] * a["mx"] + a["my"] * a["my"] + _SSIM_C1)
+                * (a["xx"] - a["mx"] * a["mx"] + a["yy"] - a["my"] * a["my"] + _SSIM_C2)
+            ),
+            **stats,
+        )
+        total += scores.resize((1, 1), Image.Resampling.BOX).getpixel((0, 0)) * blocks[0] * blocks[1]
+    return total / ((width // window) * (height // window))
+
+
 def _unsupported_compression_error(input_path: str) -> str:
     ext = Path(str(input_path or "")).suffix.lower()
     if not ext:
@@ -186,14 +300,14 @@ def _unsupported_compression_error(input_path: str) -> str:
     return ""
 
 
-def _strip_jpeg_metadata_file(path: str) -> None:
+def _strip_jpeg_metadata_file(path: str, mode="keep_icc") -> None:
     directory = os.path.dirname(os.path.abspath(path)) or "."
     fd, temp_path = tempfile.mkstemp(prefix=".imageflow-strip-", suffix=".jpg", dir=directory)
     os.close(fd)
     try:
         with open(path, "rb") as src, open(temp_path, "wb") as dst:
-            _copy_jpeg_without_metadata(src, dst)
-        os.replace(temp_path, path)
+            _copy_jpeg_without_metadata(src, dst, mode)
+        replace_with_retry(temp_path, path)
     except Exception:
         try:
             os.remove(temp_path)
@@ -212,8 +326,14 @@ class CompressionLevel:
     EXTREME = 5  # 40% quality
 
     @classmethod
-    def get_quality(cls, level):
-        """Get quality percentage for a given level."""
+    def get_quality(cls, level, quality=0):
+        """Get quality percentage for a given level; an explicit 1-100 quality wins."""
+        try:
+            explicit = int(quality or 0)
+        except (TypeError, ValueError):
+            explicit = 0
+        if explicit > 0:
+            return max(1, min(100, explicit))
         quality_map = {
             cls.LOSSLESS: 100,
             cls.LIGHT: 90,
@@ -237,6 +357,9 @@ class ImageCompressor:
         self.mozjpeg_available = HAS_MOZJPEG
         self.imagequant_available = HAS_IMAGEQUANT
         self.oxipng_available = HAS_OXIPNG
+        # What wrote the last output: pillow, mozjpeg, pngquant, oxipng, "pngquant+oxipng",
+        # "pillow+oxipng" or "copy" (JPEG level 1 byte copy).
+        self._engine_used = ""
 
         logger.info(f"ImageCompressor initialized:")
         logger.info(f"  - MoZJPEG: {self.mozjpeg_available}")
@@ -251,6 +374,11 @@ class ImageCompressor:
         engine="",
         target_size_kb=0,
         strip_metadata=False,
+        quality=0,
+        estimate=False,
+        lossless=False,
+        strip_mode="",
+        target_ssim=0,
     ):
         """
         Compress an image.
@@ -259,6 +387,25 @@ class ImageCompressor:
             input_path (str): Path to the input image
             output_path (str): Path to save the compressed image
             level (int): Compression level (1-5)
+            quality (int): Explicit encoder quality (1-100); 0 derives it from level
+            target_size_kb (int): Largest acceptable output in KB; 0 disables the search.
+                JPEG bisects quality in memory (5 up to the level's quality) before the
+                MozJPEG/Pillow save; PNG bisects pngquant/imagequant (or Pillow quantize)
+                quality with OxiPNG after each try; WebP bisects Pillow quality. Lossless
+                levels (and `engine="oxipng"`) never lower quality: PNG retries at OxiPNG's
+                slowest preset, JPEG and WebP encode once, as do other formats. A target
+                that cannot be met still writes the smallest result, with a warning naming
+                its size and `target_met: false`.
+            estimate (bool): Encode to a registered temp file, report the sizes and delete it;
+                `output_path` is not written (and may be empty)
+            lossless (bool): Encode PNG/WebP/AVIF exactly whatever the level; the level
+                then only sets encoder effort. Other formats ignore it with a warning.
+            strip_mode (str): "all" removes every metadata block including the ICC profile,
+                "keep_icc" everything but the ICC profile, "exif_only" just the EXIF block
+                (GPS included). Empty follows `strip_metadata` (True means keep_icc).
+            target_ssim (float): Lowest acceptable SSIM in (0, 1]; 0 disables the search.
+                JPEG/WebP only: bisects quality for the lowest one whose output reaches it
+                (see `_compress_to_ssim`); `quality` and `target_size_kb` are then ignored.
 
         Returns:
             dict: Compression result with success status and metadata
@@ -266,7 +413,11 @@ class ImageCompressor:
         tmp_output_path = None
         img = None
         try:
-            strip_metadata = bool(strip_metadata)
+            try:
+                strip_mode = _resolve_strip_mode(strip_mode, strip_metadata)
+            except ValueError as exc:
+                return {"success": False, "error": str(exc)}
+            strip_metadata = bool(strip_mode)
             # Validate compression level
             if not CompressionLevel.validate(level):
                 logger.warning(f"Invalid compression level: {level}, using MEDIUM")
@@ -275,13 +426,28 @@ class ImageCompressor:
             unsupported_error = _unsupported_compression_error(input_path)
             if unsupported_error:
                 return {"success": False, "error": unsupported_error}
+            if target_ssim:
+                return self._compress_to_ssim(
+                    input_path,
+                    output_path,
+                    float(target_ssim),
+                    level,
+                    engine=engine,
+                    strip_mode=strip_mode,
+                    estimate=estimate,
+                    lossless=lossless,
+                )
 
+            estimate = bool(estimate)
             input_abs = os.path.abspath(input_path)
-            output_abs = os.path.abspath(output_path)
-            same_file = input_abs == output_abs
+            output_abs = os.path.abspath(output_path or input_path)
+            same_file = not estimate and input_abs == output_abs
 
             work_output_path = output_path
-            if same_file:
+            if estimate:
+                tmp_output_path = TEMP_REGISTRY.create(suffix=Path(output_path or input_path).suffix or ".tmp")
+                work_output_path = tmp_output_path
+            elif same_file:
                 final_dir = os.path.dirname(output_abs) or "."
                 os.makedirs(final_dir, exist_ok=True)
                 with tempfile.NamedTemporaryFile(
@@ -321,6 +487,12 @@ class ImageCompressor:
             engine = str(engine or "").strip().lower()
             warning = ""
             fallback_used = False
+            self._engine_used = ""
+            lossless_ignored = ""
+            lossless = bool(lossless)
+            if lossless and format_type not in LOSSLESS_COMPRESSION_FORMATS:
+                lossless_ignored = f"{format_type} 不支持无损重新编码，已忽略 lossless 并按压缩等级处理"
+                lossless = False
 
             if format_type in ["JPEG", "JPG"]:
                 warning = self._compress_jpeg(
@@ -330,7 +502,8 @@ class ImageCompressor:
                     level,
                     engine=engine,
                     target_bytes=target_bytes,
-                    strip_metadata=bool(strip_metadata),
+                    strip_mode=strip_mode,
+                    quality=quality,
                 )
             elif format_type == "PNG":
                 warning = self._compress_png(
@@ -340,7 +513,9 @@ class ImageCompressor:
                     level,
                     engine=engine,
                     target_bytes=target_bytes,
-                    strip_metadata=bool(strip_metadata),
+                    strip_mode=strip_mode,
+                    quality=quality,
+                    lossless=lossless,
                 )
             elif format_type == "WEBP":
                 warning = self._compress_webp(
@@ -350,7 +525,9 @@ class ImageCompressor:
                     level,
                     engine=engine,
                     target_bytes=target_bytes,
-                    strip_metadata=bool(strip_metadata),
+                    strip_mode=strip_mode,
+                    quality=quality,
+                    lossless=lossless,
                 )
             else:
                 # Fallback to Pillow for other formats
@@ -362,9 +539,18 @@ class ImageCompressor:
                     level,
                     engine=engine,
                     target_bytes=target_bytes,
-                    strip_metadata=bool(strip_metadata),
+                    strip_mode=strip_mode,
+                    quality=quality,
+                    lossless=lossless,
                 )
 
+            if lossless_ignored:
+                warning = _append_warning(lossless_ignored, warning)
+            # Level 1 is exact for JPEG/PNG/WebP (byte copy or lossless encode), as is OxiPNG alone.
+            lossless_output = lossless or (
+                format_type in ("JPEG", "JPG", "PNG", "WEBP") and level == CompressionLevel.LOSSLESS
+            ) or (format_type == "PNG" and engine == "oxipng")
+
             # Explicitly close image to free memory
             img.close()
             img = None
@@ -376,18 +562,24 @@ class ImageCompressor:
                 and (not strip_metadata or fallback_used)
             ):
                 warning = _append_warning(warning, "压缩结果大于原图，已保留原文件内容")
-                if same_file:
+                lossless_output = True
+                if estimate:
+                    candidate_size = original_size
+                elif same_file:
                     os.remove(work_output_path)
                     tmp_output_path = None
                 else:
                     _copy_file_streaming(input_path, work_output_path)
 
-            if tmp_output_path:
-                os.replace(tmp_output_path, output_path)
+            if estimate:
+                TEMP_REGISTRY.discard(tmp_output_path)
+                tmp_output_path = None
+            elif tmp_output_path:
+                replace_with_retry(tmp_output_path, output_path)
                 tmp_output_path = None
 
             # Get compressed file size
-            compressed_size = os.path.getsize(output_path)
+            compressed_size = candidate_size if estimate else getsize_with_retry(output_path)
             compression_rate = (
                 (1 - compressed_size / original_size) * 100 if original_size > 0 else 0
             )
@@ -405,7 +597,17 @@ class ImageCompressor:
                 "compressed_size": compressed_size,
                 "compression_rate": round(compression_rate, 2),
                 "compression_level": level,
+                "lossless": lossless_output,
             }
+            if self._engine_used:
+                out["engine_used"] = self._engine_used
+            if strip_mode:
+                out["strip_mode"] = strip_mode
+            if estimate:
+                out["estimate"] = True
+            if target_bytes > 0:
+                out["target_size_kb"] = target_bytes // 1024
+                out["target_met"] = compressed_size <= target_bytes
             if warning:
                 out["warning"] = warning
             return out
@@ -430,6 +632,71 @@ class ImageCompressor:
             except OSError as cleanup_err:
                 logger.warning(f"Failed to cleanup temp compressed file {tmp_output_path}: {cleanup_err}")
 
+    def _compress_to_ssim(self, input_path, output_path, target_ssim, level, **options):
+        """Compress at the lowest quality whose output reaches `target_ssim` against the input.
+
+        Each candidate is a real `compress` into a registered temp file, decoded and scored
+        with `ssim_score` at the input's full resolution. The encoders are deterministic, so
+        the reported `ssim` is that of the file written at the chosen quality.
+        """
+        with Image.open(input_path) as source:
+            source_format = source.format or ""
+            if source_format not in SSIM_SEARCH_FORMATS:
+                return {
+                    "success": False,
+                    "error": f"[UNSUPPORTED_FORMAT] 按画质目标压缩仅支持 JPEG/WebP，当前为 {source_format or '?'}",
+                }
+            reference = source.convert("L")
+        # Level 1 is a byte copy and would ignore the searched quality.
+        level = max(CompressionLevel.LIGHT, level)
+        scores = {}
+        try:
+            low, high = 1, 100
+            while low < high:
+                middle = (low + high) // 2
+                failure = self._score_ssim_candidate(input_path, reference, scores, middle, level, options)
+                if failure:
+                    return failure
+                if scores[middle] >= target_ssim:
+                    high = middle
+                else:
+                    low = middle + 1
+            failure = self._score_ssim_candidate(input_path, reference, scores, low, level, options)
+            if failure:
+                return failure
+        finally:
+            reference.close()
+
+        result = self.compress(input_path, output_path, level=level, quality=low, **options)
+        if not result.get("success"):
+            return result
+        reached = scores[low] >= target_ssim
+        result.update(
+            {"quality": low, "ssim": round(scores[low], 4), "target_ssim": target_ssim, "target_reached": reached}
+        )
+        if not reached:
+            result["warning"] = _append_warning(
+                result.get("warning", ""), f"质量 100 时 SSIM 仍低于目标 {target_ssim}，已使用最高质量"
+            )
+        return result
+
+    def _score_ssim_candidate(self, input_path, reference, scores, quality, level, options):
+        """Store the SSIM of a `quality` encode in `scores`; returns the result of a failed encode."""
+        if quality in scores:
+            return None
+        candidate_path = TEMP_REGISTRY.create(suffix=Path(input_path).suffix or ".tmp")
+        try:
+            result = self.compress(
+                input_path, candidate_path, level=level, quality=quality, **{**options, "estimate": False}
+            )
+            if not result.get("success"):
+                return result
+            with Image.open(candidate_path) as encoded, encoded.convert("L") as candidate:
+                scores[quality] = ssim_score(reference, candidate)
+            return None
+        finally:
+            TEMP_REGISTRY.discard(candidate_path)
+
     def _compress_jpeg(
         self,
         img,
@@ -438,12 +705,13 @@ class ImageCompressor:
         level,
         engine="",
         target_bytes=0,
-        strip_metadata=False,
+        strip_mode="",
+        quality=0,
     ):
         """Compress JPEG using MoZJPEG or Pillow."""
         logger.info(f"Compressing JPEG (level: {level})")
 
-        quality = CompressionLevel.get_quality(level)
+        quality = CompressionLevel.get_quality(level, quality)
 
         use_mozjpeg = self.mozjpeg_available and engine in ("", "auto", "mozjpeg")
         force_pillow = engine in ("pillow",)
@@ -466,26 +734,29 @@ class ImageCompressor:
                 except OSError:
                     input_size = 0
                 if max_mozjpeg_bytes > 0 and input_size > max_mozjpeg_bytes:
-                    if strip_metadata:
+                    if strip_mode:
                         with open(input_path, "rb") as src, open(output_path, "wb") as dst:
-                            _copy_jpeg_without_metadata(src, dst)
+                            _copy_jpeg_without_metadata(src, dst, strip_mode)
                     else:
                         _copy_file_streaming(input_path, output_path)
+                    self._engine_used = "copy"
                     return
                 with open(input_path, "rb") as f:
                     jpeg_bytes = f.read()
                 optimized_bytes = mozjpeg_lossless_optimization.optimize(jpeg_bytes)
-                if strip_metadata:
-                    optimized_bytes = _strip_jpeg_metadata_bytes(optimized_bytes)
+                if strip_mode:
+                    optimized_bytes = _strip_jpeg_metadata_bytes(optimized_bytes, strip_mode)
                 with open(output_path, "wb") as f:
                     f.write(optimized_bytes)
+                self._engine_used = "mozjpeg"
                 return
             if level == CompressionLevel.LOSSLESS and (force_pillow or not use_mozjpeg):
-                if strip_metadata:
+                if strip_mode:
                     with open(input_path, "rb") as src, open(output_path, "wb") as dst:
-                        _copy_jpeg_without_metadata(src, dst)
+                        _copy_jpeg_without_metadata(src, dst, strip_mode)
                 else:
                     _copy_file_streaming(input_path, output_path)
+                self._engine_used = "copy"
                 return
 
             save_quality = 100 if level == CompressionLevel.LOSSLESS else int(q)
@@ -496,11 +767,15 @@ class ImageCompressor:
                 "optimize": True,
                 "progressive": (level != CompressionLevel.LOSSLESS),
             }
+            icc_profile = img.info.get("icc_profile")
+            if icc_profile and strip_mode != "all":
+                save_kwargs["icc_profile"] = icc_profile
             work.save(output_path, **save_kwargs)
+            self._engine_used = "pillow"
             if use_mozjpeg and not force_pillow and self.mozjpeg_available:
                 try:
                     max_mozjpeg_bytes = int(os.getenv("IMAGEFLOW_MOZJPEG_MAX_BYTES", str(48 * 1024 * 1024)))
-                    output_size = os.path.getsize(output_path)
+                    output_size = getsize_with_retry(output_path)
                     if max_mozjpeg_bytes > 0 and output_size > max_mozjpeg_bytes:
                         raise RuntimeError("mozjpeg skipped for large output")
                     with open(output_path, "rb") as f:
@@ -508,62 +783,39 @@ class ImageCompressor:
                     optimized_bytes = mozjpeg_lossless_optimization.optimize(jpeg_bytes)
                     with open(output_path, "wb") as f:
                         f.write(optimized_bytes)
+                    self._engine_used = "mozjpeg"
                 except Exception as e:
                     logger.warning(f"mozjpeg optimize failed: {e}")
-            if strip_metadata:
+            if strip_mode:
                 try:
-                    _strip_jpeg_metadata_file(output_path)
+                    _strip_jpeg_metadata_file(output_path, strip_mode)
                 except OSError as strip_err:
                     logger.warning(f"Failed to strip JPEG metadata for {output_path}: {strip_err}")
 
         if target_bytes > 0 and level == CompressionLevel.LOSSLESS:
             save_once(100)
             try:
-                if os.path.getsize(output_path) <= target_bytes:
+                if getsize_with_retry(output_path) <= target_bytes:
                     return lossless_warning
             except OSError:
                 logger.debug("Failed to stat JPEG output during target size check: %s", output_path)
-            return (lossless_warning + "，" if lossless_warning else "") + f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+            return (lossless_warning + "，" if lossless_warning else "") + _target_missed_warning(target_bytes, output_path)
 
         if target_bytes > 0:
-            import io as _io
-
             if img.mode in ("RGBA", "P", "LA"):
                 work = img.convert("RGB")
             else:
                 work = img
 
-            best_q = None
-            best_size = None
-            low = 5
-            high = int(quality)
-            last_size = None
-            stable_hits = 0
-            for _ in range(12):
-                if low > high:
-                    break
-                q = (low + high) // 2
-                buf = _io.BytesIO()
+            def encoded_size(q):
+                buf = BytesIO()
                 work.save(buf, format="JPEG", quality=q, optimize=False, progressive=True)
-                size = buf.tell()
-                buf.close()
-                if last_size == size:
-                    stable_hits += 1
-                else:
-                    stable_hits = 0
-                last_size = size
-                if size <= target_bytes:
-                    best_q = q
-                    best_size = size
-                    low = q + 1
-                else:
-                    high = q - 1
-                if stable_hits >= 2:
-                    break
-            if best_q is not None:
-                save_once(best_q)
-                return lossless_warning
-            return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+                return buf.tell()
+
+            # When nothing fits this is the smallest quality tried, so the caller still gets a file.
+            best_q, met = search_quality_for_size(encoded_size, target_bytes, int(quality))
+            save_once(best_q)
+            return lossless_warning if met else _target_missed_warning(target_bytes, output_path)
 
         save_once(quality)
         return lossless_warning
@@ -576,7 +828,9 @@ class ImageCompressor:
         level,
         engine="",
         target_bytes=0,
-        strip_metadata=False,
+        strip_mode="",
+        quality=0,
+        lossless=False,
     ):
         """Compress PNG using imagequant (lossy) or oxipng (lossless)."""
         logger.info(f"Compressing PNG (level: {level})")
@@ -586,7 +840,13 @@ class ImageCompressor:
         use_pngquant = self.imagequant_available and engine in ("", "auto", "pngquant", "imagequant")
         force_pillow = engine in ("pillow",)
 
-        quality = CompressionLevel.get_quality(level)
+        quality = CompressionLevel.get_quality(level, quality)
+
+        # "all" drops iCCP too; the other modes carry the source profile into the re-encode.
+        icc_kwargs = {"icc_profile": None if strip_mode == "all" else img.info.get("icc_profile")}
+
+        def oxipng_strip():
+            return oxipng.StripChunks.all() if strip_mode == "all" else oxipng.StripChunks.safe()
 
         def oxipng_level_for(lvl):
             if lvl <= CompressionLevel.LIGHT:
@@ -595,18 +855,20 @@ class ImageCompressor:
                 return 4
             return 6
 
-        def save_lossless():
-            save_kwargs = {"format": "PNG", "optimize": True}
+        def save_lossless(oxipng_level=None):
+            save_kwargs = {"format": "PNG", "optimize": True, **icc_kwargs}
             img.save(output_path, **save_kwargs)
+            self._engine_used = "pillow"
             if use_oxipng and not force_pillow:
                 try:
-                    strip_mode = oxipng.StripChunks.safe()
+                    strip = oxipng_strip()
                     oxipng.optimize(
                         output_path,
                         output_path,
-                        level=oxipng_level_for(level),
-                        strip=strip_mode,
+                        level=oxipng_level or oxipng_level_for(level),
+                        strip=strip,
                     )
+                    self._engine_used = "oxipng"
                 except Exception as e:
                     logger.warning(f"OxiPNG optimization failed: {e}")
 
@@ -623,17 +885,19 @@ class ImageCompressor:
                                 max_colors=256,
                                 dithering_level=1.0,
                             )
-                            save_kwargs = {"format": "PNG"}
+                            save_kwargs = {"format": "PNG", **icc_kwargs}
                             quantized_img.save(output_path, **save_kwargs)
+                            self._engine_used = "pngquant"
                             if use_oxipng and not force_pillow:
                                 try:
-                                    strip_mode = oxipng.StripChunks.safe()
+                                    strip = oxipng_strip()
                                     oxipng.optimize(
                                         output_path,
                                         output_path,
                                         level=oxipng_level_for(level),
-                                        strip=strip_mode,
+                                        strip=strip,
                                     )
+                                    self._engine_used += "+oxipng"
                                 except Exception as e:
                                     logger.warning(f"OxiPNG optimization failed: {e}")
                         finally:
@@ -652,17 +916,19 @@ class ImageCompressor:
                                 max_colors=256,
                                 dithering_level=1.0,
                             )
-                            save_kwargs = {"format": "PNG"}
+                            save_kwargs = {"format": "PNG", **icc_kwargs}
                             quantized_img.save(output_path, **save_kwargs)
+                            self._engine_used = "pngquant"
                             if use_oxipng and not force_pillow:
                                 try:
-                                    strip_mode = oxipng.StripChunks.safe()
+                                    strip = oxipng_strip()
                                     oxipng.optimize(
                                         output_path,
                                         output_path,
                                         level=oxipng_level_for(level),
-                                        strip=strip_mode,
+                                        strip=strip,
                                     )
+                                    self._engine_used += "+oxipng"
                                 except Exception as e:
                                     logger.warning(f"OxiPNG optimization failed: {e}")
                         finally:
@@ -672,22 +938,25 @@ class ImageCompressor:
                                 rgba_img.close()
                         return
                     if img.mode == "P":
-                        save_kwargs = {"format": "PNG", "optimize": True}
+                        save_kwargs = {"format": "PNG", "optimize": True, **icc_kwargs}
                         img.save(output_path, **save_kwargs)
+                        self._engine_used = "pillow"
                         if use_oxipng and not force_pillow:
                             try:
-                                strip_mode = oxipng.StripChunks.safe()
+                                strip = oxipng_strip()
                                 oxipng.optimize(
                                     output_path,
                                     output_path,
                                     level=2,
-                                    strip=strip_mode,
+                                    strip=strip,
                                 )
+                                self._engine_used += "+oxipng"
                             except Exception as e:
                                 logger.warning(f"OxiPNG optimization failed: {e}")
                         return
-                    save_kwargs = {"format": "PNG", "optimize": True}
+                    save_kwargs = {"format": "PNG", "optimize": True, **icc_kwargs}
                     img.save(output_path, **save_kwargs)
+                    self._engine_used = "pillow"
                     return
                 except Exception as e:
                     logger.warning(f"pngquant compression failed: {e}")
@@ -698,34 +967,42 @@ class ImageCompressor:
                 quantize_method = 2 if img.mode in ("RGBA", "LA") else 0
                 quantized = img.quantize(colors=colors, method=quantize_method, dither=1)
                 try:
-                    save_kwargs = {"format": "PNG"}
+                    save_kwargs = {"format": "PNG", **icc_kwargs}
                     quantized.save(output_path, **save_kwargs)
+                    self._engine_used = "pillow"
                     if use_oxipng and not force_pillow:
                         try:
-                            strip_mode = oxipng.StripChunks.safe()
+                            strip = oxipng_strip()
                             oxipng.optimize(
                                 output_path,
                                 output_path,
                                 level=oxipng_level_for(level),
-                                strip=strip_mode,
+                                strip=strip,
                             )
+                            self._engine_used += "+oxipng"
                         except Exception as e:
                             logger.warning(f"OxiPNG optimization failed: {e}")
                 finally:
                     quantized.close()
             else:
-                save_kwargs = {"format": "PNG", "optimize": True, "compress_level": 9}
+                save_kwargs = {"format": "PNG", "optimize": True, "compress_level": 9, **icc_kwargs}
                 img.save(output_path, **save_kwargs)
+                self._engine_used = "pillow"
 
-        if level == CompressionLevel.LOSSLESS or engine == "oxipng":
+        if lossless or level == CompressionLevel.LOSSLESS or engine == "oxipng":
             save_lossless()
             if target_bytes > 0:
                 try:
-                    if os.path.getsize(output_path) <= target_bytes:
+                    if getsize_with_retry(output_path) <= target_bytes:
                         return ""
+                    # Lossless output can only shrink further with oxipng's slowest preset.
+                    if use_oxipng and not force_pillow and oxipng_level_for(level) < 6:
+                        save_lossless(oxipng_level=6)
+                        if getsize_with_retry(output_path) <= target_bytes:
+                            return ""
                 except OSError:
                     logger.debug("Failed to stat PNG output during target size check: %s", output_path)
-                return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+                return _target_missed_warning(target_bytes, output_path)
             return ""
 
         if target_bytes > 0:
@@ -758,7 +1035,7 @@ class ImageCompressor:
                 last_q = q
                 save_for_quality(q)
                 try:
-                    size = os.path.getsize(output_path)
+                    size = getsize_with_retry(output_path)
                 except OSError:
                     break
                 if last_size == size:
@@ -779,7 +1056,7 @@ class ImageCompressor:
                 if best_size is None or best_q != last_q:
                     save_for_quality(best_q)
                 return ""
-            return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+            return _target_missed_warning(target_bytes, output_path)
 
         min_quality = max(0, quality - 10)
         max_quality = min(100, quality + 10)
@@ -794,66 +1071,53 @@ class ImageCompressor:
         level,
         engine="",
         target_bytes=0,
-        strip_metadata=False,
+        strip_mode="",
+        quality=0,
+        lossless=False,
     ):
         """Compress WEBP using Pillow with quality control."""
         logger.info(f"Compressing WEBP (level: {level})")
 
-        quality = CompressionLevel.get_quality(level)
+        quality = CompressionLevel.get_quality(level, quality)
+
+        lossless = lossless or level == CompressionLevel.LOSSLESS
+        icc_profile = None if strip_mode == "all" else img.info.get("icc_profile")
 
         def save_once(q):
-            if level == CompressionLevel.LOSSLESS:
+            if lossless:
                 save_kwargs = {"format": "WEBP", "lossless": True, "method": 6}
             else:
                 q2 = max(1, min(100, int(q)))
                 save_kwargs = {"format": "WEBP", "quality": q2, "method": 6}
+            if icc_profile:
+                save_kwargs["icc_profile"] = icc_profile
             img.save(output_path, **save_kwargs)
+            self._engine_used = "pillow"
 
-        if target_bytes > 0 and level == CompressionLevel.LOSSLESS:
+        if target_bytes > 0 and lossless:
             save_once(100)
             try:
-                if os.path.getsize(output_path) <= target_bytes:
+                if getsize_with_retry(output_path) <= target_bytes:
                     return ""
             except OSError:
                 logger.debug("Failed to stat WEBP output during target size check: %s", output_path)
-            return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+            return _target_missed_warning(target_bytes, output_path)
 
-        if target_bytes > 0 and level != CompressionLevel.LOSSLESS:
-            best_q = None
-            best_size = None
-            low = 5
-            high = int(quality)
-            last_size = None
-            stable_hits = 0
-            last_q = None
-            for _ in range(12):
-                if low > high:
-                    break
-                q = (low + high) // 2
-                last_q = q
+        if target_bytes > 0 and not lossless:
+            saved = []
+
+            def encoded_size(q):
                 save_once(q)
+                saved.append(q)
                 try:
-                    size = os.path.getsize(output_path)
+                    return getsize_with_retry(output_path)
                 except OSError:
-                    break
-                if last_size == size:
-                    stable_hits += 1
-                else:
-                    stable_hits = 0
-                last_size = size
-                if size <= target_bytes:
-                    best_q = q
-                    best_size = size
-                    low = q + 1
-                else:
-                    high = q - 1
-                if stable_hits >= 2:
-                    break
-            if best_q is not None:
-                if best_size is None or best_q != last_q:
-                    save_once(best_q)
-                return ""
-            return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+                    return None
+
+            best_q, met = search_quality_for_size(encoded_size, target_bytes, int(quality))
+            if not saved or saved[-1] != best_q:
+                save_once(best_q)
+            return "" if met else _target_missed_warning(target_bytes, output_path)
 
         save_once(quality)
         return ""
@@ -866,34 +1130,47 @@ class ImageCompressor:
         level,
         engine="",
         target_bytes=0,
-        strip_metadata=False,
+        strip_mode="",
+        quality=0,
+        lossless=False,
     ):
         """Fallback compression for unsupported formats."""
         logger.warning(f"Unsupported format, using fallback compression")
 
-        quality = CompressionLevel.get_quality(level)
+        quality = CompressionLevel.get_quality(level, quality)
 
         # Try to save with optimization
         try:
             save_kwargs = {"format": img.format, "optimize": True}
-            if level != CompressionLevel.LOSSLESS:
+            if lossless and img.format == "AVIF":
+                # libavif is only exact at q100 with full-range 4:4:4 samples.
+                save_kwargs = {"format": "AVIF", "quality": 100, "subsampling": "4:4:4", "range": "full", "speed": 6}
+            elif level != CompressionLevel.LOSSLESS:
                 save_kwargs["quality"] = quality
             img.save(output_path, **save_kwargs)
         except Exception as e:
             # Just save without optimization
             logger.warning(f"Optimization failed: {e}, saving without optimization")
             img.save(output_path)
+        self._engine_used = "pillow"
 
         if target_bytes > 0:
             try:
-                if os.path.getsize(output_path) <= target_bytes:
+                if getsize_with_retry(output_path) <= target_bytes:
                     return ""
             except OSError:
                 logger.debug("Failed to stat fallback output during target size check: %s", output_path)
-            return f"目标大小 {int(target_bytes / 1024)}KB 未达成，已输出最小可得文件"
+            return _target_missed_warning(target_bytes, output_path)
         return ""
 
 
+def _log_request(input_path, output_path, level, engine):
+    # The request carries `level` and `engine`; there is no separate compression mode.
+    logger.info(
+        f"Received compression request: {input_path} -> {output_path} (level: {level}, engine: {engine or 'auto'})"
+    )
+
+
 def process(input_data):
     """
     Process function used by the desktop API engine bridge.
@@ -912,9 +1189,15 @@ def process(input_data):
         engine = input_data.get("engine", "")
         target_size_kb = input_data.get("target_size_kb", 0)
         strip_metadata = input_data.get("strip_metadata", False)
-
-        # Validate required parameters
-        if not input_path or not output_path:
+        strip_mode = input_data.get("strip_mode", "")
+        quality = input_data.get("quality", 0)
+        estimate = bool(input_data.get("estimate", False))
+        lossless = bool(input_data.get("lossless", False))
+        target_ssim = input_data.get("target_ssim", 0)
+        _log_request(input_path, output_path, level, engine)
+
+        # Validate required parameters; an estimate writes no output.
+        if not input_path or not (output_path or estimate):
             return {
                 "success": False,
                 "error": "Missing required parameters: input_path or output_path",
@@ -929,6 +1212,11 @@ def process(input_data):
             engine=engine,
             target_size_kb=target_size_kb,
             strip_metadata=strip_metadata,
+            strip_mode=strip_mode,
+            quality=quality,
+            estimate=estimate,
+            lossless=lossless,
+            target_ssim=target_ssim,
         )
 
         return result
@@ -943,9 +1231,6 @@ def main():
     try:
         # Read input from stdin
         input_data = json.load(sys.stdin)
-        logger.info(
-            f"Received compression request: {input_data.get('input_path')} -> {input_data.get('output_path')}"
-        )
 
         # Extract parameters
         input_path = input_data.get("input_path")
@@ -954,9 +1239,15 @@ def main():
         engine = input_data.get("engine", "")
         target_size_kb = input_data.get("target_size_kb", 0)
         strip_metadata = input_data.get("strip_metadata", False)
-
-        # Validate required parameters
-        if not input_path or not output_path:
+        strip_mode = input_data.get("strip_mode", "")
+        quality = input_data.get("quality", 0)
+        estimate = bool(input_data.get("estimate", False))
+        lossless = bool(input_data.get("lossless", False))
+        target_ssim = input_data.get("target_ssim", 0)
+        _log_request(input_path, output_path, level, engine)
+
+        # Validate required parameters; an estimate writes no output.
+        if not input_path or not (output_path or estimate):
             result = {
                 "success": False,
                 "error": "Missing required parameters: input_path or output_path",
@@ -971,6 +1262,11 @@ def main():
                 engine=engine,
                 target_size_kb=target_size_kb,
                 strip_metadata=strip_metadata,
+                strip_mode=strip_mode,
+                quality=quality,
+                estimate=estimate,
+                lossless=lossless,
+                target_ssim=target_ssim,
             )
 
         # Write result to stdout
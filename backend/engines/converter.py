@@ -16,23 +16,34 @@ Usage:
 
 import sys
 import json
+import math
 import os
 import io
 import re
 import shutil
 import subprocess
-import tempfile
 from pathlib import Path
-from PIL import Image
+from PIL import Image, ImageChops, ImageOps
 import logging
 import time
 
+from temp_registry import TEMP_REGISTRY, getsize_with_retry, replace_with_retry
+from compressor import _target_missed_warning, search_quality_for_size
+
 # Configure logging
 logger = logging.getLogger(__name__)
 _PROFILE_ENABLED = os.getenv("IMAGEFLOW_PROFILE") == "1"
 SVG_DIMENSION_SCAN_BYTES = 256 * 1024
 MAX_SVG_EDGE = 8192
 MAX_SVG_PIXELS = 16_000_000
+# Default `max_megapixels`: well above real photos, low enough that a crafted header
+# claiming tens of thousands of pixels per side fails before any pixel is decoded.
+DEFAULT_MAX_MEGAPIXELS = 256
+# Targets that store a resolution tag; `dpi` is ignored for the rest.
+DPI_OUTPUT_FORMATS = frozenset({"jpg", "jpeg", "png", "tiff", "tif"})
+MAX_DPI = 10000
+# Metadata Pillow copies from img.info on save; `strip_metadata` drops it along with EXIF.
+STRIPPED_INFO_KEYS = ('comment', 'xmp', 'XML:com.adobe.xmp')
 _SVG_UNSAFE_PATTERN = re.compile(
     r"(?is)"
     r"(<!DOCTYPE\b|<!ENTITY\b|"
@@ -222,12 +233,555 @@ def _with_single_ico_size_suffix(output_path: str, ico_sizes) -> str:
     return str(target.with_name(next_name))
 
 
+def written_ico_sizes(path):
+    """Frame edges actually stored in an .ico file, read back from its directory."""
+    try:
+        with Image.open(path) as saved:
+            return sorted({width for width, _height in saved.info.get('sizes', ())})
+    except Exception:
+        return []
+
+
+def convert_cmyk_to_srgb(img):
+    """Return an sRGB copy of a CMYK image, using its embedded ICC profile when present."""
+    return cmyk_to_srgb(img)[0]
+
+
+def cmyk_to_srgb(img):
+    """`(sRGB copy, used_embedded_profile)`; without a usable profile Pillow's default transform is used."""
+    icc_bytes = img.info.get("icc_profile")
+    if icc_bytes:
+        try:
+            from PIL import ImageCms
+
+            source_profile = ImageCms.ImageCmsProfile(io.BytesIO(icc_bytes))
+            target_profile = ImageCms.createProfile("sRGB")
+            converted = ImageCms.profileToProfile(img, source_profile, target_profile, outputMode="RGB")
+            if converted is not None:
+                return converted, True
+        except Exception as e:
+            logger.warning(f"ICC-based CMYK conversion failed, falling back to naive conversion: {e}")
+    return img.convert("RGB"), False
+
+
+def convert_icc_to_srgb(img, icc_bytes):
+    """Return an sRGB copy of an RGB/RGBA image tagged with `icc_bytes`, or None if it cannot be transformed."""
+    if img.mode not in ("RGB", "RGBA") or not icc_bytes:
+        return None
+    try:
+        from PIL import ImageCms
+
+        source_profile = ImageCms.ImageCmsProfile(io.BytesIO(icc_bytes))
+        target_profile = ImageCms.createProfile("sRGB")
+        return ImageCms.profileToProfile(img, source_profile, target_profile, outputMode=img.mode)
+    except Exception as e:
+        logger.warning(f"ICC to sRGB conversion failed: {e}")
+        return None
+
+
+def exif_with_srgb_color_space(exif_bytes):
+    """Rewrite EXIF ColorSpace to sRGB so kept metadata matches pixels that were converted to sRGB."""
+    try:
+        exif = Image.Exif()
+        exif.load(exif_bytes)
+        exif_ifd = exif.get_ifd(0x8769)
+        if exif_ifd.get(0xA001) in (None, 1):
+            return exif_bytes
+        exif_ifd[0xA001] = 1
+        return exif.tobytes()
+    except Exception as e:
+        logger.warning(f"Failed to update EXIF ColorSpace: {e}")
+        return exif_bytes
+
+
+EXIF_ORIENTATION_TAG = 0x0112
+
+
+def exif_with_normal_orientation(exif_bytes):
+    """Reset EXIF Orientation to 1 once the pixels have been physically rotated."""
+    try:
+        exif = Image.Exif()
+        exif.load(exif_bytes)
+        if exif.get(EXIF_ORIENTATION_TAG) in (None, 1):
+            return exif_bytes
+        exif[EXIF_ORIENTATION_TAG] = 1
+        return exif.tobytes()
+    except Exception as e:
+        logger.warning(f"Failed to reset EXIF Orientation: {e}")
+        return exif_bytes
+
+
+# Built-in names beyond LittleCMS's own sRGB are resolved from the OS profile folders.
+_SYSTEM_PROFILE_DIRS = (
+    os.path.join(os.environ.get("SystemRoot", r"C:\Windows"), "System32", "spool", "drivers", "color"),
+    "/Library/ColorSync/Profiles",
+    "/System/Library/ColorSync/Profiles",
+    "/usr/share/color/icc",
+    "/usr/share/color/icc/colord",
+    os.path.expanduser("~/.local/share/icc"),
+)
+BUILTIN_PROFILE_FILES = {
+    "adobergb": ("AdobeRGB1998.icc", "Adobe RGB (1998).icc", "AdobeRGB.icc", "compatibleWithAdobeRGB1998.icc"),
+    "displayp3": ("Display P3.icc", "DisplayP3.icc", "Display-P3.icc"),
+}
+ICC_OUTPUT_FORMATS = frozenset({"jpg", "jpeg", "png", "webp", "tiff", "tif", "avif", "jxl"})
+COLOR_PROFILE_MODES = ("assign", "convert")
+
+
+def _builtin_profile_key(name):
+    return re.sub(r"[^a-z0-9]", "", str(name or "").lower())
+
+
+def resolve_color_profile(profile):
+    """Load a built-in profile name or a `.icc`/`.icm` path; returns (ImageCmsProfile, label).
+
+    Raises ValueError carrying an error-code prefix the host shows verbatim.
+    """
+    from PIL import ImageCms
+
+    value = str(profile or "").strip()
+    if not value:
+        raise ValueError("[BAD_INPUT] Missing profile")
+    key = _builtin_profile_key(value)
+    if key == "srgb":
+        return ImageCms.ImageCmsProfile(ImageCms.createProfile("sRGB")), "sRGB"
+    if key in BUILTIN_PROFILE_FILES:
+        for folder in _SYSTEM_PROFILE_DIRS:
+            for filename in BUILTIN_PROFILE_FILES[key]:
+                candidate = os.path.join(folder, filename)
+                if os.path.isfile(candidate):
+                    value = candidate
+                    break
+            else:
+                continue
+            break
+        else:
+            raise ValueError(f"[NOT_FOUND] 系统中未找到内置配置文件 {profile}，请改用 .icc 文件路径")
+    if not os.path.isfile(value):
+        raise ValueError(f"[NOT_FOUND] Profile file not found: {value}")
+    try:
+        loaded = ImageCms.getOpenProfile(value)
+    except (ImageCms.PyCMSError, OSError) as e:
+        raise ValueError(f"[BAD_INPUT] 无效的 ICC 配置文件: {e}")
+    label = ""
+    try:
+        label = ImageCms.getProfileDescription(loaded).strip()
+    except ImageCms.PyCMSError:
+        pass
+    return loaded, label or Path(value).stem
+
+
+def _profile_color_space(profile):
+    return str(getattr(profile.profile, "xcolor_space", "") or "").strip().upper()
+
+
+def apply_color_profile(input_path, output_path, profile, mode="assign"):
+    """Embed (`assign`) or transform pixels into (`convert`) the given ICC profile.
+
+    `assign` keeps pixel values and only retags them; `convert` runs a LittleCMS
+    transform from the embedded profile (sRGB when none) to the target.
+    """
+    from PIL import ImageCms
+
+    mode = str(mode or "assign").strip().lower()
+    if mode not in COLOR_PROFILE_MODES:
+        return {'success': False, 'error': f'[BAD_INPUT] Unknown profile mode: {mode}'}
+    format_type = Path(str(output_path or "")).suffix.lower().lstrip(".")
+    if format_type not in ICC_OUTPUT_FORMATS:
+        return {'success': False, 'error': f'[UNSUPPORTED_FORMAT] {format_type or "?"} 不支持嵌入 ICC 配置文件'}
+    try:
+        target_profile, label = resolve_color_profile(profile)
+    except ValueError as e:
+        return {'success': False, 'error': str(e)}
+    if _profile_color_space(target_profile) not in ("RGB", "GRAY"):
+        return {'success': False, 'error': '[BAD_INPUT] 仅支持 RGB 或灰度配置文件'}
+
+    img = None
+    tmp_output_path = None
+    try:
+        img = Image.open(input_path)
+        img.load()
+        exif_bytes = img.info.get('exif')
+        source_icc = img.info.get('icc_profile')
+        target_space = _profile_color_space(target_profile)
+        converted = False
+
+        if mode == "convert":
+            if source_icc:
+                source_profile = ImageCms.ImageCmsProfile(io.BytesIO(source_icc))
+            else:
+                source_profile = ImageCms.ImageCmsProfile(ImageCms.createProfile("sRGB"))
+            source_space = _profile_color_space(source_profile)
+            if source_space == "CMYK" and img.mode == "CMYK":
+                work = img
+            elif source_space == "GRAY":
+                work = img if img.mode == "L" else img.convert("L")
+            else:
+                has_alpha = 'A' in img.getbands() or 'transparency' in img.info
+                work = img if img.mode in ("RGB", "RGBA") else img.convert("RGBA" if has_alpha else "RGB")
+            if target_space == "GRAY":
+                output_mode = "L"
+            else:
+                output_mode = "RGBA" if work.mode == "RGBA" else "RGB"
+            transformed = ImageCms.profileToProfile(
+                work, source_profile, target_profile, outputMode=output_mode
+            )
+            if work is not img:
+                work.close()
+            img = _replace_image_ref(img, transformed)
+            converted = True
+        else:
+            if img.mode == "CMYK" or (target_space == "GRAY") != (img.mode in ("L", "LA")):
+                return {'success': False, 'error': f'[BAD_INPUT] 图像模式 {img.mode} 与配置文件色彩空间不符，请使用 convert 模式'}
+
+        if format_type in ("jpg", "jpeg") and img.mode not in ("RGB", "L"):
+            img = _replace_image_ref(img, ImageConverter()._flatten_alpha(img) if 'A' in img.getbands() else img.convert("RGB"))
+
+        save_params = {'icc_profile': target_profile.tobytes()}
+        if exif_bytes:
+            save_params['exif'] = exif_bytes
+        if format_type in ("jpg", "jpeg", "webp", "avif"):
+            save_params['quality'] = 95
+        output_dir = os.path.dirname(os.path.abspath(output_path)) or "."
+        os.makedirs(output_dir, exist_ok=True)
+        tmp_output_path = TEMP_REGISTRY.create(suffix=Path(output_path).suffix or ".tmp", dir=output_dir)
+        img.save(tmp_output_path, format=ImageConverter()._convert_format_name(format_type), **save_params)
+        replace_with_retry(tmp_output_path, output_path)
+        TEMP_REGISTRY.unregister(tmp_output_path)
+        tmp_output_path = None
+
+        return {
+            'success': True,
+            'input_path': input_path,
+            'output_path': output_path,
+            'mode': mode,
+            'converted': converted,
+            'profile': label,
+            'had_embedded_profile': bool(source_icc),
+        }
+    except FileNotFoundError:
+        return {'success': False, 'error': f'[NOT_FOUND] Input file not found: {input_path}'}
+    except Exception as e:
+        logger.error(f"Color profile operation failed: {e}", exc_info=True)
+        return {'success': False, 'error': str(e)}
+    finally:
+        if tmp_output_path:
+            TEMP_REGISTRY.discard(tmp_output_path)
+        if img is not None:
+            try:
+                img.close()
+            except Exception:
+                pass
+
+
+def _replace_image_ref(current, replacement):
+    if replacement is not current:
+        try:
+            current.close()
+        except Exception:
+            pass
+    return replacement
+
+
+# Formats whose Pillow encoder can be asked for an exact (lossless) encode.
+LOSSLESS_FORMATS = frozenset({"webp", "avif", "jxl"})
+# Targets that cannot store alpha, so transparency is composited onto a solid fill.
+NO_ALPHA_FORMATS = frozenset({"jpg", "jpeg", "bmp", "pdf"})
+DEFAULT_BACKGROUND = (255, 255, 255)
+_HEX_COLOR_RE = re.compile(r'^#?(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$')
+# Pillow's JPEG `subsampling` argument for each chroma ratio.
+JPEG_SUBSAMPLING = {'4:4:4': 0, '4:2:2': 1, '4:2:0': 2}
+# TIFF `compression` names as Pillow/libtiff spell them; LZW is lossless and widely readable.
+TIFF_COMPRESSION = {'none': 'raw', 'lzw': 'tiff_lzw', 'deflate': 'tiff_adobe_deflate', 'packbits': 'packbits'}
+DEFAULT_TIFF_COMPRESSION = 'lzw'
+# `cover` crop anchors (and `pad` placement) as ImageOps.fit/pad centering (x, y) fractions.
+CROP_ANCHORS = {
+    'center': (0.5, 0.5),
+    'top': (0.5, 0.0),
+    'bottom': (0.5, 1.0),
+    'left': (0.0, 0.5),
+    'right': (1.0, 0.5),
+}
+
+
+def cover_scale_size(source_w, source_h, target_w, target_h):
+    """Smallest aspect-preserving size that fully covers target_w x target_h."""
+    scale = max(target_w / float(source_w), target_h / float(source_h))
+    return max(target_w, int(math.ceil(source_w * scale))), max(target_h, int(math.ceil(source_h * scale)))
+
+
+def megapixel_limit_error(width, height, max_megapixels):
+    """`[IMAGE_TOO_LARGE]` message when `width`x`height` is over `max_megapixels`, else ''."""
+    if int(width) * int(height) <= int(max_megapixels) * 1_000_000:
+        return ''
+    return f'[IMAGE_TOO_LARGE] image exceeds {max_megapixels} megapixels ({width}x{height})'
+
+
+def source_dpi(img):
+    """The (x, y) resolution the source file declares, or None when it has none."""
+    dpi = img.info.get('dpi')
+    try:
+        x_dpi, y_dpi = (float(value) for value in dpi)
+    except (TypeError, ValueError):
+        return None
+    if x_dpi <= 0 or y_dpi <= 0:
+        return None
+    return x_dpi, y_dpi
+
+
+def pad_fill_color(pad_color, format_type, background=None):
+    """
+    RGBA fill for `pad`: the requested colour, else transparent where the target has alpha
+    and the flatten background (white unless background_color is set) otherwise.
+    """
+    if pad_color:
+        return parse_hex_color(pad_color) + (255,)
+    if format_type in NO_ALPHA_FORMATS:
+        return tuple(background or DEFAULT_BACKGROUND) + (255,)
+    return (0, 0, 0, 0)
+
+
+def pad_to_size(img, target_w, target_h, fill, centering=(0.5, 0.5)):
+    """Fit `img` inside target_w x target_h keeping its aspect ratio and fill the rest with `fill` (RGBA)."""
+    scale = min(target_w / float(img.size[0]), target_h / float(img.size[1]))
+    resample = Image.Resampling.BILINEAR if scale < 0.85 else Image.Resampling.LANCZOS
+    if img.mode == 'I' and fill[3] == 255:
+        # 16-bit grey stays 16-bit; the fill becomes its grey level.
+        grey = int(round(0.299 * fill[0] + 0.587 * fill[1] + 0.114 * fill[2])) * 257
+        return ImageOps.pad(img, (target_w, target_h), resample, color=grey, centering=centering)
+    has_alpha = 'A' in img.getbands() or (img.mode == 'P' and 'transparency' in img.info)
+    canvas_mode = 'RGBA' if has_alpha or fill[3] < 255 else 'RGB'
+    source = img
+    if img.mode == 'I':
+        source = img.point(lambda v: v / 257.0).convert('L')
+    if source.mode != canvas_mode:
+        converted = source.convert(canvas_mode)
+        if source is not img:
+            source.close()
+        source = converted
+    color = fill if canvas_mode == 'RGBA' else fill[:3]
+    try:
+        return ImageOps.pad(source, (target_w, target_h), resample, color=color, centering=centering)
+    finally:
+        if source is not img:
+            source.close()
+
+
+def parse_hex_color(value):
+    """Parse `#RGB` / `#RRGGBB` (leading # optional) into an RGB tuple, or None if malformed."""
+    text = str(value or '').strip()
+    if not _HEX_COLOR_RE.match(text):
+        return None
+    digits = text.lstrip('#')
+    if len(digits) == 3:
+        digits = ''.join(ch * 2 for ch in digits)
+    return tuple(int(digits[i:i + 2], 16) for i in range(0, 6, 2))
+
+
+def avif_encoder_available():
+    """Pillow >= 11.2 ships AVIF; older builds need the pillow-avif-plugin registration import."""
+    try:
+        import pillow_avif  # noqa: F401
+    except ImportError:
+        pass
+    Image.init()
+    return "AVIF" in Image.SAVE
+
+
+def jxl_plugin_available():
+    """JPEG XL is only available through pillow-jxl-plugin; importing it registers open and save."""
+    try:
+        import pillow_jxl  # noqa: F401
+    except ImportError:
+        return False
+    Image.init()
+    return "JXL" in Image.SAVE
+
+
+def jxl_distance_from_quality(quality):
+    """libjxl's quality-to-Butteraugli-distance mapping; 100 means mathematically lossless."""
+    q = max(0, min(100, int(quality)))
+    if q >= 100:
+        return 0.0
+    if q >= 30:
+        return round(0.1 + (100 - q) * 0.09, 3)
+    return round(53.0 / 3000.0 * q * q - 23.0 / 20.0 * q + 25.0, 3)
+
+
+# Register optional decoders up front so previews and info can read these inputs too.
+jxl_plugin_available()
+
+
+HIGH_BIT_DEPTH_MODES = frozenset({"I;16", "I;16L", "I;16B", "I;16N", "I", "F"})
+SIXTEEN_BIT_FORMATS = frozenset({"png", "tif", "tiff"})
+UINT16_MAX = 65535
+
+
+def _reinhard_tone_map(img):
+    from PIL import ImageMath
+
+    return ImageMath.lambda_eval(lambda args: args["a"] / (args["a"] + 1.0), a=img)
+
+
+def bit_depth_request(input_data):
+    """`preserve_bit_depth` (or the older `preserve_16bit`) as True/False, None when the request leaves it unset."""
+    value = input_data.get('preserve_bit_depth', input_data.get('preserve_16bit'))
+    return None if value is None else bool(value)
+
+
+def normalize_high_bit_depth(img, keep_16bit):
+    """
+    Bring 16-bit/HDR single-channel pixels into an encodable range.
+
+    Samples are normalized to 0-1 (16-bit integers are divided by 65535, float data is
+    taken as-is). Anything above 1.0 is treated as HDR and Reinhard tone-mapped. The
+    result is an "I" image holding 0-65535 samples when keep_16bit is set, otherwise
+    an 8-bit "L" image, so Pillow never silently clips 16-bit data to white.
+
+    Returns:
+        tuple: (image, tone_mapped)
+    """
+    if img.mode not in HIGH_BIT_DEPTH_MODES:
+        return img, False
+
+    if img.mode == "F":
+        normalized = img
+    else:
+        integer = img if img.mode == "I" else img.convert("I")
+        normalized = integer.convert("F").point(lambda v: v * (1.0 / UINT16_MAX))
+        if integer is not img:
+            integer.close()
+
+    _low, high = normalized.getextrema()
+    tone_mapped = high > 1.0
+    if tone_mapped:
+        mapped = _reinhard_tone_map(normalized)
+        if normalized is not img:
+            normalized.close()
+        normalized = mapped
+
+    scale = UINT16_MAX if keep_16bit else 255
+    scaled = normalized.point(lambda v: v * scale)
+    result = scaled.convert("I" if keep_16bit else "L")
+    for intermediate in (normalized, scaled):
+        if intermediate is not img:
+            intermediate.close()
+    return result, tone_mapped
+
+
+def bits_per_sample(mode):
+    """Per-channel bit depth an image in `mode` is written with ("I" only ever holds 0-65535 here)."""
+    if mode == "1":
+        return 1
+    if mode == "F":
+        return 32
+    if mode == "I" or str(mode).startswith("I;16"):
+        return 16
+    return 8
+
+
+def is_16bit_color_source(img, input_path):
+    """True for 48/64-bit colour PNG/TIFF, which Pillow can only decode as 8 bits per channel."""
+    try:
+        if img.format == "PNG":
+            with open(input_path, "rb") as handle:
+                header = handle.read(26)
+            return len(header) == 26 and header[24] == 16 and header[25] in (2, 6)
+        if img.format == "TIFF":
+            bits = img.tag_v2.get(258)
+            return isinstance(bits, tuple) and len(bits) >= 3 and max(bits) > 8
+    except (OSError, AttributeError):
+        return False
+    return False
+
+
+def prepare_16bit_for_save(img, format_type):
+    """TIFF stores "I" as 32-bit samples; narrow it to a true 16-bit layout on save."""
+    if img.mode == "I" and str(format_type or "").lower() in {"tif", "tiff"}:
+        return img.convert("I;16")
+    return img
+
+
+# XMP properties camera/drone firmware uses to record roll at capture time.
+# Positive values mean the camera was rolled clockwise.
+LEVEL_ROLL_XMP_TAGS = (
+    "GPano:PoseRollDegrees",
+    "drone-dji:GimbalRollDegree",
+    "drone-dji:FlightRollDegree",
+)
+# Straightening crops to the rectangle left inside the rotated frame, which shrinks to
+# nothing as the roll nears 90 degrees; larger rolls are a sideways shot, not a tilt.
+MAX_LEVEL_ROLL_DEGREES = 45.0
+
+
+def trim_border_box(img, tolerance=0):
+    """
+    Crop box of the content inside a uniform or fully transparent border.
+
+    The border colour is the top-left pixel. With an alpha channel and a transparent
+    corner, every pixel whose alpha is at most `tolerance` counts as border; otherwise a
+    pixel is border when no channel differs from the corner by more than `tolerance`.
+
+    Returns:
+        tuple | None: (left, top, right, bottom), or None when there is nothing to trim
+        (no border, or the whole image is border)
+    """
+    probe = img
+    if img.mode in HIGH_BIT_DEPTH_MODES:
+        probe, _tone_mapped = normalize_high_bit_depth(img, False)
+    has_alpha = 'A' in probe.getbands() or (probe.mode == 'P' and 'transparency' in probe.info)
+    target_mode = 'RGBA' if has_alpha else 'RGB'
+    converted = probe if probe.mode == target_mode else probe.convert(target_mode)
+    try:
+        corner = converted.getpixel((0, 0))
+        if has_alpha and corner[3] <= tolerance:
+            content = converted.getchannel('A').point(lambda v: 255 if v > tolerance else 0)
+        else:
+            diff = ImageChops.difference(converted, Image.new(target_mode, converted.size, corner))
+            bands = diff.split()
+            content = bands[0]
+            for band in bands[1:]:
+                content = ImageChops.lighter(content, band)
+            content = content.point(lambda v: 255 if v > tolerance else 0)
+        box = content.getbbox()
+    finally:
+        for intermediate in (converted, probe):
+            if intermediate is not img:
+                intermediate.close()
+    if box is None or box == (0, 0) + tuple(img.size):
+        return None
+    return box
+
+
+def read_level_roll_degrees(xmp):
+    """Return the recorded camera roll in degrees from an XMP packet, or None.
+
+    Rolls outside +/-MAX_LEVEL_ROLL_DEGREES are ignored, as if no level data were recorded.
+    """
+    if not xmp:
+        return None
+    text = xmp.decode("utf-8", errors="replace") if isinstance(xmp, bytes) else str(xmp)
+    for tag in LEVEL_ROLL_XMP_TAGS:
+        escaped = re.escape(tag)
+        match = re.search(
+            rf'{escaped}\s*=\s*["\']\s*([+-]?\d+(?:\.\d+)?)\s*["\']|<{escaped}>\s*([+-]?\d+(?:\.\d+)?)\s*</{escaped}>',
+            text,
+        )
+        if match:
+            value = float(match.group(1) or match.group(2))
+            if -MAX_LEVEL_ROLL_DEGREES <= value <= MAX_LEVEL_ROLL_DEGREES:
+                return value
+    return None
+
+
 class ImageConverter:
     """Handles image format conversion operations."""
     
     # Supported output formats and their parameters
-    OUTPUT_FORMATS = ['jpg', 'jpeg', 'png', 'webp', 'bmp', 'tiff', 'tif', 'ico', 'avif']
-    VALID_ICO_SIZES = {16, 32, 48, 64, 128, 256}
+    OUTPUT_FORMATS = ['jpg', 'jpeg', 'png', 'webp', 'bmp', 'tiff', 'tif', 'ico', 'avif', 'jxl']
+    # ICO frames are at most 256x256; an empty request writes the common Windows set.
+    MAX_ICO_SIZE = 256
+    DEFAULT_ICO_SIZES = (16, 32, 48, 256)
 
     # Quality-aware formats
     QUALITY_FORMATS = ['jpg', 'jpeg', 'webp', 'avif']
@@ -268,10 +822,14 @@ class ImageConverter:
             scale = le / float(max(base_w, base_h))
             target_w = max(1, int(base_w * scale))
             target_h = max(1, int(base_h * scale))
-        elif mode == "fixed" and (int(width or 0) > 0 or int(height or 0) > 0):
+        elif mode == "cover" and int(width or 0) > 0 and int(height or 0) > 0:
+            # Rasterize just large enough to cover; convert() crops the overflow afterwards.
+            target_w, target_h = cover_scale_size(base_w, base_h, int(width), int(height))
+        elif mode in ("fixed", "cover", "pad") and (int(width or 0) > 0 or int(height or 0) > 0):
+            # `pad` rasterizes at the contained size; convert() adds the padding afterwards.
             w = int(width or 0)
             h = int(height or 0)
-            if maintain_ar:
+            if maintain_ar or mode in ("cover", "pad"):
                 if w > 0 and h == 0:
                     scale = w / float(base_w)
                     target_w = w
@@ -402,8 +960,7 @@ class ImageConverter:
         if inkscape:
             tmp_path = None
             try:
-                with tempfile.NamedTemporaryFile(suffix=".png", delete=False) as tmp:
-                    tmp_path = tmp.name
+                tmp_path = TEMP_REGISTRY.create(suffix=".png")
                 args = [
                     inkscape,
                     svg_path,
@@ -427,10 +984,7 @@ class ImageConverter:
                 logger.warning(f"Inkscape SVG render failed: {e}")
             finally:
                 if tmp_path is not None:
-                    try:
-                        os.unlink(tmp_path)
-                    except OSError:
-                        pass
+                    TEMP_REGISTRY.discard(tmp_path)
 
         raise RuntimeError(
             "SVG input is not supported by the current Python environment. "
@@ -438,32 +992,26 @@ class ImageConverter:
         )
 
     def _normalize_ico_sizes(self, ico_sizes):
-        values = []
-        if ico_sizes and isinstance(ico_sizes, list):
-            for s in ico_sizes:
-                try:
-                    v = int(s)
-                    if v in self.VALID_ICO_SIZES:
-                        values.append(v)
-                except (TypeError, ValueError):
-                    continue
-        values = sorted(set(values))
-        return values
-
-    def _default_ico_sizes(self, source_edge):
-        edge = max(1, int(source_edge or 0))
-        allowed = sorted(self.VALID_ICO_SIZES)
-        sizes = [size for size in allowed if size <= min(edge, 256)]
-        if not sizes:
-            return [allowed[0]]
-        return sizes
+        """Sorted, de-duplicated ICO edges; raises ValueError for entries outside 1-256."""
+        values = set()
+        for s in ico_sizes or []:
+            if isinstance(s, (list, tuple)) and len(s) == 2 and s[0] == s[1]:
+                s = s[0]
+            try:
+                v = int(s)
+            except (TypeError, ValueError):
+                v = None
+            if v is None or isinstance(s, bool) or (isinstance(s, float) and v != s):
+                raise ValueError(f'Invalid ico size: {s!r}')
+            if not 1 <= v <= self.MAX_ICO_SIZE:
+                raise ValueError(f'ICO size {v} is out of range (1-{self.MAX_ICO_SIZE})')
+            values.add(v)
+        return sorted(values)
 
     def _prepare_ico_image(self, img, ico_sizes):
-        source_edge = max(img.size)
-        sizes = self._normalize_ico_sizes(ico_sizes)
-        if not sizes:
-            sizes = self._default_ico_sizes(source_edge)
-        max_edge = max(sizes) if sizes else source_edge
+        """Square the image and scale it to the largest requested edge; returns (image, sizes)."""
+        sizes = self._normalize_ico_sizes(ico_sizes) or list(self.DEFAULT_ICO_SIZES)
+        max_edge = max(sizes)
 
         if img.mode != 'RGBA':
             img = self._replace_image(img, img.convert('RGBA'))
@@ -490,7 +1038,23 @@ class ImageConverter:
                 long_edge=0,
                 keep_metadata=False,
                 compress_level=6,
-                ico_sizes=None):
+                ico_sizes=None,
+                preserve_16bit=None,
+                lossless=False,
+                background_color=None,
+                crop_anchor='center',
+                progressive=None,
+                subsampling='',
+                keep_color_profile=True,
+                auto_orient=True,
+                tiff_compression='',
+                trim_borders=False,
+                trim_tolerance=0,
+                pad_color='',
+                max_megapixels=DEFAULT_MAX_MEGAPIXELS,
+                dpi=0,
+                strip_metadata=False,
+                target_size_kb=0):
         """
         Convert an image to a different format.
         
@@ -504,6 +1068,26 @@ class ImageConverter:
             maintain_ar (bool): Maintain aspect ratio when resizing
             compress_level (int): ZLIB compression level for PNG (0-9)
             ico_sizes (list): List of sizes for ICO format
+            preserve_16bit (bool | None): 16-bit samples are kept when the target format supports them
+                (PNG, TIFF) unless this is False; True also warns when they cannot be kept. The request
+                field is `preserve_bit_depth` (`preserve_16bit` is still accepted)
+            lossless (bool): Request an exact encode for WebP/AVIF; ignored elsewhere
+            background_color (str): Hex fill for transparency when the target has no alpha
+            crop_anchor (str): Which edge `cover` keeps when cropping, or where `pad` places the image
+                (center, top, bottom, left, right)
+            progressive (bool): Progressive JPEG scans; None keeps the default (on), ignored for non-JPEG
+            subsampling (str): JPEG chroma subsampling (4:4:4, 4:2:2, 4:2:0); empty for auto
+            keep_color_profile (bool): Embed the source ICC profile when the target supports it;
+                otherwise the pixels are converted to sRGB. Independent of keep_metadata (EXIF only).
+            auto_orient (bool): Rotate pixels per the EXIF Orientation tag before resizing and reset the tag
+            tiff_compression (str): TIFF compression (none, lzw, deflate, packbits); empty for lzw
+            trim_borders (bool): Crop a uniform or fully transparent border before resizing
+            trim_tolerance (int): Per-channel difference (0-255) still counted as border colour
+            pad_color (str): Hex fill for the `pad` resize mode; empty for transparent (white without alpha)
+            max_megapixels (int): Reject inputs (and SVG render sizes) above this many megapixels
+            dpi (int): Resolution tag written for JPEG/PNG/TIFF; 0 carries the source's over
+            strip_metadata (bool): Drop EXIF, XMP and comments as the compressor does (overrides keep_metadata)
+            target_size_kb (int): Lower the encoder quality until the output fits; 0 keeps `quality`
         
         Returns:
             dict: Conversion result with success status and metadata
@@ -517,6 +1101,105 @@ class ImageConverter:
                     'success': False,
                     'error': f'[UNSUPPORTED_FORMAT] Unsupported output format: {format_type}'
                 }
+            if format_type == 'avif' and not avif_encoder_available():
+                return {
+                    'success': False,
+                    'error': '[UNSUPPORTED_FORMAT] 当前环境缺少 AVIF 编码器，请升级 Pillow 至 11.2 以上或安装 pillow-avif-plugin',
+                    'missing_plugin': 'pillow-avif-plugin',
+                }
+            reads_jxl = str(input_path or '').lower().endswith('.jxl')
+            if (format_type == 'jxl' or reads_jxl) and not jxl_plugin_available():
+                return {
+                    'success': False,
+                    'error': '[UNSUPPORTED_FORMAT] 当前环境缺少 JPEG XL 支持，请安装 pillow-jxl-plugin',
+                    'missing_plugin': 'pillow-jxl-plugin',
+                }
+            if format_type == 'jxl' and int(quality or 0) >= 100:
+                lossless = True
+            lossless = bool(lossless) and format_type in LOSSLESS_FORMATS
+            background = None
+            if background_color:
+                background = parse_hex_color(background_color)
+                if background is None:
+                    return {
+                        'success': False,
+                        'error': f'[BAD_INPUT] Invalid background_color: {background_color}'
+                    }
+            if pad_color and parse_hex_color(pad_color) is None:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid pad_color: {pad_color}'
+                }
+            crop_anchor = str(crop_anchor or 'center').strip().lower()
+            if crop_anchor not in CROP_ANCHORS:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid crop_anchor: {crop_anchor} (expected one of {", ".join(CROP_ANCHORS)})'
+                }
+            subsampling = str(subsampling or '').strip()
+            if subsampling and subsampling not in JPEG_SUBSAMPLING:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid subsampling: {subsampling} (expected one of {", ".join(JPEG_SUBSAMPLING)})'
+                }
+            tiff_compression = str(tiff_compression or '').strip().lower() or DEFAULT_TIFF_COMPRESSION
+            if tiff_compression not in TIFF_COMPRESSION:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid tiff_compression: {tiff_compression} (expected one of {", ".join(TIFF_COMPRESSION)})'
+                }
+            raw_tolerance = trim_tolerance
+            try:
+                trim_tolerance = int(trim_tolerance or 0)
+            except (TypeError, ValueError):
+                trim_tolerance = -1
+            if not 0 <= trim_tolerance <= 255:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid trim_tolerance: {raw_tolerance} (expected 0-255)'
+                }
+            trim_borders = bool(trim_borders)
+            raw_limit = max_megapixels
+            try:
+                max_megapixels = int(max_megapixels or DEFAULT_MAX_MEGAPIXELS)
+            except (TypeError, ValueError):
+                max_megapixels = 0
+            if max_megapixels < 1 or isinstance(raw_limit, bool):
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid max_megapixels: {raw_limit} (expected a positive integer)'
+                }
+            raw_dpi = dpi
+            try:
+                dpi = int(dpi or 0)
+            except (TypeError, ValueError):
+                dpi = -1
+            if not 0 <= dpi <= MAX_DPI or isinstance(raw_dpi, bool):
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid dpi: {raw_dpi} (expected 0-{MAX_DPI})'
+                }
+            raw_target = target_size_kb
+            try:
+                target_size_kb = int(target_size_kb or 0)
+            except (TypeError, ValueError):
+                target_size_kb = -1
+            if target_size_kb < 0 or isinstance(raw_target, bool):
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Invalid target_size_kb: {raw_target} (expected a non-negative integer)'
+                }
+            strip_metadata = bool(strip_metadata)
+            if strip_metadata:
+                keep_metadata = False
+            if format_type == 'ico':
+                try:
+                    ico_sizes = self._normalize_ico_sizes(ico_sizes) or list(self.DEFAULT_ICO_SIZES)
+                except ValueError as e:
+                    return {
+                        'success': False,
+                        'error': f'[BAD_INPUT] {e}'
+                    }
             if not _can_convert_in_place(input_path, output_path, format_type):
                 return {
                     'success': False,
@@ -544,13 +1227,23 @@ class ImageConverter:
                         format_type=format_type,
                         ico_sizes=ico_sizes,
                     )
+                    limit_error = megapixel_limit_error(render_w, render_h, max_megapixels)
+                    if limit_error:
+                        return {'success': False, 'error': limit_error}
                     img = self._svg_to_pil(input_path, render_w, render_h)
                     logger.info(f"SVG rasterized: {render_w}x{render_h}")
-                    resize_mode = ""
+                    # Cover still needs its crop and pad its padding; everything else was fully sized by the rasterizer.
+                    # Trimming shrinks the content again, so fixed and long-edge targets are
+                    # re-applied to the trimmed raster below.
+                    svg_mode = str(resize_mode or "").strip().lower()
+                    keeps_target = trim_borders and svg_mode in ("fixed", "long_edge")
+                    if svg_mode not in ("cover", "pad") and not keeps_target:
+                        resize_mode = ""
+                        width = 0
+                        height = 0
                     scale_percent = 0
-                    long_edge = 0
-                    width = 0
-                    height = 0
+                    if not keeps_target:
+                        long_edge = 0
                 except Exception as e:
                     logger.error(f"Failed to open SVG: {e}")
                     return {
@@ -559,7 +1252,15 @@ class ImageConverter:
                     }
             else:
                 logger.info(f"Opening image: {input_path}")
-                img = Image.open(input_path)
+                try:
+                    img = Image.open(input_path)
+                except Image.DecompressionBombError as e:
+                    # Pillow's own ceiling (2x MAX_IMAGE_PIXELS) can be lower than max_megapixels.
+                    return {'success': False, 'error': f'[IMAGE_TOO_LARGE] {e}'}
+                # Only the header has been read so far; refuse before decoding any pixels.
+                limit_error = megapixel_limit_error(img.size[0], img.size[1], max_megapixels)
+                if limit_error:
+                    return {'success': False, 'error': limit_error}
                 # Avoid full pixel decode when we only need metadata/size and will resize/re-encode.
                 # Still decode on demand when filters/mode conversion require pixel access.
                 needs_full_load = True
@@ -574,7 +1275,10 @@ class ImageConverter:
                 ):
                     # Direct re-encode/copy path still requires pixel data for most formats.
                     needs_full_load = True
-                if needs_full_load:
+                # Draft sizes assume the full frame; a trimmed crop would be upscaled from it.
+                if needs_full_load and trim_borders:
+                    img.load()
+                elif needs_full_load:
                     # Prefer decoder draft for large downscales to reduce decode cost.
                     try:
                         if mode == "percent" and int(scale_percent or 0) > 0 and int(scale_percent) < 100:
@@ -585,7 +1289,7 @@ class ImageConverter:
                         elif mode == "long_edge" and int(long_edge or 0) > 0:
                             le = max(1, int(long_edge))
                             img.draft(img.mode, (le, le))
-                        elif mode == "fixed" and (int(width or 0) > 0 or int(height or 0) > 0):
+                        elif mode in ("fixed", "cover", "pad") and (int(width or 0) > 0 or int(height or 0) > 0):
                             tw = int(width or img.size[0])
                             th = int(height or img.size[1])
                             img.draft(img.mode, (max(1, tw), max(1, th)))
@@ -597,6 +1301,64 @@ class ImageConverter:
                 open_elapsed = time.perf_counter() - open_start
 
             exif_bytes = img.info.get('exif')
+            icc_bytes = img.info.get('icc_profile')
+            # Read before any transform: resized or recoloured copies do not keep img.info.
+            written_dpi = (float(dpi), float(dpi)) if dpi else source_dpi(img)
+            color_mode = img.mode
+            # Checked on the decoded file, before orientation or colour fixes replace the image.
+            sixteen_bit_color = is_16bit_color_source(img, input_path)
+
+            # Orient first so every resize mode below sees the displayed width and height.
+            auto_oriented = False
+            if auto_orient and img.getexif().get(EXIF_ORIENTATION_TAG) in range(2, 9):
+                img = self._replace_image(img, ImageOps.exif_transpose(img))
+                auto_oriented = True
+                if exif_bytes:
+                    exif_bytes = exif_with_normal_orientation(exif_bytes)
+
+            # Browsers and most encoders mishandle CMYK; normalize to sRGB up front.
+            was_cmyk = img.mode == 'CMYK'
+            warning = None
+            if was_cmyk:
+                srgb, used_profile = cmyk_to_srgb(img)
+                img = self._replace_image(img, srgb)
+                # The CMYK profile describes the source pixels, not the sRGB copy.
+                icc_bytes = None
+                warning = (
+                    '源图为 CMYK，已按嵌入的色彩配置文件转换为 sRGB' if used_profile
+                    else '源图为 CMYK 且没有可用的色彩配置文件，已按默认方式转换为 sRGB，颜色可能与原稿有偏差'
+                )
+
+            tone_mapped = False
+            if preserve_16bit and sixteen_bit_color:
+                warning = '当前图像库只能以 8 位读取 16 位彩色图像，已按 8 位输出'
+            elif img.mode in HIGH_BIT_DEPTH_MODES:
+                keep_16bit = preserve_16bit is not False and format_type in SIXTEEN_BIT_FORMATS
+                if preserve_16bit and not keep_16bit:
+                    warning = f'目标格式 {format_type.upper()} 不支持 16 位色深，已转换为 8 位'
+                normalized, tone_mapped = normalize_high_bit_depth(img, keep_16bit)
+                img = self._replace_image(img, normalized)
+
+            kept_color_profile = bool(icc_bytes) and bool(keep_color_profile) and format_type in ICC_OUTPUT_FORMATS
+            converted_to_srgb = False
+            if icc_bytes and not kept_color_profile:
+                srgb = convert_icc_to_srgb(img, icc_bytes)
+                if srgb is not None:
+                    img = self._replace_image(img, srgb)
+                    converted_to_srgb = True
+                elif img.mode in ('RGB', 'RGBA'):
+                    profile_warning = '无法将嵌入的色彩配置文件转换为 sRGB，颜色可能出现偏差'
+                    warning = f'{warning}；{profile_warning}' if warning else profile_warning
+                img.info.pop('icc_profile', None)
+
+            # Trim before sizing so fixed, long-edge and cover targets apply to the content itself.
+            trimmed = False
+            if trim_borders:
+                box = trim_border_box(img, trim_tolerance)
+                if box is not None:
+                    img = self._replace_image(img, img.crop(box))
+                    trimmed = True
+                trimmed_size = img.size
 
             mode = str(resize_mode or '').strip().lower()
             resized = False
@@ -628,11 +1390,31 @@ class ImageConverter:
                 if width > 0 or height > 0:
                     img = self._replace_image(img, self._resize_image(img, width, height, maintain_ar))
                     resized = True
+            elif mode == 'cover':
+                if width > 0 and height > 0:
+                    if img.size != (width, height):
+                        img = self._replace_image(img, self._cover_image(img, width, height, crop_anchor))
+                        resized = True
+                elif width > 0 or height > 0:
+                    # A single edge has nothing to crop against; cover keeps the aspect ratio regardless.
+                    img = self._replace_image(img, self._resize_image(img, width, height, True))
+                    resized = True
+            elif mode == 'pad':
+                if width > 0 and height > 0:
+                    if img.size != (width, height):
+                        fill = pad_fill_color(pad_color, format_type, background)
+                        img = self._replace_image(img, pad_to_size(img, width, height, fill, CROP_ANCHORS[crop_anchor]))
+                        resized = True
+                elif width > 0 or height > 0:
+                    # A single edge leaves nothing to pad; keep the aspect ratio like cover does.
+                    img = self._replace_image(img, self._resize_image(img, width, height, True))
+                    resized = True
             else:
                 if width > 0 or height > 0:
                     img = self._replace_image(img, self._resize_image(img, width, height, maintain_ar))
                     resized = True
 
+
             if _PROFILE_ENABLED and resized:
                 resize_elapsed = time.perf_counter() - resize_start
             
@@ -640,12 +1422,27 @@ class ImageConverter:
                 img, ico_sizes = self._prepare_ico_image(img, ico_sizes)
                 output_path = _with_single_ico_size_suffix(output_path, ico_sizes)
 
-            img = self._replace_image(img, self._prepare_for_output(img, format_type))
+            img = self._replace_image(img, self._prepare_for_output(img, format_type, background))
+            img = self._replace_image(img, prepare_16bit_for_save(img, format_type))
 
             # Prepare save parameters based on format
-            save_params = self._get_save_params(format_type, quality, compress_level, ico_sizes)
+            save_params = self._get_save_params(
+                format_type, quality, compress_level, ico_sizes, lossless, progressive, subsampling,
+                tiff_compression
+            )
             if keep_metadata and exif_bytes:
-                save_params['exif'] = exif_bytes
+                save_params['exif'] = exif_with_srgb_color_space(exif_bytes) if converted_to_srgb else exif_bytes
+            if kept_color_profile:
+                save_params['icc_profile'] = icc_bytes
+            elif format_type in ICC_OUTPUT_FORMATS:
+                # PNG and TIFF would otherwise fall back to img.info and re-embed a stale profile.
+                save_params['icc_profile'] = None
+            if format_type in DPI_OUTPUT_FORMATS and written_dpi:
+                # Pillow only writes a resolution it is handed, so the source's is passed on too.
+                save_params['dpi'] = written_dpi
+            if strip_metadata:
+                for key in STRIPPED_INFO_KEYS:
+                    img.info.pop(key, None)
             
             # Convert format names for Pillow
             pillow_format = self._convert_format_name(format_type)
@@ -662,30 +1459,35 @@ class ImageConverter:
                 if os.path.abspath(input_path) == os.path.abspath(output_path):
                     final_dir = os.path.dirname(os.path.abspath(output_path)) or "."
                     os.makedirs(final_dir, exist_ok=True)
-                    with tempfile.NamedTemporaryFile(
+                    tmp_output_path = TEMP_REGISTRY.create(
                         suffix=Path(output_path).suffix or ".tmp",
-                        delete=False,
                         dir=final_dir,
-                    ) as tmp:
-                        tmp_output_path = tmp.name
+                    )
                     save_path = tmp_output_path
                     logger.info(f"Overwrite mode detected; writing to temp: {save_path}")
 
                 logger.info(f"Saving to: {save_path} (format: {pillow_format})")
                 save_start = time.perf_counter() if _PROFILE_ENABLED else 0.0
-                img.save(save_path, format=pillow_format, **save_params)
+                target_met = None
+                if target_size_kb:
+                    data, target_met = self._encode_to_target(img, pillow_format, save_params, target_size_kb * 1024)
+                    with open(save_path, 'wb') as handle:
+                        handle.write(data)
+                    if not target_met:
+                        unmet = _target_missed_warning(target_size_kb * 1024, save_path)
+                        warning = f'{warning}；{unmet}' if warning else unmet
+                else:
+                    img.save(save_path, format=pillow_format, **save_params)
                 if _PROFILE_ENABLED:
                     save_elapsed = time.perf_counter() - save_start
 
                 if tmp_output_path:
-                    os.replace(tmp_output_path, output_path)
+                    replace_with_retry(tmp_output_path, output_path)
+                    TEMP_REGISTRY.unregister(tmp_output_path)
                     tmp_output_path = None
             finally:
-                try:
-                    if tmp_output_path:
-                        os.remove(tmp_output_path)
-                except OSError as cleanup_err:
-                    logger.warning(f"Failed to cleanup temp output file {tmp_output_path}: {cleanup_err}")
+                if tmp_output_path:
+                    TEMP_REGISTRY.discard(tmp_output_path)
 
             if _PROFILE_ENABLED:
                 total_elapsed = time.perf_counter() - total_start
@@ -700,11 +1502,43 @@ class ImageConverter:
                 )
 
             # Return success result
-            return {
+            result = {
                 'success': True,
                 'input_path': input_path,
-                'output_path': output_path
+                'output_path': output_path,
+                'was_cmyk': was_cmyk,
+                'color_mode': color_mode,
+                'tone_mapped': tone_mapped,
+                'lossless': lossless,
+                'kept_color_profile': kept_color_profile,
+                'converted_to_srgb': converted_to_srgb,
+                'auto_oriented': auto_oriented,
+                'file_size': getsize_with_retry(output_path),
+                'bit_depth': bits_per_sample(img.mode),
             }
+            if format_type == 'ico':
+                result['ico_sizes'] = written_ico_sizes(output_path)
+            if format_type in DPI_OUTPUT_FORMATS:
+                result['dpi'] = int(round(written_dpi[0])) if written_dpi else 0
+            if strip_metadata:
+                result['stripped_metadata'] = True
+            if target_size_kb:
+                result['target_size_kb'] = target_size_kb
+                result['target_met'] = target_met
+                if 'quality' in save_params:
+                    result['quality'] = save_params['quality']
+            if trim_borders:
+                result['trimmed'] = trimmed
+                result['trimmed_width'], result['trimmed_height'] = trimmed_size
+            if format_type in ('tiff', 'tif'):
+                result['tiff_compression'] = tiff_compression
+            if format_type in ('jpg', 'jpeg'):
+                result['progressive'] = self._is_progressive_jpeg(output_path)
+            if format_type == 'jxl':
+                result['jxl_distance'] = 0.0 if lossless else jxl_distance_from_quality(save_params.get('quality', quality))
+            if warning:
+                result['warning'] = warning
+            return result
             
         except FileNotFoundError as e:
             logger.error(f"File not found: {e}")
@@ -771,13 +1605,32 @@ class ImageConverter:
         resample = Image.Resampling.BILINEAR if scale < 0.85 else Image.Resampling.LANCZOS
         return img.resize((new_width, new_height), resample)
 
-    def _prepare_for_output(self, img, format_type):
-        if format_type not in ['jpg', 'jpeg', 'pdf']:
+    @staticmethod
+    def _is_progressive_jpeg(path):
+        """Read the written file back so the result reflects the encoder, not the request."""
+        try:
+            with Image.open(path) as saved:
+                return bool(saved.info.get('progressive') or saved.info.get('progression'))
+        except Exception:
+            return False
+
+    def _cover_image(self, img, target_width, target_height, anchor='center'):
+        """Scale to fill target_width x target_height, then crop the overflow around `anchor`."""
+        scale = max(target_width / float(img.size[0]), target_height / float(img.size[1]))
+        resample = Image.Resampling.BILINEAR if scale < 0.85 else Image.Resampling.LANCZOS
+        logger.info(f"Cover resize from {img.size[0]}x{img.size[1]} to {target_width}x{target_height} ({anchor})")
+        return ImageOps.fit(img, (target_width, target_height), resample, centering=CROP_ANCHORS[anchor])
+
+    def _prepare_for_output(self, img, format_type, background=None):
+        if format_type not in NO_ALPHA_FORMATS:
             return img
+        background = background or DEFAULT_BACKGROUND
         if img.mode in ('RGBA', 'LA'):
-            return self._flatten_alpha(img)
+            return self._flatten_alpha(img, background)
         if img.mode == 'P' and 'transparency' in img.info:
-            return self._flatten_alpha(img)
+            return self._flatten_alpha(img, background)
+        if format_type == 'bmp':
+            return img
         if img.mode != 'RGB':
             return img.convert('RGB')
         return img
@@ -794,7 +1647,37 @@ class ImageConverter:
                 rgba.close()
         return base
     
-    def _get_save_params(self, format_type, quality, compress_level=6, ico_sizes=None):
+    def _encode_to_target(self, img, pillow_format, save_params, target_bytes):
+        """Encode in memory at the highest quality that fits `target_bytes`; returns (bytes, met).
+
+        Only lossy encodes have a quality to lower; lossless ones and formats without a quality
+        are encoded once. When nothing fits, the smallest encode is kept. `save_params['quality']`
+        is left at the quality that was written.
+        """
+        def encode(params):
+            buffer = io.BytesIO()
+            img.save(buffer, format=pillow_format, **params)
+            return buffer.getvalue()
+
+        if 'quality' not in save_params or save_params.get('lossless'):
+            data = encode(save_params)
+            return data, len(data) <= target_bytes
+
+        # The compressor's search, so convert and compress agree on what a target size yields.
+        last = {}
+
+        def encoded_size(q):
+            last.clear()
+            last[q] = encode({**save_params, 'quality': q})
+            return len(last[q])
+
+        quality, met = search_quality_for_size(encoded_size, target_bytes, int(save_params['quality']))
+        data = last.get(quality) or encode({**save_params, 'quality': quality})
+        save_params['quality'] = quality
+        return data, met
+
+    def _get_save_params(self, format_type, quality, compress_level=6, ico_sizes=None, lossless=False,
+                         progressive=None, subsampling='', tiff_compression=''):
         """
         Get save parameters based on the output format.
         
@@ -803,6 +1686,10 @@ class ImageConverter:
             quality (int): Quality setting
             compress_level (int): PNG compression level
             ico_sizes (list): ICO sizes
+            lossless (bool): Exact encode for WebP/AVIF
+            progressive (bool): Progressive JPEG scans; None keeps the default (on)
+            subsampling (str): JPEG chroma subsampling key of JPEG_SUBSAMPLING; empty for auto
+            tiff_compression (str): TIFF compression key of TIFF_COMPRESSION; empty for lzw
         
         Returns:
             dict: Save parameters for PIL
@@ -815,13 +1702,39 @@ class ImageConverter:
             params['optimize'] = True
         
         if format_type in ['jpg', 'jpeg']:
-            params['progressive'] = True
+            params['progressive'] = True if progressive is None else bool(progressive)
+            if subsampling in JPEG_SUBSAMPLING:
+                params['subsampling'] = JPEG_SUBSAMPLING[subsampling]
         
         if format_type == 'png':
             params['compress_level'] = max(0, min(9, compress_level))
+
+        if format_type in ('tiff', 'tif'):
+            params['compression'] = TIFF_COMPRESSION.get(tiff_compression or DEFAULT_TIFF_COMPRESSION, 'tiff_lzw')
         
         if format_type == 'webp':
             params['method'] = 6  # Best compression
+            if lossless:
+                params['lossless'] = True
+                params['quality'] = 100
+
+        if format_type == 'jxl':
+            # pillow-jxl maps quality to a Butteraugli distance the same way libjxl does.
+            params['quality'] = max(0, min(100, int(quality)))
+            params['effort'] = 7
+            if lossless or params['quality'] >= 100:
+                params['lossless'] = True
+                params['quality'] = 100
+
+        if format_type == 'avif':
+            # The AVIF plugin has no optimize switch; speed trades encode time for size.
+            params.pop('optimize', None)
+            params['speed'] = 6
+            if lossless:
+                # libavif is only exact at q100 with full-range 4:4:4 samples.
+                params['quality'] = 100
+                params['subsampling'] = '4:4:4'
+                params['range'] = 'full'
         
         if format_type == 'ico':
             if ico_sizes and isinstance(ico_sizes, list):
@@ -903,8 +1816,21 @@ def process(input_data):
         input_path = input_data.get('input_path')
         output_path = input_data.get('output_path')
         input_path, output_path = _normalize_paths(input_path, output_path)
+        if input_data.get('action') == 'color_profile':
+            if not input_path or not output_path:
+                return {
+                    'success': False,
+                    'error': '[BAD_INPUT] Missing required parameters: input_path or output_path'
+                }
+            return apply_color_profile(
+                input_path,
+                output_path,
+                input_data.get('profile'),
+                input_data.get('mode', 'assign'),
+            )
         format_type = input_data.get('format', 'jpg')
-        quality = input_data.get('quality', 95)
+        # 0 (no per-format default configured) falls back to the engine default.
+        quality = input_data.get('quality') or 95
         width = input_data.get('width', 0)
         height = input_data.get('height', 0)
         maintain_ar = input_data.get('maintain_ar', True)
@@ -916,6 +1842,23 @@ def process(input_data):
         ico_sizes = input_data.get('ico_sizes', None)
         if not ico_sizes:
             ico_sizes = input_data.get('icoSizes', None)
+        preserve_16bit = bit_depth_request(input_data)
+        lossless = bool(input_data.get('lossless', False))
+        background_color = input_data.get('background_color') or None
+        crop_anchor = input_data.get('crop_anchor') or 'center'
+        progressive = input_data.get('progressive')
+        progressive = None if progressive is None else bool(progressive)
+        subsampling = input_data.get('subsampling') or ''
+        keep_color_profile = bool(input_data.get('keep_color_profile', True))
+        auto_orient = bool(input_data.get('auto_orient', True))
+        tiff_compression = input_data.get('tiff_compression') or ''
+        trim_borders = bool(input_data.get('trim_borders', False))
+        trim_tolerance = input_data.get('trim_tolerance', 0)
+        pad_color = input_data.get('pad_color') or ''
+        max_megapixels = input_data.get('max_megapixels') or DEFAULT_MAX_MEGAPIXELS
+        dpi = input_data.get('dpi', 0)
+        strip_metadata = input_data.get('strip_metadata', False)
+        target_size_kb = input_data.get('target_size_kb', 0)
 
         # Validate required parameters
         if not input_path or not output_path:
@@ -939,7 +1882,23 @@ def process(input_data):
             long_edge=long_edge,
             keep_metadata=keep_metadata,
             compress_level=compress_level,
-            ico_sizes=ico_sizes
+            ico_sizes=ico_sizes,
+            preserve_16bit=preserve_16bit,
+            lossless=lossless,
+            background_color=background_color,
+            crop_anchor=crop_anchor,
+            progressive=progressive,
+            subsampling=subsampling,
+            keep_color_profile=keep_color_profile,
+            auto_orient=auto_orient,
+            tiff_compression=tiff_compression,
+            trim_borders=trim_borders,
+            trim_tolerance=trim_tolerance,
+            pad_color=pad_color,
+            max_megapixels=max_megapixels,
+            dpi=dpi,
+            strip_metadata=strip_metadata,
+            target_size_kb=target_size_kb
         )
 
         return result
@@ -970,7 +1929,8 @@ def main():
         output_path = input_data.get('output_path')
         input_path, output_path = _normalize_paths(input_path, output_path)
         format_type = input_data.get('format', 'jpg')
-        quality = input_data.get('quality', 95)
+        # 0 (no per-format default configured) falls back to the engine default.
+        quality = input_data.get('quality') or 95
         width = input_data.get('width', 0)
         height = input_data.get('height', 0)
         maintain_ar = input_data.get('maintain_ar', True)
@@ -982,6 +1942,23 @@ def main():
         ico_sizes = input_data.get('ico_sizes', None)
         if not ico_sizes:
             ico_sizes = input_data.get('icoSizes', None)
+        preserve_16bit = bit_depth_request(input_data)
+        lossless = bool(input_data.get('lossless', False))
+        background_color = input_data.get('background_color') or None
+        crop_anchor = input_data.get('crop_anchor') or 'center'
+        progressive = input_data.get('progressive')
+        progressive = None if progressive is None else bool(progressive)
+        subsampling = input_data.get('subsampling') or ''
+        keep_color_profile = bool(input_data.get('keep_color_profile', True))
+        auto_orient = bool(input_data.get('auto_orient', True))
+        tiff_compression = input_data.get('tiff_compression') or ''
+        trim_borders = bool(input_data.get('trim_borders', False))
+        trim_tolerance = input_data.get('trim_tolerance', 0)
+        pad_color = input_data.get('pad_color') or ''
+        max_megapixels = input_data.get('max_megapixels') or DEFAULT_MAX_MEGAPIXELS
+        dpi = input_data.get('dpi', 0)
+        strip_metadata = input_data.get('strip_metadata', False)
+        target_size_kb = input_data.get('target_size_kb', 0)
         
         # Validate required parameters
         if not input_path or not output_path:
@@ -1005,7 +1982,23 @@ def main():
                 long_edge=long_edge,
                 keep_metadata=keep_metadata,
                 compress_level=compress_level,
-                ico_sizes=ico_sizes
+                ico_sizes=ico_sizes,
+                preserve_16bit=preserve_16bit,
+                lossless=lossless,
+                background_color=background_color,
+                crop_anchor=crop_anchor,
+                progressive=progressive,
+                subsampling=subsampling,
+                keep_color_profile=keep_color_profile,
+                auto_orient=auto_orient,
+                tiff_compression=tiff_compression,
+                trim_borders=trim_borders,
+                trim_tolerance=trim_tolerance,
+                pad_color=pad_color,
+                max_megapixels=max_megapixels,
+                dpi=dpi,
+                strip_metadata=strip_metadata,
+                target_size_kb=target_size_kb
             )
         
         # Write result to stdout
@@ -17,6 +17,7 @@ import os
 from pathlib import Path
 from PIL import Image, ImageFilter, ImageEnhance, ImageOps, ImageDraw, ImageChops
 import logging
+from temp_registry import getsize_with_retry
 
 from converter import open_image_with_svg_support
 
@@ -44,6 +45,30 @@ class ImageFilterApplier:
         'highkey', 'lowkey', 'haze', 'neon', 'matte', 'ice', 'coffee', 'caramel',
         'teal_orange', 'silver', 'crisp', 'low_contrast'
     ]
+
+    # Machado et al. (2009) full-severity simulation matrices, row-major RGB.
+    COLORBLIND_MATRICES = {
+        'protanopia': (
+            0.152286, 1.052583, -0.204868,
+            0.114503, 0.786281, 0.099216,
+            -0.003882, -0.048116, 1.051998,
+        ),
+        'deuteranopia': (
+            0.367322, 0.860646, -0.227968,
+            0.280085, 0.672501, 0.047413,
+            -0.011820, 0.042940, 0.968881,
+        ),
+        'tritanopia': (
+            1.255528, -0.076749, -0.178779,
+            -0.078411, 0.930809, 0.147602,
+            0.004733, 0.691367, 0.303900,
+        ),
+        'achromatopsia': (
+            0.299, 0.587, 0.114,
+            0.299, 0.587, 0.114,
+            0.299, 0.587, 0.114,
+        ),
+    }
     
     def __init__(self):
         """Initialize the filter applier."""
@@ -60,7 +85,7 @@ class ImageFilterApplier:
     def apply(self, input_path, output_path, filter_name, intensity=1.0,
               blur_radius=2.0, sharpen_factor=2.0, noise_level=0.1,
               vignette_strength=0.5, color_offset_x=5, color_offset_y=5,
-              grain=0.0, vignette=0.0):
+              grain=0.0, vignette=0.0, colorblind_type='protanopia'):
         """
         Apply a filter to an image.
         
@@ -75,12 +100,21 @@ class ImageFilterApplier:
             vignette_strength (float): Vignette strength (0.0-1.0)
             color_offset_x (int): Color shift X in pixels
             color_offset_y (int): Color shift Y in pixels
+            colorblind_type (str): Vision deficiency simulated by the colorblind filter
         
         Returns:
             dict: Filter application result
         """
         img = None
         alpha_channel = None
+        filter_name = str(filter_name or '').strip().lower()
+        if filter_name == 'colorblind':
+            colorblind_type = str(colorblind_type or '').strip().lower()
+            if colorblind_type not in self.COLORBLIND_MATRICES:
+                return {
+                    'success': False,
+                    'error': f'[BAD_INPUT] Unknown colorblind type: {colorblind_type}'
+                }
         try:
             # Open input image
             logger.info(f"Opening image: {input_path}")
@@ -100,11 +134,11 @@ class ImageFilterApplier:
                 img = img.convert('RGB')
                 prev.close()
             
-            filter_name = str(filter_name or '').strip().lower()
-
             # Apply filter based on type
             if filter_name in ("", "none", "original", "raw"):
                 pass
+            elif filter_name == 'colorblind':
+                img = self._replace_image(img, self._apply_colorblind_filter(img, colorblind_type, intensity))
             elif filter_name in self.PRESET_FILTERS:
                 img = self._replace_image(img, self._apply_preset_filter(img, filter_name, intensity))
             elif filter_name in self.BASIC_FILTERS:
@@ -159,7 +193,7 @@ class ImageFilterApplier:
             img = None
 
             # Get file size
-            file_size = os.path.getsize(output_path)
+            file_size = getsize_with_retry(output_path)
 
             return {
                 'success': True,
@@ -296,6 +330,27 @@ class ImageFilterApplier:
         
         return img
 
+    def _apply_colorblind_filter(self, img, colorblind_type, intensity):
+        """Simulate how an RGB image appears with the given color vision deficiency."""
+        logger.debug(f"Applying colorblind simulation: {colorblind_type} (intensity: {intensity})")
+
+        m = self.COLORBLIND_MATRICES[colorblind_type]
+        simulated = img.convert('RGB', (
+            m[0], m[1], m[2], 0,
+            m[3], m[4], m[5], 0,
+            m[6], m[7], m[8], 0,
+        ))
+        try:
+            amount = max(0.0, min(1.0, float(intensity)))
+        except (TypeError, ValueError):
+            amount = 1.0
+        if amount >= 1.0:
+            return simulated
+        try:
+            return Image.blend(img, simulated, amount)
+        finally:
+            simulated.close()
+
     def _apply_preset_filter(self, img, preset, intensity):
         """Apply a preset filter by combining simple adjustments."""
         try:
@@ -652,6 +707,7 @@ def process(input_data):
         color_offset_y = input_data.get('color_offset_y', 5)
         grain = input_data.get('grain', 0.0)
         vignette = input_data.get('vignette', 0.0)
+        colorblind_type = input_data.get('colorblind_type', 'protanopia')
 
         # Validate required parameters
         if not input_path or not output_path:
@@ -679,7 +735,8 @@ def process(input_data):
             color_offset_x=color_offset_x,
             color_offset_y=color_offset_y,
             grain=grain,
-            vignette=vignette
+            vignette=vignette,
+            colorblind_type=colorblind_type
         )
 
         return result
@@ -712,6 +769,7 @@ def main():
         color_offset_y = input_data.get('color_offset_y', 5)
         grain = input_data.get('grain', 0.0)
         vignette = input_data.get('vignette', 0.0)
+        colorblind_type = input_data.get('colorblind_type', 'protanopia')
         
         # Validate required parameters
         if not input_path or not output_path:
@@ -739,7 +797,8 @@ def main():
                 color_offset_x=color_offset_x,
                 color_offset_y=color_offset_y,
                 grain=grain,
-                vignette=vignette
+                vignette=vignette,
+                colorblind_type=colorblind_type
             )
         
         # Write result to stdout
@@ -10,6 +10,7 @@ This script handles GIF operations using the Pillow library:
 - compress a GIF with adjustable quality
 - resize a GIF while keeping aspect ratio
 - convert animated GIF/APNG/WEBP between each other
+- lay frames out on a contact sheet for review
 
 Usage:
     python gif_splitter.py
@@ -19,12 +20,13 @@ Usage:
 
 import json
 import logging
+import math
 import os
 import struct
 import sys
 from pathlib import Path
 
-from PIL import Image, ImageSequence, UnidentifiedImageError
+from PIL import Image, ImageDraw, ImageFont, ImageSequence, UnidentifiedImageError
 
 # Configure logging
 logger = logging.getLogger(__name__)
@@ -34,11 +36,24 @@ MIN_SPEED_FACTOR = 0.5
 MAX_SPEED_FACTOR = 3.0
 MIN_QUALITY = 1
 MAX_QUALITY = 100
+# `max_colors` range for compress; 256 still keeps index 255 for transparency, so at most 255 are used.
+MIN_GIF_COLORS = 2
+MAX_GIF_COLORS = 256
 GIF_MIN_DELAY_MS = 20
 GIF_DELAY_UNIT_MS = 10
 FRAME_OUTPUT_FORMATS = {"png", "bmp"}
 MAX_FRAME_PIXEL_BUDGET = 16_000_000
 MAX_TOTAL_FRAME_PIXEL_BUDGET = 256_000_000
+# Request ceilings checked from the file structure before any frame is decoded; the host
+# fills them from the gif_max_* settings.
+DEFAULT_MAX_FRAMES = 10_000
+DEFAULT_MAX_TOTAL_MEGAPIXELS = 2_048
+DEFAULT_MAX_EXPORT_FILES = 2_000
+# Contact sheet layout: pixels between tiles, label strip under each tile, backdrop for transparency.
+SHEET_GUTTER = 6
+SHEET_LABEL_HEIGHT = 14
+SHEET_BACKGROUND = (240, 240, 240)
+GUARDED_ACTIONS = {"export_frames", "reverse", "change_speed", "compress", "resize", "crop", "boomerang", "contact_sheet", "convert_animation"}
 
 
 def _error_response(code, message, detail=None):
@@ -48,13 +63,173 @@ def _error_response(code, message, detail=None):
     return payload
 
 
+# Extension labels whose data the block walker keeps; others are skipped unread.
+GIF_CONTROL_EXTENSION = 0xF9
+GIF_APPLICATION_EXTENSION = 0xFF
+GIF_LOOP_APPLICATIONS = (b"NETSCAPE2.0", b"ANIMEXTS1.0")
+
+
+def _read_sub_blocks(handle, keep=False):
+    """Consume a sub-block chain; returns (data, length), with data empty unless `keep`."""
+    data = bytearray()
+    length = 0
+    while True:
+        size = handle.read(1)
+        if not size or size[0] == 0:
+            return bytes(data), length
+        if keep:
+            data += handle.read(size[0])
+        else:
+            handle.seek(size[0], os.SEEK_CUR)
+        length += size[0]
+
+
+def _palette_entries(flags):
+    return 2 << (flags & 0x07) if flags & 0x80 else 0
+
+
+def _read_gif_screen(handle):
+    """Read the header and logical screen; returns (width, height, palette_entries) or None."""
+    header = handle.read(13)
+    if len(header) < 13 or header[:6] not in (b"GIF87a", b"GIF89a"):
+        return None
+    palette = _palette_entries(header[10])
+    handle.seek(3 * palette, os.SEEK_CUR)
+    return int.from_bytes(header[6:8], "little"), int.from_bytes(header[8:10], "little"), palette
+
+
+def _gif_blocks(handle):
+    """Yield the blocks after the logical screen until the trailer or a truncated/unknown block.
+
+    Extensions come as ("extension", label, data) and images as
+    ("image", left, top, width, height, palette_entries, data_bytes); image data is skipped.
+    """
+    while True:
+        introducer = handle.read(1)
+        if introducer == b"\x21":
+            label = handle.read(1)
+            if not label:
+                return
+            keep = label[0] in (GIF_CONTROL_EXTENSION, GIF_APPLICATION_EXTENSION)
+            data, _length = _read_sub_blocks(handle, keep)
+            yield "extension", label[0], data
+        elif introducer == b"\x2c":
+            descriptor = handle.read(9)
+            if len(descriptor) < 9:
+                return
+            palette = _palette_entries(descriptor[8])
+            handle.seek(3 * palette + 1, os.SEEK_CUR)
+            _data, length = _read_sub_blocks(handle)
+            left, top, width, height = (int.from_bytes(descriptor[i : i + 2], "little") for i in range(0, 8, 2))
+            yield "image", left, top, width, height, palette, length
+        else:
+            return
+
+
+def scan_gif(path, stop_after=None):
+    """Return (width, height, frame_count) by walking the GIF block structure, or None if not a GIF.
+
+    Only block headers are read and image data is skipped, so this costs a few reads per frame;
+    counting stops once `stop_after` frames are seen.
+    """
+    with open(path, "rb") as handle:
+        screen = _read_gif_screen(handle)
+        if screen is None:
+            return None
+        frames = 0
+        for block in _gif_blocks(handle):
+            if block[0] == "image":
+                frames += 1
+                if stop_after is not None and frames >= stop_after:
+                    break
+    return screen[0], screen[1], frames
+
+
+def inspect_gif(path):
+    """Per-frame delay, disposal, placement and encoded size of a GIF, read without decoding.
+
+    Frames that do not cover the whole canvas carry `left`/`top`/`width`/`height`, and frames
+    with their own colour table carry `palette_size`. `loop` is the NETSCAPE loop count
+    (0 = forever) or None when the file has none and plays once.
+    """
+    with open(path, "rb") as handle:
+        screen = _read_gif_screen(handle)
+        if screen is None:
+            return None
+        width, height, palette = screen
+        frames = []
+        control = {}
+        loop = None
+        elapsed = 0
+        for block in _gif_blocks(handle):
+            if block[0] == "extension":
+                _kind, label, data = block
+                if label == GIF_CONTROL_EXTENSION and len(data) >= 4:
+                    control = {"delay_ms": int.from_bytes(data[1:3], "little") * 10, "disposal": (data[0] >> 2) & 0x07}
+                elif label == GIF_APPLICATION_EXTENSION and data[:11] in GIF_LOOP_APPLICATIONS and len(data) >= 14:
+                    loop = int.from_bytes(data[12:14], "little")
+                continue
+            _kind, left, top, frame_width, frame_height, frame_palette, size = block
+            delay = control.get("delay_ms", 0)
+            elapsed += delay
+            frame = {
+                "index": len(frames),
+                "delay_ms": delay,
+                "disposal": control.get("disposal", 0),
+                "bytes": size,
+                "cumulative_ms": elapsed,
+            }
+            if (left, top, frame_width, frame_height) != (0, 0, width, height):
+                frame.update(left=left, top=top, width=frame_width, height=frame_height)
+            if frame_palette:
+                frame["palette_size"] = frame_palette
+            frames.append(frame)
+            control = {}
+    return {
+        "width": width,
+        "height": height,
+        "frame_count": len(frames),
+        "palette_size": palette,
+        "loop": loop,
+        "total_duration_ms": elapsed,
+        "largest_frame": max(range(len(frames)), key=lambda index: frames[index]["bytes"]) if frames else None,
+        "frames": frames,
+    }
+
+
+def animation_limit_error(input_path, max_frames, max_total_megapixels):
+    """`GIF_TOO_LARGE` response when the animation is over either ceiling, else None.
+
+    GIFs are measured with `scan_gif`; APNG and WebP report their frame count from the header.
+    Unreadable inputs return None and are left to the action's own error handling.
+    """
+    try:
+        scanned = scan_gif(input_path, max_frames + 1)
+        if scanned is None:
+            with Image.open(input_path) as animated:
+                scanned = (*animated.size, int(getattr(animated, "n_frames", 1) or 1))
+    except Exception:
+        return None
+    width, height, frames = scanned
+    if frames > max_frames:
+        return _error_response("GIF_TOO_LARGE", f"Animation has more than {max_frames} frames")
+    total_pixels = width * height * max(1, frames)
+    if total_pixels > max_total_megapixels * 1_000_000:
+        return _error_response(
+            "GIF_TOO_LARGE",
+            f"Animation is {width}x{height} with {frames} frames ({total_pixels} pixels, "
+            f"limit {max_total_megapixels} megapixels)",
+        )
+    return None
+
+
 class GIFTool:
     """Handles GIF operations."""
 
     def __init__(self):
         logger.info("GIFTool initialized")
 
-    def export_frames(self, input_path, output_dir, output_format="png", frame_range="all"):
+    def export_frames(self, input_path, output_dir, output_format="png", frame_range="all", max_files=DEFAULT_MAX_EXPORT_FILES):
         try:
             output_format = (output_format or "png").lower()
             if output_format not in FRAME_OUTPUT_FORMATS:
@@ -73,6 +248,10 @@ class GIFTool:
                 frame_indices = [i for i in frame_indices if 0 <= i < frame_count]
                 if not frame_indices:
                     return _error_response("GIF_EXPORT_EMPTY_SELECTION", "No frames selected for export")
+                if len(frame_indices) > max_files:
+                    return _error_response(
+                        "GIF_TOO_LARGE", f"Export would write {len(frame_indices)} files (limit {max_files})"
+                    )
 
                 # Stream only selected frames instead of materializing the full animation.
                 for frame_idx in frame_indices:
@@ -100,6 +279,128 @@ class GIFTool:
             logger.error("GIF export failed: %s", exc, exc_info=True)
             return _error_response("GIF_EXPORT_FAILED", str(exc))
 
+    def export_frame_at(self, input_path, output_path, timestamp_ms, output_format=None):
+        """Write the single frame on screen `timestamp_ms` into the animation (a poster frame)."""
+        try:
+            output_format = (output_format or Path(output_path).suffix.lstrip(".") or "png").lower()
+            if output_format not in FRAME_OUTPUT_FORMATS:
+                return _error_response("GIF_EXPORT_UNSUPPORTED_FORMAT", f"Unsupported output format: {output_format}")
+            try:
+                timestamp = int(timestamp_ms)
+            except (TypeError, ValueError):
+                return _error_response("GIF_BAD_REQUEST", f"Invalid timestamp_ms: {timestamp_ms}")
+
+            with Image.open(input_path) as animated:
+                frame_count = int(getattr(animated, "n_frames", 1) or 1)
+                if frame_count <= 1:
+                    return _error_response("GIF_EXPORT_FAILED", "Input image is not an animated image")
+                self._assert_frame_pixel_budget(animated.size, 1)
+                default_duration = animated.info.get("duration", 100)
+                frame_index = -1
+                frame_start = elapsed = 0
+                for idx in range(frame_count):
+                    animated.seek(idx)
+                    duration = animated.info.get("duration", default_duration)
+                    if not isinstance(duration, (int, float)) or duration <= 0:
+                        duration = default_duration
+                    if frame_index < 0 and timestamp < elapsed + duration:
+                        frame_index, frame_start = idx, elapsed
+                    elapsed += duration
+                total_duration = int(round(elapsed))
+                if timestamp < 0 or frame_index < 0:
+                    return _error_response(
+                        "GIF_TIMESTAMP_OUT_OF_RANGE",
+                        f"timestamp_ms {timestamp} is outside the {total_duration} ms animation",
+                    )
+                animated.seek(frame_index)
+                self._ensure_parent_dir(output_path)
+                with animated.convert("RGBA") as frame:
+                    frame.save(output_path, format=output_format.upper())
+
+            return {
+                "success": True,
+                "input_path": input_path,
+                "output_path": output_path,
+                "frame_count": frame_count,
+                "export_count": 1,
+                "frame_paths": [output_path],
+                "frame_index": frame_index,
+                "timestamp_ms": timestamp,
+                "frame_start_ms": int(round(frame_start)),
+                "total_duration_ms": total_duration,
+            }
+        except FileNotFoundError:
+            return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
+        except UnidentifiedImageError:
+            return _error_response("GIF_UNSUPPORTED_IMAGE", f"Unsupported image format: {input_path}")
+        except Exception as exc:
+            logger.error("GIF frame export failed: %s", exc, exc_info=True)
+            return _error_response("GIF_EXPORT_FAILED", str(exc))
+
+    def contact_sheet(self, input_path, output_path, columns=0, max_frames=0):
+        """One PNG with the (evenly sampled) frames in a grid, each labelled with its frame index."""
+        sheet = None
+        try:
+            with Image.open(input_path) as animated:
+                frame_count = int(getattr(animated, "n_frames", 1) or 1)
+                indices = self._sample_frame_indices(frame_count, self._sanitize_dimension(max_frames))
+                tile_count = len(indices)
+                cols = self._sanitize_dimension(columns) or math.ceil(math.sqrt(tile_count))
+                cols = max(1, min(cols, tile_count))
+                rows = math.ceil(tile_count / cols)
+
+                # Shrink the tiles when the full-size sheet would not fit the frame pixel budget.
+                frame_width, frame_height = animated.size
+                full_width = cols * frame_width + (cols + 1) * SHEET_GUTTER
+                full_height = rows * (frame_height + SHEET_LABEL_HEIGHT) + (rows + 1) * SHEET_GUTTER
+                scale = min(1.0, math.sqrt(MAX_FRAME_PIXEL_BUDGET / float(full_width * full_height)))
+                tile_width = max(1, int(frame_width * scale))
+                tile_height = max(1, int(frame_height * scale))
+                width = cols * tile_width + (cols + 1) * SHEET_GUTTER
+                height = rows * (tile_height + SHEET_LABEL_HEIGHT) + (rows + 1) * SHEET_GUTTER
+
+                sheet = Image.new("RGB", (width, height), SHEET_BACKGROUND)
+                draw = ImageDraw.Draw(sheet)
+                font = ImageFont.load_default()
+                resample = self._get_resample_filter()
+                for position, frame_idx in enumerate(indices):
+                    animated.seek(frame_idx)
+                    left = SHEET_GUTTER + (position % cols) * (tile_width + SHEET_GUTTER)
+                    top = SHEET_GUTTER + (position // cols) * (tile_height + SHEET_LABEL_HEIGHT + SHEET_GUTTER)
+                    with animated.convert("RGBA") as frame:
+                        tile = frame if scale >= 1.0 else frame.resize((tile_width, tile_height), resample=resample)
+                        try:
+                            sheet.paste(tile, (left, top), tile)
+                        finally:
+                            if tile is not frame:
+                                tile.close()
+                    draw.text((left, top + tile_height + 1), f"#{frame_idx}", fill=(0, 0, 0), font=font)
+
+            self._ensure_parent_dir(output_path)
+            sheet.save(output_path, format="PNG")
+            return {
+                "success": True,
+                "input_path": input_path,
+                "output_path": output_path,
+                "frame_count": frame_count,
+                "sheet_frame_count": tile_count,
+                "frame_indices": indices,
+                "grid_columns": cols,
+                "grid_rows": rows,
+                "width": width,
+                "height": height,
+            }
+        except FileNotFoundError:
+            return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
+        except UnidentifiedImageError:
+            return _error_response("GIF_UNSUPPORTED_IMAGE", f"Unsupported image format: {input_path}")
+        except Exception as exc:
+            logger.error("GIF contact sheet failed: %s", exc, exc_info=True)
+            return _error_response("GIF_CONTACT_SHEET_FAILED", str(exc))
+        finally:
+            if sheet is not None:
+                sheet.close()
+
     def reverse_gif(self, input_path, output_path, loop=None):
         try:
             with Image.open(input_path) as gif:
@@ -132,6 +433,56 @@ class GIFTool:
             logger.error("GIF reverse failed: %s", exc, exc_info=True)
             return _error_response("GIF_REVERSE_FAILED", str(exc))
 
+    def boomerang_gif(self, input_path, output_path, loop=None):
+        frames = []
+        quantized = []
+        try:
+            with Image.open(input_path) as gif:
+                if gif.format != "GIF":
+                    raise ValueError("Input file is not a GIF")
+                frames, durations, _gif_loop, _disposals = self._extract_gif_frames_with_disposal(gif)
+            source_frame_count = len(frames)
+            quantized = [self._quantize_rgba_frame(frame, 255) for frame in frames]
+            # Play back without repeating the last frame or the first one the loop returns to.
+            quantized += [frame.copy() for frame in quantized[-2:0:-1]]
+            durations += durations[-2:0:-1]
+
+            # Frames are composited onto the full canvas, so each one replaces the previous outright.
+            loop_value = 0 if loop is None else loop
+            self._ensure_parent_dir(output_path)
+            self._save_gif(
+                quantized,
+                output_path,
+                durations,
+                loop_value,
+                disposal=2,
+                transparency=255,
+            )
+
+            return {
+                "success": True,
+                "input_path": input_path,
+                "output_path": output_path,
+                "frame_count": len(quantized),
+                "source_frame_count": source_frame_count,
+                "loop": loop_value,
+            }
+        except FileNotFoundError:
+            return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
+        except UnidentifiedImageError:
+            return _error_response("GIF_UNSUPPORTED_IMAGE", f"Unsupported image format: {input_path}")
+        except MemoryError as exc:
+            return _error_response("GIF_MEMORY_LIMIT", "GIF is too large to process safely", exc)
+        except Exception as exc:
+            logger.error("GIF boomerang failed: %s", exc, exc_info=True)
+            return _error_response("GIF_BOOMERANG_FAILED", str(exc))
+        finally:
+            for frame in (*frames, *quantized):
+                try:
+                    frame.close()
+                except Exception:
+                    pass
+
     def change_speed(self, input_path, output_path, speed_factor, loop=None):
         try:
             with Image.open(input_path) as gif:
@@ -190,7 +541,7 @@ class GIFTool:
             logger.error("GIF speed change failed: %s", exc, exc_info=True)
             return _error_response("GIF_SPEED_CHANGE_FAILED", str(exc))
 
-    def compress_gif(self, input_path, output_path, quality=90, loop=None):
+    def compress_gif(self, input_path, output_path, quality=90, loop=None, max_colors=None, dither=True):
         try:
             with Image.open(input_path) as gif:
                 if gif.format != "GIF":
@@ -198,10 +549,17 @@ class GIFTool:
                 frames, durations, gif_loop, disposals = self._extract_gif_frames_with_disposal(gif)
 
             quality_value = self._sanitize_quality(quality)
-            palette_size = self._quality_to_palette_size(quality_value)
+            # An explicit palette size wins over the one derived from quality.
+            if max_colors:
+                palette_size = max(MIN_GIF_COLORS, min(MAX_GIF_COLORS, int(max_colors)))
+            else:
+                palette_size = self._quality_to_palette_size(quality_value)
+            use_dither = self._coerce_bool(dither, default=True)
+            used_colors = set()
             for idx, frame in enumerate(frames):
                 try:
-                    frames[idx] = self._quantize_rgba_frame(frame, palette_size)
+                    frames[idx] = self._quantize_rgba_frame(frame, palette_size, dither=use_dither)
+                    used_colors.update(self._opaque_palette_colors(frames[idx]))
                 finally:
                     frame.close()
 
@@ -230,6 +588,9 @@ class GIFTool:
                 "output_path": output_path,
                 "frame_count": frame_count,
                 "quality": quality_value,
+                "max_colors": palette_size,
+                "dither": use_dither,
+                "color_count": len(used_colors),
                 "input_size": in_size,
                 "output_size": out_size,
             }
@@ -389,6 +750,62 @@ class GIFTool:
             logger.error("GIF resize failed: %s", exc, exc_info=True)
             return _error_response("GIF_RESIZE_FAILED", str(exc))
 
+    def crop_gif(self, input_path, output_path, x, y, width, height, loop=None):
+        try:
+            with Image.open(input_path) as gif:
+                if gif.format != "GIF":
+                    raise ValueError("Input file is not a GIF")
+                original_width, original_height = gif.size
+                box = self._resolve_crop_box((original_width, original_height), x, y, width, height)
+                if isinstance(box, dict):
+                    return box
+                frames, durations, gif_loop, disposals = self._extract_gif_frames_with_disposal(gif)
+            for idx, frame in enumerate(frames):
+                cropped = frame.crop(box)
+                try:
+                    frames[idx] = self._quantize_rgba_frame(cropped, 255)
+                finally:
+                    cropped.close()
+                    frame.close()
+
+            loop_value = gif_loop if loop is None else loop
+            self._ensure_parent_dir(output_path)
+            frame_count = len(frames)
+            try:
+                self._save_gif(
+                    frames,
+                    output_path,
+                    durations,
+                    loop_value,
+                    disposal=disposals,
+                    transparency=255,
+                )
+            finally:
+                for frame in frames:
+                    frame.close()
+
+            return {
+                "success": True,
+                "input_path": input_path,
+                "output_path": output_path,
+                "frame_count": frame_count,
+                "width": box[2] - box[0],
+                "height": box[3] - box[1],
+                "original_width": original_width,
+                "original_height": original_height,
+                "crop_x": box[0],
+                "crop_y": box[1],
+            }
+        except FileNotFoundError:
+            return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
+        except UnidentifiedImageError:
+            return _error_response("GIF_UNSUPPORTED_IMAGE", f"Unsupported image format: {input_path}")
+        except MemoryError as exc:
+            return _error_response("GIF_MEMORY_LIMIT", "GIF is too large to process safely", exc)
+        except Exception as exc:
+            logger.error("GIF crop failed: %s", exc, exc_info=True)
+            return _error_response("GIF_CROP_FAILED", str(exc))
+
     def convert_animation(self, input_path, output_path, output_format, quality=90, loop=None):
         frames = []
         quantized = []
@@ -430,6 +847,7 @@ class GIFTool:
                 "frame_count": frame_count,
                 "output_format": target,
                 "source_format": source_format,
+                "loop": loop_value,
             }
         except FileNotFoundError:
             return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
@@ -722,6 +1140,27 @@ class GIFTool:
             return text
         raise ValueError(f"Unsupported output format: {value}")
 
+    def _resolve_crop_box(self, size, x, y, width, height):
+        """(left, top, right, bottom) for the crop, or an error response when it leaves the canvas."""
+        left, top = self._sanitize_dimension(x), self._sanitize_dimension(y)
+        crop_width, crop_height = self._sanitize_dimension(width), self._sanitize_dimension(height)
+        if crop_width <= 0 or crop_height <= 0:
+            return _error_response("GIF_CROP_INVALID_SIZE", "Missing crop_width or crop_height for crop")
+        if left + crop_width > size[0] or top + crop_height > size[1]:
+            return _error_response(
+                "GIF_CROP_OUT_OF_BOUNDS",
+                f"Crop {crop_width}x{crop_height} at ({left}, {top}) exceeds the {size[0]}x{size[1]} GIF",
+            )
+        return left, top, left + crop_width, top + crop_height
+
+    def _sample_frame_indices(self, frame_count, limit):
+        """All frame indices, or `limit` of them spread evenly from the first frame to the last."""
+        if limit <= 0 or limit >= frame_count:
+            return list(range(frame_count))
+        if limit == 1:
+            return [0]
+        return [round(i * (frame_count - 1) / (limit - 1)) for i in range(limit)]
+
     def _sanitize_dimension(self, value):
         try:
             dim = int(round(float(value)))
@@ -854,7 +1293,7 @@ class GIFTool:
         final_disposals = retimed_disposals or [disposals[-1] if disposals else 0]
         return final_frames, final_durations, final_disposals
 
-    def _quantize_rgba_frame(self, frame, palette_size):
+    def _quantize_rgba_frame(self, frame, palette_size, dither=True):
         rgba = frame.convert("RGBA")
         try:
             alpha = rgba.getchannel("A")
@@ -864,7 +1303,7 @@ class GIFTool:
                 quantized = rgba.quantize(
                     colors=colors,
                     method=Image.FASTOCTREE,
-                    dither=Image.FLOYDSTEINBERG,
+                    dither=Image.FLOYDSTEINBERG if dither else Image.NONE,
                 )
                 palette = quantized.getpalette() or []
                 if len(palette) < 768:
@@ -881,6 +1320,15 @@ class GIFTool:
         finally:
             rgba.close()
 
+    def _opaque_palette_colors(self, quantized):
+        """RGB values of the palette entries a quantized frame actually uses, minus the transparent index."""
+        palette = quantized.getpalette() or []
+        return {
+            tuple(palette[index * 3 : index * 3 + 3])
+            for _count, index in quantized.getcolors(256) or []
+            if index != 255
+        }
+
     def _ensure_parent_dir(self, path):
         parent = os.path.dirname(path)
         if parent:
@@ -912,18 +1360,26 @@ def _normalize_action(value):
         return "export_frames"
     if action in ("reverse", "reverse_gif"):
         return "reverse"
+    if action in ("boomerang", "ping_pong", "pingpong"):
+        return "boomerang"
     if action in ("change_speed", "change_frame_rate", "speed"):
         return "change_speed"
     if action in ("compress", "compress_gif"):
         return "compress"
     if action in ("resize", "resize_gif", "scale", "scale_gif"):
         return "resize"
+    if action in ("crop", "crop_gif"):
+        return "crop"
+    if action in ("contact_sheet", "montage"):
+        return "contact_sheet"
     if action in ("build", "compose", "combine", "build_gif", "make_gif"):
         return "build_gif"
     if action in ("convert", "convert_animation", "transcode", "convert_animated", "convert_anim"):
         return "convert_animation"
     if action == "get_frame_count":
         return "get_frame_count"
+    if action in ("inspect", "details", "get_details"):
+        return "inspect"
     return action
 
 
@@ -937,10 +1393,36 @@ def _coerce_input_paths(input_data):
     return []
 
 
+def _request_limit(input_data, key, default):
+    value = input_data.get(key)
+    if value is None or value == "":
+        return default, None
+    try:
+        limit = int(value)
+    except (TypeError, ValueError):
+        limit = 0
+    if limit < 1 or isinstance(value, bool):
+        return None, _error_response("GIF_BAD_REQUEST", f"Invalid {key}: {value} (expected a positive integer)")
+    return limit, None
+
+
 def handle_request(input_data):
     tool = GIFTool()
     action = _normalize_action(input_data.get("action"))
 
+    if action in GUARDED_ACTIONS:
+        max_frames, error = _request_limit(input_data, "max_frames", DEFAULT_MAX_FRAMES)
+        if error:
+            return error
+        max_total_megapixels, error = _request_limit(input_data, "max_total_megapixels", DEFAULT_MAX_TOTAL_MEGAPIXELS)
+        if error:
+            return error
+        input_path = input_data.get("input_path")
+        if input_path:
+            error = animation_limit_error(input_path, max_frames, max_total_megapixels)
+            if error:
+                return error
+
     if action == "get_frame_count":
         input_path = input_data.get("input_path")
         if not input_path:
@@ -958,6 +1440,26 @@ def handle_request(input_data):
         except Exception as exc:
             return _error_response("GIF_GET_FRAME_COUNT_FAILED", str(exc))
 
+    if action == "inspect":
+        input_path = input_data.get("input_path")
+        if not input_path:
+            return _error_response("GIF_BAD_REQUEST", "Missing input_path")
+        try:
+            details = inspect_gif(input_path)
+        except FileNotFoundError:
+            return _error_response("GIF_INPUT_NOT_FOUND", f"Input file not found: {input_path}")
+        if details is None:
+            return _error_response("GIF_UNSUPPORTED_IMAGE", f"Input file is not a GIF: {input_path}")
+        return {"success": True, "input_path": input_path, "file_size": os.path.getsize(input_path), **details}
+
+    if action == "export_frames" and input_data.get("timestamp_ms") is not None:
+        input_path = input_data.get("input_path")
+        output_path = input_data.get("output_path")
+        if not input_path or not output_path:
+            return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
+        output_format = input_data.get("output_format") or input_data.get("format")
+        return tool.export_frame_at(input_path, output_path, input_data.get("timestamp_ms"), output_format)
+
     if action == "export_frames":
         input_path = input_data.get("input_path")
         output_dir = input_data.get("output_dir")
@@ -965,7 +1467,10 @@ def handle_request(input_data):
             return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_dir")
         output_format = input_data.get("output_format") or input_data.get("format") or "png"
         frame_range = _build_frame_range_from_request(input_data)
-        return tool.export_frames(input_path, output_dir, output_format, frame_range)
+        max_files, error = _request_limit(input_data, "max_export_files", DEFAULT_MAX_EXPORT_FILES)
+        if error:
+            return error
+        return tool.export_frames(input_path, output_dir, output_format, frame_range, max_files)
 
     if action == "reverse":
         input_path = input_data.get("input_path")
@@ -975,6 +1480,14 @@ def handle_request(input_data):
             return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
         return tool.reverse_gif(input_path, output_path, loop)
 
+    if action == "boomerang":
+        input_path = input_data.get("input_path")
+        output_path = input_data.get("output_path")
+        loop = input_data.get("loop")
+        if not input_path or not output_path:
+            return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
+        return tool.boomerang_gif(input_path, output_path, loop)
+
     if action == "change_speed":
         input_path = input_data.get("input_path")
         output_path = input_data.get("output_path")
@@ -991,19 +1504,56 @@ def handle_request(input_data):
         loop = input_data.get("loop")
         if not input_path or not output_path:
             return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
-        return tool.compress_gif(input_path, output_path, quality, loop)
+        return tool.compress_gif(
+            input_path,
+            output_path,
+            quality,
+            loop,
+            max_colors=input_data.get("max_colors"),
+            dither=input_data.get("dither", True),
+        )
 
     if action == "resize":
         input_path = input_data.get("input_path")
         output_path = input_data.get("output_path")
         width = input_data.get("width")
         height = input_data.get("height")
-        maintain_aspect = input_data.get("maintain_aspect", True)
+        # `maintain_ar` is the converter's name for the same switch.
+        maintain_aspect = input_data.get("maintain_aspect", input_data.get("maintain_ar", True))
         loop = input_data.get("loop")
         if not input_path or not output_path:
             return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
         return tool.resize_gif(input_path, output_path, width, height, maintain_aspect, loop)
 
+    if action == "contact_sheet":
+        input_path = input_data.get("input_path")
+        output_path = input_data.get("output_path")
+        if not input_path or not output_path:
+            return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
+        # `max_frames` is the decode guard above; the host moves a contact sheet's `max_frames` here.
+        return tool.contact_sheet(
+            input_path,
+            output_path,
+            input_data.get("grid_columns", 0),
+            input_data.get("sheet_max_frames", 0),
+        )
+
+    if action == "crop":
+        input_path = input_data.get("input_path")
+        output_path = input_data.get("output_path")
+        loop = input_data.get("loop")
+        if not input_path or not output_path:
+            return _error_response("GIF_BAD_REQUEST", "Missing input_path or output_path")
+        return tool.crop_gif(
+            input_path,
+            output_path,
+            input_data.get("crop_x", 0),
+            input_data.get("crop_y", 0),
+            input_data.get("crop_width"),
+            input_data.get("crop_height"),
+            loop,
+        )
+
     if action == "build_gif":
         output_path = input_data.get("output_path")
         fps = input_data.get("fps")
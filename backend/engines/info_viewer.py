@@ -6,6 +6,7 @@ Extracts real, verifiable image information from file headers, container
 structures, standard metadata blocks, and supported EXIF readers.
 """
 
+import html
 import json
 import logging
 import os
@@ -18,17 +19,24 @@ from pathlib import Path
 
 import exifread
 import piexif
-from PIL import Image, UnidentifiedImageError
+from PIL import Image, ImageOps, UnidentifiedImageError
 
 from converter import (
     extract_svg_attribute,
     extract_svg_root_fragment,
     parse_svg_intrinsic_size_from_text,
+    read_level_roll_degrees,
 )
 
 
 logger = logging.getLogger(__name__)
 
+# Side of the dHash grid; HASH_SIZE * HASH_SIZE bits per hash.
+HASH_SIZE = 8
+# Dominant colours are counted on a copy at most this many pixels on a side.
+PALETTE_SAMPLE_SIZE = 128
+MAX_PALETTE_SIZE = 16
+
 
 class InfoViewer:
     """Handles image information extraction and display."""
@@ -54,6 +62,7 @@ class InfoViewer:
         "modified": "修改时间",
         "orientation": "方向",
         "has_alpha": "Alpha 通道",
+        "is_cmyk": "CMYK",
         "is_animated": "动画",
         "frame_count": "帧数",
         "duration_ms": "时长(ms)",
@@ -73,6 +82,7 @@ class InfoViewer:
         "SVG": "image/svg+xml",
         "HEIC": "image/heic",
         "HEIF": "image/heif",
+        "JXL": "image/jxl",
     }
 
     MODE_BIT_DEPTHS = {
@@ -109,10 +119,58 @@ class InfoViewer:
         "avis",
     }
 
+    # IPTC-IIM application record (2) datasets reported under metadata["iptc"].
+    IPTC_DATASETS = {
+        5: "title",
+        15: "category",
+        25: "keywords",
+        40: "instructions",
+        55: "date_created",
+        80: "byline",
+        85: "byline_title",
+        90: "city",
+        95: "province_state",
+        101: "country",
+        105: "headline",
+        110: "credit",
+        115: "source",
+        116: "copyright",
+        120: "caption",
+        122: "caption_writer",
+    }
+
+    # XMP properties reported under metadata["xmp"], keyed by their usual prefixed name.
+    XMP_PROPERTIES = (
+        "dc:title",
+        "dc:description",
+        "dc:creator",
+        "dc:subject",
+        "dc:rights",
+        "photoshop:Headline",
+        "photoshop:City",
+        "photoshop:State",
+        "photoshop:Country",
+        "photoshop:Credit",
+        "photoshop:Source",
+        "photoshop:DateCreated",
+        "xmp:CreateDate",
+        "xmp:ModifyDate",
+        "xmp:CreatorTool",
+        "xmp:Rating",
+        "xmpRights:UsageTerms",
+        "xmpRights:WebStatement",
+    )
+
+    IPTC_FORMATS = {"JPEG", "JPG", "TIFF", "TIF"}
+    XMP_FORMATS = {"JPEG", "JPG", "TIFF", "TIF", "PNG", "WEBP", "GIF"}
+    XMP_SIGNATURE = b"http://ns.adobe.com/xap/1.0/\x00"
+    TIFF_IPTC_TAG = 33723
+    TIFF_XMP_TAG = 700
+
     def __init__(self):
         logger.info("InfoViewer initialized")
 
-    def get_info(self, input_path):
+    def get_info(self, input_path, include_hash=False, palette_size=0):
         try:
             logger.info("Reading image info: %s", input_path)
 
@@ -134,11 +192,26 @@ class InfoViewer:
                 "extra": extra_meta,
             }
             flat_meta = self._flatten_metadata(metadata_groups)
+            gps = self._get_gps_coordinates(input_path, image_info.get("format"))
+            iptc_packet, xmp_packet = self._read_embedded_packets(
+                input_path, image_info.get("format")
+            )
+            # Decoded fields get their own groups; the flat `exif` map stays EXIF and container data.
+            metadata_groups["iptc"] = self._parse_iptc(iptc_packet)
+            metadata_groups["xmp"] = self._parse_xmp(xmp_packet)
             basic = self._build_basic_info(input_path, file_info, image_info)
             fields = self._build_fields(
                 basic, format_details, metadata_groups, piexif_meta
             )
 
+            perceptual_hash = ""
+            if include_hash:
+                perceptual_hash = self._perceptual_hash(input_path, warnings)
+            dominant_colors = []
+            if palette_size and int(palette_size) > 0:
+                dominant_colors = self._dominant_colors(input_path, int(palette_size), warnings)
+            has_alpha, has_transparency = self._alpha_usage(input_path, warnings)
+
             result = {
                 "file_name": file_info["name"],
                 "file_size": file_info["size"],
@@ -154,6 +227,15 @@ class InfoViewer:
                 "format_details": format_details,
                 "fields": fields,
                 "warnings": warnings,
+                "level_roll": image_info.get("level_roll"),
+                "has_level_data": image_info.get("level_roll") is not None,
+                "latitude": gps[0] if gps else 0.0,
+                "longitude": gps[1] if gps else 0.0,
+                "has_gps": gps is not None,
+                "perceptual_hash": perceptual_hash,
+                "dominant_colors": dominant_colors,
+                "has_alpha": has_alpha,
+                "has_transparency": has_transparency,
                 "success": True,
             }
 
@@ -171,6 +253,11 @@ class InfoViewer:
             logger.error("Failed to get image info: %s", exc, exc_info=True)
             return {"success": False, "error": f"[INTERNAL] {str(exc)}"}
 
+    def _fill_level_roll(self, info, xmp):
+        roll = read_level_roll_degrees(xmp)
+        if roll is not None:
+            info["level_roll"] = roll
+
     def _get_file_info(self, file_path):
         stat = os.stat(file_path)
         return {
@@ -195,6 +282,7 @@ class InfoViewer:
             "modified": int(file_info["modified"]),
             "orientation": self._stringify_value(image_info.get("orientation")),
             "has_alpha": bool(image_info.get("has_alpha")),
+            "is_cmyk": str(image_info.get("mode") or "").upper() == "CMYK",
             "is_animated": bool(image_info.get("is_animated")),
             "frame_count": int(image_info.get("frame_count") or 0),
             "duration_ms": int(image_info.get("duration_ms") or 0),
@@ -218,6 +306,7 @@ class InfoViewer:
             "dpi_y",
             "orientation",
             "has_alpha",
+            "is_cmyk",
             "is_animated",
             "frame_count",
             "duration_ms",
@@ -294,6 +383,8 @@ class InfoViewer:
         return f"{source}.{normalized}"
 
     def _metadata_group_for_field(self, source, key):
+        if source in ("iptc", "xmp"):
+            return source
         if source in ("exifread", "piexif"):
             prefix = key.split(":", 1)[0].split(" ", 1)[0].lower()
             if prefix == "gps":
@@ -438,6 +529,243 @@ class InfoViewer:
                 tags_out[f"{ifd_name}:{tag_name}"] = self._stringify_value(value)
         return tags_out
 
+    def _read_embedded_packets(self, input_path, fmt=None):
+        """Raw IPTC-IIM and XMP packets (bytes, or None) embedded in the file."""
+        fmt = str(fmt or "").upper()
+        if fmt not in self.IPTC_FORMATS and fmt not in self.XMP_FORMATS:
+            return None, None
+        iptc = xmp = None
+        try:
+            with Image.open(input_path) as img:
+                for marker, data in getattr(img, "applist", None) or []:
+                    if marker == "APP13" and iptc is None:
+                        iptc = self._photoshop_iptc_block(data)
+                    elif marker == "APP1" and xmp is None and data.startswith(self.XMP_SIGNATURE):
+                        xmp = data[len(self.XMP_SIGNATURE) :]
+                tags = getattr(img, "tag_v2", None)
+                if tags is not None:
+                    raw_iptc = tags.get(self.TIFF_IPTC_TAG)
+                    if isinstance(raw_iptc, (tuple, list)) and all(
+                        isinstance(v, int) and 0 <= v <= 255 for v in raw_iptc
+                    ):
+                        raw_iptc = bytes(raw_iptc)
+                    if iptc is None and isinstance(raw_iptc, bytes):
+                        iptc = raw_iptc
+                    if xmp is None and isinstance(tags.get(self.TIFF_XMP_TAG), bytes):
+                        xmp = tags.get(self.TIFF_XMP_TAG)
+                if xmp is None:
+                    xmp = img.info.get("xmp") or img.info.get("XML:com.adobe.xmp")
+        except Exception as exc:
+            logger.warning("Embedded IPTC/XMP read failed: %s", exc)
+        if isinstance(xmp, str):
+            xmp = xmp.encode("utf-8")
+        return iptc, xmp
+
+    def _photoshop_iptc_block(self, data):
+        """The IPTC-IIM resource (0x0404) from a Photoshop 3.0 APP13 segment, or None."""
+        prefix = b"Photoshop 3.0\x00"
+        if not data.startswith(prefix):
+            return None
+        pos = len(prefix)
+        while pos + 12 <= len(data) and data[pos : pos + 4] == b"8BIM":
+            resource_id = struct.unpack(">H", data[pos + 4 : pos + 6])[0]
+            name_length = data[pos + 6]
+            # Pascal name (length byte included) is padded to an even size, as is the data.
+            pos += 6 + name_length + 1 + ((name_length + 1) % 2)
+            if pos + 4 > len(data):
+                break
+            size = struct.unpack(">I", data[pos : pos + 4])[0]
+            pos += 4
+            if resource_id == 0x0404:
+                return data[pos : pos + size]
+            pos += size + (size % 2)
+        return None
+
+    def _parse_iptc(self, data):
+        """Application-record datasets by name; repeated ones (keywords, bylines) joined with "; "."""
+        values = {}
+        if not data:
+            return {}
+        encoding = "latin-1"
+        pos = 0
+        while pos + 5 <= len(data) and data[pos] == 0x1C:
+            record, dataset = data[pos + 1], data[pos + 2]
+            length = struct.unpack(">H", data[pos + 3 : pos + 5])[0]
+            pos += 5
+            if length & 0x8000:
+                # Extended dataset: the low bits give how many bytes hold the real length.
+                count = length & 0x7FFF
+                length = int.from_bytes(data[pos : pos + count], "big")
+                pos += count
+            value = data[pos : pos + length]
+            pos += length
+            if record == 1 and dataset == 90 and value in (b"\x1b%G", b"\x1b%/I"):
+                encoding = "utf-8"
+            elif record == 2 and dataset in self.IPTC_DATASETS:
+                values.setdefault(self.IPTC_DATASETS[dataset], []).append(value)
+        decoded = {}
+        for name, items in values.items():
+            texts = [self._decode_iptc_text(item, encoding) for item in items]
+            text = "; ".join(item for item in texts if item)
+            if text:
+                decoded[name] = self._stringify_value(text)
+        return decoded
+
+    def _decode_iptc_text(self, value, encoding):
+        try:
+            return value.decode(encoding).strip()
+        except UnicodeDecodeError:
+            return value.decode("latin-1").strip()
+
+    def _parse_xmp(self, data):
+        """Known XMP properties as text, found by scanning the packet instead of parsing XML.
+
+        Attribute (`dc:rights="..."`) and element forms are both read; rdf:Bag/Seq/Alt lists
+        are joined with "; ".
+        """
+        if not data:
+            return {}
+        text = data.decode("utf-8", errors="replace") if isinstance(data, bytes) else str(data)
+        values = {}
+        for prop in self.XMP_PROPERTIES:
+            escaped = re.escape(prop)
+            match = re.search(rf"<{escaped}(?:\s[^>]*)?>(.*?)</{escaped}>", text, re.S)
+            if match:
+                items = re.findall(r"<rdf:li(?:\s[^>]*)?>(.*?)</rdf:li>", match.group(1), re.S)
+                parts = items if items else [match.group(1)]
+            else:
+                match = re.search(rf"[\s<]{escaped}\s*=\s*(?:\"([^\"]*)\"|'([^']*)')", text)
+                parts = [match.group(1) if match.group(1) is not None else match.group(2)] if match else []
+            cleaned = [html.unescape(re.sub(r"<[^>]+>", "", part)).strip() for part in parts]
+            joined = "; ".join(part for part in cleaned if part)
+            if joined:
+                values[prop] = self._stringify_value(joined)
+        return values
+
+    def _perceptual_hash(self, input_path, warnings):
+        """64-bit difference hash (dHash) as 16 hex digits, or "" when the pixels cannot be read.
+
+        The upright first frame is shrunk to 9x8 grey pixels and each bit records whether a
+        pixel is brighter than its right-hand neighbour, so re-encodes and resizes of the
+        same picture land a few bits apart.
+        """
+        try:
+            with Image.open(input_path) as img:
+                upright = ImageOps.exif_transpose(img)
+                try:
+                    with upright.convert("L").resize((HASH_SIZE + 1, HASH_SIZE), Image.LANCZOS) as small:
+                        pixels = list(small.getdata())
+                finally:
+                    if upright is not img:
+                        upright.close()
+        except Exception as exc:
+            warnings.append({"code": "HASH_FAILED", "message": str(exc)})
+            return ""
+        bits = 0
+        for row in range(HASH_SIZE):
+            offset = row * (HASH_SIZE + 1)
+            for col in range(HASH_SIZE):
+                bits = (bits << 1) | int(pixels[offset + col] > pixels[offset + col + 1])
+        return f"{bits:0{HASH_SIZE * HASH_SIZE // 4}x}"
+
+    def _dominant_colors(self, input_path, count, warnings):
+        """Up to `count` "#rrggbb" colours, most common first, from a median-cut quantization.
+
+        The image is shrunk first since the palette barely changes with size; transparent
+        pixels are left out of the counts.
+        """
+        try:
+            with Image.open(input_path) as img:
+                img.draft("RGB", (PALETTE_SAMPLE_SIZE, PALETTE_SAMPLE_SIZE))
+                rgba = img.convert("RGBA")
+            try:
+                rgba.thumbnail((PALETTE_SAMPLE_SIZE, PALETTE_SAMPLE_SIZE))
+                with rgba.convert("RGB") as rgb, rgb.quantize(
+                    colors=max(1, min(MAX_PALETTE_SIZE, count)), method=Image.MEDIANCUT
+                ) as quantized:
+                    palette = quantized.getpalette() or []
+                    counts = {}
+                    for index, alpha in zip(quantized.getdata(), rgba.getchannel("A").getdata()):
+                        if alpha >= 128:
+                            counts[index] = counts.get(index, 0) + 1
+            finally:
+                rgba.close()
+        except Exception as exc:
+            warnings.append({"code": "PALETTE_FAILED", "message": str(exc)})
+            return []
+        ranked = sorted(counts.items(), key=lambda item: (-item[1], item[0]))
+        return ["#{:02x}{:02x}{:02x}".format(*palette[index * 3 : index * 3 + 3]) for index, _ in ranked]
+
+    def _alpha_usage(self, input_path, warnings):
+        """(has_alpha, has_transparency) for the first frame.
+
+        `has_alpha` means the file carries an alpha channel or a transparent palette entry;
+        `has_transparency` only when some pixel is actually below full opacity, so a fully
+        opaque RGBA image can be flattened to JPEG without changing how it looks. Only the
+        alpha band (or the palette/gray histogram) is read, never a full RGBA copy.
+        """
+        try:
+            with Image.open(input_path) as img:
+                alpha_band = next((band for band in img.getbands() if band in ("A", "a")), None)
+                if alpha_band is not None:
+                    low, _high = img.getchannel(alpha_band).getextrema()
+                    return True, low < 255
+                transparency = img.info.get("transparency")
+                if transparency is None:
+                    return False, False
+                if img.mode in ("P", "L"):
+                    # One count per palette index (or gray value); a transparent entry counts only if used.
+                    if isinstance(transparency, bytes):
+                        transparent = [index for index, alpha in enumerate(transparency) if alpha < 255]
+                    else:
+                        transparent = [int(transparency)]
+                    counts = img.histogram()
+                    return True, any(counts[index] for index in transparent if index < len(counts))
+                # A color key on RGB (or other modes) is rare; compare through the converted alpha.
+                with img.convert("RGBA") as rgba:
+                    low, _high = rgba.getchannel("A").getextrema()
+        except Exception as exc:
+            warnings.append({"code": "ALPHA_FAILED", "message": str(exc)})
+            return False, False
+        return True, low < 255
+
+    def _get_gps_coordinates(self, input_path, fmt=None):
+        """(latitude, longitude) in signed decimal degrees from the EXIF GPS IFD, or None."""
+        supported = {"JPEG", "JPG", "TIFF", "TIF", "WEBP"}
+        if fmt and fmt.upper() not in supported:
+            return None
+        try:
+            gps_ifd = piexif.load(input_path).get("GPS") or {}
+        except Exception as exc:
+            logger.warning("piexif GPS load failed: %s", exc)
+            return None
+        latitude = self._gps_degrees(
+            gps_ifd.get(piexif.GPSIFD.GPSLatitude), gps_ifd.get(piexif.GPSIFD.GPSLatitudeRef), b"S", 90
+        )
+        longitude = self._gps_degrees(
+            gps_ifd.get(piexif.GPSIFD.GPSLongitude), gps_ifd.get(piexif.GPSIFD.GPSLongitudeRef), b"W", 180
+        )
+        if latitude is None or longitude is None:
+            return None
+        return latitude, longitude
+
+    def _gps_degrees(self, value, ref, negative_ref, limit):
+        """Degrees/minutes/seconds rationals to decimal degrees, negated for the S or W reference."""
+        if not isinstance(value, (tuple, list)) or not value:
+            return None
+        try:
+            parts = [float(num) / float(den) for num, den in value[:3]]
+        except (TypeError, ValueError, ZeroDivisionError):
+            return None
+        degrees = sum(part / (60.0**idx) for idx, part in enumerate(parts))
+        if isinstance(ref, str):
+            ref = ref.encode("ascii", errors="ignore")
+        if isinstance(ref, bytes) and ref.strip(b"\x00 ").upper() == negative_ref:
+            degrees = -degrees
+        if abs(degrees) > limit:
+            return None
+        return round(degrees, 7)
+
     def _fill_image_info_from_exif(self, image_info, exifread_meta, piexif_meta):
         info = dict(image_info or {})
         if not info.get("width"):
@@ -813,6 +1141,8 @@ class InfoViewer:
                             extra[f"PNG:Text:{key}"] = self._stringify_value(
                                 text.decode("utf-8", errors="replace")
                             )
+                            if key == "XML:com.adobe.xmp":
+                                self._fill_level_roll(info, text)
                         except (ValueError, zlib.error) as exc:
                             warnings.append(
                                 {"code": "PNG_ITXT_PARSE_FAILED", "message": str(exc)}
@@ -1147,6 +1477,7 @@ class InfoViewer:
                     ):
                         xmp = data[len(b"http://ns.adobe.com/xap/1.0/\x00") :]
                         extra["JPEG:XMP"] = self._stringify_value(xmp)
+                        self._fill_level_roll(info, xmp)
                     elif marker_id == 0xE2 and data.startswith(
                         b"ICC_PROFILE\x00"
                     ) and len(data) >= 14:
@@ -1250,6 +1581,7 @@ class InfoViewer:
                         extra["WEBP:EXIF"] = self._stringify_value(data)
                     elif chunk_type == b"XMP ":
                         extra["WEBP:XMP"] = self._stringify_value(data)
+                        self._fill_level_roll(info, data)
                     elif chunk_type == b"ANIM" and len(data) >= 6:
                         info["is_animated"] = True
                         details["webp.loop_count"] = str(
@@ -1614,7 +1946,7 @@ class InfoViewer:
                     f.write(f"  Bit Depth: {image_info.get('bit_depth')}\n\n")
 
                     metadata = image_info.get("metadata", {})
-                    for group in ("exifread", "piexif", "extra"):
+                    for group in ("exifread", "piexif", "extra", "iptc", "xmp"):
                         group_data = metadata.get(group) or {}
                         if not group_data:
                             continue
@@ -1748,7 +2080,11 @@ def process(input_data):
                     "error": "[BAD_INPUT] Missing required parameter: input_path",
                 }
             viewer = InfoViewer()
-            result = viewer.get_info(input_path)
+            result = viewer.get_info(
+                input_path,
+                include_hash=input_data.get("include_hash") is True,
+                palette_size=input_data.get("palette_size") or 0,
+            )
             if isinstance(result, dict):
                 result["input_path"] = input_path
             return result
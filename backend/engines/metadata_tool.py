@@ -7,6 +7,11 @@ import traceback
 from pathlib import Path
 
 from PIL import Image
+from temp_registry import replace_with_retry
+
+
+ORIENTATION_VALUES = range(1, 9)
+ORIENTATION_EXTENSIONS = (".jpg", ".jpeg")
 
 
 def _as_bool(v):
@@ -93,7 +98,7 @@ def strip_metadata(input_path: str, output_path: str, overwrite: bool):
             _rewrite_without_metadata(input_path, final_output_path)
 
         if tmp_output_path:
-            os.replace(tmp_output_path, input_path)
+            replace_with_retry(tmp_output_path, input_path)
             tmp_output_path = None
             return {"success": True, "input_path": input_path, "output_path": input_path}
         return {"success": True, "input_path": input_path, "output_path": output_path}
@@ -105,6 +110,44 @@ def strip_metadata(input_path: str, output_path: str, overwrite: bool):
             pass
 
 
+def _temp_beside(path: str) -> str:
+    final_dir = os.path.dirname(os.path.abspath(path)) or "."
+    os.makedirs(final_dir, exist_ok=True)
+    with tempfile.NamedTemporaryFile(delete=False, dir=final_dir, suffix=Path(path).suffix or ".tmp") as tmp:
+        return tmp.name
+
+
+def set_orientation(input_path: str, output_path: str, orientation: int):
+    """Rewrite only the EXIF Orientation tag of a JPEG; the entropy-coded pixels are copied as is."""
+    import piexif
+
+    exif = piexif.load(input_path)
+    zeroth = exif.setdefault("0th", {})
+    previous = zeroth.get(piexif.ImageIFD.Orientation)
+    zeroth[piexif.ImageIFD.Orientation] = int(orientation)
+    exif_bytes = piexif.dump(exif)
+
+    # Written beside the target and renamed over it, so an in-place edit never leaves a half file.
+    tmp_output_path = _temp_beside(output_path)
+    try:
+        piexif.insert(exif_bytes, input_path, tmp_output_path)
+        replace_with_retry(tmp_output_path, output_path)
+        tmp_output_path = None
+    finally:
+        try:
+            if tmp_output_path:
+                os.remove(tmp_output_path)
+        except Exception:
+            pass
+    return {
+        "success": True,
+        "input_path": input_path,
+        "output_path": output_path,
+        "orientation": int(orientation),
+        "previous_orientation": previous if isinstance(previous, int) else 1,
+    }
+
+
 def process(input_data):
     try:
         action = str(input_data.get("action") or "").strip().lower() or "strip_metadata"
@@ -112,7 +155,7 @@ def process(input_data):
         output_path = input_data.get("output_path")
         overwrite = _as_bool(input_data.get("overwrite", False))
 
-        if action != "strip_metadata":
+        if action not in ("strip_metadata", "set_orientation"):
             return {"success": False, "error": "[INVALID_ACTION] unsupported action", "details": action}
         if not input_path:
             return {"success": False, "error": "[BAD_INPUT] missing input_path"}
@@ -123,6 +166,13 @@ def process(input_data):
         if not os.path.exists(input_path):
             return {"success": False, "error": f"[NOT_FOUND] input file not found: {input_path}"}
 
+        if action == "set_orientation":
+            orientation = input_data.get("orientation")
+            if isinstance(orientation, bool) or not isinstance(orientation, int) or orientation not in ORIENTATION_VALUES:
+                return {"success": False, "error": f"[BAD_INPUT] Invalid orientation: {orientation!r} (expected 1-8)"}
+            if Path(str(input_path)).suffix.lower() not in ORIENTATION_EXTENSIONS:
+                return {"success": False, "error": "[UNSUPPORTED_FORMAT] Orientation can only be rewritten without re-encoding for JPEG"}
+            return set_orientation(str(input_path), str(output_path), orientation)
         return strip_metadata(str(input_path), str(output_path), overwrite)
     except PermissionError as e:
         return {"success": False, "error": f"[PERMISSION_DENIED] {e}"}
@@ -15,7 +15,6 @@ import sys
 import json
 import os
 import math
-import tempfile
 from pathlib import Path
 from PIL import Image
 from reportlab.lib.pagesizes import (
@@ -38,12 +37,23 @@ from reportlab.lib.pagesizes import (
 from reportlab.platypus import SimpleDocTemplate, PageBreak, Image as RLImage
 from reportlab.pdfgen import canvas
 import logging
+from temp_registry import TEMP_REGISTRY, getsize_with_retry, replace_with_retry
 
 from converter import is_svg_path, open_image_with_svg_support
 
 # Configure logging
 logger = logging.getLogger(__name__)
 
+# Permission names a request may grant; each maps to reportlab's canXxx flag.
+PDF_PERMISSIONS = ('print', 'modify', 'copy', 'annotate')
+# Header, footer and page-number text; non-Latin text uses reportlab's built-in CID font.
+PAGE_TEXT_SIZE = 9
+PAGE_CJK_FONT = 'STSong-Light'
+# JPEG quality for JPEG sources shrunk by `image_dpi` without a compression level.
+DOWNSAMPLED_JPEG_QUALITY = 90
+# SimpleDocTemplate's frame pads its content by this much on every side.
+FRAME_PADDING = 6
+
 
 def _coerce_images(input_data):
     images = input_data.get("images", [])
@@ -68,6 +78,214 @@ def _coerce_image_references(values):
     return paths
 
 
+def _coerce_count(value):
+    try:
+        return max(0, int(value or 0))
+    except (TypeError, ValueError):
+        return 0
+
+
+def _grid_shape(per_page, columns=0):
+    """(columns, rows) that hold `per_page` tiles; 0 columns picks the most square grid."""
+    columns = min(columns, per_page) if columns > 0 else int(math.ceil(math.sqrt(per_page)))
+    return columns, int(math.ceil(per_page / columns))
+
+
+def _with_gutters(size, count, gutter):
+    widths = []
+    for index in range(count):
+        if index and gutter:
+            widths.append(gutter)
+        widths.append(size)
+    return widths
+
+
+def _pdf_encryption(user_password, owner_password, permissions):
+    """(StandardEncryption or None, scheme label) for the requested passwords.
+
+    AES-256 needs pyaes. Without it this raises rather than falling back to reportlab's
+    128-bit RC4, which is too weak to hand out as password protection.
+    """
+    user_password = str(user_password or '')
+    owner_password = str(owner_password or '')
+    if not user_password and not owner_password:
+        return None, ''
+    from reportlab.lib.pdfencrypt import StandardEncryption
+
+    if isinstance(permissions, str):
+        permissions = [item for item in permissions.split(',') if item.strip()]
+    if permissions is None:
+        allowed = set(PDF_PERMISSIONS)
+    else:
+        allowed = {str(item).strip().lower() for item in permissions}
+        unknown = sorted(allowed - set(PDF_PERMISSIONS))
+        if unknown:
+            raise ValueError(f"unknown permissions: {', '.join(unknown)}")
+    flags = {f'can{name.capitalize()}': int(name in allowed) for name in PDF_PERMISSIONS}
+    try:
+        return StandardEncryption(user_password, owner_password or None, strength=256, **flags), 'AES-256'
+    except ValueError as e:
+        raise RuntimeError(f"AES-256 encryption needs the pyaes package: {e}") from e
+
+
+def _fill_page_placeholders(text, page, total, title):
+    return (
+        str(text or '')
+        .replace('{page}', str(page))
+        .replace('{total}', str(total))
+        .replace('{title}', str(title or ''))
+    )
+
+
+def _page_font(*texts):
+    """Helvetica, or reportlab's built-in CJK font when any text needs more than Latin-1."""
+    try:
+        for text in texts:
+            str(text or '').encode('latin-1')
+        return 'Helvetica'
+    except UnicodeEncodeError:
+        pass
+    from reportlab.pdfbase import pdfmetrics
+    from reportlab.pdfbase.cidfonts import UnicodeCIDFont
+
+    if PAGE_CJK_FONT not in pdfmetrics.getRegisteredFontNames():
+        pdfmetrics.registerFont(UnicodeCIDFont(PAGE_CJK_FONT))
+    return PAGE_CJK_FONT
+
+
+def _page_decorator(page_numbers, header_text, footer_text, title, total, margin):
+    """onPage callback drawing the header, footer and page number, or None when all are off.
+
+    `total` is the page count the layout produces, which is exact since every layout
+    places a fixed number of images per page.
+    """
+    header_text = str(header_text or '').strip()
+    footer_text = str(footer_text or '').strip()
+    if not (page_numbers or header_text or footer_text):
+        return None
+    font = _page_font(header_text, footer_text, title)
+    # Centre the text in the margin band, but keep it on the page when the margin is tiny.
+    offset = max(margin / 2, PAGE_TEXT_SIZE * 1.5)
+
+    def decorate(canvas_obj, doc):
+        page = canvas_obj.getPageNumber()
+        width, height = doc.pagesize
+        canvas_obj.saveState()
+        canvas_obj.setFont(font, PAGE_TEXT_SIZE)
+        canvas_obj.setFillGray(0.35)
+        if header_text:
+            canvas_obj.drawCentredString(width / 2, height - offset, _fill_page_placeholders(header_text, page, total, title))
+        if footer_text:
+            canvas_obj.drawCentredString(width / 2, offset - PAGE_TEXT_SIZE / 2, _fill_page_placeholders(footer_text, page, total, title))
+        if page_numbers:
+            canvas_obj.drawRightString(width - max(margin, offset), offset - PAGE_TEXT_SIZE / 2, f'{page} / {total}')
+        canvas_obj.restoreState()
+
+    return decorate
+
+
+def _join_keywords(keywords):
+    """The Keywords entry: one text string, conventionally comma-separated."""
+    if isinstance(keywords, str):
+        keywords = keywords.split(',')
+    return ', '.join(str(item).strip() for item in keywords or [] if str(item).strip())
+
+
+def _dpi_pixel_size(size, width_pt, height_pt, dpi):
+    """Pixel size showing `size` at `dpi` in a width_pt x height_pt box; None if no shrink is needed."""
+    if dpi <= 0 or not size:
+        return None
+    max_width = max(1, round(width_pt / 72 * dpi))
+    max_height = max(1, round(height_pt / 72 * dpi))
+    if size[0] <= max_width and size[1] <= max_height:
+        return None
+    ratio = min(max_width / size[0], max_height / size[1])
+    return max(1, round(size[0] * ratio)), max(1, round(size[1] * ratio))
+
+
+def _append_pdf(existing_path, pages_path, output_path, metadata=None):
+    """Write `existing_path`'s pages followed by `pages_path`'s to `output_path`.
+
+    The existing document info is kept, except for the non-empty `metadata` entries
+    (e.g. `/Title`) the request set. Returns the existing page count. The merged file is staged beside the output and
+    renamed over it, so appending in place never leaves a half-written PDF.
+    """
+    try:
+        from pypdf import PdfReader, PdfWriter
+    except ImportError as e:
+        raise RuntimeError("appending to an existing PDF needs the pypdf package") from e
+
+    reader = PdfReader(existing_path)
+    if reader.is_encrypted:
+        raise ValueError(f"{existing_path} is encrypted")
+    existing_pages = len(reader.pages)
+    writer = PdfWriter(clone_from=reader)
+    writer.append(pages_path)
+    updates = {key: value for key, value in (metadata or {}).items() if value}
+    if updates:
+        writer.add_metadata(updates)
+    staging = TEMP_REGISTRY.create(prefix="imageflow_pdf_", suffix=".pdf", dir=os.path.dirname(output_path) or None)
+    try:
+        with open(staging, "wb") as handle:
+            writer.write(handle)
+        replace_with_retry(staging, output_path)
+        TEMP_REGISTRY.unregister(staging)
+    except Exception:
+        TEMP_REGISTRY.discard(staging)
+        raise
+    return existing_pages
+
+
+def _bookmark_labels(images, valid_images, bookmarks, auto_from_filename):
+    """Outline label per valid image ('' for none), dropping the labels of skipped images."""
+    if bookmarks:
+        requested = [str(label or '').strip() for label in bookmarks]
+    elif auto_from_filename:
+        requested = [Path(str(image)).stem for image in images]
+    else:
+        return [''] * len(valid_images)
+    labels = []
+    remaining = iter(zip(images, requested))
+    for valid in valid_images:
+        for image, label in remaining:
+            if image == valid:
+                labels.append(label)
+                break
+    return labels + [''] * (len(valid_images) - len(labels))
+
+
+def _outline_marker(labels, images_per_page):
+    """onPage callback adding an outline entry for every labelled image placed on the page."""
+    if not any(labels):
+        return None
+
+    def mark(canvas_obj, _doc):
+        page = canvas_obj.getPageNumber()
+        first = (page - 1) * images_per_page
+        for index, label in enumerate(labels[first:first + images_per_page], start=first):
+            if not label:
+                continue
+            key = f'image{index}'
+            canvas_obj.bookmarkPage(key)
+            canvas_obj.addOutlineEntry(label, key, level=0)
+        if page == 1:
+            canvas_obj.showOutline()
+
+    return mark
+
+
+def _chain_page_callbacks(*callbacks):
+    active = [callback for callback in callbacks if callback]
+    if not active:
+        return None
+
+    def run(canvas_obj, doc):
+        for callback in active:
+            callback(canvas_obj, doc)
+
+    return run
+
+
 def _normalize_layout(value):
     text = str(value or "").strip().lower()
     if text in {"portrait", "landscape", "纵向", "横向", ""}:
@@ -116,11 +334,18 @@ class PDFGenerator:
         logger.info("PDFGenerator initialized")
         self._size_cache: dict[str, tuple[int, int]] = {}
         self._temp_image_paths: list[str] = []
+        # Per generate(): the `image_dpi` ceiling and how many images it shrank.
+        self._image_dpi = 0
+        self._downsampled = 0
     
     def generate(self, images, output_path, layout='single',
                  custom_rows=2, custom_cols=2, page_size='A4',
                  margin=DEFAULT_MARGIN, title='', author='', portrait=True,
-                 compression_level=0, fit_mode='contain'):
+                 compression_level=0, fit_mode='contain', images_per_page=0,
+                 grid_columns=0, user_password='', owner_password='', permissions=None,
+                 page_numbers=False, header_text='', footer_text='', bookmarks=None,
+                 auto_bookmark_from_filename=False, append_to_path='', image_dpi=0,
+                 subject='', keywords=None):
         """
         Generate a PDF from multiple images.
         
@@ -134,12 +359,37 @@ class PDFGenerator:
             margin (float): Page margin in points
             title (str): PDF metadata title
             author (str): PDF metadata author
+            subject (str): PDF metadata subject
+            keywords (list): PDF metadata keywords, written comma-separated
             portrait (bool): True for portrait, False for landscape
             compression_level (int): 0=none, 1-3 JPEG quality levels
+            images_per_page (int): Tile this many images per page (contact sheet);
+                0 or 1 keeps `layout`
+            grid_columns (int): Columns of that grid; 0 picks a near-square grid
+            user_password (str): Password needed to open the PDF
+            owner_password (str): Password that lifts the permission limits; the PDF is
+                encrypted when either password is set
+            permissions (list): What opening with the user password allows (print,
+                modify, copy, annotate); None allows everything
+            page_numbers (bool): Print "page / total" at the bottom right of every page
+            header_text (str): Running header; `{page}`, `{total}` and `{title}` are filled in
+            footer_text (str): Running footer, with the same placeholders
+            bookmarks (list): One outline label per entry of `images`; an empty label
+                adds no entry
+            auto_bookmark_from_filename (bool): Without `bookmarks`, label each image
+                with its file name (no extension)
+            append_to_path (str): Existing PDF whose pages come first; the new image pages
+                follow it in `output_path`, which may be the same file
+            image_dpi (int): Downsample images whose resolution at their placed size exceeds
+                this many pixels per inch; 0 embeds them at full resolution
         
         Returns:
             dict: Generation result with success status and metadata
         """
+        append_to_path = str(append_to_path or '').strip()
+        pages_path = None
+        self._image_dpi = _coerce_count(image_dpi)
+        self._downsampled = 0
         try:
             # Validate images
             valid_images = self._validate_images(images)
@@ -150,7 +400,17 @@ class PDFGenerator:
                 }
 
             image_count = len(valid_images)
-            page_count = self._estimate_page_count(image_count, layout, custom_rows, custom_cols)
+            labels = _bookmark_labels(images, valid_images, bookmarks, auto_bookmark_from_filename)
+            # Requests may carry these as strings or aliases ("fill"); settle them once here.
+            compression_level = min(3, _coerce_count(compression_level))
+            fit_mode = self._normalize_fit_mode(fit_mode)
+            per_page = _coerce_count(images_per_page)
+            if per_page > 1:
+                layout = 'grid'
+                custom_cols, custom_rows = _grid_shape(per_page, _coerce_count(grid_columns))
+            else:
+                per_page = 0
+            page_count = self._estimate_page_count(image_count, layout, custom_rows, custom_cols, per_page)
             logger.info(f"Generating PDF with {image_count} images")
 
             page_size_key = str(page_size or '').strip()
@@ -169,20 +429,35 @@ class PDFGenerator:
             if output_dir:
                 os.makedirs(output_dir, exist_ok=True)
 
+            try:
+                encrypt, encryption = _pdf_encryption(user_password, owner_password, permissions)
+            except Exception as e:
+                logger.error(f"PDF encryption setup failed: {e}")
+                return {'success': False, 'error': f'[PDF_ENCRYPTION_FAILED] {e}'}
+            if append_to_path and encrypt is not None:
+                return {'success': False, 'error': '[BAD_INPUT] Passwords cannot be combined with append_to_path'}
+
+            # Appending builds the new pages on their own, then merges them after the existing ones.
+            if append_to_path:
+                pages_path = TEMP_REGISTRY.create(prefix="imageflow_pdf_", suffix=".pdf")
+
             # Create PDF document
             safe_margin = max(0, float(margin))
             doc = SimpleDocTemplate(
-                output_path,
+                pages_path or output_path,
                 pagesize=pagesize,
                 leftMargin=safe_margin,
                 rightMargin=safe_margin,
                 topMargin=safe_margin,
-                bottomMargin=safe_margin
+                bottomMargin=safe_margin,
+                encrypt=encrypt,
             )
 
             # Set metadata
             doc.title = title
             doc.author = author
+            doc.subject = str(subject or '')
+            doc.keywords = _join_keywords(keywords)
 
             # Build content based on layout
             story = self._build_content(
@@ -194,23 +469,53 @@ class PDFGenerator:
                 safe_margin,
                 compression_level,
                 fit_mode,
+                per_page,
             )
 
             # Generate PDF
-            doc.build(story)
+            decorate = _chain_page_callbacks(
+                _page_decorator(page_numbers, header_text, footer_text, title, page_count, safe_margin),
+                _outline_marker(labels, self._images_per_page(layout, custom_rows, custom_cols, per_page)),
+            )
+            if decorate:
+                doc.build(story, onFirstPage=decorate, onLaterPages=decorate)
+            else:
+                doc.build(story)
+
+            if append_to_path:
+                try:
+                    metadata = {
+                        '/Title': doc.title, '/Author': doc.author, '/Subject': doc.subject, '/Keywords': doc.keywords,
+                    }
+                    page_count += _append_pdf(append_to_path, pages_path, output_path, metadata)
+                except Exception as e:
+                    logger.error(f"Appending to {append_to_path} failed: {e}")
+                    return {'success': False, 'error': f'[PDF_APPEND_FAILED] {e}'}
 
             # Get file size
-            file_size = os.path.getsize(output_path)
+            file_size = getsize_with_retry(output_path)
 
             logger.info(f"PDF generated: {output_path} ({file_size} bytes)")
 
-            return {
+            result = {
                 'success': True,
                 'output_path': output_path,
                 'file_size': file_size,
                 'image_count': image_count,
                 'page_count': page_count,
+                'fit_mode': fit_mode,
+                'compression_level': compression_level,
             }
+            if encryption:
+                result['encryption'] = encryption
+            if self._image_dpi:
+                result['image_dpi'] = self._image_dpi
+                result['downsampled_count'] = self._downsampled
+            if append_to_path:
+                result['appended_to'] = append_to_path
+            if any(labels):
+                result['bookmark_count'] = sum(1 for label in labels if label)
+            return result
 
         except Exception as e:
             logger.error(f"PDF generation failed: {e}", exc_info=True)
@@ -220,6 +525,7 @@ class PDFGenerator:
             }
         finally:
             self._cleanup_temp_images()
+            TEMP_REGISTRY.discard(pages_path)
     
     def _validate_images(self, images):
         """Validate that all image files exist and can be opened, caching sizes."""
@@ -254,7 +560,7 @@ class PDFGenerator:
 
         return valid_images
     
-    def _build_content(self, images, layout, custom_rows, custom_cols, pagesize, margin, compression_level=0, fit_mode='contain'):
+    def _build_content(self, images, layout, custom_rows, custom_cols, pagesize, margin, compression_level=0, fit_mode='contain', per_page=0):
         """
         Build the content list for the PDF based on the layout.
         
@@ -265,6 +571,7 @@ class PDFGenerator:
             custom_cols (int): Number of columns for custom layout
             pagesize (tuple): Page size (width, height)
             margin (float): Page margin in points
+            per_page (int): Images per page of the `grid` layout
         
         Returns:
             list: List of reportlab flowables
@@ -296,6 +603,13 @@ class PDFGenerator:
         elif layout == 'custom':
             # Custom grid layout
             story.extend(self._create_grid_layout(images, custom_rows, custom_cols, pagesize, margin, compression_level, fit_mode))
+
+        elif layout == 'grid':
+            # images_per_page tiles, with the page margin as the gutter between them
+            story.extend(self._create_grid_layout(
+                images, custom_rows, custom_cols, pagesize, margin, compression_level, fit_mode,
+                per_page=per_page, gutter=margin,
+            ))
         
         else:
             # Default to single image per page
@@ -317,27 +631,46 @@ class PDFGenerator:
         
         return story
     
-    def _create_grid_layout(self, images, rows, cols, pagesize, margin, compression_level=0, fit_mode='contain'):
-        """Create a grid layout for images."""
+    def _create_grid_layout(self, images, rows, cols, pagesize, margin, compression_level=0, fit_mode='contain',
+                            per_page=0, gutter=None):
+        """Create a grid layout for images.
+
+        `per_page` (default rows * cols) images fill each page row by row. With a `gutter`
+        the table is sized to fit inside the frame and a positive gutter adds empty
+        columns/rows of that width between the cells, without cell borders.
+        """
         from reportlab.platypus import Table, TableStyle
         from reportlab.lib import colors
         
         story = []
         page_width = pagesize[0] - 2 * margin
         page_height = pagesize[1] - 2 * margin
+        per_page = per_page or rows * cols
+        fitted = gutter is not None
+        gutter = max(0.0, float(gutter or 0))
+        if fitted:
+            # Keep the table inside the frame padding so a page never spills onto the next.
+            page_width -= 2 * FRAME_PADDING + gutter * (cols - 1)
+            page_height -= 2 * FRAME_PADDING + gutter * (rows - 1)
+            if page_width <= 0 or page_height <= 0:
+                raise ValueError("Invalid margins or page size; no space for the grid")
         
         cell_width = page_width / cols
         cell_height = page_height / rows
         
         # Process images in batches
-        for i in range(0, len(images), rows * cols):
-            batch = images[i:i + rows * cols]
+        for i in range(0, len(images), per_page):
+            batch = images[i:i + per_page]
             
             # Create table
             table_data = []
             for row in range(rows):
+                if gutter and row:
+                    table_data.append([''] * (2 * cols - 1))
                 row_data = []
                 for col in range(cols):
+                    if gutter and col:
+                        row_data.append('')
                     idx = row * cols + col
                     if idx < len(batch):
                         img = self._create_image_flowable(
@@ -353,7 +686,19 @@ class PDFGenerator:
                         row_data.append('')
                 table_data.append(row_data)
             
-            if table_data:
+            if table_data and fitted:
+                table = Table(
+                    table_data,
+                    colWidths=_with_gutters(cell_width, cols, gutter),
+                    rowHeights=_with_gutters(cell_height, rows, gutter),
+                )
+                table.setStyle(TableStyle([
+                    ('ALIGN', (0, 0), (-1, -1), 'CENTER'),
+                    ('VALIGN', (0, 0), (-1, -1), 'MIDDLE'),
+                ]))
+                story.append(table)
+                story.append(PageBreak())
+            elif table_data:
                 table = Table(
                     table_data,
                     colWidths=[cell_width] * cols,
@@ -389,6 +734,8 @@ class PDFGenerator:
         # Use cached size if available, otherwise open to get dimensions
         cached_size = self._size_cache.get(img_path)
         force_buffer = is_svg_path(img_path)
+        compression_level = min(3, _coerce_count(compression_level))
+        fit_mode = self._normalize_fit_mode(fit_mode)
 
         if cached_size and compression_level <= 0 and not force_buffer and fit_mode != 'cover':
             # Fast path: use cached size, skip PIL open entirely
@@ -401,8 +748,7 @@ class PDFGenerator:
 
             width_ratio = avail_width / img_width
             height_ratio = avail_height / img_height
-            fit_mode_normalized = self._normalize_fit_mode(fit_mode)
-            if fit_mode_normalized == 'original':
+            if fit_mode == 'original':
                 scale_factor = min(width_ratio, height_ratio, 1.0)
             else:
                 scale_factor = min(width_ratio, height_ratio)
@@ -410,11 +756,12 @@ class PDFGenerator:
             final_width = img_width * scale_factor
             final_height = img_height * scale_factor
 
-            img = RLImage(img_path, width=final_width, height=final_height)
-            img.hAlign = "CENTER"
-            return img
+            if not _dpi_pixel_size(cached_size, final_width, final_height, self._image_dpi):
+                img = RLImage(img_path, width=final_width, height=final_height)
+                img.hAlign = "CENTER"
+                return img
 
-        # Slow path: need PIL for SVG, compression, or cover crop
+        # Slow path: need PIL for SVG, compression, cover crop or downsampling
         pil_img = open_image_with_svg_support(img_path, format_type="png")
         working_img = pil_img
         try:
@@ -427,7 +774,6 @@ class PDFGenerator:
             if avail_width <= 0 or avail_height <= 0:
                 raise ValueError("Invalid margins or page size; no space for content")
 
-            fit_mode = self._normalize_fit_mode(fit_mode)
             use_buffer = False
 
             if fit_mode == 'cover':
@@ -446,9 +792,29 @@ class PDFGenerator:
                 final_width = img_width * scale_factor
                 final_height = img_height * scale_factor
 
+            downsampled = False
+            target_size = _dpi_pixel_size(working_img.size, final_width, final_height, self._image_dpi)
+            if target_size:
+                resized = working_img.resize(target_size, Image.Resampling.LANCZOS)
+                if working_img is not pil_img:
+                    working_img.close()
+                working_img, use_buffer, downsampled = resized, True, True
+                self._downsampled += 1
+
             if compression_level > 0:
                 temp_path = self._encode_image_for_pdf(working_img, compression_level)
                 img = RLImage(temp_path, width=final_width, height=final_height)
+            elif downsampled and Path(img_path).suffix.lower() in ('.jpg', '.jpeg'):
+                # A downsampled photo re-encoded as PNG could outgrow the original JPEG.
+                prepared = self._prepare_jpeg_image(working_img)
+                try:
+                    temp_path = self._create_temp_image_file(
+                        prepared, ".jpg", "JPEG", quality=DOWNSAMPLED_JPEG_QUALITY, optimize=True
+                    )
+                finally:
+                    if prepared is not working_img:
+                        prepared.close()
+                img = RLImage(temp_path, width=final_width, height=final_height)
             elif use_buffer or force_buffer:
                 prepared = self._prepare_png_image(working_img)
                 try:
@@ -473,20 +839,21 @@ class PDFGenerator:
                 except Exception:
                     pass
 
-    def _estimate_page_count(self, image_count, layout, rows, cols):
+    def _estimate_page_count(self, image_count, layout, rows, cols, per_page=0):
         if image_count <= 0:
             return 0
-        if layout == 'single':
-            return image_count
+        return int(math.ceil(image_count / self._images_per_page(layout, rows, cols, per_page)))
+
+    def _images_per_page(self, layout, rows, cols, per_page=0):
+        if layout == 'grid':
+            return max(1, per_page)
         if layout == '2x2':
-            per_page = 4
-        elif layout == '3x3':
-            per_page = 9
-        elif layout == 'custom':
-            per_page = max(1, rows) * max(1, cols)
-        else:
-            return image_count
-        return int(math.ceil(image_count / per_page))
+            return 4
+        if layout == '3x3':
+            return 9
+        if layout == 'custom':
+            return max(1, rows) * max(1, cols)
+        return 1
 
     def _encode_image_for_pdf(self, pil_img, compression_level):
         quality_map = {1: 85, 2: 70, 3: 50}
@@ -507,33 +874,18 @@ class PDFGenerator:
                 prepared.close()
 
     def _create_temp_image_file(self, pil_img, suffix, image_format, **save_options):
-        temp_file = tempfile.NamedTemporaryFile(
-            prefix="imageflow_pdf_",
-            suffix=suffix,
-            delete=False,
-        )
-        temp_path = temp_file.name
-        temp_file.close()
+        temp_path = TEMP_REGISTRY.create(prefix="imageflow_pdf_", suffix=suffix)
         try:
             pil_img.save(temp_path, format=image_format, **save_options)
         except Exception:
-            try:
-                os.remove(temp_path)
-            except OSError:
-                pass
+            TEMP_REGISTRY.discard(temp_path)
             raise
         self._temp_image_paths.append(temp_path)
         return temp_path
 
     def _cleanup_temp_images(self):
         while self._temp_image_paths:
-            temp_path = self._temp_image_paths.pop()
-            try:
-                os.remove(temp_path)
-            except FileNotFoundError:
-                pass
-            except OSError as exc:
-                logger.warning("Failed to remove temporary PDF image %s: %s", temp_path, exc)
+            TEMP_REGISTRY.discard(self._temp_image_paths.pop())
 
     def _prepare_jpeg_image(self, pil_img):
         if pil_img.mode in ("RGBA", "LA") or (pil_img.mode == "P" and "transparency" in pil_img.info):
@@ -601,9 +953,23 @@ def process(input_data):
         margin = input_data.get('margin', 72)
         title = input_data.get('title', '')
         author = input_data.get('author', '')
+        subject = input_data.get('subject', '')
+        keywords = input_data.get('keywords') or []
         portrait = _coerce_portrait(input_data.get('portrait'), raw_layout)
         compression_level = input_data.get('compression_level', 0)
         fit_mode = input_data.get('fit_mode', 'contain')
+        images_per_page = input_data.get('images_per_page', 0)
+        grid_columns = input_data.get('grid_columns', 0)
+        user_password = input_data.get('user_password', '')
+        owner_password = input_data.get('owner_password', '')
+        permissions = input_data.get('permissions')
+        page_numbers = input_data.get('page_numbers') is True
+        header_text = input_data.get('header_text', '')
+        footer_text = input_data.get('footer_text', '')
+        bookmarks = input_data.get('bookmarks') or []
+        auto_bookmark_from_filename = input_data.get('auto_bookmark_from_filename') is True
+        append_to_path = input_data.get('append_to_path', '')
+        image_dpi = input_data.get('image_dpi', 0)
 
         # Validate required parameters
         if not images or not output_path:
@@ -624,9 +990,23 @@ def process(input_data):
             margin=margin,
             title=title,
             author=author,
+            subject=subject,
+            keywords=keywords,
             portrait=portrait,
             compression_level=compression_level,
             fit_mode=fit_mode,
+            images_per_page=images_per_page,
+            grid_columns=grid_columns,
+            user_password=user_password,
+            owner_password=owner_password,
+            permissions=permissions,
+            page_numbers=page_numbers,
+            header_text=header_text,
+            footer_text=footer_text,
+            bookmarks=bookmarks,
+            auto_bookmark_from_filename=auto_bookmark_from_filename,
+            append_to_path=append_to_path,
+            image_dpi=image_dpi,
         )
 
         return result
@@ -657,9 +1037,23 @@ def main():
         margin = input_data.get('margin', 72)
         title = input_data.get('title', '')
         author = input_data.get('author', '')
+        subject = input_data.get('subject', '')
+        keywords = input_data.get('keywords') or []
         portrait = _coerce_portrait(input_data.get('portrait'), raw_layout)
         compression_level = input_data.get('compression_level', 0)
         fit_mode = input_data.get('fit_mode', 'contain')
+        images_per_page = input_data.get('images_per_page', 0)
+        grid_columns = input_data.get('grid_columns', 0)
+        user_password = input_data.get('user_password', '')
+        owner_password = input_data.get('owner_password', '')
+        permissions = input_data.get('permissions')
+        page_numbers = input_data.get('page_numbers') is True
+        header_text = input_data.get('header_text', '')
+        footer_text = input_data.get('footer_text', '')
+        bookmarks = input_data.get('bookmarks') or []
+        auto_bookmark_from_filename = input_data.get('auto_bookmark_from_filename') is True
+        append_to_path = input_data.get('append_to_path', '')
+        image_dpi = input_data.get('image_dpi', 0)
         
         # Validate required parameters
         if not images or not output_path:
@@ -680,9 +1074,23 @@ def main():
                 margin=margin,
                 title=title,
                 author=author,
+                subject=subject,
+                keywords=keywords,
                 portrait=portrait,
                 compression_level=compression_level,
                 fit_mode=fit_mode,
+                images_per_page=images_per_page,
+                grid_columns=grid_columns,
+                user_password=user_password,
+                owner_password=owner_password,
+                permissions=permissions,
+                page_numbers=page_numbers,
+                header_text=header_text,
+                footer_text=footer_text,
+                bookmarks=bookmarks,
+                auto_bookmark_from_filename=auto_bookmark_from_filename,
+                append_to_path=append_to_path,
+                image_dpi=image_dpi,
             )
         
         # Write result to stdout
@@ -0,0 +1,173 @@
+#!/usr/bin/env python3
+"""
+Temp File Registry
+
+Tracks temp files created by engines so cancelled, failed, or interrupted work
+never leaves them behind. Files are registered on create and unregistered once
+they are renamed into place or removed. `scope()` purges anything a single
+operation left registered, and an atexit hook purges whatever remains when the
+process shuts down. A scope may also name the directory scratch files go to
+(e.g. a RAM disk); files that must be renamed onto an output still pass `dir`.
+
+Also home to the bounded retry engines use when renaming or stat-ing a freshly
+written output that antivirus (or an indexer) briefly holds open on Windows.
+"""
+
+import atexit
+import errno
+import logging
+import os
+import shutil
+import tempfile
+import threading
+import time
+from contextlib import contextmanager
+
+logger = logging.getLogger(__name__)
+
+
+class TempRegistry:
+    """Thread-safe set of temp paths with per-operation cleanup scopes."""
+
+    def __init__(self):
+        self._lock = threading.Lock()
+        self._paths = set()
+        self._local = threading.local()
+
+    def create(self, suffix="", prefix="imageflow_", dir=None):
+        """Create an empty temp file and register it; returns its path."""
+        fd, path = tempfile.mkstemp(suffix=suffix, prefix=prefix, dir=dir or self.default_dir())
+        os.close(fd)
+        self.register(path)
+        return path
+
+    def register(self, path):
+        if not path:
+            return path
+        path = os.path.abspath(path)
+        with self._lock:
+            self._paths.add(path)
+        for scope in getattr(self._local, "scopes", ()):
+            scope.add(path)
+        return path
+
+    def unregister(self, path):
+        """Forget a path that was renamed into place or removed by its owner."""
+        if not path:
+            return
+        with self._lock:
+            self._paths.discard(os.path.abspath(path))
+
+    def create_dir(self, prefix="imageflow_", dir=None):
+        """Create a temp directory and register it; purging removes the whole tree."""
+        return self.register(tempfile.mkdtemp(prefix=prefix, dir=dir or self.default_dir()))
+
+    def default_dir(self):
+        """Scratch directory of the innermost scope that set one; None means the system temp dir."""
+        for scope_dir in reversed(getattr(self._local, "dirs", ())):
+            if scope_dir:
+                return scope_dir
+        return None
+
+    def discard(self, path):
+        """Remove a registered temp file or directory (if still present) and forget it."""
+        if not path:
+            return
+        try:
+            if os.path.isdir(path) and not os.path.islink(path):
+                shutil.rmtree(path)
+            else:
+                os.remove(path)
+        except FileNotFoundError:
+            pass
+        except OSError as exc:
+            logger.warning(f"Failed to remove temp file {path}: {exc}")
+        self.unregister(path)
+
+    def paths(self):
+        with self._lock:
+            return sorted(self._paths)
+
+    def purge(self, paths=None):
+        """Remove the given registered paths (default: all); returns how many were purged."""
+        with self._lock:
+            targets = set(self._paths) if paths is None else set(paths) & self._paths
+        for path in targets:
+            self.discard(path)
+        return len(targets)
+
+    @contextmanager
+    def scope(self, temp_dir=None):
+        """Purge every temp registered by this thread inside the block once it exits.
+
+        `temp_dir` makes it the default directory for temps created without `dir`.
+        """
+        created = set()
+        scopes = getattr(self._local, "scopes", None)
+        if scopes is None:
+            scopes = self._local.scopes = []
+        dirs = getattr(self._local, "dirs", None)
+        if dirs is None:
+            dirs = self._local.dirs = []
+        scopes.append(created)
+        dirs.append(temp_dir)
+        try:
+            yield created
+        finally:
+            dirs.pop()
+            scopes.remove(created)
+            leaked = self.purge(created)
+            if leaked:
+                logger.info(f"Purged {leaked} leftover temp file(s) after operation")
+
+
+TEMP_REGISTRY = TempRegistry()
+atexit.register(TEMP_REGISTRY.purge)
+
+# ERROR_SHARING_VIOLATION / ERROR_LOCK_VIOLATION on Windows; EBUSY elsewhere.
+_LOCKED_WINERRORS = (32, 33)
+DEFAULT_LOCK_RETRY_ATTEMPTS = 5
+DEFAULT_LOCK_RETRY_DELAY_MS = 30
+
+
+def _env_int(name, default, minimum):
+    raw_value = str(os.getenv(name, "") or "").strip()
+    if not raw_value:
+        return default
+    try:
+        return max(minimum, int(raw_value))
+    except ValueError:
+        return default
+
+
+def is_transient_lock_error(exc):
+    return isinstance(exc, OSError) and (
+        getattr(exc, "winerror", None) in _LOCKED_WINERRORS or exc.errno == errno.EBUSY
+    )
+
+
+def retry_when_locked(operation, *args):
+    """Run `operation(*args)`, retrying with doubling delays while the file is transiently locked.
+
+    Defaults to 5 attempts over ~450 ms; tune with IMAGEFLOW_LOCK_RETRY_ATTEMPTS and
+    IMAGEFLOW_LOCK_RETRY_DELAY_MS (first delay). Any other error is raised immediately.
+    """
+    attempts = _env_int("IMAGEFLOW_LOCK_RETRY_ATTEMPTS", DEFAULT_LOCK_RETRY_ATTEMPTS, 1)
+    delay = _env_int("IMAGEFLOW_LOCK_RETRY_DELAY_MS", DEFAULT_LOCK_RETRY_DELAY_MS, 0) / 1000.0
+    for attempt in range(1, attempts + 1):
+        try:
+            return operation(*args)
+        except OSError as exc:
+            if attempt == attempts or not is_transient_lock_error(exc):
+                raise
+            logger.info(f"Output locked ({exc}); retry {attempt}/{attempts - 1} in {delay * 1000:.0f} ms")
+            time.sleep(delay)
+            delay *= 2
+
+
+def replace_with_retry(src, dst):
+    return retry_when_locked(os.replace, src, dst)
+
+
+def getsize_with_retry(path):
+    return retry_when_locked(os.path.getsize, path)
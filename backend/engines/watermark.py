@@ -14,15 +14,23 @@ Usage:
 import sys
 import json
 import os
+from collections import OrderedDict
 from pathlib import Path
 from PIL import Image, ImageDraw, ImageFont, ImageEnhance, ImageFilter, ImageChops
 import logging
+from temp_registry import getsize_with_retry
 
 from converter import open_image_with_svg_support
 
 # Configure logging
 logger = logging.getLogger(__name__)
 
+# Batch workers are reused across files, so the same logo would otherwise be
+# decoded and resized once per image. Entries are keyed by path+mtime+size and a
+# changed file simply misses; stale entries for that path are dropped on insert.
+_WATERMARK_CACHE_MAX = 8
+_watermark_cache = OrderedDict()
+
 
 def _resolve_request_payload(input_data):
     return {
@@ -43,11 +51,47 @@ def _resolve_request_payload(input_data):
         "blend_mode": input_data.get("blend_mode", "normal"),
         "tiled": input_data.get("tiled", False),
         "shadow": input_data.get("shadow", False),
+        "guide_type": input_data.get("guide_type", "thirds"),
+        "guide_color": input_data.get("guide_color", "#FFFFFF"),
+        "line_width": input_data.get("line_width", 0),
     }
 
 
+def _watermark_source_key(watermark_path):
+    try:
+        stat = os.stat(watermark_path)
+    except OSError:
+        return None
+    return (os.path.normcase(os.path.abspath(watermark_path)), stat.st_mtime_ns, stat.st_size)
+
+
+def _cache_get(key):
+    image = _watermark_cache.get(key)
+    if image is not None:
+        _watermark_cache.move_to_end(key)
+    return image
+
+
+def _cache_put(key, image):
+    source_path = key[0][0]
+    for stale in [k for k in _watermark_cache if k[0][0] == source_path and k[0] != key[0]]:
+        _watermark_cache.pop(stale).close()
+    _watermark_cache[key] = image
+    _watermark_cache.move_to_end(key)
+    while len(_watermark_cache) > _WATERMARK_CACHE_MAX:
+        _watermark_cache.popitem(last=False)[1].close()
+
+
+def clear_watermark_cache():
+    while _watermark_cache:
+        _watermark_cache.popitem()[1].close()
+
+
 class WatermarkApplier:
     """Handles watermark application to images."""
+
+    GUIDE_TYPES = ('thirds', 'golden', 'center')
+    GOLDEN_RATIO_SPLIT = 0.381966
     
     def __init__(self):
         """Initialize the watermark applier."""
@@ -58,12 +102,13 @@ class WatermarkApplier:
               watermark_path='', watermark_scale=0.2,
               opacity=1.0, position='center', rotation=0,
               offset_x=0, offset_y=0, blend_mode='normal',
-              tiled=False, shadow=False):
+              tiled=False, shadow=False,
+              guide_type='thirds', guide_color='#FFFFFF', line_width=0):
         """
         Apply a watermark to an image.
         
         Args:
-            watermark_type (str): Type of watermark ('text' or 'image')
+            watermark_type (str): Type of watermark ('text', 'image' or 'guides')
             input_path (str): Path to the input image
             output_path (str): Path to save the watermarked image
             text (str): Text content for text watermark
@@ -80,6 +125,9 @@ class WatermarkApplier:
             blend_mode (str): Blend mode (normal, multiply, screen, overlay, soft_light)
             tiled (bool): Tile watermark across the image
             shadow (bool): Add a soft shadow behind the watermark
+            guide_type (str): Composition guide for 'guides' (thirds, golden, center)
+            guide_color (str): Guide line color in hex format
+            line_width (int): Guide line width in pixels (0 scales with the image)
         
         Returns:
             dict: Watermark application result
@@ -100,6 +148,13 @@ class WatermarkApplier:
                         'success': False,
                         'error': f'File not found: {watermark_path}'
                     }
+            elif watermark_type == 'guides':
+                guide_type = str(guide_type or '').strip().lower()
+                if guide_type not in self.GUIDE_TYPES:
+                    return {
+                        'success': False,
+                        'error': f'[BAD_INPUT] Unknown guide type: {guide_type}'
+                    }
 
             # Open input image
             logger.info(f"Opening image: {input_path}")
@@ -160,6 +215,8 @@ class WatermarkApplier:
                     self._apply_image_watermark(overlay, img.size, watermark_path,
                                               watermark_scale, opacity, position,
                                               rotation, offset_x, offset_y, shadow)
+            elif watermark_type == 'guides':
+                self._apply_guides(overlay, guide_type, guide_color, opacity, line_width)
             else:
                 return {
                     'success': False,
@@ -188,7 +245,7 @@ class WatermarkApplier:
             self._save_image(watermarked, output_path, img_format)
             
             # Get file size
-            file_size = os.path.getsize(output_path)
+            file_size = getsize_with_retry(output_path)
             
             return {
                 'success': True,
@@ -378,16 +435,26 @@ class WatermarkApplier:
             watermark.close()
 
     def _build_image_watermark(self, watermark_path, img_size, watermark_scale, opacity):
-        with Image.open(watermark_path) as base_wm:
-            watermark = base_wm.convert('RGBA') if base_wm.mode != 'RGBA' else base_wm.copy()
-
-        orig_width, orig_height = watermark.size
+        """Return a caller-owned RGBA watermark scaled to `img_size` with opacity applied."""
         try:
             scale = float(watermark_scale)
         except (TypeError, ValueError):
             scale = 0.2
         scale = max(0.01, scale)
         new_width = max(1, int(img_size[0] * scale))
+
+        source_key = _watermark_source_key(watermark_path)
+        prepared_key = None
+        if source_key is not None:
+            prepared_key = (source_key, new_width, float(opacity))
+            cached = _cache_get(prepared_key)
+            if cached is not None:
+                return cached.copy()
+
+        with Image.open(watermark_path) as base_wm:
+            watermark = base_wm.convert('RGBA') if base_wm.mode != 'RGBA' else base_wm.copy()
+
+        orig_width, orig_height = watermark.size
         new_height = max(1, int(orig_height * new_width / max(1, orig_width)))
         resized = watermark.resize((new_width, new_height), Image.Resampling.LANCZOS)
         watermark.close()
@@ -404,8 +471,35 @@ class WatermarkApplier:
             finally:
                 alpha.close()
 
+        if prepared_key is not None:
+            _cache_put(prepared_key, watermark.copy())
         return watermark
 
+    def _apply_guides(self, overlay, guide_type, guide_color, opacity, line_width):
+        """Draw rule-of-thirds, golden-ratio or center-cross lines onto the overlay."""
+        width, height = overlay.size
+        try:
+            line_width = int(line_width)
+        except (TypeError, ValueError):
+            line_width = 0
+        if line_width <= 0:
+            line_width = max(1, round(min(width, height) / 400))
+
+        if guide_type == 'thirds':
+            fractions = (1 / 3, 2 / 3)
+        elif guide_type == 'golden':
+            fractions = (self.GOLDEN_RATIO_SPLIT, 1 - self.GOLDEN_RATIO_SPLIT)
+        else:
+            fractions = (0.5,)
+
+        color = self._parse_color(guide_color, opacity)
+        draw = ImageDraw.Draw(overlay)
+        for fraction in fractions:
+            x = round((width - 1) * fraction)
+            y = round((height - 1) * fraction)
+            draw.line([(x, 0), (x, height - 1)], fill=color, width=line_width)
+            draw.line([(0, y), (width - 1, y)], fill=color, width=line_width)
+
     def _create_shadow_image(self, watermark, opacity):
         alpha = watermark.split()[3]
         try:
@@ -589,7 +683,10 @@ def process(input_data):
             offset_y=payload["offset_y"],
             blend_mode=payload["blend_mode"],
             tiled=payload["tiled"],
-            shadow=payload["shadow"]
+            shadow=payload["shadow"],
+            guide_type=payload["guide_type"],
+            guide_color=payload["guide_color"],
+            line_width=payload["line_width"]
         )
 
         return result
@@ -641,7 +738,10 @@ def main():
                 offset_y=payload["offset_y"],
                 blend_mode=payload["blend_mode"],
                 tiled=payload["tiled"],
-                shadow=payload["shadow"]
+                shadow=payload["shadow"],
+                guide_type=payload["guide_type"],
+                guide_color=payload["guide_color"],
+                line_width=payload["line_width"]
             )
         
         # Write result to stdout
@@ -0,0 +1,224 @@
+from __future__ import annotations
+
+import logging
+import os
+import shutil
+import threading
+import zipfile
+from pathlib import Path, PurePosixPath
+
+from backend.domain.paths import SUPPORTED_EXTENSIONS
+from backend.infrastructure.engine_loader import engine_temp_registry, staged_output
+
+ARCHIVE_EXTENSIONS = {".zip"}
+MAX_ARCHIVE_ENTRIES = 10_000
+MAX_ARCHIVE_IMAGE_BYTES = 4 * 1024 * 1024 * 1024
+# Entries that inflate more than this are treated as zip bombs and skipped.
+MAX_COMPRESSION_RATIO = 200
+_RATIO_CHECK_MIN_BYTES = 1024 * 1024
+_COPY_CHUNK_BYTES = 1024 * 1024
+
+logger = logging.getLogger(__name__)
+
+# (archive path, archive mtime) -> registered temp dir holding the members extracted so far.
+_EXTRACT_ROOTS: dict[tuple[str, int], str] = {}
+_EXTRACT_LOCK = threading.Lock()
+
+
+def is_archive_path(path_value: str) -> bool:
+    return Path(str(path_value or "")).suffix.lower() in ARCHIVE_EXTENSIONS
+
+
+def safe_member_name(name: str) -> str | None:
+    """Return a normalized relative member path, or None for zip-slip style names."""
+    cleaned = str(name or "").replace("\\", "/")
+    if not cleaned or cleaned.startswith("/") or "\x00" in cleaned:
+        return None
+    parts = PurePosixPath(cleaned).parts
+    if not parts or any(part in ("..", "") for part in parts) or ":" in parts[0]:
+        return None
+    normalized = "/".join(part for part in parts if part != ".")
+    return normalized or None
+
+
+def _is_image_member(info: zipfile.ZipInfo) -> bool:
+    return not info.is_dir() and PurePosixPath(info.filename).suffix.lower() in SUPPORTED_EXTENSIONS
+
+
+def _is_bomb(info: zipfile.ZipInfo) -> bool:
+    if info.file_size < _RATIO_CHECK_MIN_BYTES:
+        return False
+    return info.file_size > max(1, info.compress_size) * MAX_COMPRESSION_RATIO
+
+
+def member_reference(archive_path: str, member: str) -> str:
+    """Path-like reference to a zip member: the archive path followed by the member path."""
+    return str(Path(archive_path, *member.split("/")))
+
+
+def split_member_reference(path_value: str) -> tuple[str, str] | None:
+    """(archive_path, member) when `path_value` points inside an existing zip, else None."""
+    text = str(path_value or "").strip()
+    if not text:
+        return None
+    path = Path(text)
+    if path.exists():
+        return None
+    for parent in path.parents:
+        if is_archive_path(str(parent)) and parent.is_file():
+            member = safe_member_name(path.relative_to(parent).as_posix())
+            return (str(parent), member) if member else None
+    return None
+
+
+def member_listed(path_value: str) -> bool:
+    """Whether `path_value` is a member reference its archive lists as a file; nothing is extracted."""
+    reference = split_member_reference(path_value)
+    if reference is None:
+        return False
+    archive_path, member = reference
+    try:
+        with zipfile.ZipFile(archive_path) as archive:
+            names = archive.namelist()
+    except (OSError, zipfile.BadZipFile):
+        return False
+    return any(not name.endswith("/") and safe_member_name(name) == member for name in names)
+
+
+def expand_archive(archive_path: str) -> dict:
+    """List the supported image entries of a zip without extracting them.
+
+    Each returned file mirrors an `expand_input_paths` entry, with `input_path` set to a
+    member reference (see `member_reference`) that `resolve_member_path` extracts on first
+    use, `source_root`/`archive_path` pointing at the archive and `relative_path` at the member.
+    """
+    source = Path(archive_path)
+    files: list[dict] = []
+    warnings: list[str] = []
+    try:
+        archive = zipfile.ZipFile(source)
+    except (OSError, zipfile.BadZipFile) as exc:
+        return {"files": [], "warnings": [f"无法读取压缩包 {source.name}: {exc}"]}
+
+    mod_time = 0
+    try:
+        mod_time = int(os.path.getmtime(source))
+    except OSError:
+        pass
+    with archive:
+        members = archive.infolist()
+        if len(members) > MAX_ARCHIVE_ENTRIES:
+            return {"files": [], "warnings": [f"压缩包 {source.name} 条目过多（>{MAX_ARCHIVE_ENTRIES}），已跳过"]}
+
+        total_bytes = 0
+        seen: set[str] = set()
+        for info in members:
+            if not _is_image_member(info):
+                continue
+            member = safe_member_name(info.filename)
+            if member is None:
+                warnings.append(f"已跳过不安全的压缩包路径: {info.filename}")
+                continue
+            if member.casefold() in seen:
+                continue
+            if info.flag_bits & 0x1:
+                warnings.append(f"已跳过加密条目: {member}")
+                continue
+            if _is_bomb(info):
+                warnings.append(f"已跳过压缩比异常的条目: {member}")
+                continue
+            total_bytes += info.file_size
+            if total_bytes > MAX_ARCHIVE_IMAGE_BYTES:
+                warnings.append(f"压缩包 {source.name} 解压体积超过上限，其余条目已跳过")
+                break
+
+            seen.add(member.casefold())
+            files.append(
+                {
+                    "input_path": member_reference(str(source), member),
+                    "source_root": str(source),
+                    "relative_path": member,
+                    "is_from_dir_drop": True,
+                    "size": info.file_size,
+                    "mod_time": mod_time,
+                    "archive_path": str(source),
+                    "archive_member": member,
+                }
+            )
+
+    files.sort(key=lambda item: str(item["relative_path"]).lower())
+    return {"files": files, "warnings": warnings}
+
+
+def extract_member(archive_path: str, member: str) -> str:
+    """Stream one member into the archive's registered temp dir; later calls reuse that copy.
+
+    Raises FileNotFoundError for a member the archive does not have (or only under an unsafe
+    name) and ValueError for encrypted or zip-bomb entries, which `expand_archive` never lists.
+    """
+    source = os.path.abspath(archive_path)
+    key = (source, os.stat(source).st_mtime_ns)
+    with _EXTRACT_LOCK:
+        root = _EXTRACT_ROOTS.get(key)
+        target = Path(root, *member.split("/")) if root else None
+        if target is not None and target.is_file():
+            return str(target)
+        with zipfile.ZipFile(source) as archive:
+            info = next((item for item in archive.infolist() if safe_member_name(item.filename) == member), None)
+            if info is None or info.is_dir():
+                raise FileNotFoundError(f"{member} not found in {Path(source).name}")
+            if info.flag_bits & 0x1 or _is_bomb(info):
+                raise ValueError(f"{member} in {Path(source).name} is encrypted or too highly compressed")
+            registry = engine_temp_registry()
+            if root is None or not os.path.isdir(root):
+                root = registry.create_dir(prefix="imageflow_zip_")
+                _EXTRACT_ROOTS[key] = root
+            target = Path(root, *member.split("/"))
+            target.parent.mkdir(parents=True, exist_ok=True)
+            try:
+                with archive.open(info) as src, open(target, "wb") as dst:
+                    shutil.copyfileobj(src, dst, _COPY_CHUNK_BYTES)
+            except BaseException:
+                try:
+                    target.unlink()
+                except OSError:
+                    pass
+                raise
+    return str(target)
+
+
+def resolve_member_path(path_value: str) -> str:
+    """The extracted file for a member reference; any other path is returned unchanged.
+
+    A member that cannot be extracted keeps its reference, so the engine reports it as missing.
+    """
+    reference = split_member_reference(path_value)
+    if reference is None:
+        return path_value
+    try:
+        return extract_member(*reference)
+    except (OSError, ValueError, zipfile.BadZipFile, RuntimeError) as exc:
+        logger.warning("Could not extract %s from %s: %s", reference[1], reference[0], exc)
+        return path_value
+
+
+def write_zip(entries: list[tuple[str, str]], output_path: str) -> dict:
+    """Stream (source_path, arcname) pairs into a new zip; returns path, count and size."""
+    target = Path(output_path)
+    written = 0
+    with staged_output(output_path, ".zip.tmp") as tmp_path:
+        with zipfile.ZipFile(tmp_path, "w", compression=zipfile.ZIP_DEFLATED, allowZip64=True) as archive:
+            used: set[str] = set()
+            for source_path, arcname in entries:
+                name = safe_member_name(arcname) or Path(source_path).name
+                if name.casefold() in used:
+                    stem, suffix = os.path.splitext(name)
+                    index = 1
+                    while f"{stem}_{index:02d}{suffix}".casefold() in used:
+                        index += 1
+                    name = f"{stem}_{index:02d}{suffix}"
+                used.add(name.casefold())
+                # ZipFile.write streams the file in chunks rather than reading it whole.
+                archive.write(source_path, name)
+                written += 1
+    return {"output_path": str(target), "file_count": written, "size": target.stat().st_size}
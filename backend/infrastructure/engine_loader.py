@@ -1,5 +1,8 @@
 import importlib.util
+import os
+import shutil
 import sys
+from contextlib import contextmanager
 from functools import lru_cache
 from pathlib import Path
 from typing import Any
@@ -10,6 +13,9 @@ ALLOWED_ENGINES = frozenset({
     "metadata_tool", "info_viewer", "subtitle_stitcher",
 })
 
+# Shared non-engine helper every engine may import; loaded from the packaged file first.
+TEMP_REGISTRY_MODULE = "temp_registry"
+
 ENGINES_REQUIRING_CONVERTER = frozenset({
     "adjuster",
     "filter",
@@ -18,6 +24,18 @@ ENGINES_REQUIRING_CONVERTER = frozenset({
     "watermark",
 })
 
+# Engines that only read `input_path` and write elsewhere; a working copy keeps the source
+# unlocked for other apps (and the preview) while they run.
+WORKING_COPY_ENGINES = frozenset({
+    "adjuster",
+    "compressor",
+    "converter",
+    "filter",
+    "gif_splitter",
+    "info_viewer",
+    "watermark",
+})
+
 
 def ensure_engine_scripts_path() -> Path:
     scripts_dir = Path(__file__).resolve().parents[1] / "engines"
@@ -57,17 +75,92 @@ def _load_module_from_engine_file(module_name: str):
 def load_engine_module(module_name: str):
     if module_name not in ALLOWED_ENGINES:
         raise ImportError(f"Module '{module_name}' is not in the allowed engines list")
+    _load_module_from_engine_file(TEMP_REGISTRY_MODULE)
     if module_name in ENGINES_REQUIRING_CONVERTER:
         load_engine_module("converter")
     return _load_module_from_engine_file(module_name)
 
 
+def engine_temp_registry():
+    return _load_module_from_engine_file(TEMP_REGISTRY_MODULE).TEMP_REGISTRY
+
+
+@contextmanager
+def staged_output(output_path: str, suffix: str):
+    """Yield a registered temp file beside `output_path` and rename it over the output on success.
+
+    The rename retries while another app briefly holds the target; if the body raises, the
+    temp file is removed and the existing output is left as it was.
+    """
+    temp_registry = _load_module_from_engine_file(TEMP_REGISTRY_MODULE)
+    target = Path(output_path)
+    target.parent.mkdir(parents=True, exist_ok=True)
+    tmp_path = temp_registry.TEMP_REGISTRY.create(suffix=suffix, dir=str(target.parent))
+    try:
+        yield tmp_path
+        temp_registry.replace_with_retry(tmp_path, str(target))
+        temp_registry.TEMP_REGISTRY.unregister(tmp_path)
+    finally:
+        temp_registry.TEMP_REGISTRY.discard(tmp_path)
+
+
+def purge_engine_temp_files() -> int:
+    """Remove every temp file engines in this process still have registered."""
+    return engine_temp_registry().purge()
+
+
+def _same_path(left: str, right: str) -> bool:
+    return os.path.normcase(os.path.abspath(left)) == os.path.normcase(os.path.abspath(right))
+
+
+def _working_copy_payload(module_name: str, payload: dict[str, Any]) -> tuple[dict[str, Any], str]:
+    """Point `input_path` at a registered temp copy when the payload asks for one.
+
+    Returns the payload to run and the copy's path ("" when the original is used as-is).
+    The copy keeps the original file name so engines that derive names from it are unaffected.
+    """
+    if not payload.get("working_copy") or module_name not in WORKING_COPY_ENGINES:
+        return payload, ""
+    input_path = str(payload.get("input_path") or "")
+    output_path = str(payload.get("output_path") or "")
+    # Missing inputs fall through so the engine reports them; in-place writes need the real file.
+    if not input_path or not os.path.isfile(input_path) or (output_path and _same_path(input_path, output_path)):
+        return payload, ""
+    registry = engine_temp_registry()
+    copy_dir = registry.create_dir(prefix="imageflow_wc_")
+    copy_path = os.path.join(copy_dir, os.path.basename(input_path))
+    try:
+        shutil.copy2(input_path, copy_path)
+    except OSError:
+        registry.discard(copy_dir)
+        return payload, ""
+    return {**payload, "input_path": copy_path}, copy_path
+
+
+def _restore_input_path(value: Any, copy_path: str, input_path: str) -> Any:
+    if isinstance(value, str):
+        return value.replace(copy_path, input_path)
+    if isinstance(value, list):
+        return [_restore_input_path(item, copy_path, input_path) for item in value]
+    if isinstance(value, dict):
+        return {key: _restore_input_path(item, copy_path, input_path) for key, item in value.items()}
+    return value
+
+
 def invoke_engine_process(module_name: str, payload: dict[str, Any]) -> dict[str, Any]:
     module = load_engine_module(module_name)
     process = getattr(module, "process", None)
     if process is None:
         raise AttributeError(f"{module_name} 缺少 process()")
-    result = process(payload)
+    # Whatever the operation leaves registered (cancelled, failed, or forgotten) is purged here,
+    # including the working copy of the input.
+    # `temp_dir` (a RAM disk when enabled) receives scratch files; outputs still land next to their targets.
+    with engine_temp_registry().scope(temp_dir=str(payload.get("temp_dir") or "") or None):
+        run_payload, copy_path = _working_copy_payload(module_name, payload)
+        result = process(run_payload)
     if not isinstance(result, dict):
         raise TypeError(f"{module_name}.process() 未返回 dict")
+    if copy_path:
+        # Callers match results on their own input path; never leak the temp copy's.
+        result = _restore_input_path(result, copy_path, str(payload.get("input_path") or ""))
     return result
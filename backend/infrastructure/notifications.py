@@ -0,0 +1,61 @@
+from __future__ import annotations
+
+import os
+import shutil
+import subprocess
+import sys
+
+_NOTIFY_TIMEOUT_SECONDS = 5
+
+_WINDOWS_TOAST_SCRIPT = """
+$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode($env:IMAGEFLOW_NOTIFY_TITLE)) | Out-Null
+$texts.Item(1).AppendChild($template.CreateTextNode($env:IMAGEFLOW_NOTIFY_BODY)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('ImageFlow').Show($toast)
+"""
+
+
+def _applescript_string(value: str) -> str:
+    return '"' + value.replace("\\", "\\\\").replace('"', '\\"') + '"'
+
+
+def _notify_command(title: str, body: str) -> tuple[list[str], dict[str, str] | None] | None:
+    if sys.platform.startswith("win"):
+        powershell = shutil.which("powershell") or shutil.which("pwsh")
+        if not powershell:
+            return None
+        # Title and body travel through the environment so no quoting reaches the script.
+        env = {"IMAGEFLOW_NOTIFY_TITLE": title, "IMAGEFLOW_NOTIFY_BODY": body}
+        return [powershell, "-NoProfile", "-NonInteractive", "-Command", _WINDOWS_TOAST_SCRIPT], env
+    if sys.platform == "darwin":
+        script = f"display notification {_applescript_string(body)} with title {_applescript_string(title)}"
+        return ["osascript", "-e", script], None
+    notify_send = shutil.which("notify-send")
+    if not notify_send:
+        return None
+    return [notify_send, "--app-name=ImageFlow", title, body], None
+
+
+def notify(title: str, body: str) -> bool:
+    """Show a native desktop notification; returns False instead of raising when unavailable."""
+    command = _notify_command(str(title or ""), str(body or ""))
+    if command is None:
+        return False
+    args, extra_env = command
+    env = {**os.environ, **extra_env} if extra_env else None
+    try:
+        completed = subprocess.run(
+            args,
+            env=env,
+            stdout=subprocess.DEVNULL,
+            stderr=subprocess.DEVNULL,
+            timeout=_NOTIFY_TIMEOUT_SECONDS,
+            check=False,
+        )
+    except (OSError, subprocess.SubprocessError):
+        return False
+    return completed.returncode == 0
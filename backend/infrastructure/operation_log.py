@@ -0,0 +1,205 @@
+from __future__ import annotations
+
+import json
+import logging
+import os
+import re
+import threading
+import time
+import uuid
+from pathlib import Path
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+OPERATION_LOG_NAME = "operations.jsonl"
+# Every record is flushed to the OS, which survives a killed process; fsync (for power
+# loss) is batched so a large batch does not wait on the disk for every item.
+FSYNC_INTERVAL_SECONDS = 1.0
+# Request fields never written to the journal; an intent that carried one lists it in
+# `redacted` and cannot be resumed from the log alone.
+SECRET_FIELDS = ("user_password", "owner_password")
+# Registry temp names: the engine prefix, then the 8 characters `tempfile.mkstemp` draws from
+# lowercase letters, digits and "_"; user files that merely start with "imageflow_" do not match.
+TEMP_NAME_PATTERN = r"imageflow_(?:pdf_)?[a-z0-9_]{8}"
+
+
+class OperationLog:
+    """Append-only intent/result journal for engine items, one JSON record per line.
+
+    An intent is written before an item is handed to the engine and a result once it
+    settles. Intents still open when the app starts again belong to a session that was
+    killed mid-batch; they stay in the log until they are resumed or discarded.
+    """
+
+    def __init__(self, path: Path, fsync_interval: float = FSYNC_INTERVAL_SECONDS):
+        self._path = Path(path)
+        self._fsync_interval = fsync_interval
+        self._lock = threading.Lock()
+        self._handle = None
+        self._last_sync = 0.0
+        self._interrupted: dict[str, dict[str, Any]] = {}
+
+    @property
+    def path(self) -> Path:
+        return self._path
+
+    def open(self) -> None:
+        """Collect intents the previous session left open and rewrite the log to just those."""
+        with self._lock:
+            self._interrupted = _open_intents(_read_records(self._path))
+            self._path.parent.mkdir(parents=True, exist_ok=True)
+            tmp_path = self._path.with_name(self._path.name + ".tmp")
+            with open(tmp_path, "w", encoding="utf-8") as handle:
+                for record in self._interrupted.values():
+                    handle.write(json.dumps(record, ensure_ascii=False) + "\n")
+                handle.flush()
+                os.fsync(handle.fileno())
+            os.replace(tmp_path, self._path)
+            self._handle = open(self._path, "a", encoding="utf-8")
+
+    def close(self) -> None:
+        with self._lock:
+            if self._handle is None:
+                return
+            try:
+                self._handle.flush()
+                os.fsync(self._handle.fileno())
+            except OSError:
+                pass
+            self._handle.close()
+            self._handle = None
+
+    def begin(self, module_name: str, payload: dict[str, Any]) -> str:
+        operation_id = uuid.uuid4().hex
+        redacted = [field for field in SECRET_FIELDS if payload.get(field)]
+        record = {
+            "kind": "intent",
+            "id": operation_id,
+            "module": module_name,
+            "payload": {key: value for key, value in payload.items() if key not in SECRET_FIELDS},
+            "started_at": time.time(),
+        }
+        if redacted:
+            record["redacted"] = redacted
+        self._append(record)
+        return operation_id
+
+    def finish(self, operation_id: str, result: Any) -> None:
+        success = bool(result.get("success")) if isinstance(result, dict) else False
+        error = str(result.get("error") or "") if isinstance(result, dict) else ""
+        self._append({"kind": "result", "id": operation_id, "success": success, "error": error})
+
+    def interrupted(self) -> list[dict[str, Any]]:
+        with self._lock:
+            return [dict(record) for record in self._interrupted.values()]
+
+    def resolve(self, operation_ids: list[str]) -> list[dict[str, Any]]:
+        """Close interrupted intents (resumed or discarded); returns the ones that were open."""
+        with self._lock:
+            resolved = [self._interrupted.pop(op_id) for op_id in operation_ids if op_id in self._interrupted]
+        for record in resolved:
+            self._append({"kind": "resolved", "id": record["id"]}, sync=True)
+        return resolved
+
+    def _append(self, record: dict[str, Any], sync: bool = False) -> None:
+        line = json.dumps(record, ensure_ascii=False, default=str) + "\n"
+        with self._lock:
+            if self._handle is None:
+                return
+            try:
+                self._handle.write(line)
+                self._handle.flush()
+                now = time.monotonic()
+                if sync or now - self._last_sync >= self._fsync_interval:
+                    os.fsync(self._handle.fileno())
+                    self._last_sync = now
+            except (OSError, ValueError) as exc:
+                logger.warning("Operation log write failed: %s", exc)
+
+
+def _read_records(path: Path) -> list[dict[str, Any]]:
+    try:
+        text = path.read_text(encoding="utf-8", errors="replace")
+    except FileNotFoundError:
+        return []
+    except OSError as exc:
+        logger.warning("Operation log unreadable, starting fresh: %s", exc)
+        return []
+    records = []
+    for line in text.splitlines():
+        try:
+            record = json.loads(line)
+        except ValueError:
+            # The last line of a killed session may be cut short.
+            continue
+        if isinstance(record, dict) and record.get("id"):
+            records.append(record)
+    return records
+
+
+def _open_intents(records: list[dict[str, Any]]) -> dict[str, dict[str, Any]]:
+    intents: dict[str, dict[str, Any]] = {}
+    for record in records:
+        if record.get("kind") == "intent" and isinstance(record.get("payload"), dict):
+            intents[record["id"]] = record
+        elif record.get("kind") in ("result", "resolved"):
+            intents.pop(record["id"], None)
+    return intents
+
+
+def leftover_temp_files(record: dict[str, Any]) -> list[str]:
+    """Temp files an interrupted item may have left beside its output.
+
+    Engines stage their output through the temp registry (`mkstemp` with an `imageflow_` or
+    `imageflow_pdf_` prefix) next to the output and rename it into place; a hard kill skips
+    their cleanup. Only names of exactly that shape and at least as new as the intent are listed.
+    """
+    output_path = str((record.get("payload") or {}).get("output_path") or "")
+    if not output_path:
+        return []
+    output = Path(output_path)
+    pattern = re.compile(TEMP_NAME_PATTERN + re.escape(output.suffix or ".tmp"))
+    started_at = float(record.get("started_at") or 0)
+    try:
+        names = sorted(os.listdir(output.parent))
+    except OSError:
+        return []
+    leftovers = []
+    for name in names:
+        if not pattern.fullmatch(name):
+            continue
+        candidate = os.path.join(str(output.parent), name)
+        try:
+            if os.path.isfile(candidate) and os.stat(candidate).st_mtime >= started_at - 1:
+                leftovers.append(candidate)
+        except OSError:
+            continue
+    return leftovers
+
+
+_active: OperationLog | None = None
+
+
+def active_operation_log() -> OperationLog | None:
+    """The log opened by the desktop host; None in tests and scripts, which keep no journal."""
+    return _active
+
+
+def start_operation_log(path: Path) -> OperationLog | None:
+    global _active
+    log = OperationLog(path)
+    try:
+        log.open()
+    except OSError as exc:
+        logger.warning("Operation log disabled: %s", exc)
+        return None
+    _active = log
+    return log
+
+
+def stop_operation_log() -> None:
+    global _active
+    log, _active = _active, None
+    if log is not None:
+        log.close()
@@ -0,0 +1,62 @@
+from __future__ import annotations
+
+import logging
+import os
+import shutil
+import sys
+import tempfile
+
+logger = logging.getLogger(__name__)
+
+# Below this much free space a RAM disk is skipped: an intermediate that no longer fits
+# there fails the operation instead of spilling to disk.
+DEFAULT_RAM_TEMP_MIN_FREE_MB = 512
+
+
+def ram_temp_min_free_bytes() -> int:
+    raw_value = str(os.getenv("IMAGEFLOW_RAM_TEMP_MIN_FREE_MB", "") or "").strip()
+    try:
+        megabytes = int(raw_value) if raw_value else DEFAULT_RAM_TEMP_MIN_FREE_MB
+    except ValueError:
+        megabytes = DEFAULT_RAM_TEMP_MIN_FREE_MB
+    return max(0, megabytes) * 1024 * 1024
+
+
+def ram_temp_candidates(configured: str = "") -> list[str]:
+    """The configured RAM-backed directory, else `/dev/shm` on Linux; other platforms need a path."""
+    configured = str(configured or "").strip()
+    if configured:
+        return [os.path.abspath(os.path.expanduser(configured))]
+    if sys.platform.startswith("linux"):
+        return ["/dev/shm"]
+    return []
+
+
+def ram_temp_dir_error(path: str) -> str:
+    """Why `path` cannot hold scratch files ("" when it can): missing, not writable, or too full."""
+    if not os.path.isdir(path):
+        return f"目录不存在: {path}"
+    try:
+        fd, probe = tempfile.mkstemp(prefix=".imageflow-probe-", dir=path)
+        os.close(fd)
+        os.remove(probe)
+    except OSError as exc:
+        return f"目录不可写: {path} ({exc})"
+    try:
+        free = shutil.disk_usage(path).free
+    except OSError as exc:
+        return f"无法读取可用空间: {path} ({exc})"
+    minimum = ram_temp_min_free_bytes()
+    if free < minimum:
+        return f"可用空间不足: {path} 剩余 {free // (1024 * 1024)} MB，至少需要 {minimum // (1024 * 1024)} MB"
+    return ""
+
+
+def resolve_ram_temp_dir(configured: str = "") -> str:
+    """First usable RAM-backed directory, or "" to fall back to the normal temp dir."""
+    for candidate in ram_temp_candidates(configured):
+        error = ram_temp_dir_error(candidate)
+        if not error:
+            return candidate
+        logger.warning("RAM temp directory unavailable, using the system temp dir: %s", error)
+    return ""
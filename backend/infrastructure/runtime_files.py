@@ -0,0 +1,72 @@
+from __future__ import annotations
+
+import json
+import sys
+from pathlib import Path
+
+# Written into the PyInstaller dist folder at release time; lists every shipped file and its size.
+RUNTIME_MANIFEST_NAME = "runtime-manifest.json"
+
+
+def build_runtime_manifest(root: Path, version: str = "") -> dict:
+    files = {}
+    for path in sorted(root.rglob("*")):
+        if not path.is_file() or path.name == RUNTIME_MANIFEST_NAME:
+            continue
+        files[path.relative_to(root).as_posix()] = path.stat().st_size
+    return {"version": version, "files": files}
+
+
+def write_runtime_manifest(root: Path, version: str = "") -> Path:
+    manifest_path = root / RUNTIME_MANIFEST_NAME
+    manifest = build_runtime_manifest(root, version)
+    manifest_path.write_text(json.dumps(manifest, indent=0, sort_keys=True), encoding="utf-8")
+    return manifest_path
+
+
+def runtime_root() -> Path | None:
+    """Folder a packaged build runs from; None for source checkouts, which ship no manifest."""
+    if not getattr(sys, "frozen", False):
+        return None
+    return Path(sys.executable).resolve().parent
+
+
+def runtime_version(root: Path | None = None) -> str:
+    """Release version recorded in the manifest; "" for source runs or older manifests."""
+    root = runtime_root() if root is None else root
+    if root is None:
+        return ""
+    try:
+        manifest = json.loads((root / RUNTIME_MANIFEST_NAME).read_text(encoding="utf-8"))
+    except (OSError, ValueError):
+        return ""
+    return str(manifest.get("version") or "") if isinstance(manifest, dict) else ""
+
+
+def verify_runtime_files(root: Path | None = None) -> list[str]:
+    """Relative paths that are missing or whose size differs from the release manifest.
+
+    An interrupted unzip or install leaves the executable in place while libraries are
+    missing or cut short, which otherwise only shows up later as an import error.
+    Returns an empty list when there is no manifest to check against.
+    """
+    root = runtime_root() if root is None else root
+    if root is None:
+        return []
+    manifest_path = root / RUNTIME_MANIFEST_NAME
+    try:
+        expected = json.loads(manifest_path.read_text(encoding="utf-8")).get("files", {})
+    except FileNotFoundError:
+        return []
+    except (OSError, ValueError, AttributeError):
+        return [RUNTIME_MANIFEST_NAME]
+    problems = []
+    for relative, size in expected.items():
+        try:
+            actual = (root / relative).stat().st_size
+        except OSError:
+            problems.append(relative)
+            continue
+        if actual != size:
+            problems.append(relative)
+    return problems
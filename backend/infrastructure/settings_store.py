@@ -4,9 +4,18 @@ from dataclasses import asdict, fields
 from pathlib import Path
 from typing import Any
 
-from backend.contracts.settings import AppSettings, default_app_settings
+from backend.contracts.messages import normalize_language
+from backend.contracts.settings import (
+    BATCH_ORDERS,
+    CONFLICT_STRATEGIES,
+    PREVIEW_MODES,
+    AppSettings,
+    default_app_settings,
+)
+from backend.domain.formats import FORMAT_CAPABILITIES, canonical_format
 
 MAX_RECENT_PATHS = 4
+SETTINGS_BACKUP_SUFFIX = ".bak"
 
 
 def _clamp(value: int, min_value: int, max_value: int) -> int:
@@ -68,13 +77,38 @@ def _normalize_recent_paths(values: Any) -> list[str]:
     return normalized
 
 
+def _normalize_format_quality_defaults(values: Any) -> dict[str, int]:
+    if not isinstance(values, dict):
+        return {}
+
+    normalized: dict[str, int] = {}
+    for raw_format, raw_quality in values.items():
+        format_name = canonical_format(raw_format)
+        capabilities = FORMAT_CAPABILITIES.get(format_name)
+        if not capabilities or not capabilities.get("quality"):
+            continue
+        if isinstance(raw_quality, bool):
+            continue
+        quality = _coerce_int(raw_quality, 0)
+        if quality <= 0:
+            continue
+        normalized[format_name] = _clamp(quality, 1, 100)
+    return normalized
+
+
 def normalize_settings(settings: AppSettings) -> AppSettings:
     defaults = default_app_settings()
     output_prefix = str(settings.output_prefix or "").strip() or defaults.output_prefix
     output_template = str(settings.output_template or "").strip() or defaults.output_template
     conflict_strategy = str(settings.conflict_strategy or "").strip() or defaults.conflict_strategy
-    if conflict_strategy != "rename":
+    if conflict_strategy not in CONFLICT_STRATEGIES:
         conflict_strategy = defaults.conflict_strategy
+    batch_order = str(settings.batch_order or "").strip().lower()
+    if batch_order not in BATCH_ORDERS:
+        batch_order = defaults.batch_order
+    preview_mode = str(settings.preview_mode or "").strip().lower()
+    if preview_mode not in PREVIEW_MODES:
+        preview_mode = defaults.preview_mode
 
     return AppSettings(
         max_concurrency=_clamp(_coerce_int(settings.max_concurrency, defaults.max_concurrency), 1, 32),
@@ -85,6 +119,21 @@ def normalize_settings(settings: AppSettings) -> AppSettings:
         default_output_dir=_normalize_saved_path(settings.default_output_dir),
         recent_input_dirs=_normalize_recent_paths(settings.recent_input_dirs),
         recent_output_dirs=_normalize_recent_paths(settings.recent_output_dirs),
+        preview_mode=preview_mode,
+        format_quality_defaults=_normalize_format_quality_defaults(settings.format_quality_defaults),
+        language=normalize_language(settings.language),
+        open_folder_when_done=_coerce_bool(settings.open_folder_when_done, defaults.open_folder_when_done),
+        notify_on_completion=_coerce_bool(settings.notify_on_completion, defaults.notify_on_completion),
+        use_working_copy=_coerce_bool(settings.use_working_copy, defaults.use_working_copy),
+        batch_order=batch_order,
+        use_ram_temp=_coerce_bool(settings.use_ram_temp, defaults.use_ram_temp),
+        ram_temp_dir=_normalize_saved_path(settings.ram_temp_dir),
+        max_megapixels=_clamp(_coerce_int(settings.max_megapixels, defaults.max_megapixels), 1, 10_000),
+        gif_max_frames=_clamp(_coerce_int(settings.gif_max_frames, defaults.gif_max_frames), 1, 100_000),
+        gif_max_megapixels=_clamp(_coerce_int(settings.gif_max_megapixels, defaults.gif_max_megapixels), 1, 100_000),
+        gif_max_export_files=_clamp(_coerce_int(settings.gif_max_export_files, defaults.gif_max_export_files), 1, 100_000),
+        safe_mode=_coerce_bool(settings.safe_mode, defaults.safe_mode),
+        edit_history_depth=_clamp(_coerce_int(settings.edit_history_depth, defaults.edit_history_depth), 1, 100),
     )
 
 
@@ -95,6 +144,21 @@ def settings_from_dict(data: dict[str, Any]) -> AppSettings:
     return AppSettings(**values)
 
 
+def settings_to_json(settings: AppSettings) -> str:
+    return json.dumps(asdict(normalize_settings(settings)), ensure_ascii=False, indent=2)
+
+
+def settings_from_json(data: str) -> AppSettings:
+    """Parse exported settings JSON; unknown keys are dropped and values normalized."""
+    try:
+        parsed = json.loads(str(data or ""))
+    except json.JSONDecodeError as exc:
+        raise ValueError(f"[BAD_INPUT] Invalid settings JSON: {exc.msg} (line {exc.lineno}, column {exc.colno})") from exc
+    if not isinstance(parsed, dict):
+        raise ValueError("[BAD_INPUT] Settings JSON must be an object")
+    return normalize_settings(settings_from_dict(parsed))
+
+
 def _settings_file_path() -> tuple[Path, bool]:
     override = os.getenv("IMAGEFLOW_SETTINGS_FILE", "").strip()
     if override:
@@ -148,3 +212,25 @@ def save_settings(settings: AppSettings) -> AppSettings:
             pass
         raise
     return normalized
+
+
+def settings_backup_path() -> Path:
+    path, _is_override = _settings_file_path()
+    return path.with_name(path.name + SETTINGS_BACKUP_SUFFIX)
+
+
+def operation_log_path() -> Path:
+    from backend.infrastructure.operation_log import OPERATION_LOG_NAME
+
+    path, _is_override = _settings_file_path()
+    return path.with_name(OPERATION_LOG_NAME)
+
+
+def reset_settings() -> AppSettings:
+    """Write default settings, keeping the prior file (even if corrupt) as a .bak copy."""
+    import shutil
+
+    path, _is_override = _settings_file_path()
+    if path.exists():
+        shutil.copy2(path, settings_backup_path())
+    return save_settings(default_app_settings())
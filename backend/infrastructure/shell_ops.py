@@ -0,0 +1,31 @@
+from __future__ import annotations
+
+import os
+import subprocess
+import sys
+from pathlib import Path
+
+
+def reveal_path(path_value: str) -> bool:
+    """Open the OS file manager at a directory, or at a file's parent with it selected.
+
+    Returns False when the path does not exist or no file manager could be launched.
+    """
+    target = Path(str(path_value or "")).expanduser()
+    if not str(path_value or "").strip() or not target.exists():
+        return False
+    try:
+        if sys.platform.startswith("win"):
+            if target.is_dir():
+                os.startfile(str(target))  # type: ignore[attr-defined]
+            else:
+                subprocess.Popen(["explorer", f"/select,{target}"])
+        elif sys.platform == "darwin":
+            args = ["open", str(target)] if target.is_dir() else ["open", "-R", str(target)]
+            subprocess.Popen(args)
+        else:
+            folder = target if target.is_dir() else target.parent
+            subprocess.Popen(["xdg-open", str(folder)])
+    except (OSError, AttributeError):
+        return False
+    return True
@@ -1,5 +1,6 @@
 from __future__ import annotations
 
+import json
 
 _WINDOW_MAXIMIZED = False
 
@@ -42,3 +43,15 @@ def set_window_maximized(is_maximized: bool) -> None:
 
 def is_window_maximized() -> bool:
     return _WINDOW_MAXIMIZED
+
+
+def dispatch_window_event(name: str, detail: dict) -> bool:
+    """Dispatch a CustomEvent on the frontend window; returns False when no window is open."""
+    window = _get_first_window()
+    if window is None:
+        return False
+    window.evaluate_js(
+        "window.dispatchEvent(new CustomEvent(%s, { detail: %s }));"
+        % (json.dumps(str(name)), json.dumps(detail, ensure_ascii=True))
+    )
+    return True
@@ -2,8 +2,6 @@ from backend.host.window import build_window_api, configure_window, resolve_fron
 
 
 def main() -> None:
-    import threading
-
     import webview
     from PIL import Image
 
@@ -27,20 +25,47 @@ def main() -> None:
     )
     configure_window(window)
 
-    def _warm_runtime() -> None:
-        try:
-            from backend.application.image_ops import warm_process_pool
-            warm_process_pool()
-        except Exception:
-            pass
-        try:
-            from backend.infrastructure.engine_loader import load_engine_module
-            load_engine_module("converter")
-        except Exception:
-            pass
-
-    threading.Thread(target=_warm_runtime, name="imageflow-warmup", daemon=True).start()
-    webview.start()
+    from backend.api.desktop_api import notify_runtime_progress
+    from backend.application.runtime_status import runtime_preparation
+    from backend.application.startup_status import safe_mode_requested, startup_status
+    from backend.infrastructure.operation_log import start_operation_log
+    from backend.infrastructure.settings_store import load_settings, operation_log_path
+
+    # Nothing below may stop the window from opening; failures are kept for GetStartupError.
+    status = startup_status()
+    settings = None
+    with status.step("settings"):
+        settings = load_settings()
+    reason = safe_mode_requested(settings)
+    if reason:
+        with status.step("safe_mode"):
+            status.enter_safe_mode(reason)
+
+    # Before any engine runs: picks up items a killed session left unfinished.
+    with status.step("operation_log"):
+        start_operation_log(operation_log_path())
+
+    # Engine checks and pool warm-up stay off the UI thread; the UI follows them via
+    # GetRuntimeStatus and runtime progress events.
+    with status.step("runtime_preparation"):
+        runtime_preparation().start(notify_runtime_progress)
+    try:
+        webview.start()
+    finally:
+        _shutdown()
+
+
+def _shutdown() -> None:
+    try:
+        from backend.infrastructure.engine_loader import purge_engine_temp_files
+        purge_engine_temp_files()
+    except Exception:
+        pass
+    try:
+        from backend.infrastructure.operation_log import stop_operation_log
+        stop_operation_log()
+    except Exception:
+        pass
 
 
 def bootstrap() -> None:
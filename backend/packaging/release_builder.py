@@ -10,6 +10,7 @@ from zipfile import ZIP_DEFLATED, ZipFile
 
 from PIL import Image
 
+from backend.infrastructure.runtime_files import write_runtime_manifest
 from backend.packaging.inno import find_iscc, write_inno_script
 from backend.packaging.release_config import ReleasePaths, create_release_paths, validate_windows_build_host
 
@@ -135,6 +136,8 @@ def run_pyinstaller(paths: ReleasePaths) -> Path:
     exe_path = paths.pyinstaller_dist_dir / f"{paths.app_name}.exe"
     if not exe_path.exists():
         raise FileNotFoundError(f"PyInstaller output missing: {exe_path}")
+    # Lets the app detect an interrupted unzip/install on startup (see verify_runtime_files).
+    write_runtime_manifest(paths.pyinstaller_dist_dir, paths.version)
     return paths.pyinstaller_dist_dir
 
 
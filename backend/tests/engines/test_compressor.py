@@ -1,9 +1,11 @@
 import os
+import re
 import sys
 import tempfile
 import unittest
 from io import BytesIO
 from pathlib import Path
+from unittest import mock
 
 from PIL import Image
 
@@ -90,6 +92,29 @@ class CompressorTests(unittest.TestCase):
         self.assertIn(b"JFIF", stripped)
         self.assertIn(b"\xff\xda\x00\x04AB\x11\x22\xff\xd9", stripped)
 
+    def test_jpeg_strip_modes_choose_which_segments_go(self):
+        # SOI + APP1(EXIF) + APP1(XMP) + APP2(ICC) + SOS + image bytes + EOI.
+        jpeg_bytes = (
+            b"\xff\xd8"
+            b"\xff\xe1\x00\x0aExif\x00GPS"
+            b"\xff\xe1\x00\x06<xmp"
+            b"\xff\xe2\x00\x10ICC_PROFILE\x00\x01\x01"
+            b"\xff\xda\x00\x04AB"
+            b"\x11\x22\xff\xd9"
+        )
+        kept = {}
+        for mode in compressor.STRIP_MODES:
+            dst = BytesIO()
+            _copy_jpeg_without_metadata(BoundedReadBytesIO(jpeg_bytes), dst, mode)
+            kept[mode] = [marker for marker in (b"GPS", b"<xmp", b"ICC_PROFILE") if marker in dst.getvalue()]
+            self.assertTrue(dst.getvalue().endswith(b"\xff\xda\x00\x04AB\x11\x22\xff\xd9"), mode)
+
+        self.assertEqual(kept, {"all": [], "exif_only": [b"<xmp", b"ICC_PROFILE"], "keep_icc": [b"ICC_PROFILE"]})
+        with self.assertRaises(ValueError):
+            compressor._resolve_strip_mode("gps", True)
+        self.assertEqual(compressor._resolve_strip_mode("", True), "keep_icc")
+        self.assertEqual(compressor._resolve_strip_mode("", False), "")
+
     def test_compress_closes_open_image_when_engine_errors(self):
         src = self._path("input.png")
         with open(src, "wb") as handle:
@@ -326,6 +351,146 @@ class CompressorTests(unittest.TestCase):
         self.assertIsNotNone(source.quantized)
         self.assertTrue(source.quantized.closed)
 
+    def test_estimate_reports_sizes_without_writing_the_output(self):
+        src = self._path("estimate.jpg")
+        Image.effect_noise((96, 96), 60).convert("RGB").save(src, quality=98)
+        dst = self._path("estimate_out.jpg")
+        registered = compressor.TEMP_REGISTRY.paths()
+
+        result = compressor.process({"input_path": src, "output_path": dst, "level": 4, "estimate": True})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["estimate"])
+        self.assertEqual(result["original_size"], os.path.getsize(src))
+        self.assertLess(result["compressed_size"], result["original_size"])
+        self.assertGreater(result["compression_rate"], 0)
+        self.assertFalse(os.path.exists(dst))
+        self.assertEqual(compressor.TEMP_REGISTRY.paths(), registered)
+        self.assertTrue(compressor.process({"input_path": src, "estimate": True}).get("success"))
+
+    def test_unreachable_target_size_writes_the_smallest_file_and_names_its_size(self):
+        noise = Image.effect_noise((256, 256), 80).convert("RGB")
+        for fmt, ext in (("JPEG", ".jpg"), ("PNG", ".png"), ("WEBP", ".webp")):
+            src = self._path(f"target_src{ext}")
+            noise.save(src, format=fmt, **({} if fmt == "PNG" else {"quality": 95}))
+            dst = self._path(f"target_out{ext}")
+
+            result = ImageCompressor().compress(src, dst, level=CompressionLevel.MEDIUM, target_size_kb=1)
+
+            self.assertTrue(result.get("success"), (fmt, result))
+            self.assertTrue(os.path.exists(dst), fmt)
+            self.assertEqual((result["target_size_kb"], result["target_met"]), (1, False), fmt)
+            achieved = f"{os.path.getsize(dst) / 1024:.1f}KB"
+            self.assertIn(f"目标大小 1KB 未达成，已输出最小可得文件（{achieved}）", result["warning"], fmt)
+
+        reached = ImageCompressor().compress(src, self._path("reached.webp"), level=CompressionLevel.MEDIUM, target_size_kb=4096)
+        self.assertTrue(reached["target_met"])
+        self.assertNotIn("warning", reached)
+
+    def test_lossless_flag_encodes_png_and_webp_exactly_at_any_level(self):
+        art = Image.new("RGB", (64, 64), (255, 255, 255))
+        for x in range(64):
+            art.putpixel((x, x), (255, 40 + x, 0))
+        for fmt, ext in (("PNG", ".png"), ("WEBP", ".webp")):
+            src = self._path(f"art{ext}")
+            art.save(src, format=fmt, **({} if fmt == "PNG" else {"lossless": True}))
+            dst = self._path(f"art_out{ext}")
+
+            result = compressor.process(
+                {"input_path": src, "output_path": dst, "level": CompressionLevel.EXTREME, "lossless": True}
+            )
+
+            self.assertTrue(result.get("success"), (fmt, result))
+            self.assertTrue(result["lossless"], fmt)
+            with Image.open(dst) as out:
+                self.assertEqual(list(out.convert("RGB").getdata()), list(art.getdata()), fmt)
+
+        lossy = compressor.process({"input_path": src, "output_path": dst, "level": CompressionLevel.EXTREME})
+        self.assertFalse(lossy["lossless"])
+
+    def test_lossless_flag_is_ignored_with_a_warning_for_jpeg(self):
+        src = self._path("photo.jpg")
+        Image.effect_noise((32, 32), 40).convert("RGB").save(src, quality=95)
+
+        result = ImageCompressor().compress(src, self._path("photo_out.jpg"), level=CompressionLevel.MEDIUM, lossless=True)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result["lossless"])
+        self.assertIn("已忽略 lossless", result["warning"])
+
+    def test_result_names_the_engine_that_wrote_the_output(self):
+        noise = Image.effect_noise((48, 48), 60).convert("RGB")
+        jpeg = self._path("engine.jpg")
+        noise.save(jpeg, quality=95)
+        png = self._path("engine.png")
+        noise.save(png)
+        webp = self._path("engine.webp")
+        noise.save(webp, quality=95)
+        cases = (
+            (jpeg, CompressionLevel.LOSSLESS, "pillow", "copy"),
+            (jpeg, CompressionLevel.MEDIUM, "pillow", "pillow"),
+            (png, CompressionLevel.MEDIUM, "pillow", "pillow"),
+            (webp, CompressionLevel.MEDIUM, "", "pillow"),
+        )
+        for src, level, engine, expected in cases:
+            dst = self._path(f"engine_out_{level}{Path(src).suffix}")
+
+            result = ImageCompressor().compress(src, dst, level=level, engine=engine)
+
+            self.assertTrue(result.get("success"), result)
+            self.assertEqual(result["engine_used"], expected, (src, level))
+
+    def test_ssim_score_is_one_for_identical_images_and_drops_with_noise(self):
+        gradient = Image.linear_gradient("L").resize((64, 40))
+        noisy = Image.blend(gradient, Image.effect_noise((64, 40), 80), 0.5)
+
+        self.assertAlmostEqual(compressor.ssim_score(gradient, gradient.copy()), 1.0, places=4)
+        self.assertLess(compressor.ssim_score(gradient, noisy), 0.9)
+
+    def test_target_ssim_picks_the_lowest_quality_reaching_it_on_the_written_file(self):
+        src = self._path("search.jpg")
+        image = Image.new("RGB", (900, 600))
+        image.putdata([((x * 5) % 256, (y * 3) % 256, (x ^ y) % 256) for y in range(600) for x in range(900)])
+        image.save(src, quality=98)
+
+        strict = compressor.process({"input_path": src, "output_path": self._path("strict.jpg"), "target_ssim": 0.99})
+        loose = compressor.process({"input_path": src, "output_path": self._path("loose.jpg"), "target_ssim": 0.5})
+
+        self.assertTrue(strict.get("success"), strict)
+        self.assertGreaterEqual(strict["quality"], loose["quality"])
+        self.assertEqual(strict["target_ssim"], 0.99)
+        with Image.open(src) as original, Image.open(self._path("strict.jpg")) as written:
+            score = compressor.ssim_score(original.convert("L"), written.convert("L"))
+        self.assertAlmostEqual(strict["ssim"], score, places=4)
+
+    def test_target_ssim_rejects_formats_it_cannot_search(self):
+        src = self._path("flat.png")
+        Image.new("RGB", (16, 16), (10, 20, 30)).save(src)
+
+        result = ImageCompressor().compress(src, self._path("flat_out.png"), target_ssim=0.9)
+
+        self.assertIn("[UNSUPPORTED_FORMAT]", result["error"])
+
+    def test_compress_request_model_matches_what_the_engine_reads(self):
+        models = Path(__file__).resolve().parents[3] / "frontend" / "types" / "backend-models.ts"
+        block = re.search(
+            r"export class CompressRequest \{(.*?)static createFrom", models.read_text(encoding="utf-8"), re.S
+        ).group(1)
+        fields = set(re.findall(r"^\s*(\w+)\??:", block, re.M))
+        # Consumed by the host: skipping outputs that are already fresh.
+        host_only = {"skip_up_to_date"}
+        payload = {name: f"<{name}>" for name in fields}
+
+        with mock.patch.object(ImageCompressor, "__init__", return_value=None), mock.patch.object(
+            ImageCompressor, "compress", return_value={"success": True}
+        ) as compress, self.assertLogs(compressor.logger, "INFO") as logs:
+            compressor.process(payload)
+
+        self.assertNotIn("mode", fields)
+        self.assertEqual(set(compress.call_args.kwargs), fields - host_only)
+        self.assertEqual({key: compress.call_args.kwargs[key] for key in ("level", "engine")}, {"level": "<level>", "engine": "<engine>"})
+        self.assertIn("(level: <level>, engine: <engine>)", "\n".join(logs.output))
+
 
 if __name__ == "__main__":
     unittest.main()
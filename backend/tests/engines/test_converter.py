@@ -274,8 +274,47 @@ class ICOConversionTests(unittest.TestCase):
         with Image.open(out) as img:
             self.assertEqual(
                 sorted(img.info.get("sizes", [])),
-                [(16, 16), (32, 32), (48, 48), (64, 64), (128, 128), (256, 256)],
+                [(16, 16), (32, 32), (48, 48), (256, 256)],
             )
+        self.assertEqual(result.get("ico_sizes"), [16, 32, 48, 256])
+
+    def test_ico_writes_requested_sizes_deduplicated_and_sorted(self):
+        src = self._path("source.png")
+        Image.new("RGBA", (40, 40), (10, 120, 220, 255)).save(src, format="PNG")
+        out = self._path("sizes.ico")
+
+        result = convert_process(
+            {
+                "input_path": src,
+                "output_path": out,
+                "format": "ico",
+                "ico_sizes": [128, 16, 24, 16, 256],
+            }
+        )
+
+        self.assertTrue(result.get("success"))
+        self.assertEqual(result.get("ico_sizes"), [16, 24, 128, 256])
+        with Image.open(out) as img:
+            self.assertEqual(sorted(img.info.get("sizes", [])), [(16, 16), (24, 24), (128, 128), (256, 256)])
+
+    def test_ico_rejects_sizes_above_256(self):
+        src = self._path("source.png")
+        Image.new("RGBA", (64, 64), (10, 120, 220, 255)).save(src, format="PNG")
+        out = self._path("too_big.ico")
+
+        for sizes in ([16, 512], [0], ["big"]):
+            result = convert_process(
+                {
+                    "input_path": src,
+                    "output_path": out,
+                    "format": "ico",
+                    "ico_sizes": sizes,
+                }
+            )
+
+            self.assertFalse(result.get("success"), sizes)
+            self.assertTrue(result.get("error", "").startswith("[BAD_INPUT]"), sizes)
+        self.assertFalse(os.path.exists(out))
 
     def test_single_size_ico_adds_size_suffix_to_output_path(self):
         src = self._path("source.png")
@@ -361,6 +400,127 @@ class ICOConversionTests(unittest.TestCase):
             self.assertEqual(sorted(img.info.get("sizes", [])), [(16, 16), (32, 32), (64, 64)])
 
 
+class ColorSpaceConversionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def test_cmyk_jpeg_is_converted_to_rgb_and_flagged(self):
+        src = self._path("print.jpg")
+        Image.new("CMYK", (32, 24), (0, 255, 255, 0)).save(src, format="JPEG", quality=95)
+        out = self._path("print.png")
+
+        result = convert_process({"input_path": src, "output_path": out, "format": "png"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result.get("was_cmyk"))
+        self.assertEqual(result.get("color_mode"), "CMYK")
+        self.assertIn("CMYK", result.get("warning", ""))
+        with Image.open(out) as img:
+            self.assertEqual(img.mode, "RGB")
+            r, g, b = img.getpixel((16, 12))
+        self.assertGreater(r, 200)
+        self.assertLess(g, 60)
+        self.assertLess(b, 60)
+
+    def test_rgb_input_is_not_flagged_as_cmyk(self):
+        src = self._path("plain.png")
+        Image.new("RGB", (8, 8), (10, 20, 30)).save(src, format="PNG")
+
+        result = convert_process({"input_path": src, "output_path": self._path("plain.jpg"), "format": "jpg"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result.get("was_cmyk"))
+        self.assertEqual(result.get("color_mode"), "RGB")
+        self.assertNotIn("warning", result)
+
+
+class HighBitDepthConversionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def test_16bit_png_is_preserved_when_requested(self):
+        src = self._path("scan.png")
+        Image.new("I;16", (8, 8), 40000).save(src, format="PNG")
+        out = self._path("scan_out.png")
+
+        result = convert_process({"input_path": src, "output_path": out, "format": "png", "preserve_16bit": True})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertNotIn("warning", result)
+        with Image.open(out) as img:
+            self.assertIn(img.mode, {"I", "I;16"})
+            self.assertAlmostEqual(img.getpixel((4, 4)), 40000, delta=1)
+
+    def test_16bit_png_keeps_its_depth_unless_the_request_opts_out(self):
+        src = self._path("scan.png")
+        Image.new("I;16", (8, 8), 40000).save(src, format="PNG")
+
+        for fmt, preserve, depth in (("png", None, 16), ("tiff", None, 16), ("png", False, 8)):
+            out = self._path(f"scan_out_{preserve}.{fmt}")
+            payload = {"input_path": src, "output_path": out, "format": fmt}
+            if preserve is not None:
+                payload["preserve_bit_depth"] = preserve
+
+            result = convert_process(payload)
+
+            self.assertTrue(result.get("success"), result)
+            self.assertEqual(result["bit_depth"], depth, (fmt, preserve))
+            self.assertNotIn("warning", result)
+
+    def test_16bit_source_is_scaled_not_clipped_when_downconverted(self):
+        src = self._path("scan.png")
+        Image.new("I;16", (8, 8), 32768).save(src, format="PNG")
+        out = self._path("scan.jpg")
+
+        result = convert_process({"input_path": src, "output_path": out, "format": "jpg", "preserve_16bit": True})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertIn("8 位", result.get("warning", ""))
+        with Image.open(out) as img:
+            value = img.convert("L").getpixel((4, 4))
+        self.assertAlmostEqual(value, 128, delta=3)
+
+    def test_preserve_bit_depth_reports_written_depth(self):
+        src = self._path("film.png")
+        Image.new("I;16", (8, 8), 50000).save(src, format="PNG")
+
+        for fmt, depth in (("png", 16), ("tiff", 16), ("jpg", 8)):
+            out = self._path(f"film_out.{fmt}")
+            result = convert_process({"input_path": src, "output_path": out, "format": fmt, "preserve_bit_depth": True})
+
+            self.assertTrue(result.get("success"), result)
+            self.assertEqual(result["bit_depth"], depth, fmt)
+            self.assertEqual("warning" in result, depth == 8, fmt)
+            if fmt == "tiff":
+                with Image.open(out) as img:
+                    self.assertEqual(img.mode, "I;16")
+
+    def test_hdr_float_tiff_is_tone_mapped(self):
+        src = self._path("hdr.tif")
+        Image.new("F", (8, 8), 3.0).save(src, format="TIFF")
+        out = self._path("hdr.png")
+
+        result = convert_process({"input_path": src, "output_path": out, "format": "png"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result.get("tone_mapped"))
+        with Image.open(out) as img:
+            # Reinhard maps 3.0 to 0.75 rather than clipping to white.
+            self.assertAlmostEqual(img.getpixel((4, 4)), 191, delta=2)
+
+
 class ConversionResourceTests(unittest.TestCase):
     def setUp(self):
         self.temp_dir = tempfile.TemporaryDirectory()
@@ -421,6 +581,729 @@ class ConversionResourceTests(unittest.TestCase):
         finally:
             converter.Image.open = original_open
 
+    def test_oversized_input_is_rejected_before_decoding(self):
+        class HugeHeader:
+            size = (50000, 50000)
+            mode = "RGB"
+            info = {}
+            loaded = False
+            closed = False
+
+            def load(self):
+                self.loaded = True
+
+            def close(self):
+                self.closed = True
+
+        source = HugeHeader()
+        original_open = converter.Image.open
+        try:
+            converter.Image.open = lambda _path: source
+            output_path = self._path("bomb.png")
+
+            result = converter.ImageConverter().convert(
+                input_path="virtual-input.png",
+                output_path=output_path,
+                format_type="png",
+            )
+
+            self.assertFalse(result.get("success"))
+            self.assertEqual(result.get("error"), "[IMAGE_TOO_LARGE] image exceeds 256 megapixels (50000x50000)")
+            self.assertFalse(source.loaded)
+            self.assertTrue(source.closed)
+            self.assertFalse(os.path.exists(output_path))
+        finally:
+            converter.Image.open = original_open
+
+    def test_max_megapixels_is_configurable_and_validated(self):
+        src = self._path("source.png")
+        Image.new("RGB", (1500, 1000), (10, 120, 220)).save(src, format="PNG")
+
+        limited = convert_process(
+            {"input_path": src, "output_path": self._path("limited.png"), "format": "png", "max_megapixels": 1}
+        )
+        self.assertTrue(limited.get("error", "").startswith("[IMAGE_TOO_LARGE] image exceeds 1 megapixels"))
+
+        allowed = convert_process(
+            {"input_path": src, "output_path": self._path("allowed.png"), "format": "png", "max_megapixels": 2}
+        )
+        self.assertTrue(allowed.get("success"))
+
+        invalid = convert_process(
+            {"input_path": src, "output_path": self._path("invalid.png"), "format": "png", "max_megapixels": -5}
+        )
+        self.assertTrue(invalid.get("error", "").startswith("[BAD_INPUT] Invalid max_megapixels"))
+
+    def test_dpi_is_written_for_resolution_formats_and_kept_from_the_source(self):
+        src = self._path("dpi.jpg")
+        Image.new("RGB", (40, 30), (10, 120, 220)).save(src, format="JPEG", dpi=(150, 150))
+
+        for extension, fmt in (("jpg", "jpg"), ("png", "png"), ("tif", "tiff")):
+            output_path = self._path(f"print.{extension}")
+            result = convert_process({"input_path": src, "output_path": output_path, "format": fmt, "dpi": 300})
+            self.assertEqual(result.get("dpi"), 300, fmt)
+            with Image.open(output_path) as written:
+                self.assertEqual(tuple(round(value) for value in written.info["dpi"]), (300, 300), fmt)
+
+        kept = convert_process({"input_path": src, "output_path": self._path("kept.png"), "format": "png"})
+        self.assertEqual(kept.get("dpi"), 150)
+
+        webp = convert_process({"input_path": src, "output_path": self._path("web.webp"), "format": "webp", "dpi": 300})
+        self.assertTrue(webp.get("success"))
+        self.assertNotIn("dpi", webp)
+
+        invalid = convert_process({"input_path": src, "output_path": self._path("bad.jpg"), "format": "jpg", "dpi": -1})
+        self.assertTrue(invalid.get("error", "").startswith("[BAD_INPUT] Invalid dpi"))
+
+    def test_target_size_lowers_quality_in_the_same_call_and_strips_metadata(self):
+        src = self._path("noisy.png")
+        Image.frombytes("RGB", (256, 256), os.urandom(256 * 256 * 3)).save(src, format="PNG")
+        exif = Image.Exif()
+        exif[0x010F] = "Camera"
+        exif_src = self._path("noisy.jpg")
+        with Image.open(src) as img:
+            img.save(exif_src, format="JPEG", quality=95, exif=exif.tobytes(), comment=b"note")
+        output_path = self._path("small.jpg")
+
+        result = convert_process(
+            {
+                "input_path": exif_src,
+                "output_path": output_path,
+                "format": "jpg",
+                "quality": 90,
+                "resize_mode": "long_edge",
+                "long_edge": 128,
+                "keep_metadata": True,
+                "strip_metadata": True,
+                "target_size_kb": 8,
+            }
+        )
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["target_met"])
+        self.assertLess(result["quality"], 90)
+        self.assertLessEqual(result["file_size"], 8 * 1024)
+        self.assertEqual(os.path.getsize(output_path), result["file_size"])
+        with Image.open(output_path) as written:
+            self.assertEqual(max(written.size), 128)
+            self.assertNotIn("exif", written.info)
+            self.assertNotIn("comment", written.info)
+
+        unmet = convert_process(
+            {"input_path": src, "output_path": self._path("big.png"), "format": "png", "target_size_kb": 1}
+        )
+        self.assertTrue(unmet.get("success"), unmet)
+        self.assertFalse(unmet["target_met"])
+        self.assertIn("未达成", unmet.get("warning", ""))
+
+        invalid = convert_process(
+            {"input_path": src, "output_path": self._path("bad.jpg"), "format": "jpg", "target_size_kb": -1}
+        )
+        self.assertTrue(invalid.get("error", "").startswith("[BAD_INPUT] Invalid target_size_kb"))
+
+    def test_pillow_decompression_bomb_maps_to_image_too_large(self):
+        def bomb(_path):
+            raise Image.DecompressionBombError("Image size (200000000 pixels) exceeds limit")
+
+        original_open = converter.Image.open
+        try:
+            converter.Image.open = bomb
+            result = converter.ImageConverter().convert(
+                input_path="virtual-input.png",
+                output_path=self._path("bomb.png"),
+                format_type="png",
+            )
+        finally:
+            converter.Image.open = original_open
+
+        self.assertFalse(result.get("success"))
+        self.assertTrue(result.get("error", "").startswith("[IMAGE_TOO_LARGE]"))
+
+    def test_svg_render_size_is_checked_against_the_limit(self):
+        svg_path = self._path("big.svg")
+        with open(svg_path, "w", encoding="utf-8") as f:
+            f.write('<svg xmlns="http://www.w3.org/2000/svg" width="4000" height="4000"></svg>')
+
+        result = convert_process(
+            {"input_path": svg_path, "output_path": self._path("big.png"), "format": "png", "max_megapixels": 4}
+        )
+
+        self.assertFalse(result.get("success"))
+        self.assertTrue(result.get("error", "").startswith("[IMAGE_TOO_LARGE] image exceeds 4 megapixels (4000x4000)"))
+
+
+class AVIFConversionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def test_avif_save_params_honor_quality_and_lossless(self):
+        engine = converter.ImageConverter()
+
+        lossy = engine._get_save_params("avif", 55)
+        exact = engine._get_save_params("avif", 55, lossless=True)
+
+        self.assertEqual(lossy["quality"], 55)
+        self.assertNotIn("optimize", lossy)
+        self.assertEqual((exact["quality"], exact["subsampling"]), (100, "4:4:4"))
+
+    def test_avif_missing_encoder_returns_structured_error(self):
+        src = self._path("in.png")
+        Image.new("RGB", (8, 8), (1, 2, 3)).save(src)
+        original = converter.avif_encoder_available
+        try:
+            converter.avif_encoder_available = lambda: False
+            result = converter.ImageConverter().convert(src, self._path("out.avif"), "avif")
+        finally:
+            converter.avif_encoder_available = original
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[UNSUPPORTED_FORMAT]"))
+
+    def test_avif_conversion_resizes_and_reports_lossless(self):
+        if not converter.avif_encoder_available():
+            self.skipTest("AVIF encoder not available")
+        src = self._path("in.png")
+        Image.new("RGB", (80, 40), (10, 120, 200)).save(src)
+        out = self._path("out.avif")
+
+        result = convert_process(
+            {"input_path": src, "output_path": out, "format": "avif", "resize_mode": "fixed", "width": 40, "maintain_ar": True, "lossless": True}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["lossless"])
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (40, 20))
+
+
+class JPEGXLConversionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def test_quality_maps_to_jxl_distance(self):
+        self.assertEqual(converter.jxl_distance_from_quality(100), 0.0)
+        self.assertEqual(converter.jxl_distance_from_quality(90), 1.0)
+        self.assertGreater(converter.jxl_distance_from_quality(20), converter.jxl_distance_from_quality(60))
+
+    def test_quality_100_requests_lossless_jxl(self):
+        params = converter.ImageConverter()._get_save_params("jxl", 100)
+
+        self.assertTrue(params["lossless"])
+        self.assertNotIn("optimize", params)
+
+    def test_missing_jxl_plugin_returns_structured_error(self):
+        src = self._path("in.png")
+        Image.new("RGB", (8, 8)).save(src)
+        original = converter.jxl_plugin_available
+        try:
+            converter.jxl_plugin_available = lambda: False
+            result = converter.ImageConverter().convert(src, self._path("out.jxl"), "jxl")
+        finally:
+            converter.jxl_plugin_available = original
+
+        self.assertTrue(result["error"].startswith("[UNSUPPORTED_FORMAT]"))
+        self.assertEqual(result["missing_plugin"], "pillow-jxl-plugin")
+
+
+class BackgroundColorTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def _transparent_png(self):
+        src = self._path("in.png")
+        Image.new("RGBA", (16, 16), (0, 0, 0, 0)).save(src)
+        return src
+
+    def test_parse_hex_color(self):
+        self.assertEqual(converter.parse_hex_color("#FF8000"), (255, 128, 0))
+        self.assertEqual(converter.parse_hex_color("0f0"), (0, 255, 0))
+        self.assertIsNone(converter.parse_hex_color("#12345"))
+        self.assertIsNone(converter.parse_hex_color("red"))
+
+    def test_transparency_defaults_to_white_for_jpeg(self):
+        out = self._path("out.jpg")
+        result = convert_process({"input_path": self._transparent_png(), "output_path": out, "format": "jpg"})
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertTrue(all(channel > 245 for channel in img.getpixel((8, 8))))
+
+    def test_background_color_fills_transparency(self):
+        out = self._path("out.jpg")
+        result = convert_process(
+            {"input_path": self._transparent_png(), "output_path": out, "format": "jpg", "background_color": "#FF0000"}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            red, green, blue = img.getpixel((8, 8))
+        self.assertGreater(red, 240)
+        self.assertLess(max(green, blue), 16)
+
+    def test_background_color_is_ignored_for_alpha_formats(self):
+        out = self._path("out.png")
+        result = convert_process(
+            {"input_path": self._transparent_png(), "output_path": out, "format": "png", "background_color": "#FF0000"}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertEqual(img.mode, "RGBA")
+            self.assertEqual(img.getpixel((8, 8))[3], 0)
+
+    def test_invalid_background_color_is_rejected(self):
+        result = convert_process(
+            {"input_path": self._transparent_png(), "output_path": self._path("out.jpg"), "format": "jpg", "background_color": "#nothex"}
+        )
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[BAD_INPUT]"))
+
+
+class CoverResizeTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def _split_source(self):
+        # Left half red, right half blue, so the anchor decides which color survives.
+        src = self._path("wide.png")
+        img = Image.new("RGB", (200, 100), (255, 0, 0))
+        img.paste((0, 0, 255), (100, 0, 200, 100))
+        img.save(src)
+        return src
+
+    def _cover(self, anchor=None, **extra):
+        out = self._path(f"cover-{anchor or 'default'}.png")
+        payload = {
+            "input_path": self._split_source(),
+            "output_path": out,
+            "format": "png",
+            "resize_mode": "cover",
+            "width": 50,
+            "height": 50,
+            "maintain_ar": False,
+            **extra,
+        }
+        if anchor:
+            payload["crop_anchor"] = anchor
+        return convert_process(payload), out
+
+    def test_cover_crops_to_exact_size_around_center(self):
+        result, out = self._cover()
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (50, 50))
+            self.assertEqual(img.getpixel((2, 25))[:3], (255, 0, 0))
+            self.assertEqual(img.getpixel((47, 25))[:3], (0, 0, 255))
+
+    def test_cover_honors_crop_anchor(self):
+        left_result, left_out = self._cover("left")
+        right_result, right_out = self._cover("right")
+
+        self.assertTrue(left_result.get("success"), left_result)
+        self.assertTrue(right_result.get("success"), right_result)
+        with Image.open(left_out) as left, Image.open(right_out) as right:
+            self.assertEqual(left.getpixel((47, 25))[:3], (255, 0, 0))
+            self.assertEqual(right.getpixel((2, 25))[:3], (0, 0, 255))
+
+    def test_invalid_crop_anchor_is_rejected(self):
+        result, _out = self._cover("middle")
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[BAD_INPUT]"))
+
+    def test_svg_cover_matches_raster_dimensions(self):
+        svg_path = os.path.abspath(
+            os.path.join(os.path.dirname(__file__), "..", "..", "testdata", "simple.svg")
+        )
+        out = self._path("svg-cover.png")
+        result = convert_process(
+            {
+                "input_path": svg_path,
+                "output_path": out,
+                "format": "png",
+                "resize_mode": "cover",
+                "width": 90,
+                "height": 40,
+            }
+        )
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (90, 40))
+
+    def test_svg_render_size_covers_target(self):
+        svg_path = self._path("wide.svg")
+        with open(svg_path, "w", encoding="utf-8") as handle:
+            handle.write('<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100"></svg>')
+
+        size = converter.ImageConverter()._calculate_svg_render_size(
+            svg_path, "cover", 0, 0, 50, 50, False, "png", None
+        )
+
+        self.assertEqual(size, (100, 50))
+
+    def _pad(self, fmt="png", **extra):
+        out = self._path(f"pad-{len(extra)}.{fmt}")
+        payload = {
+            "input_path": self._split_source(),
+            "output_path": out,
+            "format": fmt,
+            "resize_mode": "pad",
+            "width": 50,
+            "height": 50,
+            **extra,
+        }
+        return convert_process(payload), out
+
+    def test_pad_letterboxes_to_exact_size_with_transparent_default(self):
+        result, out = self._pad()
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (50, 50))
+            self.assertEqual(img.mode, "RGBA")
+            self.assertEqual(img.getpixel((25, 2))[3], 0)
+            self.assertEqual(img.getpixel((2, 25)), (255, 0, 0, 255))
+            self.assertEqual(img.getpixel((47, 25)), (0, 0, 255, 255))
+
+    def test_pad_uses_white_without_alpha_and_honors_pad_color(self):
+        jpeg, jpeg_out = self._pad("jpg")
+        colored, colored_out = self._pad("png", pad_color="#00ff00", crop_anchor="top")
+
+        self.assertTrue(jpeg.get("success"), jpeg)
+        self.assertTrue(colored.get("success"), colored)
+        with Image.open(jpeg_out) as img:
+            self.assertEqual(img.size, (50, 50))
+            self.assertTrue(all(channel > 245 for channel in img.getpixel((25, 2))))
+        with Image.open(colored_out) as img:
+            self.assertEqual(img.convert("RGB").getpixel((25, 47)), (0, 255, 0))
+            self.assertEqual(img.convert("RGB").getpixel((2, 2)), (255, 0, 0))
+
+    def test_invalid_pad_color_is_rejected(self):
+        result, _out = self._pad(pad_color="green")
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid pad_color"))
+
+    def test_svg_pad_rasterizes_contained_size_then_pads(self):
+        svg_path = self._path("wide.svg")
+        with open(svg_path, "w", encoding="utf-8") as handle:
+            handle.write('<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100"></svg>')
+
+        size = converter.ImageConverter()._calculate_svg_render_size(
+            svg_path, "pad", 0, 0, 50, 50, False, "png", None
+        )
+
+        self.assertEqual(size, (50, 25))
+
+
+class JPEGEncodingTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.src = os.path.join(self.temp_dir.name, "src.png")
+        Image.new("RGB", (64, 48), (10, 120, 200)).save(self.src)
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _convert(self, output_name, fmt, **extra):
+        out = os.path.join(self.temp_dir.name, output_name)
+        return convert_process({"input_path": self.src, "output_path": out, "format": fmt, **extra}), out
+
+    def test_progressive_flag_is_reported_from_the_written_file(self):
+        progressive, prog_out = self._convert("prog.jpg", "jpg", progressive=True)
+        baseline, base_out = self._convert("base.jpg", "jpg", progressive=False)
+
+        self.assertTrue(progressive.get("success"), progressive)
+        self.assertTrue(progressive["progressive"])
+        self.assertFalse(baseline["progressive"])
+        with Image.open(prog_out) as prog, Image.open(base_out) as base:
+            self.assertTrue(prog.info.get("progressive"))
+            self.assertFalse(base.info.get("progressive"))
+
+    def test_subsampling_maps_to_jpeg_chroma_sampling(self):
+        from PIL import JpegImagePlugin
+
+        for value, expected in (("4:4:4", 0), ("4:2:2", 1), ("4:2:0", 2)):
+            result, out = self._convert(f"sub-{expected}.jpg", "jpg", subsampling=value)
+            self.assertTrue(result.get("success"), result)
+            with Image.open(out) as img:
+                self.assertEqual(JpegImagePlugin.get_sampling(img), expected, value)
+
+    def test_subsampling_is_ignored_for_non_jpeg_and_validated(self):
+        ignored, _out = self._convert("sub.png", "png", subsampling="4:4:4")
+        rejected, _out = self._convert("bad.jpg", "jpg", subsampling="4:1:1")
+
+        self.assertTrue(ignored.get("success"), ignored)
+        self.assertTrue(rejected["error"].startswith("[BAD_INPUT]"))
+
+    def test_tiff_compression_defaults_to_lzw_and_reports_file_size(self):
+        from PIL import TiffImagePlugin
+
+        sizes = {}
+        for value, expected in (("", "tiff_lzw"), ("none", "raw"), ("deflate", "tiff_adobe_deflate"), ("packbits", "packbits")):
+            result, out = self._convert(f"scan-{value or 'default'}.tiff", "tiff", tiff_compression=value)
+            self.assertTrue(result.get("success"), result)
+            self.assertEqual(result["tiff_compression"], value or "lzw")
+            self.assertEqual(result["file_size"], os.path.getsize(out))
+            sizes[value or "lzw"] = result["file_size"]
+            with Image.open(out) as img:
+                self.assertIsInstance(img, TiffImagePlugin.TiffImageFile)
+                self.assertEqual(img.info.get("compression"), expected, value)
+        self.assertLess(sizes["lzw"], sizes["none"])
+
+    def test_tiff_compression_is_ignored_for_non_tiff_and_validated(self):
+        ignored, _out = self._convert("plain.png", "png", tiff_compression="deflate")
+        rejected, _out = self._convert("bad.tiff", "tiff", tiff_compression="jpeg")
+
+        self.assertTrue(ignored.get("success"), ignored)
+        self.assertNotIn("tiff_compression", ignored)
+        self.assertTrue(rejected["error"].startswith("[BAD_INPUT]"))
+
+    def test_progressive_is_ignored_for_non_jpeg_targets(self):
+        result, _out = self._convert("out.png", "png", progressive=True)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertNotIn("progressive", result)
+
+
+class ColorProfileTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    def _srgb_icc(self):
+        from PIL import ImageCms
+
+        path = self._path("srgb.icc")
+        with open(path, "wb") as handle:
+            handle.write(ImageCms.ImageCmsProfile(ImageCms.createProfile("sRGB")).tobytes())
+        return path
+
+    def test_assign_embeds_profile_without_touching_pixels(self):
+        src = self._path("in.png")
+        Image.new("RGB", (4, 4), (200, 30, 60)).save(src)
+        out = self._path("out.png")
+
+        result = convert_process({"action": "color_profile", "input_path": src, "output_path": out, "profile": "sRGB"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual((result["mode"], result["converted"]), ("assign", False))
+        with Image.open(out) as img:
+            self.assertTrue(img.info.get("icc_profile"))
+            self.assertEqual(img.getpixel((0, 0)), (200, 30, 60))
+
+    def test_convert_reports_transform_with_icc_file(self):
+        src = self._path("in.png")
+        Image.new("RGB", (4, 4), (10, 20, 30)).save(src)
+        out = self._path("out.jpg")
+
+        result = convert_process(
+            {"action": "color_profile", "input_path": src, "output_path": out, "profile": self._srgb_icc(), "mode": "convert"}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["converted"])
+        self.assertFalse(result["had_embedded_profile"])
+
+    def test_invalid_icc_file_is_rejected(self):
+        src = self._path("in.png")
+        Image.new("RGB", (4, 4)).save(src)
+        bogus = self._path("bogus.icc")
+        with open(bogus, "wb") as handle:
+            handle.write(b"not a profile")
+
+        result = convert_process({"action": "color_profile", "input_path": src, "output_path": self._path("o.png"), "profile": bogus})
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[BAD_INPUT]"))
+
+    def _tagged_source(self):
+        from PIL import ImageCms
+
+        src = self._path("tagged.png")
+        icc = ImageCms.ImageCmsProfile(ImageCms.createProfile("sRGB")).tobytes()
+        Image.new("RGB", (8, 8), (200, 30, 60)).save(src, icc_profile=icc)
+        return src
+
+    def test_embedded_profile_is_carried_into_icc_capable_targets(self):
+        src = self._tagged_source()
+        for fmt in ("jpg", "png", "webp", "tiff"):
+            out = self._path(f"kept.{fmt}")
+            result = convert_process({"input_path": src, "output_path": out, "format": fmt})
+
+            self.assertTrue(result.get("success"), result)
+            self.assertTrue(result["kept_color_profile"], fmt)
+            self.assertFalse(result["converted_to_srgb"], fmt)
+            with Image.open(out) as img:
+                self.assertTrue(img.info.get("icc_profile"), fmt)
+
+    def test_profile_is_dropped_and_pixels_converted_when_not_kept(self):
+        src = self._tagged_source()
+        for fmt, extra in (("png", {"keep_color_profile": False}), ("bmp", {})):
+            out = self._path(f"srgb.{fmt}")
+            result = convert_process({"input_path": src, "output_path": out, "format": fmt, **extra})
+
+            self.assertTrue(result.get("success"), result)
+            self.assertFalse(result["kept_color_profile"], fmt)
+            self.assertTrue(result["converted_to_srgb"], fmt)
+            with Image.open(out) as img:
+                self.assertFalse(img.info.get("icc_profile"), fmt)
+
+    def test_untagged_source_reports_no_profile_work(self):
+        src = self._path("plain.png")
+        Image.new("RGB", (4, 4), (1, 2, 3)).save(src)
+
+        result = convert_process({"input_path": src, "output_path": self._path("plain.webp"), "format": "webp"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result["kept_color_profile"])
+        self.assertFalse(result["converted_to_srgb"])
+
+
+class AutoOrientTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.src = os.path.join(self.temp_dir.name, "phone.jpg")
+        # Stored landscape, displayed portrait: Orientation 6 means rotate 90 degrees clockwise.
+        exif = Image.Exif()
+        exif[0x0112] = 6
+        Image.new("RGB", (40, 20), (200, 40, 40)).save(self.src, exif=exif.tobytes())
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _convert(self, name, **extra):
+        out = os.path.join(self.temp_dir.name, name)
+        payload = {"input_path": self.src, "output_path": out, "format": "jpg", "keep_metadata": True, **extra}
+        return convert_process(payload), out
+
+    def test_orientation_is_applied_before_resize_and_tag_reset(self):
+        result, out = self._convert("oriented.jpg", resize_mode="long_edge", long_edge=20)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["auto_oriented"])
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (10, 20))
+            self.assertEqual(img.getexif().get(0x0112), 1)
+
+    def test_disabled_keeps_raw_pixels_and_tag(self):
+        result, out = self._convert("raw.jpg", auto_orient=False)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result["auto_oriented"])
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (40, 20))
+            self.assertEqual(img.getexif().get(0x0112), 6)
+
+    def test_untagged_image_is_left_alone(self):
+        plain = os.path.join(self.temp_dir.name, "plain.png")
+        Image.new("RGB", (40, 20)).save(plain)
+        out = os.path.join(self.temp_dir.name, "plain.jpg")
+
+        result = convert_process({"input_path": plain, "output_path": out, "format": "jpg"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result["auto_oriented"])
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (40, 20))
+
+
+class TrimBordersTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _source(self, name, img):
+        path = os.path.join(self.temp_dir.name, name)
+        img.save(path)
+        return path
+
+    def _convert(self, src, name, **extra):
+        out = os.path.join(self.temp_dir.name, name)
+        return convert_process({"input_path": src, "output_path": out, "format": "png", "trim_borders": True, **extra}), out
+
+    def test_uniform_border_is_cropped_before_resize(self):
+        img = Image.new("RGB", (60, 40), (255, 255, 255))
+        img.paste((20, 80, 160), (10, 5, 50, 25))
+        src = self._source("padded.png", img)
+
+        result, out = self._convert(src, "trimmed.png", resize_mode="long_edge", long_edge=20)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result["trimmed"])
+        self.assertEqual((result["trimmed_width"], result["trimmed_height"]), (40, 20))
+        with Image.open(out) as converted:
+            self.assertEqual(converted.size, (20, 10))
+            self.assertEqual(converted.convert("RGB").getpixel((0, 0)), (20, 80, 160))
+
+    def test_transparent_padding_and_tolerance(self):
+        img = Image.new("RGBA", (30, 30), (0, 0, 0, 0))
+        img.paste((200, 0, 0, 255), (5, 5, 25, 25))
+        transparent = self._source("sticker.png", img)
+        noisy = Image.new("RGB", (30, 30), (250, 250, 250))
+        noisy.paste((246, 246, 246), (0, 0, 30, 2))
+        noisy.paste((0, 0, 0), (10, 10, 20, 20))
+        noisy_src = self._source("noisy.png", noisy)
+
+        cut, _out = self._convert(transparent, "sticker-out.png")
+        strict, _out = self._convert(noisy_src, "strict.png")
+        loose, _out = self._convert(noisy_src, "loose.png", trim_tolerance=8)
+
+        self.assertEqual((cut["trimmed_width"], cut["trimmed_height"]), (20, 20))
+        self.assertEqual((strict["trimmed_width"], strict["trimmed_height"]), (30, 28))
+        self.assertEqual((loose["trimmed_width"], loose["trimmed_height"]), (10, 10))
+
+    def test_no_border_is_a_no_op_and_tolerance_is_validated(self):
+        img = Image.new("RGB", (16, 16), (255, 255, 255))
+        img.putpixel((15, 15), (0, 0, 0))
+        img.putpixel((0, 15), (0, 0, 0))
+        img.putpixel((15, 0), (0, 0, 0))
+        src = self._source("edge.png", img)
+
+        result, out = self._convert(src, "edge-out.png")
+        rejected, _out = self._convert(src, "bad.png", trim_tolerance=256)
+        plain = convert_process({"input_path": src, "output_path": out + ".png", "format": "png"})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result["trimmed"])
+        self.assertEqual((result["trimmed_width"], result["trimmed_height"]), (16, 16))
+        self.assertTrue(rejected["error"].startswith("[BAD_INPUT] Invalid trim_tolerance"))
+        self.assertNotIn("trimmed", plain)
+
 
 if __name__ == "__main__":
     unittest.main()
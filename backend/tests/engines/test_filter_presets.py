@@ -59,6 +59,33 @@ class FilterPresetTests(unittest.TestCase):
         self.assertTrue(result.get("success"))
         self._assert_same(src, out)
 
+    def test_colorblind_protanopia_collapses_red_green(self):
+        src = self._path("cb_base.png")
+        Image.new("RGB", (8, 8), (255, 0, 0)).save(src, format="PNG")
+        out = self._path("cb_out.png")
+        result = ImageFilterApplier().apply(
+            input_path=src,
+            output_path=out,
+            filter_name="colorblind",
+            colorblind_type="protanopia",
+        )
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            r, g, _b = img.convert("RGB").getpixel((4, 4))
+        self.assertLess(abs(r - g), 40)
+
+    def test_colorblind_rejects_unknown_type(self):
+        src = self._make_base("cb_bad.png")
+        result = filter_engine.process({
+            "input_path": src,
+            "output_path": self._path("cb_bad_out.png"),
+            "filter": "colorblind",
+            "colorblind_type": "infrared",
+        })
+        self.assertFalse(result.get("success"))
+        self.assertIn("[BAD_INPUT]", result.get("error", ""))
+        self.assertFalse(os.path.exists(self._path("cb_bad_out.png")))
+
     def test_polaroid_intensity_zero_no_change(self):
         src = self._make_base("polaroid_base.png")
         out = self._path("polaroid_out.png")
@@ -133,6 +160,46 @@ class FilterPresetTests(unittest.TestCase):
         with Image.open(out) as img:
             self.assertEqual(img.format, "PNG")
 
+    def test_adjuster_auto_level_straightens_from_xmp_roll(self):
+        src = self._path("tilted.jpg")
+        xmp = b'<x:xmpmeta xmlns:x="adobe:ns:meta/"><drone-dji:GimbalRollDegree>4.0</drone-dji:GimbalRollDegree></x:xmpmeta>'
+        Image.new("RGB", (120, 80), (64, 128, 192)).save(src, format="JPEG", xmp=xmp)
+        out = self._path("leveled.jpg")
+
+        result = ImageAdjuster().adjust(input_path=src, output_path=out, auto_level=True)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result.get("auto_level_applied"))
+        self.assertAlmostEqual(result.get("level_roll"), 4.0)
+        with Image.open(out) as img:
+            self.assertLess(img.size[0], 120)
+            self.assertAlmostEqual(img.size[0] / img.size[1], 1.5, delta=0.05)
+
+    def test_adjuster_auto_level_ignores_rolls_beyond_the_straighten_range(self):
+        src = self._path("sideways.jpg")
+        xmp = b'<x:xmpmeta xmlns:x="adobe:ns:meta/"><drone-dji:GimbalRollDegree>120.0</drone-dji:GimbalRollDegree></x:xmpmeta>'
+        Image.new("RGB", (120, 80), (64, 128, 192)).save(src, format="JPEG", xmp=xmp)
+        out = self._path("sideways_out.jpg")
+
+        result = ImageAdjuster().adjust(input_path=src, output_path=out, auto_level=True)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result.get("auto_level_applied"))
+        self.assertTrue(result.get("level_data_missing"))
+        with Image.open(out) as img:
+            self.assertEqual(img.size, (120, 80))
+
+    def test_adjuster_auto_level_without_level_data_is_noop(self):
+        src = self._make_base("flat.png")
+        out = self._path("flat_out.png")
+
+        result = ImageAdjuster().adjust(input_path=src, output_path=out, auto_level=True)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertFalse(result.get("auto_level_applied"))
+        self.assertTrue(result.get("level_data_missing"))
+        self._assert_same(src, out)
+
     def test_adjuster_accepts_svg_input(self):
         out = self._path("adjust_svg.png")
         adjuster = ImageAdjuster()
@@ -222,6 +222,25 @@ class GifSplitterTests(unittest.TestCase):
         self.assertTrue(result.get("success"), result)
         self.assertEqual(result.get("frame_count"), 1)
 
+    def test_inspect_reports_frame_timing_and_sizes_without_decoding(self):
+        gif_path = self._make_gif()
+
+        result = handle_request({"action": "inspect", "input_path": gif_path})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual((result["width"], result["height"], result["frame_count"]), (12, 12, 2))
+        self.assertEqual((result["loop"], result["total_duration_ms"]), (0, 200))
+        self.assertEqual([frame["delay_ms"] for frame in result["frames"]], [100, 100])
+        self.assertEqual([frame["cumulative_ms"] for frame in result["frames"]], [100, 200])
+        self.assertTrue(all(frame["bytes"] > 0 for frame in result["frames"]))
+        self.assertTrue(result["palette_size"] or all(frame.get("palette_size") for frame in result["frames"]))
+        self.assertEqual(result["file_size"], os.path.getsize(gif_path))
+        self.assertIn(result["largest_frame"], (0, 1))
+
+        png_path = self._path("still.png")
+        Image.new("RGB", (4, 4)).save(png_path)
+        self.assertEqual(handle_request({"action": "inspect", "input_path": png_path})["error_code"], "GIF_UNSUPPORTED_IMAGE")
+
     def test_compress_gif_success(self):
         gif_path = self._make_gif()
         output_path = self._path("sample_compress.gif")
@@ -285,6 +304,108 @@ class GifSplitterTests(unittest.TestCase):
                 rgba = frame.convert("RGBA")
                 self.assertEqual(rgba.getpixel((0, 0))[3], 0)
 
+    def test_compress_gif_max_colors_limits_the_palette(self):
+        gif_path = self._path("gradient.gif")
+        frames = []
+        for shift in (0, 64):
+            frame = Image.new("RGB", (32, 32))
+            frame.putdata([((x * 8 + shift) % 256, y * 8, 128) for y in range(32) for x in range(32)])
+            frames.append(frame)
+        frames[0].save(gif_path, format="GIF", save_all=True, append_images=frames[1:], duration=[100, 100], loop=0)
+        output_path = self._path("gradient_4.gif")
+        result = handle_request(
+            {
+                "action": "compress",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "max_colors": 4,
+                "dither": False,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("max_colors"), result.get("dither")), (4, False))
+        self.assertLessEqual(result.get("color_count"), 8)
+        self.assertGreater(result.get("color_count"), 1)
+        self.assertEqual(result.get("output_size"), os.path.getsize(output_path))
+        with Image.open(output_path) as img:
+            self.assertEqual(getattr(img, "n_frames", 1), 2)
+
+    def test_boomerang_plays_forward_then_back_without_repeating_the_ends(self):
+        gif_path = self._make_transparent_gif()
+        output_path = self._path("boomerang.gif")
+        result = handle_request(
+            {
+                "action": "boomerang",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "loop": 2,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("frame_count"), result.get("source_frame_count")), (6, 4))
+        with Image.open(output_path) as img:
+            self.assertEqual(img.info.get("loop"), 2)
+            left_edges = []
+            for frame in ImageSequence.Iterator(img):
+                rgba = frame.convert("RGBA")
+                left_edges.append(next(x for x in range(24) if rgba.getpixel((x, 10))[3] == 255))
+            self.assertEqual(left_edges, [2, 6, 10, 14, 10, 6])
+
+    def test_export_frame_at_timestamp_picks_the_frame_on_screen(self):
+        gif_path = self._make_transparent_gif()
+        output_path = self._path("poster.png")
+        result = handle_request(
+            {
+                "action": "export_frames",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "timestamp_ms": 200,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("frame_index"), result.get("frame_start_ms")), (2, 180))
+        self.assertEqual(result.get("total_duration_ms"), 360)
+        with Image.open(output_path) as img:
+            self.assertEqual(img.format, "PNG")
+            self.assertEqual(img.convert("RGBA").getpixel((10, 10))[3], 255)
+            self.assertEqual(img.convert("RGBA").getpixel((9, 10))[3], 0)
+
+        late = handle_request(
+            {
+                "action": "export_frames",
+                "input_path": gif_path,
+                "output_path": self._path("late.png"),
+                "timestamp_ms": 360,
+            }
+        )
+        self.assertFalse(late.get("success"))
+        self.assertEqual(late.get("error_code"), "GIF_TIMESTAMP_OUT_OF_RANGE")
+
+    def test_contact_sheet_samples_frames_into_a_labelled_grid(self):
+        gif_path = self._make_transparent_gif()
+        output_path = self._path("sheet.png")
+        result = handle_request(
+            {
+                "action": "contact_sheet",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "grid_columns": 2,
+                "sheet_max_frames": 3,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual(result.get("frame_indices"), [0, 2, 3])
+        self.assertEqual((result.get("sheet_frame_count"), result.get("grid_columns"), result.get("grid_rows")), (3, 2, 2))
+        gutter, label = gif_splitter.SHEET_GUTTER, gif_splitter.SHEET_LABEL_HEIGHT
+        expected = (2 * 24 + 3 * gutter, 2 * (24 + label) + 3 * gutter)
+        self.assertEqual((result.get("width"), result.get("height")), expected)
+        with Image.open(output_path) as img:
+            self.assertEqual((img.format, img.size), ("PNG", expected))
+            # Second tile is frame 2, whose red block starts ten pixels in.
+            left = 2 * gutter + 24
+            self.assertEqual(img.convert("RGB").getpixel((left + 10, gutter + 10)), (255, 0, 0))
+            self.assertEqual(img.convert("RGB").getpixel((left + 9, gutter + 10)), gif_splitter.SHEET_BACKGROUND)
+
     def test_reverse_gif_success(self):
         gif_path = self._make_gif()
         output_path = self._path("sample_reverse.gif")
@@ -426,6 +547,23 @@ class GifSplitterTests(unittest.TestCase):
         with Image.open(output_path) as img:
             self.assertEqual(img.size, (10, 5))
 
+    def test_resize_gif_accepts_the_converter_maintain_ar_name(self):
+        gif_path = self._make_rect_gif((20, 10))
+        output_path = self._path("sample_resize_ar.gif")
+        result = handle_request(
+            {
+                "action": "resize",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "width": 8,
+                "height": 8,
+                "maintain_ar": False,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("width"), result.get("height")), (8, 8))
+        self.assertFalse(result.get("maintain_aspect"))
+
     def test_resize_gif_keep_aspect_in_box(self):
         gif_path = self._make_rect_gif((20, 10))
         output_path = self._path("sample_resize_box.gif")
@@ -491,6 +629,49 @@ class GifSplitterTests(unittest.TestCase):
         self.assertFalse(result.get("success"))
         self.assertEqual(result.get("error_code"), "GIF_UNSUPPORTED_ACTION")
 
+    def test_crop_applies_one_rectangle_to_every_frame_and_keeps_timing(self):
+        gif_path = self._make_transparent_gif()
+        output_path = self._path("cropped.gif")
+        result = handle_request(
+            {
+                "action": "crop",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "crop_x": 2,
+                "crop_y": 8,
+                "crop_width": 20,
+                "crop_height": 8,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("width"), result.get("height")), (20, 8))
+        self.assertEqual((result.get("original_width"), result.get("original_height")), (24, 24))
+        self.assertEqual(result.get("frame_count"), 4)
+        with Image.open(output_path) as img:
+            self.assertEqual(img.size, (20, 8))
+            self.assertEqual(getattr(img, "n_frames", 1), 4)
+            self.assertEqual(img.info.get("loop"), 0)
+            durations = [frame.info.get("duration", 0) for frame in ImageSequence.Iterator(img)]
+            self._assert_durations_close([90, 90, 90, 90], durations)
+            img.seek(0)
+            self.assertEqual(img.convert("RGBA").getpixel((0, 0))[:3], (255, 0, 0))
+
+    def test_crop_outside_the_canvas_returns_error_code(self):
+        gif_path = self._make_transparent_gif()
+        result = handle_request(
+            {
+                "action": "crop",
+                "input_path": gif_path,
+                "output_path": self._path("cropped_bad.gif"),
+                "crop_x": 10,
+                "crop_width": 20,
+                "crop_height": 4,
+            }
+        )
+        self.assertFalse(result.get("success"))
+        self.assertEqual(result.get("error_code"), "GIF_CROP_OUT_OF_BOUNDS")
+        self.assertFalse(os.path.exists(self._path("cropped_bad.gif")))
+
     def test_convert_gif_to_apng_preserves_timing_and_alpha(self):
         gif_path = self._make_transparent_gif()
         output_path = self._path("converted.apng")
@@ -512,6 +693,24 @@ class GifSplitterTests(unittest.TestCase):
             for frame in ImageSequence.Iterator(img):
                 self.assertEqual(frame.convert("RGBA").getpixel((0, 0))[3], 0)
 
+    def test_convert_animation_reports_frames_and_keeps_the_requested_loop(self):
+        gif_path = self._make_transparent_gif()
+        output_path = self._path("converted_loop.apng")
+        result = handle_request(
+            {
+                "action": "convert_animation",
+                "input_path": gif_path,
+                "output_path": output_path,
+                "output_format": "apng",
+                "loop": 3,
+            }
+        )
+        self.assertTrue(result.get("success"))
+        self.assertEqual((result.get("frame_count"), result.get("loop")), (4, 3))
+        self.assertEqual(result.get("source_format"), "GIF")
+        with Image.open(output_path) as img:
+            self.assertEqual(img.info.get("loop"), 3)
+
     def test_convert_apng_to_webp_preserves_timing_and_alpha(self):
         self._ensure_webp_anim_support()
         apng_path = self._make_apng()
@@ -7,7 +7,9 @@ import unittest
 import xml.etree.ElementTree as stdlib_et
 from io import BytesIO
 from pathlib import Path
+from unittest import mock
 
+import piexif
 from PIL import Image
 from PIL import PngImagePlugin
 
@@ -15,7 +17,7 @@ ENGINE_DIR = Path(__file__).resolve().parents[2] / "engines"
 if str(ENGINE_DIR) not in sys.path:
     sys.path.insert(0, str(ENGINE_DIR))
 
-from info_viewer import InfoViewer
+from info_viewer import InfoViewer, process
 
 
 class GuardedReadBytesIO(BytesIO):
@@ -124,6 +126,161 @@ class InfoViewerMetadataTests(unittest.TestCase):
         self.assertTrue(any(field.get("source") == "piexif" and field.get("value") == "UnitTestMake" for field in fields))
         self.assertIsInstance(info.get("warnings", []), list)
 
+    def test_jpeg_xmp_roll_is_exposed_as_level_data(self):
+        xmp = (
+            b'<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">'
+            b'<rdf:Description xmlns:GPano="http://ns.google.com/photos/1.0/panorama/" GPano:PoseRollDegrees="-2.5"/>'
+            b'</rdf:RDF></x:xmpmeta>'
+        )
+        path = self._path("level.jpg")
+        Image.new("RGB", (16, 16), (40, 40, 40)).save(path, format="JPEG", xmp=xmp)
+
+        info = InfoViewer().get_info(path)
+
+        self.assertTrue(info.get("success"))
+        self.assertTrue(info.get("has_level_data"))
+        self.assertAlmostEqual(info.get("level_roll"), -2.5)
+
+    def test_jpeg_iptc_and_xmp_are_decoded_into_their_own_groups(self):
+        def dataset(record, number, value):
+            return bytes([0x1C, record, number]) + struct.pack(">H", len(value)) + value
+
+        iim = (
+            dataset(1, 90, b"\x1b%G")
+            + dataset(2, 120, "Café at dawn".encode("utf-8"))
+            + dataset(2, 25, b"street")
+            + dataset(2, 25, b"morning")
+            + dataset(2, 80, b"A. Photographer")
+        )
+        resource = b"8BIM" + struct.pack(">H", 0x0404) + b"\x00\x00" + struct.pack(">I", len(iim)) + iim
+        app13 = b"Photoshop 3.0\x00" + resource
+        xmp = (
+            b'<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">'
+            b'<rdf:Description xmlns:dc="http://purl.org/dc/elements/1.1/">'
+            b"<dc:subject><rdf:Bag><rdf:li>street</rdf:li><rdf:li>cats &amp; dogs</rdf:li></rdf:Bag></dc:subject>"
+            b'<dc:rights><rdf:Alt><rdf:li xml:lang="x-default">(c) 2026 A. Photographer</rdf:li></rdf:Alt></dc:rights>'
+            b"</rdf:Description></rdf:RDF></x:xmpmeta>"
+        )
+        buffer = BytesIO()
+        Image.new("RGB", (16, 16), (40, 40, 40)).save(buffer, format="JPEG", xmp=xmp)
+        data = buffer.getvalue()
+        path = self._path("archival.jpg")
+        with open(path, "wb") as f:
+            f.write(data[:2] + b"\xff\xed" + struct.pack(">H", len(app13) + 2) + app13 + data[2:])
+
+        info = InfoViewer().get_info(path)
+
+        self.assertTrue(info.get("success"))
+        meta = info.get("metadata", {})
+        self.assertEqual(
+            meta.get("iptc"),
+            {"caption": "Café at dawn", "keywords": "street; morning", "byline": "A. Photographer"},
+        )
+        self.assertEqual(meta.get("xmp", {}).get("dc:subject"), "street; cats & dogs")
+        self.assertEqual(meta.get("xmp", {}).get("dc:rights"), "(c) 2026 A. Photographer")
+        fields = info.get("fields", [])
+        self.assertTrue(any(field.get("key") == "iptc.caption" and field.get("group") == "iptc" for field in fields))
+        self.assertNotIn("caption", info.get("exif", {}))
+
+    def test_jpeg_gps_is_converted_to_signed_decimal_degrees(self):
+        gps = {
+            piexif.GPSIFD.GPSLatitudeRef: b"S",
+            piexif.GPSIFD.GPSLatitude: ((33, 1), (51, 1), (3540, 100)),
+            piexif.GPSIFD.GPSLongitudeRef: b"E",
+            piexif.GPSIFD.GPSLongitude: ((151, 1), (12, 1), (3000, 100)),
+        }
+        path = self._path("gps.jpg")
+        exif = piexif.dump({"0th": {}, "Exif": {}, "GPS": gps, "1st": {}, "thumbnail": None})
+        Image.new("RGB", (16, 16), (40, 40, 40)).save(path, format="JPEG", exif=exif)
+
+        info = InfoViewer().get_info(path)
+
+        self.assertTrue(info.get("has_gps"))
+        self.assertAlmostEqual(info.get("latitude"), -(33 + 51 / 60 + 35.4 / 3600), places=6)
+        self.assertAlmostEqual(info.get("longitude"), 151 + 12 / 60 + 30 / 3600, places=6)
+
+    def test_missing_gps_reports_zero_coordinates(self):
+        path = self._path("no_gps.jpg")
+        Image.new("RGB", (16, 16), (40, 40, 40)).save(path, format="JPEG")
+
+        info = InfoViewer().get_info(path)
+
+        self.assertFalse(info.get("has_gps"))
+        self.assertEqual((info.get("latitude"), info.get("longitude")), (0.0, 0.0))
+
+    def test_perceptual_hash_is_only_computed_when_requested(self):
+        gradient = Image.new("L", (64, 48))
+        gradient.putdata([(x * 4 + y) % 256 for y in range(48) for x in range(64)])
+        original = self._path("original.png")
+        resized = self._path("resized.jpg")
+        gradient.save(original, format="PNG")
+        gradient.resize((128, 96)).convert("RGB").save(resized, format="JPEG", quality=70)
+
+        plain = process({"input_path": original})
+        hashed = process({"input_path": original, "include_hash": True})
+        copy = process({"input_path": resized, "include_hash": True})
+
+        self.assertEqual(plain.get("perceptual_hash"), "")
+        self.assertRegex(hashed.get("perceptual_hash"), r"^[0-9a-f]{16}$")
+        distance = bin(int(hashed["perceptual_hash"], 16) ^ int(copy["perceptual_hash"], 16)).count("1")
+        self.assertLessEqual(distance, 6)
+
+    def test_dominant_colors_are_ordered_by_prevalence_and_skip_transparency(self):
+        img = Image.new("RGBA", (40, 10), (255, 0, 0, 255))
+        img.paste((0, 0, 255, 255), (30, 0, 40, 10))
+        img.paste((0, 255, 0, 0), (0, 0, 12, 10))
+        path = self._path("palette.png")
+        img.save(path, format="PNG")
+
+        plain = process({"input_path": path})
+        result = process({"input_path": path, "palette_size": 4})
+
+        self.assertEqual(plain.get("dominant_colors"), [])
+        self.assertEqual(result.get("dominant_colors"), ["#ff0000", "#0000ff"])
+
+    def test_alpha_is_reported_separately_from_actual_transparency(self):
+        opaque = self._path("opaque.png")
+        Image.new("RGBA", (8, 8), (10, 20, 30, 255)).save(opaque, format="PNG")
+        holed = self._path("holed.png")
+        img = Image.new("RGBA", (8, 8), (10, 20, 30, 255))
+        img.putpixel((3, 3), (10, 20, 30, 200))
+        img.save(holed, format="PNG")
+        flat = self._path("flat.jpg")
+        Image.new("RGB", (8, 8), (10, 20, 30)).save(flat, format="JPEG")
+
+        results = [process({"input_path": path}) for path in (opaque, holed, flat)]
+
+        self.assertEqual(
+            [(result.get("has_alpha"), result.get("has_transparency")) for result in results],
+            [(True, False), (True, True), (False, False)],
+        )
+
+    def test_alpha_is_read_from_the_alpha_band_or_palette_without_an_rgba_copy(self):
+        gray_alpha = self._path("gray_alpha.png")
+        Image.new("LA", (8, 8), (90, 128)).save(gray_alpha, format="PNG")
+        unused_key = self._path("unused_key.png")
+        palette = Image.new("P", (8, 8), 1)
+        palette.putpalette([0, 0, 0, 255, 255, 255] + [0] * 762)
+        palette.save(unused_key, format="PNG", transparency=0)
+        used_key = self._path("used_key.png")
+        palette.putpixel((2, 2), 0)
+        palette.save(used_key, format="PNG", transparency=0)
+
+        with mock.patch.object(Image.Image, "convert", side_effect=AssertionError("full RGBA copy")):
+            flags = [InfoViewer()._alpha_usage(path, []) for path in (gray_alpha, unused_key, used_key)]
+
+        self.assertEqual(flags, [(True, True), (True, False), (True, True)])
+
+    def test_missing_level_data_is_reported_as_absent(self):
+        path = self._path("plain.jpg")
+        Image.new("RGB", (16, 16), (40, 40, 40)).save(path, format="JPEG")
+
+        info = InfoViewer().get_info(path)
+
+        self.assertTrue(info.get("success"))
+        self.assertFalse(info.get("has_level_data"))
+        self.assertIsNone(info.get("level_roll"))
+
     def test_png_text_metadata(self):
         img = Image.new("RGB", (16, 16), (0, 255, 0))
         pnginfo = PngImagePlugin.PngInfo()
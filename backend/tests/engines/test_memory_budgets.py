@@ -7,6 +7,7 @@ import sys
 import tempfile
 import unittest
 from pathlib import Path
+from unittest import mock
 
 from PIL import Image
 
@@ -46,6 +47,63 @@ class GifMemoryBudgetTests(unittest.TestCase):
         )
         return path
 
+    def _write_many_frame_gif(self, name: str, frames: int, size=(1, 1)) -> str:
+        """Hand-assemble a GIF of `frames` 1x1 images on a `size` canvas (Pillow would be slow)."""
+        width, height = size
+        screen = width.to_bytes(2, "little") + height.to_bytes(2, "little") + b"\x80\x00\x00" + b"\x00" * 6
+        delay = b"\x21\xf9\x04\x00\x0a\x00\x00\x00"
+        image = b"\x2c\x00\x00\x00\x00\x01\x00\x01\x00\x00" + b"\x02\x02\x44\x01\x00"
+        path = self._path(name)
+        Path(path).write_bytes(b"GIF89a" + screen + (delay + image) * frames + b"\x3b")
+        return path
+
+    def test_many_frame_gif_is_refused_before_any_frame_is_decoded(self):
+        path = self._write_many_frame_gif("many.gif", gif_splitter.DEFAULT_MAX_FRAMES + 1)
+        out_dir = self._path("many-frames")
+
+        self.assertEqual(gif_splitter.scan_gif(path), (1, 1, gif_splitter.DEFAULT_MAX_FRAMES + 1))
+        with mock.patch.object(gif_splitter.Image, "open", side_effect=AssertionError("GIF was decoded")):
+            result = gif_splitter.handle_request({"action": "export_frames", "input_path": path, "output_dir": out_dir})
+            reversed_result = gif_splitter.handle_request(
+                {"action": "reverse", "input_path": path, "output_path": self._path("r.gif"), "max_frames": 100}
+            )
+
+        self.assertEqual(result.get("error_code"), "GIF_TOO_LARGE", result)
+        self.assertIn(str(gif_splitter.DEFAULT_MAX_FRAMES), result["error"])
+        self.assertEqual(reversed_result.get("error_code"), "GIF_TOO_LARGE")
+        self.assertFalse(os.path.exists(out_dir))
+
+    def test_huge_canvas_gif_trips_the_total_pixel_guard(self):
+        path = self._write_many_frame_gif("canvas.gif", 3, size=(20_000, 20_000))
+
+        result = gif_splitter.handle_request(
+            {"action": "compress", "input_path": path, "output_path": self._path("c.gif"), "max_total_megapixels": 1000}
+        )
+
+        self.assertEqual(result.get("error_code"), "GIF_TOO_LARGE")
+        self.assertIn("1000 megapixels", result["error"])
+
+    def test_export_frames_caps_the_number_of_files_written(self):
+        path = self._make_gif("export-cap.gif", frames=5)
+        out_dir = self._path("capped")
+
+        capped = gif_splitter.handle_request(
+            {"action": "export_frames", "input_path": path, "output_dir": out_dir, "max_export_files": 4}
+        )
+        allowed = gif_splitter.handle_request(
+            {"action": "export_frames", "input_path": path, "output_dir": out_dir, "frame_range": "1-4", "max_export_files": 4}
+        )
+
+        self.assertEqual(capped.get("error_code"), "GIF_TOO_LARGE")
+        self.assertTrue(allowed.get("success"), allowed)
+        self.assertEqual(allowed["export_count"], 4)
+        self.assertEqual(
+            gif_splitter.handle_request({"action": "reverse", "input_path": path, "output_path": "x.gif", "max_frames": 0})[
+                "error_code"
+            ],
+            "GIF_BAD_REQUEST",
+        )
+
     def test_extract_animation_frames_enforces_total_pixel_budget(self):
         path = self._make_gif("budget.gif", size=(32, 32), frames=3)
         original_total = gif_splitter.MAX_TOTAL_FRAME_PIXEL_BUDGET
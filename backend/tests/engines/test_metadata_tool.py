@@ -81,5 +81,60 @@ class MetadataToolStripTests(unittest.TestCase):
         self.assertEqual(result.get("error"), "[BAD_INPUT] missing output_path")
 
 
+
+class MetadataToolOrientationTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _path(self, name):
+        return os.path.join(self.temp_dir.name, name)
+
+    @staticmethod
+    def _scan_data(path):
+        data = Path(path).read_bytes()
+        return data[data.index(b"\xff\xda"):]
+
+    def test_orientation_tag_is_rewritten_without_touching_pixels(self):
+        src = self._path("photo.jpg")
+        exif = Image.Exif()
+        exif[0x010F] = "KeepMake"
+        Image.new("RGB", (24, 16), (200, 30, 30)).save(src, format="JPEG", quality=90, exif=exif)
+
+        result = process({"action": "set_orientation", "input_path": src, "orientation": 6, "overwrite": True})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual((result["orientation"], result["previous_orientation"]), (6, 1))
+        with Image.open(src) as img:
+            self.assertEqual(img.getexif().get(0x0112), 6)
+            self.assertEqual(img.getexif().get(0x010F), "KeepMake")
+            self.assertEqual(img.size, (24, 16))
+
+    def test_copy_keeps_the_source_and_the_compressed_scan(self):
+        src = self._path("source.jpg")
+        out = self._path("rotated.jpg")
+        Image.new("RGB", (24, 16), (20, 90, 200)).save(src, format="JPEG", quality=90)
+
+        result = process({"action": "set_orientation", "input_path": src, "output_path": out, "orientation": 3})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(self._scan_data(out), self._scan_data(src))
+        with Image.open(src) as original:
+            self.assertIsNone(original.getexif().get(0x0112))
+
+    def test_invalid_orientation_and_non_jpeg_are_rejected(self):
+        src = self._path("plain.png")
+        Image.new("RGB", (4, 4)).save(src, format="PNG")
+
+        self.assertTrue(
+            process({"action": "set_orientation", "input_path": src, "overwrite": True, "orientation": 9})["error"].startswith("[BAD_INPUT]")
+        )
+        self.assertTrue(
+            process({"action": "set_orientation", "input_path": src, "overwrite": True, "orientation": 2})["error"].startswith("[UNSUPPORTED_FORMAT]")
+        )
+
+
 if __name__ == "__main__":
     unittest.main()
@@ -1,8 +1,10 @@
+import importlib.util
 import os
 import sys
 import tempfile
 import unittest
 from pathlib import Path
+from unittest import mock
 
 from PIL import Image
 
@@ -10,7 +12,16 @@ ENGINE_DIR = Path(__file__).resolve().parents[2] / "engines"
 if str(ENGINE_DIR) not in sys.path:
     sys.path.insert(0, str(ENGINE_DIR))
 
-from pdf_generator import PDFGenerator, process as pdf_process
+from pdf_generator import (
+    PDFGenerator,
+    _bookmark_labels,
+    _dpi_pixel_size,
+    _fill_page_placeholders,
+    _grid_shape,
+    _join_keywords,
+    _page_font,
+    process as pdf_process,
+)
 
 
 class PDFGenerationTests(unittest.TestCase):
@@ -169,5 +180,198 @@ class PDFGenerationTests(unittest.TestCase):
         self.assertTrue(all(not os.path.exists(path) for path in created_paths))
 
 
+    def test_images_per_page_tiles_images_and_reduces_the_page_count(self):
+        images = []
+        for index in range(7):
+            image_path = self._path(f"tile{index}.png")
+            Image.new("RGB", (40, 30), (index * 30, 64, 128)).save(image_path)
+            images.append(image_path)
+
+        grid = pdf_process(
+            {"image_paths": images, "output_path": self._path("sheet.pdf"), "images_per_page": 4, "margin": 12}
+        )
+        single = pdf_process({"image_paths": images, "output_path": self._path("single.pdf"), "images_per_page": 1})
+
+        self.assertTrue(grid.get("success"), grid)
+        self.assertEqual(grid["page_count"], 2)
+        self.assertEqual(single["page_count"], 7)
+
+    def test_fit_mode_aliases_and_string_compression_level_are_honoured(self):
+        image_path = self._path("wide.png")
+        Image.new("RGB", (80, 20), (0, 128, 255)).save(image_path)
+
+        generator = PDFGenerator()
+        generator._validate_images([image_path])
+        flowable = generator._create_image_flowable(image_path, available_size=(100, 100), scale=1.0, fit_mode="fill")
+        self.assertEqual((flowable.drawWidth, flowable.drawHeight), (100, 100))
+        generator._cleanup_temp_images()
+
+        result = pdf_process(
+            {
+                "image_paths": [image_path],
+                "output_path": self._path("compressed.pdf"),
+                "fit_mode": "crop",
+                "compression_level": "2",
+            }
+        )
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual((result["fit_mode"], result["compression_level"]), ("cover", 2))
+
+    @unittest.skipUnless(importlib.util.find_spec("pyaes"), "AES-256 encryption needs pyaes")
+    def test_passwords_encrypt_the_pdf_with_aes_256(self):
+        image_path = self._path("secret.png")
+        Image.new("RGB", (40, 30), (200, 10, 10)).save(image_path)
+        base = {"image_paths": [image_path], "output_path": self._path("secret.pdf")}
+
+        plain = pdf_process(base)
+        self.assertNotIn("encryption", plain)
+        result = pdf_process({**base, "user_password": "open", "owner_password": "admin", "permissions": ["print"]})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(result["encryption"], "AES-256")
+        with open(base["output_path"], "rb") as handle:
+            self.assertIn(b"/Encrypt", handle.read())
+
+    def test_bad_permissions_and_missing_aes_fail_structured_without_writing(self):
+        image_path = self._path("secret.png")
+        Image.new("RGB", (40, 30), (200, 10, 10)).save(image_path)
+        base = {"image_paths": [image_path], "output_path": self._path("secret.pdf")}
+
+        failed = pdf_process({**base, "owner_password": "admin", "permissions": ["share"]})
+        with mock.patch("reportlab.lib.pdfencrypt.StandardEncryption", side_effect=ValueError("AES requires pyaes")):
+            no_aes = pdf_process({**base, "user_password": "open"})
+
+        for result in (failed, no_aes):
+            self.assertFalse(result["success"])
+            self.assertTrue(result["error"].startswith("[PDF_ENCRYPTION_FAILED]"), result)
+        self.assertIn("pyaes", no_aes["error"])
+        self.assertFalse(os.path.exists(base["output_path"]))
+
+    def test_page_numbers_and_running_text_are_drawn_on_every_page(self):
+        images = []
+        for index in range(3):
+            image_path = self._path(f"page{index}.png")
+            Image.new("RGB", (40, 30), (index * 60, 90, 30)).save(image_path)
+            images.append(image_path)
+        out = self._path("numbered.pdf")
+
+        result = pdf_process(
+            {
+                "image_paths": images,
+                "output_path": out,
+                "title": "Scans",
+                "page_numbers": True,
+                "header_text": "{title}",
+                "footer_text": "第 {page} 页",
+                "compression_level": 0,
+            }
+        )
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(result["page_count"], 3)
+        with open(out, "rb") as handle:
+            self.assertIn(b"STSong-Light", handle.read())
+
+    def test_page_placeholders_and_font_choice(self):
+        self.assertEqual(_fill_page_placeholders("{title}: {page} of {total} {x}", 3, 12, "Scans"), "Scans: 3 of 12 {x}")
+        self.assertEqual(_page_font("Café", ""), "Helvetica")
+        self.assertEqual(_page_font("扫描件"), "STSong-Light")
+
+    def test_bookmarks_build_an_outline_pointing_at_each_page(self):
+        images = []
+        for index in range(3):
+            image_path = self._path(f"chapter{index + 1}.png")
+            Image.new("RGB", (40, 30), (index * 60, 30, 90)).save(image_path)
+            images.append(image_path)
+        out = self._path("outline.pdf")
+
+        result = pdf_process({"image_paths": images, "output_path": out, "auto_bookmark_from_filename": True})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(result["bookmark_count"], 3)
+        with open(out, "rb") as handle:
+            data = handle.read()
+        self.assertIn(b"/Outlines", data)
+        self.assertIn(b"chapter2", data)
+
+    def test_bookmark_labels_skip_images_that_could_not_be_opened(self):
+        self.assertEqual(_bookmark_labels(["a.png", "b.png", "c.png"], ["a.png", "c.png"], ["A", "B", "C"], False), ["A", "C"])
+        self.assertEqual(_bookmark_labels(["x/scan 1.jpg"], ["x/scan 1.jpg"], [], True), ["scan 1"])
+        self.assertEqual(_bookmark_labels(["x/scan 1.jpg"], ["x/scan 1.jpg"], [], False), [""])
+
+    def test_append_to_path_adds_new_pages_after_the_existing_ones_in_place(self):
+        try:
+            from pypdf import PdfReader
+        except ImportError:
+            self.skipTest("pypdf not installed")
+        images = []
+        for index in range(3):
+            image_path = self._path(f"scan{index}.png")
+            Image.new("RGB", (40, 30), (index * 60, 30, 90)).save(image_path)
+            images.append(image_path)
+        book = self._path("book.pdf")
+        self.assertTrue(pdf_process({"image_paths": images[:2], "output_path": book})["success"])
+
+        result = pdf_process({"image_paths": images[2:], "output_path": book, "append_to_path": book})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(result["page_count"], 3)
+        self.assertEqual(len(PdfReader(book).pages), 3)
+        self.assertEqual([name for name in os.listdir(self.temp_dir.name) if name.startswith("imageflow_pdf_")], [])
+
+    def test_append_to_a_file_that_is_not_a_pdf_fails_structured(self):
+        image_path = self._path("scan.png")
+        Image.new("RGB", (40, 30), (10, 30, 90)).save(image_path)
+        broken = self._path("broken.pdf")
+        with open(broken, "wb") as handle:
+            handle.write(b"%PDF-1.4 truncated")
+
+        result = pdf_process({"image_paths": [image_path], "output_path": self._path("out.pdf"), "append_to_path": broken})
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[PDF_APPEND_FAILED]"))
+        self.assertFalse(os.path.exists(self._path("out.pdf")))
+
+    def test_image_dpi_downsamples_large_photos_and_shrinks_the_file(self):
+        photo = self._path("photo.jpg")
+        Image.effect_noise((3000, 2000), 64).convert("RGB").save(photo, quality=95)
+
+        full = pdf_process({"image_paths": [photo], "output_path": self._path("full.pdf")})
+        small = pdf_process({"image_paths": [photo], "output_path": self._path("small.pdf"), "image_dpi": 72})
+
+        self.assertTrue(small.get("success"), small)
+        self.assertEqual((small["image_dpi"], small["downsampled_count"]), (72, 1))
+        self.assertNotIn("image_dpi", full)
+        self.assertLess(small["file_size"], full["file_size"] / 4)
+
+    def test_dpi_pixel_size_only_shrinks_images_above_the_ceiling(self):
+        # 144pt x 72pt is 2in x 1in: 300 x 150 pixels at 150 DPI.
+        self.assertEqual(_dpi_pixel_size((3000, 1500), 144, 72, 150), (300, 150))
+        self.assertIsNone(_dpi_pixel_size((200, 100), 144, 72, 150))
+        self.assertIsNone(_dpi_pixel_size((3000, 1500), 144, 72, 0))
+
+    def test_subject_and_keywords_are_written_to_the_document_info(self):
+        image_path = self._path("doc.png")
+        Image.new("RGB", (40, 30), (10, 30, 90)).save(image_path)
+        out = self._path("indexed.pdf")
+
+        result = pdf_process(
+            {"image_paths": [image_path], "output_path": out, "subject": "Invoices", "keywords": ["tax", " 2026 ", ""]}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        with open(out, "rb") as handle:
+            data = handle.read()
+        self.assertIn(b"/Subject (Invoices)", data)
+        self.assertIn(b"/Keywords (tax, 2026)", data)
+        self.assertEqual(_join_keywords("a, b,,c"), "a, b, c")
+
+    def test_grid_shape_honours_columns_and_defaults_to_square(self):
+        self.assertEqual(_grid_shape(4), (2, 2))
+        self.assertEqual(_grid_shape(6), (3, 2))
+        self.assertEqual(_grid_shape(6, 2), (2, 3))
+        self.assertEqual(_grid_shape(3, 5), (3, 1))
+
+
 if __name__ == "__main__":
     unittest.main()
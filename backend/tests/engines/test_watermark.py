@@ -1,6 +1,7 @@
 import os
 import sys
 import tempfile
+import time
 import unittest
 from pathlib import Path
 
@@ -10,14 +11,17 @@ ENGINE_DIR = Path(__file__).resolve().parents[2] / "engines"
 if str(ENGINE_DIR) not in sys.path:
     sys.path.insert(0, str(ENGINE_DIR))
 
+import watermark as watermark_module
 from watermark import WatermarkApplier, process as watermark_process
 
 
 class WatermarkTests(unittest.TestCase):
     def setUp(self):
         self.temp_dir = tempfile.TemporaryDirectory()
+        watermark_module.clear_watermark_cache()
 
     def tearDown(self):
+        watermark_module.clear_watermark_cache()
         self.temp_dir.cleanup()
 
     def _path(self, name):
@@ -63,6 +67,68 @@ class WatermarkTests(unittest.TestCase):
         self.assertTrue(result.get("success"))
         self.assertTrue(os.path.exists(out))
 
+    def test_image_watermark_is_decoded_once_per_batch(self):
+        logo = self._path("logo_cached.png")
+        Image.new("RGBA", (20, 10), (255, 0, 0, 255)).save(logo, format="PNG")
+        applier = WatermarkApplier()
+        opened = []
+        original_open = watermark_module.Image.open
+
+        def counting_open(path, *args, **kwargs):
+            if os.path.abspath(str(path)) == os.path.abspath(logo):
+                opened.append(path)
+            return original_open(path, *args, **kwargs)
+
+        watermark_module.Image.open = counting_open
+        try:
+            first = applier._build_image_watermark(logo, (100, 100), 0.2, 0.5)
+            second = applier._build_image_watermark(logo, (100, 100), 0.2, 0.5)
+        finally:
+            watermark_module.Image.open = original_open
+
+        self.assertEqual(len(opened), 1)
+        self.assertEqual(first.size, (20, 10))
+        self.assertEqual(first.tobytes(), second.tobytes())
+        first.close()
+        # Callers close what they receive; the cached copy must survive that.
+        self.assertEqual(applier._build_image_watermark(logo, (100, 100), 0.2, 0.5).size, (20, 10))
+
+    def test_image_watermark_cache_invalidates_when_file_changes(self):
+        logo = self._path("logo_changed.png")
+        Image.new("RGBA", (20, 10), (255, 0, 0, 255)).save(logo, format="PNG")
+        applier = WatermarkApplier()
+        before = applier._build_image_watermark(logo, (100, 100), 0.2, 1.0)
+
+        Image.new("RGBA", (20, 10), (0, 0, 255, 255)).save(logo, format="PNG")
+        stat = os.stat(logo)
+        os.utime(logo, ns=(stat.st_atime_ns, stat.st_mtime_ns + 1_000_000_000))
+        after = applier._build_image_watermark(logo, (100, 100), 0.2, 1.0)
+
+        self.assertEqual(before.getpixel((0, 0))[:3], (255, 0, 0))
+        self.assertEqual(after.getpixel((0, 0))[:3], (0, 0, 255))
+
+    @unittest.skipUnless(os.getenv("IMAGEFLOW_BENCHMARK"), "set IMAGEFLOW_BENCHMARK=1 to time the watermark cache")
+    def test_benchmark_cached_watermark_against_decoding_per_file(self):
+        logo = self._path("logo_bench.png")
+        Image.effect_noise((1200, 600), 64).convert("RGBA").save(logo, format="PNG")
+        applier = WatermarkApplier()
+        files = 50
+
+        def build_batch(clear_each_time):
+            started = time.perf_counter()
+            for _ in range(files):
+                if clear_each_time:
+                    watermark_module.clear_watermark_cache()
+                applier._build_image_watermark(logo, (4000, 3000), 0.2, 0.5).close()
+            return (time.perf_counter() - started) * 1000
+
+        uncached_ms = build_batch(True)
+        watermark_module.clear_watermark_cache()
+        cached_ms = build_batch(False)
+
+        print(f"\nwatermark for {files} files: {uncached_ms:.0f} ms decoding each time, {cached_ms:.0f} ms cached")
+        self.assertLess(cached_ms, uncached_ms)
+
     def test_image_watermark_requires_existing_watermark_path(self):
         src = self._path("base3.png")
         Image.new("RGB", (24, 24), (40, 80, 120)).save(src, format="PNG")
@@ -136,6 +202,44 @@ class WatermarkTests(unittest.TestCase):
         self.assertTrue(result.get("success"))
         self.assertTrue(os.path.exists(out))
 
+    def test_thirds_guides_draw_lines_at_one_third(self):
+        src = self._path("guides_base.png")
+        Image.new("RGB", (91, 61), (0, 0, 0)).save(src, format="PNG")
+
+        out = self._path("guides_out.png")
+        result = watermark_process(
+            {
+                "input_path": src,
+                "output_path": out,
+                "type": "guides",
+                "guide_type": "thirds",
+                "guide_color": "#FF0000",
+                "line_width": 1,
+            }
+        )
+
+        self.assertTrue(result.get("success"), result)
+        with Image.open(out) as img:
+            self.assertEqual(img.getpixel((30, 5)), (255, 0, 0))
+            self.assertEqual(img.getpixel((5, 20)), (255, 0, 0))
+            self.assertEqual(img.getpixel((45, 5)), (0, 0, 0))
+
+    def test_guides_reject_unknown_guide_type(self):
+        src = self._path("guides_bad.png")
+        Image.new("RGB", (24, 24), (0, 0, 0)).save(src, format="PNG")
+
+        out = self._path("guides_bad_out.png")
+        result = WatermarkApplier().apply(
+            watermark_type="guides",
+            input_path=src,
+            output_path=out,
+            guide_type="spiral",
+        )
+
+        self.assertFalse(result.get("success"))
+        self.assertIn("[BAD_INPUT]", result.get("error", ""))
+        self.assertFalse(os.path.exists(out))
+
 
 if __name__ == "__main__":
     unittest.main()
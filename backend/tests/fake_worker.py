@@ -0,0 +1,68 @@
+"""In-process stand-in for the engine process pool, for deterministic `image_ops` tests.
+
+`FakeWorkerPool` has the `submit`/`shutdown` surface `image_ops._run_jobs` uses and returns
+real `concurrent.futures.Future` objects, so the real `wait` loop, cancellation and result
+handling run unchanged. What each job does is scripted by the `fake` key of its payload:
+
+- `ok` (default): run the submitted function in-process (the real `_invoke_engine_job`).
+- `bad`: the worker hands back something that is not a result dict.
+- `raise`: the worker call itself fails.
+- `hang`: the job never finishes until it is cancelled.
+- `crash`: the worker process dies, as a segfaulting decoder would.
+"""
+
+from __future__ import annotations
+
+from concurrent.futures import Future
+from concurrent.futures.process import BrokenProcessPool
+from typing import Any, Callable
+
+
+class FakeWorkerPool:
+    def __init__(self, max_workers: int = 1):
+        self._max_workers = max_workers
+        self.submitted: list[tuple[str, dict[str, Any]]] = []
+        self.pending: list[Future] = []
+        self.broken = False
+        self.shut_down = False
+
+    def submit(self, fn: Callable[..., Any], module_name: str, payload: dict[str, Any]) -> Future:
+        if self.shut_down:
+            raise RuntimeError("cannot schedule new futures after shutdown")
+        if self.broken:
+            raise BrokenProcessPool("A child process terminated abruptly, the process pool is not usable anymore.")
+        self.submitted.append((module_name, payload))
+        future: Future = Future()
+        behaviour = str(payload.get("fake") or "ok")
+        if behaviour == "hang":
+            self.pending.append(future)
+            return future
+        future.set_running_or_notify_cancel()
+        if behaviour == "bad":
+            future.set_result("not a result")
+        elif behaviour == "raise":
+            future.set_exception(RuntimeError("worker failed"))
+        elif behaviour == "crash":
+            self.broken = True
+            future.set_exception(BrokenProcessPool("A child process terminated abruptly"))
+        else:
+            future.set_result(fn(module_name, payload))
+        return future
+
+    def shutdown(self, wait: bool = True, cancel_futures: bool = False) -> None:
+        self.shut_down = True
+        if cancel_futures:
+            for future in self.pending:
+                future.cancel()
+
+
+class FakePoolFactory:
+    """Replacement for `ProcessPoolExecutor` that records every pool `image_ops` creates."""
+
+    def __init__(self):
+        self.pools: list[FakeWorkerPool] = []
+
+    def __call__(self, max_workers: int = 1) -> FakeWorkerPool:
+        pool = FakeWorkerPool(max_workers)
+        self.pools.append(pool)
+        return pool
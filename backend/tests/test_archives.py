@@ -0,0 +1,207 @@
+import os
+import tempfile
+import unittest
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.infrastructure import archives
+from backend.infrastructure.engine_loader import engine_temp_registry
+
+
+class ArchiveInputTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+
+    def tearDown(self):
+        engine_temp_registry().purge()
+        self.temp_dir.cleanup()
+
+    def _zip(self, members, name="photos.zip"):
+        path = self.root / name
+        with zipfile.ZipFile(path, "w") as archive:
+            for member, data in members.items():
+                archive.writestr(member, data)
+        return path
+
+    def test_safe_member_name_rejects_zip_slip_paths(self):
+        self.assertEqual(archives.safe_member_name("a/./b.png"), "a/b.png")
+        for name in ("../evil.png", "a/../../evil.png", "/abs.png", "C:/win.png", "a\\..\\evil.png"):
+            with self.subTest(name=name):
+                self.assertIsNone(archives.safe_member_name(name))
+
+    def test_expand_archive_lists_image_entries_without_extracting(self):
+        archive = self._zip({"trip/a.jpg": b"jpg", "trip/notes.txt": b"txt", "b.png": b"png", "../evil.png": b"x"})
+        registered = engine_temp_registry().paths()
+
+        result = archives.expand_archive(str(archive))
+
+        self.assertEqual([item["relative_path"] for item in result["files"]], ["b.png", "trip/a.jpg"])
+        for item in result["files"]:
+            self.assertEqual(item["source_root"], str(archive))
+            self.assertEqual(item["archive_member"], item["relative_path"])
+            self.assertEqual(archives.split_member_reference(item["input_path"]), (str(archive), item["relative_path"]))
+            self.assertFalse(os.path.exists(item["input_path"]))
+        self.assertEqual(engine_temp_registry().paths(), registered)
+        self.assertTrue(any("../evil.png" in warning for warning in result["warnings"]))
+
+    def test_member_is_extracted_once_on_first_use_and_purged_with_the_registry(self):
+        result = archives.expand_archive(str(self._zip({"trip/a.png": b"png"})))
+        reference = result["files"][0]["input_path"]
+
+        extracted = archives.resolve_member_path(reference)
+
+        self.assertEqual(Path(extracted).read_bytes(), b"png")
+        self.assertEqual(Path(extracted).name, "a.png")
+        self.assertEqual(archives.resolve_member_path(reference), extracted)
+        self.assertEqual(archives.resolve_member_path(str(self.root / "plain.png")), str(self.root / "plain.png"))
+        engine_temp_registry().purge()
+        self.assertFalse(os.path.exists(extracted))
+
+    def test_missing_member_keeps_its_reference(self):
+        archive = self._zip({"a.png": b"png"})
+        reference = archives.member_reference(str(archive), "gone.png")
+
+        self.assertEqual(archives.resolve_member_path(reference), reference)
+
+    def test_engine_requests_read_the_extracted_member(self):
+        archive = self._zip({"inner/c.png": b"png"})
+        reference = archives.expand_archive(str(archive))["files"][0]["input_path"]
+
+        normalized = desktop_api._normalize_payload_paths({"input_path": reference, "output_path": str(self.root / "c.jpg")})
+
+        self.assertEqual(Path(normalized["input_path"]).read_bytes(), b"png")
+        self.assertEqual(normalized["output_path"], str((self.root / "c.jpg").resolve()))
+
+    def test_expand_archive_skips_highly_compressed_entries(self):
+        archive = self.root / "bomb.zip"
+        with zipfile.ZipFile(archive, "w", compression=zipfile.ZIP_DEFLATED) as handle:
+            handle.writestr("bomb.png", b"\0" * (archives._RATIO_CHECK_MIN_BYTES * 4))
+
+        result = archives.expand_archive(str(archive))
+
+        self.assertEqual(result["files"], [])
+        self.assertTrue(result["warnings"])
+
+    def test_bad_archive_reports_warning(self):
+        broken = self.root / "broken.zip"
+        broken.write_bytes(b"not a zip")
+
+        result = archives.expand_archive(str(broken))
+
+        self.assertEqual(result["files"], [])
+        self.assertEqual(len(result["warnings"]), 1)
+
+    def test_dropped_zip_is_expanded_alongside_regular_files(self):
+        plain = self.root / "plain.png"
+        plain.write_bytes(b"png")
+        archive = self._zip({"inner/c.webp": b"webp"})
+
+        result = desktop_api.DesktopAPI().expand_dropped_paths([str(plain), str(archive)])
+
+        self.assertTrue(result["has_directory"])
+        self.assertEqual([item["relative_path"] for item in result["files"]], ["plain.png", "inner/c.webp"])
+        self.assertEqual(result["files"][1]["archive_path"], str(archive))
+
+    def test_repack_archive_restores_member_directories(self):
+        produced = self.root / "out" / "IF_a.jpg"
+        produced.parent.mkdir()
+        produced.write_bytes(b"converted")
+        output_zip = self.root / "packed" / "results.zip"
+
+        result = desktop_api.DesktopAPI().repack_archive(
+            {
+                "output_path": str(output_zip),
+                "items": [{"output_path": str(produced), "relative_path": "trip/a.jpg"}],
+            }
+        )
+
+        self.assertTrue(result["success"], result)
+        self.assertEqual(result["file_count"], 1)
+        with zipfile.ZipFile(output_zip) as archive:
+            self.assertEqual(archive.namelist(), ["trip/IF_a.jpg"])
+            self.assertEqual(archive.read("trip/IF_a.jpg"), b"converted")
+        self.assertEqual(sorted(os.listdir(output_zip.parent)), ["results.zip"])
+
+
+class PackResultsZipTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+        self.out = self.root / "out"
+        (self.out / "a").mkdir(parents=True)
+        (self.out / "b").mkdir()
+        (self.out / "a" / "photo.jpg").write_bytes(b"one")
+        (self.out / "b" / "photo.jpg").write_bytes(b"two")
+
+    def tearDown(self):
+        engine_temp_registry().purge()
+        self.temp_dir.cleanup()
+
+    def test_pack_directory_preserves_relative_structure(self):
+        target = self.root / "results.zip"
+
+        result = desktop_api.DesktopAPI().pack_results_zip({"directory": str(self.out), "output_path": str(target)})
+
+        self.assertTrue(result["success"], result)
+        self.assertEqual(result["size"], target.stat().st_size)
+        with zipfile.ZipFile(target) as archive:
+            self.assertEqual(archive.namelist(), ["a/photo.jpg", "b/photo.jpg"])
+
+    def test_pack_flat_paths_renames_duplicate_names(self):
+        target = self.root / "flat.zip"
+
+        result = desktop_api.DesktopAPI().pack_results_zip(
+            {
+                "paths": [str(self.out / "a" / "photo.jpg"), str(self.out / "b" / "photo.jpg")],
+                "output_path": str(target),
+                "preserve_structure": False,
+            }
+        )
+
+        self.assertEqual(result["file_count"], 2)
+        with zipfile.ZipFile(target) as archive:
+            self.assertEqual(archive.namelist(), ["photo.jpg", "photo_01.jpg"])
+
+    def test_pack_paths_uses_common_root_for_structure(self):
+        target = self.root / "tree.zip"
+
+        desktop_api.DesktopAPI().pack_results_zip(
+            {"paths": [str(self.out / "a" / "photo.jpg"), str(self.out / "b" / "photo.jpg")], "output_path": str(target)}
+        )
+
+        with zipfile.ZipFile(target) as archive:
+            self.assertEqual(archive.namelist(), ["a/photo.jpg", "b/photo.jpg"])
+
+    def test_pack_rejects_empty_input_and_missing_files(self):
+        api = desktop_api.DesktopAPI()
+        target = str(self.root / "x.zip")
+
+        self.assertIn("[BAD_INPUT]", api.pack_results_zip({"paths": [], "output_path": target})["error"])
+        missing = api.pack_results_zip({"paths": [str(self.root / "nope.jpg")], "output_path": target})
+        self.assertFalse(missing["success"])
+        self.assertFalse(os.path.exists(target))
+
+    def test_pack_and_repack_share_the_zip_writer_and_its_output_checks(self):
+        api = desktop_api.DesktopAPI()
+        photo = str(self.out / "a" / "photo.jpg")
+
+        with mock.patch.object(desktop_api, "write_zip", return_value={"file_count": 1}) as writer:
+            api.pack_results_zip({"paths": [photo], "output_path": str(self.root / "p.zip")})
+            api.repack_archive(
+                {"items": [{"output_path": photo, "relative_path": "a/photo.jpg"}], "output_path": str(self.root / "r.zip")}
+            )
+
+        self.assertEqual(
+            [call.args for call in writer.call_args_list],
+            [([(photo, "photo.jpg")], str(self.root / "p.zip")), ([(photo, "a/photo.jpg")], str(self.root / "r.zip"))],
+        )
+        empty = desktop_api.message("output_path_empty")
+        self.assertEqual(api.pack_results_zip({"paths": [photo], "output_path": " "})["error"], empty)
+        self.assertEqual(api.repack_archive({"items": [{"output_path": photo}]})["error"], empty)
+
+
+if __name__ == "__main__":
+    unittest.main()
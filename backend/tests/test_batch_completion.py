@@ -0,0 +1,99 @@
+import os
+import tempfile
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.completion import batch_done_detail, batch_output_dir
+
+
+class BatchCompletionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        os.environ["IMAGEFLOW_SETTINGS_FILE"] = os.path.join(self.temp_dir.name, "settings.json")
+        self.api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        self.out_dir = os.path.join(self.temp_dir.name, "out")
+
+    def tearDown(self):
+        os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None)
+        self.temp_dir.cleanup()
+
+    def _run_batch(self, results):
+        with mock.patch.object(desktop_api, "execute_engine_batch", return_value=results), mock.patch.object(
+            desktop_api, "notify_batch_done"
+        ) as notify, mock.patch.object(desktop_api, "reveal_path", return_value=True) as reveal:
+            self.api.convert_batch(
+                [{"input_path": f"{name}.png", "output_path": os.path.join(self.out_dir, f"{name}.jpg"), "format": "jpg"} for name in ("a", "b")]
+            )
+        return notify, reveal
+
+    def test_batch_output_dir_uses_common_parent_of_successful_outputs(self):
+        results = [
+            {"success": True, "output_path": os.path.join(self.out_dir, "x", "a.jpg")},
+            {"success": True, "output_path": os.path.join(self.out_dir, "y", "b.jpg")},
+            {"success": False, "output_path": os.path.join(self.temp_dir.name, "c.jpg")},
+        ]
+
+        self.assertEqual(batch_output_dir(results), os.path.abspath(self.out_dir))
+        self.assertEqual(batch_output_dir([{"success": False}]), "")
+
+    def test_batch_emits_done_event_without_revealing_by_default(self):
+        results = [
+            {"success": True, "output_path": os.path.join(self.out_dir, "a.jpg")},
+            {"success": False, "error": "boom"},
+        ]
+        notify, reveal = self._run_batch(results)
+
+        detail = notify.call_args[0][0]
+        self.assertEqual((detail["total"], detail["succeeded"], detail["failed"]), (2, 1, 1))
+        self.assertEqual(detail["output_dir"], os.path.abspath(self.out_dir))
+        self.assertIn("1", detail["message"])
+        reveal.assert_not_called()
+
+    def test_open_folder_when_done_reveals_output_dir(self):
+        self.api.save_settings({"open_folder_when_done": True})
+        notify, reveal = self._run_batch([{"success": True, "output_path": os.path.join(self.out_dir, "a.jpg")}])
+
+        reveal.assert_called_once_with(os.path.abspath(self.out_dir))
+        notify.assert_called_once()
+
+    def test_batch_without_successes_stays_silent(self):
+        self.api.save_settings({"open_folder_when_done": True})
+        notify, reveal = self._run_batch([{"success": False, "error": "boom"}, {"success": False, "error": "boom"}])
+
+        notify.assert_not_called()
+        reveal.assert_not_called()
+
+    def test_notify_on_completion_fires_only_past_long_operation_threshold(self):
+        self.api.save_settings({"notify_on_completion": True})
+        results = [{"success": True, "output_path": os.path.join(self.out_dir, "a.jpg")}, {"success": False, "error": "boom"}]
+        with mock.patch.object(desktop_api, "notify_desktop") as desktop, mock.patch.object(
+            desktop_api, "long_operation_seconds", return_value=0.0
+        ):
+            self._run_batch(results)
+        with mock.patch.object(desktop_api, "notify_desktop") as quiet, mock.patch.object(
+            desktop_api, "long_operation_seconds", return_value=3600.0
+        ):
+            self._run_batch(results)
+
+        title, body = desktop.call_args[0]
+        self.assertTrue(title)
+        self.assertIn("1", body)
+        quiet.assert_not_called()
+
+    def test_notify_is_skipped_when_setting_is_off(self):
+        with mock.patch.object(desktop_api, "notify_desktop") as desktop, mock.patch.object(
+            desktop_api, "long_operation_seconds", return_value=0.0
+        ):
+            self._run_batch([{"success": False, "error": "boom"}])
+
+        desktop.assert_not_called()
+
+    def test_done_detail_counts_non_dict_results_as_failed(self):
+        detail = batch_done_detail("converter", [{"success": True}, None])
+
+        self.assertEqual((detail["succeeded"], detail["failed"]), (1, 1))
+
+
+if __name__ == "__main__":
+    unittest.main()
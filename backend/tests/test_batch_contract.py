@@ -102,6 +102,27 @@ class BatchContractTests(unittest.TestCase):
         self.assertEqual([item.get("index") for item in result], [0, 1, 2, 3])
         self.assertTrue(all(item.get("success") for item in result))
 
+    def test_same_named_inputs_from_different_folders_get_distinct_outputs(self):
+        captured = {}
+
+        def fake_batch(module_name, payloads, settings, task_manager):
+            captured["payloads"] = payloads
+            return [{"success": True, "output_path": item["output_path"]} for item in payloads]
+
+        desktop_api.execute_engine_batch = fake_batch
+        out_dir = Path(self.temp_dir.name) / "out"
+        result = self.app.convert_batch(
+            [
+                {"input_path": str(Path(self.temp_dir.name) / "a" / "photo.jpg"), "output_path": str(out_dir / "photo.jpg"), "format": "jpg"},
+                {"input_path": str(Path(self.temp_dir.name) / "b" / "photo.jpg"), "output_path": str(out_dir / "photo.jpg"), "format": "jpg"},
+            ]
+        )
+
+        names = [Path(item["output_path"]).name for item in captured["payloads"]]
+        self.assertEqual(names, ["photo.jpg", "photo_01.jpg"])
+        self.assertNotIn("warning", result[0])
+        self.assertIn("photo_01.jpg", result[1]["warning"])
+
     def test_empty_batch_short_circuits_without_calling_engine(self):
         called = {"value": False}
 
@@ -208,6 +229,29 @@ class PreviewIsolationTests(unittest.TestCase):
         self.assertTrue(result.get("success"), result)
         self.assertTrue(str(result.get("data_url") or "").startswith("data:image/jpeg;base64,"))
 
+    def test_build_image_preview_transcodes_cmyk_jpeg_to_srgb(self):
+        import base64
+        import io
+
+        from backend.application.preview import build_image_preview
+
+        path = os.path.join(self.temp_dir.name, "cmyk.jpg")
+        Image.new("CMYK", (40, 30), (0, 255, 255, 0)).save(path, format="JPEG", quality=95)
+
+        result = build_image_preview(path)
+
+        self.assertTrue(result.get("success"), result)
+        self.assertTrue(result.get("was_cmyk"))
+        data_url = str(result.get("data_url") or "")
+        self.assertTrue(data_url.startswith("data:image/jpeg;base64,"))
+        decoded = base64.b64decode(data_url.split(",", 1)[1])
+        with Image.open(io.BytesIO(decoded)) as img:
+            self.assertEqual(img.mode, "RGB")
+            r, g, b = img.getpixel((20, 15))
+        self.assertGreater(r, 200)
+        self.assertLess(g, 60)
+        self.assertLess(b, 60)
+
     def test_build_image_preview_skips_when_file_too_large(self):
         from backend.application.preview import build_image_preview
 
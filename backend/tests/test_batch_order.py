@@ -0,0 +1,75 @@
+import os
+import tempfile
+import unittest
+from dataclasses import replace
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.batch_order import order_batch_indexes
+from backend.contracts.settings import AppSettings, default_app_settings
+from backend.infrastructure.settings_store import normalize_settings
+
+
+class BatchOrderTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.payloads = [
+            {"input_path": self._input("b-medium.png", 200), "output_path": "/out/b.png"},
+            {"input_path": self._input("C-large.png", 300), "output_path": "/out/c.png"},
+            {"input_path": self._input("a-small.png", 100), "output_path": "/out/a.png"},
+            {"input_path": os.path.join(self.temp_dir.name, "missing.png"), "output_path": "/out/m.png"},
+        ]
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _input(self, name, size):
+        path = os.path.join(self.temp_dir.name, name)
+        with open(path, "wb") as handle:
+            handle.write(b"x" * size)
+        return path
+
+    def _run_batch(self, strategy):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        settings = replace(default_app_settings(), batch_order=strategy)
+
+        def fake_batch(_module, items, *_args):
+            return [{"success": True, "input_path": item["input_path"]} for item in items]
+
+        with mock.patch.object(api, "_settings", return_value=settings), mock.patch.object(
+            desktop_api, "execute_engine_batch", side_effect=fake_batch
+        ) as batch:
+            results = api.compress_batch(self.payloads)
+        enqueued = [os.path.basename(item["input_path"]) for item in batch.call_args.args[1]]
+        return enqueued, results
+
+    def test_enqueue_order_matches_strategy_and_results_keep_request_order(self):
+        expected = {
+            "as-is": ["b-medium.png", "C-large.png", "a-small.png", "missing.png"],
+            "smallest-first": ["missing.png", "a-small.png", "b-medium.png", "C-large.png"],
+            "largest-first": ["C-large.png", "b-medium.png", "a-small.png", "missing.png"],
+            "name": ["a-small.png", "b-medium.png", "C-large.png", "missing.png"],
+        }
+        for strategy, order in expected.items():
+            with self.subTest(strategy=strategy):
+                enqueued, results = self._run_batch(strategy)
+
+                self.assertEqual(enqueued, order)
+                self.assertEqual(
+                    [result["input_path"] for result in results],
+                    [payload["input_path"] for payload in self.payloads],
+                )
+
+    def test_sorting_is_stable_and_limited_to_given_indexes(self):
+        payloads = [{"input_path": "/in/same.png"}, {"input_path": "/in/same.png"}, {"input_path": "/in/a.png"}]
+
+        self.assertEqual(order_batch_indexes([0, 1], payloads, "name"), [0, 1])
+        self.assertEqual(order_batch_indexes([2, 1, 0], payloads, "unknown"), [2, 1, 0])
+
+    def test_unknown_setting_falls_back_to_as_is(self):
+        self.assertEqual(normalize_settings(AppSettings(batch_order="Largest-First")).batch_order, "largest-first")
+        self.assertEqual(normalize_settings(AppSettings(batch_order="random")).batch_order, "as-is")
+
+
+if __name__ == "__main__":
+    unittest.main()
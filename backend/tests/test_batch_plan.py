@@ -0,0 +1,139 @@
+import json
+import os
+import tempfile
+import unittest
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.infrastructure import archives
+from backend.infrastructure.engine_loader import engine_temp_registry
+
+
+class PlanBatchTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+        os.environ["IMAGEFLOW_SETTINGS_FILE"] = str(self.root / "settings.json")
+        self.api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        for folder in ("a", "b"):
+            (self.root / folder).mkdir()
+            (self.root / folder / "photo.png").write_bytes(b"png")
+
+    def tearDown(self):
+        os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None)
+        self.temp_dir.cleanup()
+
+    def _convert(self, folder, output_name="photo.jpg", **extra):
+        return {
+            "input_path": str(self.root / folder / "photo.png"),
+            "output_path": str(self.root / "out" / output_name),
+            "format": "jpg",
+            **extra,
+        }
+
+    def test_plan_resolves_collisions_without_running_engines(self):
+        with mock.patch.object(desktop_api, "execute_engine_batch") as batch:
+            plan = self.api.plan_batch("convert", [self._convert("a"), self._convert("b")])
+            batch.assert_not_called()
+
+        self.assertTrue(plan["success"])
+        self.assertEqual([Path(item["output_path"]).name for item in plan["items"]], ["photo.jpg", "photo_01.jpg"])
+        self.assertEqual(plan["skipped"], 0)
+        self.assertTrue(plan["items"][1]["warnings"])
+        self.assertFalse((self.root / "out").exists())
+
+    def test_plan_flags_missing_inputs_unsupported_formats_and_clamping(self):
+        requests = [
+            {**self._convert("a"), "input_path": str(self.root / "missing.png")},
+            self._convert("a", "photo.xyz", format="xyz"),
+            self._convert("b", "other.jpg", quality=300),
+        ]
+
+        plan = self.api.plan_batch("convert", json.dumps(requests))
+
+        self.assertEqual(plan["skipped"], 2)
+        self.assertTrue(plan["items"][0]["error"].startswith("[NOT_FOUND]"))
+        self.assertTrue(plan["items"][1]["error"].startswith("[UNSUPPORTED_FORMAT]"))
+        self.assertFalse(plan["items"][2]["skip"])
+        self.assertIn("quality", plan["items"][2]["warnings"][0])
+
+    def test_plan_warns_when_output_would_overwrite_existing_file(self):
+        (self.root / "out").mkdir()
+        (self.root / "out" / "photo.jpg").write_bytes(b"old")
+
+        plan = self.api.plan_batch("convert", [self._convert("a")])
+
+        self.assertEqual(plan["items"][0]["warnings"], [desktop_api.message("plan_overwrite", name="photo.jpg")])
+
+    def test_plan_warnings_follow_the_language_setting(self):
+        (self.root / "out").mkdir()
+        (self.root / "out" / "photo.jpg").write_bytes(b"old")
+        self.api.save_settings({"language": "en"})
+
+        plan = self.api.plan_batch("convert", [self._convert("a")])
+
+        self.assertEqual(plan["items"][0]["warnings"], ["Output already exists and will be overwritten: photo.jpg"])
+
+    def test_plan_reports_the_request_errors_the_batch_would_reject(self):
+        requests = [
+            self._convert("a", background_color="not-a-color"),
+            self._convert("b", "photo.tif", format="tiff", tiff_compression="bogus"),
+        ]
+
+        plan = self.api.plan_batch("convert", requests)
+
+        self.assertEqual(plan["skipped"], 2)
+        for entry in plan["items"]:
+            self.assertTrue(entry["error"].startswith("[BAD_INPUT]"), entry)
+
+    def test_plan_marks_up_to_date_outputs_as_skipped_instead_of_overwritten(self):
+        (self.root / "out").mkdir()
+        output = self.root / "out" / "photo.jpg"
+        output.write_bytes(b"newer")
+        newer = (self.root / "a" / "photo.png").stat().st_mtime + 60
+        os.utime(output, (newer, newer))
+
+        plan = self.api.plan_batch("convert", [self._convert("a", skip_up_to_date=True)])
+
+        entry = plan["items"][0]
+        self.assertTrue(entry["skip"] and entry["up_to_date"])
+        self.assertNotIn("error", entry)
+        self.assertNotIn("覆盖", " ".join(entry["warnings"]))
+
+    def test_plan_follows_the_ask_conflict_strategy(self):
+        (self.root / "out").mkdir()
+        (self.root / "out" / "photo.jpg").write_bytes(b"old")
+        self.api.save_settings({"conflict_strategy": "ask"})
+
+        plan = self.api.plan_batch("convert", [self._convert("a")])
+
+        self.assertIn("询问", plan["items"][0]["warnings"][0])
+        self.assertFalse(plan["items"][0]["skip"])
+
+    def test_plan_checks_zip_members_against_the_archive_without_extracting(self):
+        archive = self.root / "photos.zip"
+        with zipfile.ZipFile(archive, "w") as handle:
+            handle.writestr("trip/a.png", b"png")
+        registered = engine_temp_registry().paths()
+        requests = [
+            {**self._convert("a"), "input_path": archives.member_reference(str(archive), "trip/a.png")},
+            {**self._convert("b"), "input_path": archives.member_reference(str(archive), "trip/gone.png")},
+        ]
+
+        plan = self.api.plan_batch("convert", requests)
+
+        self.assertFalse(plan["items"][0]["skip"], plan["items"][0])
+        self.assertEqual(plan["items"][0]["input_path"], requests[0]["input_path"])
+        self.assertTrue(plan["items"][1]["error"].startswith("[NOT_FOUND]"))
+        self.assertEqual(engine_temp_registry().paths(), registered)
+
+    def test_plan_rejects_unknown_operation_and_malformed_requests(self):
+        self.assertIn("[BAD_INPUT]", self.api.plan_batch("explode", [])["error"])
+        self.assertIn("[BAD_INPUT]", self.api.plan_batch("convert", "{ nope")["error"])
+        self.assertIn("[BAD_INPUT]", self.api.plan_batch("convert", {"input_path": "x"})["error"])
+
+
+if __name__ == "__main__":
+    unittest.main()
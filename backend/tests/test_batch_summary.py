@@ -0,0 +1,64 @@
+import json
+import unittest
+
+from backend.api import desktop_api
+from backend.application.batch_summary import summarize_results
+
+
+class BatchSummaryTests(unittest.TestCase):
+    def test_counts_outcomes_and_aggregates_compression_sizes(self):
+        results = [
+            {"success": True, "original_size": 1000, "compressed_size": 600},
+            {"success": True, "original_size": 1000, "compressed_size": 400},
+            {"success": False, "error": "[INTERNAL] broken"},
+            {"success": False, "error": "[PY_CANCELLED] operation cancelled"},
+        ]
+
+        summary = summarize_results("compress", results, elapsed_ms=1234.5)
+
+        self.assertEqual(summary["operation"], "compress")
+        self.assertEqual(summary["total"], 4)
+        self.assertEqual(summary["succeeded"], 2)
+        self.assertEqual(summary["failed"], 1)
+        self.assertEqual(summary["cancelled"], 1)
+        self.assertEqual(summary["input_bytes"], 2000)
+        self.assertEqual(summary["output_bytes"], 1000)
+        self.assertEqual(summary["average_compression_rate"], 50.0)
+        self.assertEqual(summary["total_time_ms"], 1234.5)
+
+    def test_counts_the_engine_each_compressed_file_went_through(self):
+        results = [
+            *[{"success": True, "engine_used": "pngquant+oxipng"}] * 3,
+            {"success": True, "engine_used": "pillow"},
+            {"success": False, "error": "[INTERNAL] broken", "engine_used": "pillow"},
+        ]
+
+        self.assertEqual(summarize_results("compress", results)["engines"], {"pillow": 1, "pngquant+oxipng": 3})
+        self.assertNotIn("engines", summarize_results("adjust", [{"success": True}]))
+
+    def test_uses_file_size_for_operations_without_input_sizes(self):
+        summary = summarize_results("adjust", [{"success": True, "file_size": 321, "elapsed_ms": 10}])
+
+        self.assertEqual(summary["output_bytes"], 321)
+        self.assertEqual(summary["input_bytes"], 0)
+        self.assertEqual(summary["average_compression_rate"], 0.0)
+        self.assertEqual(summary["total_time_ms"], 10.0)
+
+    def test_accepts_raw_json_results(self):
+        raw = json.dumps([{"success": True}, {"success": False, "error": "x"}])
+
+        summary = summarize_results("convert", raw)
+
+        self.assertEqual((summary["succeeded"], summary["failed"]), (1, 1))
+
+    def test_desktop_api_reports_bad_results_payload(self):
+        api = desktop_api.DesktopAPI()
+
+        result = api.SummarizeResults("convert", 42)
+
+        self.assertFalse(result["success"])
+        self.assertIn("[BAD_INPUT]", result["error"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,56 @@
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import capabilities
+from backend.domain.formats import format_capabilities
+from backend.infrastructure.runtime_files import runtime_version, write_runtime_manifest
+
+
+class CapabilitiesTests(unittest.TestCase):
+    def setUp(self):
+        patcher = mock.patch.object(capabilities, "_cached", None)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_report_lists_every_section(self):
+        report = capabilities.build_capabilities()
+
+        self.assertTrue(report["app_version"])
+        self.assertEqual(set(report["compression_engines"]), {"mozjpeg", "imagequant", "oxipng"})
+        self.assertEqual(set(report["codecs"]), {"avif", "jxl", "heic", "raw"})
+        self.assertEqual(set(report["pdf"]), {"append", "aes256"})
+        self.assertEqual(report["models"], {"background_removal": False, "upscaling": False, "face_detection": False})
+        self.assertEqual(report["formats"], format_capabilities())
+        self.assertIn("version", report["python"])
+        self.assertTrue(report["generated_at"].endswith("+00:00"))
+
+    def test_report_is_built_once_and_callers_get_copies(self):
+        built = {"app_version": "1.0", "codecs": {"avif": True}, "generated_at": "t"}
+        with mock.patch.object(capabilities, "build_capabilities", return_value=built) as build:
+            first = capabilities.capabilities()
+            first["codecs"]["avif"] = False
+            second = capabilities.capabilities()
+
+        build.assert_called_once_with()
+        self.assertEqual(second["codecs"], {"avif": True})
+
+    def test_packaged_version_comes_from_the_manifest(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            root = Path(temp_dir)
+            self.assertEqual(runtime_version(root), "")
+            write_runtime_manifest(root, "9.8.7")
+            self.assertEqual(runtime_version(root), "9.8.7")
+            with mock.patch("backend.infrastructure.runtime_files.runtime_root", return_value=root):
+                self.assertEqual(capabilities.app_version(), "9.8.7")
+
+    def test_get_capabilities_binding_returns_the_cached_report(self):
+        api = desktop_api.DesktopAPI()
+        with mock.patch.object(desktop_api, "capabilities", return_value={"app_version": "2.0"}):
+            self.assertEqual(api.GetCapabilities(), {"app_version": "2.0"})
+
+
+if __name__ == "__main__":
+    unittest.main()
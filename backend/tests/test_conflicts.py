@@ -0,0 +1,129 @@
+import os
+import tempfile
+import threading
+import unittest
+from dataclasses import replace
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.conflicts import CANCELLED_DECISION, ConflictPrompter
+from backend.contracts.settings import default_app_settings
+
+
+class ConflictPrompterTests(unittest.TestCase):
+    def test_answer_is_matched_on_operation_and_path(self):
+        prompts = []
+        prompted = threading.Event()
+        prompter = ConflictPrompter(lambda detail: (prompts.append(detail), prompted.set()), timeout=5)
+        operation_id = prompter.new_operation_id("converter")
+
+        def reply():
+            prompted.wait(5)
+            self.assertFalse(prompter.resolve("other-1", prompts[0]["path"], "skip"))
+            self.assertTrue(prompter.resolve(operation_id, "C:\\OUT\\A.JPG", "overwrite"))
+
+        worker = threading.Thread(target=reply)
+        worker.start()
+        decision = prompter.ask(operation_id, "converter", "c:/out/a.jpg")
+        worker.join()
+
+        self.assertEqual(decision, "overwrite")
+        self.assertEqual(prompts[0]["type"], "conflict_prompt")
+        self.assertFalse(prompter.resolve(operation_id, "c:/out/a.jpg", "skip"))
+
+    def test_unanswered_prompt_falls_back_to_rename(self):
+        prompter = ConflictPrompter(lambda _detail: None, timeout=0.01)
+
+        self.assertEqual(prompter.ask("op-1", "converter", "/out/a.jpg"), "rename")
+
+    def test_cancel_releases_waiting_prompts(self):
+        prompter = ConflictPrompter(lambda _detail: prompter.cancel(), timeout=5)
+
+        self.assertEqual(prompter.ask("op-1", "converter", "/out/a.jpg"), CANCELLED_DECISION)
+
+
+class AskConflictStrategyTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        settings = replace(default_app_settings(), conflict_strategy="ask")
+        patcher = mock.patch.object(self.api, "_settings", return_value=settings)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _existing(self, name):
+        path = os.path.join(self.temp_dir.name, name)
+        with open(path, "wb") as handle:
+            handle.write(b"old")
+        return path
+
+    def _answer_with(self, decisions):
+        def notify(detail):
+            self.api.resolve_conflict(detail["operation_id"], detail["path"], decisions[os.path.basename(detail["path"])])
+
+        return mock.patch.object(desktop_api, "notify_conflict_prompt", side_effect=notify)
+
+    def test_batch_applies_each_decision(self):
+        keep = self._existing("keep.jpg")
+        skip = self._existing("skip.jpg")
+        rename = self._existing("rename.jpg")
+        fresh = os.path.join(self.temp_dir.name, "fresh.jpg")
+        payloads = [{"input_path": f"/in/{i}.png", "output_path": path} for i, path in enumerate([keep, skip, rename, fresh])]
+
+        def fake_batch(_module, items, *_args):
+            return [{"success": True, "output_path": item["output_path"]} for item in items]
+
+        decisions = {"keep.jpg": "overwrite", "skip.jpg": "skip", "rename.jpg": "rename"}
+        with self._answer_with(decisions) as notify, mock.patch.object(
+            desktop_api, "execute_engine_batch", side_effect=fake_batch
+        ) as batch:
+            results = self.api.compress_batch(payloads)
+
+        self.assertEqual(notify.call_count, 3)
+        self.assertEqual(len(batch.call_args.args[1]), 3)
+        self.assertEqual(results[0]["output_path"], keep)
+        self.assertTrue(results[1]["skipped"])
+        self.assertEqual(results[1]["warning"], desktop_api.message("conflict_skipped", name="skip.jpg"))
+        self.assertEqual(os.path.basename(results[2]["output_path"]), "rename_01.jpg")
+        self.assertEqual(results[2]["warning"], desktop_api.message("conflict_renamed", name="rename_01.jpg"))
+        self.assertEqual(results[3]["output_path"], fresh)
+
+    def test_up_to_date_outputs_are_not_prompted_for(self):
+        source = self._existing("source.png")
+        current = self._existing("current.jpg")
+        stale = self._existing("stale.jpg")
+        newer = os.stat(source).st_mtime + 60
+        os.utime(current, (newer, newer))
+        os.utime(stale, (newer - 120, newer - 120))
+        payloads = [
+            {"input_path": source, "output_path": path, "skip_up_to_date": True} for path in (current, stale)
+        ]
+
+        def fake_batch(_module, items, *_args):
+            return [{"success": True, "output_path": item["output_path"]} for item in items]
+
+        with self._answer_with({"stale.jpg": "overwrite"}) as notify, mock.patch.object(
+            desktop_api, "execute_engine_batch", side_effect=fake_batch
+        ):
+            self.api.compress_batch(payloads)
+
+        self.assertEqual([os.path.basename(call.args[0]["path"]) for call in notify.call_args_list], ["stale.jpg"])
+
+    def test_single_operation_can_be_skipped(self):
+        target = self._existing("one.png")
+        with self._answer_with({"one.png": "skip"}), mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = self.api.adjust({"input_path": "/in/one.png", "output_path": target})
+
+        engine.assert_not_called()
+        self.assertTrue(result["skipped"])
+
+    def test_resolve_conflict_validates_decision(self):
+        self.assertTrue(self.api.resolve_conflict("op", "/x", "delete")["error"].startswith("[BAD_INPUT]"))
+        self.assertTrue(self.api.resolve_conflict("op", "/x", "skip")["error"].startswith("[NOT_FOUND]"))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,139 @@
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.convert_rules import apply_rule, match_rule, normalize_rules, rules_from_format_map
+
+
+class ConvertRulesTests(unittest.TestCase):
+    def test_normalize_rules_canonicalizes_sources_and_validates(self):
+        rules, error = normalize_rules([{"source": [".JPEG", "jpg"], "format": "WebP", "quality": 80}])
+
+        self.assertEqual(error, "")
+        self.assertEqual(rules, [{"index": 0, "source": ["jpg"], "format": "webp", "quality": 80}])
+        self.assertTrue(normalize_rules([])[1].startswith("[BAD_INPUT]"))
+        self.assertTrue(normalize_rules([{"format": "png"}])[1].startswith("[BAD_INPUT]"))
+        self.assertTrue(normalize_rules([{"source": "png", "format": "gif"}])[1].startswith("[UNSUPPORTED_FORMAT]"))
+        self.assertTrue(normalize_rules([{"source": "png", "format": "jpg", "quality": 101}])[1].startswith("[BAD_INPUT]"))
+
+    def test_first_matching_rule_wins_and_fixes_output_suffix(self):
+        rules, _error = normalize_rules(
+            [{"source": "jpg", "format": "webp", "quality": 70}, {"source": ["jpg", "bmp"], "format": "png"}]
+        )
+
+        self.assertEqual(match_rule("/in/a.JPEG", rules)["index"], 0)
+        self.assertEqual(match_rule("/in/b.bmp", rules)["index"], 1)
+        self.assertIsNone(match_rule("/in/c.png", rules))
+        applied = apply_rule({"input_path": "/in/a.jpg", "output_path": "/out/a.jpg"}, rules[0])
+        self.assertEqual((applied["format"], applied["quality"]), ("webp", 70))
+        self.assertEqual(Path(applied["output_path"]), Path("/out/a.webp"))
+
+    def test_convert_by_rules_converts_matches_and_copies_the_rest(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            photo = os.path.join(temp_dir, "photo.jpg")
+            logo = os.path.join(temp_dir, "logo.png")
+            for path in (photo, logo):
+                with open(path, "wb") as handle:
+                    handle.write(b"data")
+            out_dir = os.path.join(temp_dir, "out")
+            api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+            with mock.patch.object(
+                desktop_api,
+                "execute_engine_batch",
+                side_effect=lambda _module, items, *_args: [
+                    {"success": True, "input_path": item["input_path"], "output_path": item["output_path"]}
+                    for item in items
+                ],
+            ) as batch:
+                response = api.convert_by_rules(
+                    {
+                        "rules": [{"source": "jpg", "format": "webp", "quality": 75}],
+                        "requests": [
+                            {"input_path": photo, "output_path": os.path.join(out_dir, "photo.jpg")},
+                            {"input_path": logo, "output_path": os.path.join(out_dir, "logo.png")},
+                        ],
+                    }
+                )
+
+            sent = batch.call_args[0][1]
+            self.assertEqual(len(sent), 1)
+            self.assertEqual((sent[0]["format"], sent[0]["quality"]), ("webp", 75))
+            converted, copied = response["results"]
+            self.assertTrue(converted["output_path"].endswith("photo.webp"))
+            self.assertEqual(converted["rule"]["index"], 0)
+            self.assertFalse(converted["copied"])
+            self.assertTrue(copied["success"])
+            self.assertTrue(copied["copied"])
+            self.assertIsNone(copied["rule"])
+            self.assertTrue(os.path.isfile(os.path.join(out_dir, "logo.png")))
+
+    def test_convert_by_rules_rejects_bad_rules_before_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine_batch") as batch:
+            response = api.convert_by_rules({"rules": [{"source": "png", "format": "svg"}], "requests": []})
+
+        batch.assert_not_called()
+        self.assertFalse(response["success"])
+        self.assertTrue(response["error"].startswith("[UNSUPPORTED_FORMAT]"))
+
+
+    def test_format_map_becomes_rules(self):
+        rules, error = rules_from_format_map({".PNG": "webp", "jpeg": {"format": "avif", "quality": 60}})
+
+        self.assertEqual(error, "")
+        self.assertEqual([(rule["source"], rule["format"], rule.get("quality")) for rule in rules], [
+            (["png"], "webp", None),
+            (["jpg"], "avif", 60),
+        ])
+        self.assertTrue(rules_from_format_map({})[1].startswith("[BAD_INPUT]"))
+        self.assertTrue(rules_from_format_map({"png": "gif"})[1].startswith("[UNSUPPORTED_FORMAT]"))
+
+    def test_convert_batch_by_rule_builds_requests_and_keeps_input_order(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            inputs = [os.path.join(temp_dir, name) for name in ("a.png", "b.jpg", "c.gif", "a.jpg")]
+            out_dir = os.path.join(temp_dir, "out")
+            os.makedirs(out_dir)
+            # An existing output is not overwritten; the new one gets a numbered name.
+            with open(os.path.join(out_dir, "IF_a.webp"), "wb") as handle:
+                handle.write(b"old")
+            api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+            with mock.patch.object(
+                api,
+                "convert_batch",
+                side_effect=lambda items: [{"success": True, "output_path": item["output_path"]} for item in items],
+            ) as batch:
+                results = api.ConvertBatchByRule(
+                    {
+                        "input_paths": inputs,
+                        "formats": {"png": {"format": "webp", "quality": 80}, "jpg": "avif"},
+                        "output_dir": out_dir,
+                        "options": {"keep_metadata": True, "format": "png"},
+                    }
+                )
+
+            sent = batch.call_args.args[0]
+            self.assertEqual([Path(item["output_path"]).name for item in sent], ["IF_a_01.webp", "IF_b.avif", "IF_a.avif"])
+            self.assertEqual([item["format"] for item in sent], ["webp", "avif", "avif"])
+            self.assertEqual(sent[0]["quality"], 80)
+            self.assertNotIn("quality", sent[1])
+            self.assertTrue(all(item["keep_metadata"] for item in sent))
+            self.assertEqual(len(results), 4)
+            self.assertTrue(results[0]["output_path"].endswith("IF_a_01.webp"))
+            self.assertFalse(results[2]["success"])
+            self.assertTrue(results[2]["error"].startswith("[UNSUPPORTED_FORMAT]"))
+            self.assertTrue(results[3]["output_path"].endswith("IF_a.avif"))
+
+    def test_convert_batch_by_rule_rejects_bad_maps_before_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(api, "convert_batch") as batch:
+            results = api.ConvertBatchByRule({"input_paths": ["/in/a.png"], "formats": {"png": "svg"}})
+
+        batch.assert_not_called()
+        self.assertTrue(results[0]["error"].startswith("[UNSUPPORTED_FORMAT]"))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -4,6 +4,7 @@ import threading
 import time
 import unittest
 from pathlib import Path
+from unittest import mock
 
 from PIL import Image
 
@@ -72,6 +73,28 @@ class DesktopAPITests(unittest.TestCase):
         self.assertEqual(saved["max_concurrency"], 16)
         self.assertFalse(saved["preserve_folder_structure"])
 
+    def test_export_and_import_settings_roundtrip_through_settings_file(self):
+        app = create_app()
+        app.save_settings({"max_concurrency": 3, "output_prefix": "OLD"})
+        exported = app.export_settings()
+
+        app.save_settings({"max_concurrency": 9, "output_prefix": "NEW"})
+        imported = app.import_settings(exported)
+
+        self.assertEqual(imported["max_concurrency"], 3)
+        self.assertEqual(app.get_settings()["output_prefix"], "OLD")
+
+    def test_import_settings_rejects_malformed_json_without_overwriting(self):
+        app = create_app()
+        app.save_settings({"output_prefix": "KEEP"})
+
+        result = app.import_settings("{ broken")
+
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[BAD_INPUT]"))
+        self.assertTrue(app.ImportSettings("[1, 2]")["error"].startswith("[BAD_INPUT]"))
+        self.assertEqual(app.get_settings()["output_prefix"], "KEEP")
+
     def test_update_recent_paths_deduplicates_and_limits_entries(self):
         app = create_app()
 
@@ -256,6 +279,153 @@ class DesktopAPITests(unittest.TestCase):
         finally:
             desktop_api.execute_engine = original_execute_engine
 
+    def test_generate_pdf_forwards_fit_mode_and_compression_level(self):
+        app = create_app()
+        image_path = str((Path(self.temp_dir.name) / "page.png").resolve())
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            result = app.GeneratePDF(
+                {
+                    "image_paths": [image_path],
+                    "output_path": str((Path(self.temp_dir.name) / "out.pdf").resolve()),
+                    "fit_mode": "cover",
+                    "compression_level": 2,
+                }
+            )
+
+        self.assertTrue(result["success"])
+        module_name, payload = engine.call_args.args[:2]
+        self.assertEqual(module_name, "pdf_generator")
+        self.assertEqual((payload["fit_mode"], payload["compression_level"]), ("cover", 2))
+
+    def test_generate_pdf_rejects_long_passwords_and_unknown_permissions(self):
+        app = create_app()
+        base = {"image_paths": ["a.png"], "output_path": str(Path(self.temp_dir.name) / "out.pdf")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine, mock.patch.object(
+            desktop_api, "pdf_support", return_value={"append": True, "aes256": True}
+        ) as support:
+            for extra in ({"user_password": "x" * 128}, {"owner_password": 1234}, {"permissions": ["print", "share"]}, {"permissions": "print"}):
+                result = app.GeneratePDF({**base, **extra})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT]"), extra)
+            allowed = {"user_password": "é" * 63, "owner_password": "", "permissions": ["Print", "copy"]}
+            support.return_value = {"append": True, "aes256": False}
+            no_aes = app.GeneratePDF({**base, **allowed})
+            engine.assert_not_called()
+            support.return_value = {"append": True, "aes256": True}
+            self.assertTrue(app.GeneratePDF({**base, **allowed})["success"])
+
+        self.assertTrue(no_aes["error"].startswith("[PDF_ENCRYPTION_FAILED]"))
+
+    def test_generate_pdf_requires_one_bookmark_per_image(self):
+        app = create_app()
+        base = {"image_paths": ["a.png", "b.png"], "output_path": str(Path(self.temp_dir.name) / "out.pdf")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for bookmarks in (["One"], ["One", "Two", "Three"], ["One", 2], "One"):
+                result = app.GeneratePDF({**base, "bookmarks": bookmarks})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] bookmarks"), bookmarks)
+            engine.assert_not_called()
+            for bookmarks in (None, [], ["One", ""]):
+                self.assertTrue(app.GeneratePDF({**base, "bookmarks": bookmarks})["success"])
+
+    def test_generate_pdf_checks_the_pdf_to_append_to_before_running_the_engine(self):
+        app = create_app()
+        root = Path(self.temp_dir.name)
+        existing = root / "book.pdf"
+        existing.write_bytes(b"%PDF-1.4\n%fake\n")
+        (root / "notes.txt").write_text("not a pdf", encoding="utf-8")
+        base = {"image_paths": [str(root / "a.png")], "output_path": str(existing)}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine, mock.patch.object(
+            desktop_api, "pdf_support", return_value={"append": True}
+        ) as support:
+            missing = app.GeneratePDF({**base, "append_to_path": str(root / "missing.pdf")})
+            not_pdf = app.GeneratePDF({**base, "append_to_path": str(root / "notes.txt")})
+            locked = app.GeneratePDF({**base, "append_to_path": str(existing), "user_password": "x"})
+            support.return_value = {"append": False}
+            no_pypdf = app.GeneratePDF({**base, "append_to_path": str(existing)})
+            engine.assert_not_called()
+            support.return_value = {"append": True}
+            appended = app.GeneratePDF({**base, "append_to_path": str(existing)})
+
+        self.assertTrue(missing["error"].startswith("[NOT_FOUND]"))
+        self.assertTrue(not_pdf["error"].startswith("[BAD_INPUT] append_to_path"))
+        self.assertTrue(locked["error"].startswith("[BAD_INPUT] Passwords"))
+        self.assertIn("pypdf", no_pypdf["error"])
+        self.assertTrue(appended["success"])
+        self.assertEqual(engine.call_args.args[1]["append_to_path"], str(existing.resolve()))
+
+    def test_generate_pdf_rejects_a_negative_image_dpi(self):
+        app = create_app()
+        base = {"image_paths": ["a.png"], "output_path": str(Path(self.temp_dir.name) / "out.pdf")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for dpi in (-1, 150.5, "150", True):
+                self.assertTrue(app.GeneratePDF({**base, "image_dpi": dpi})["error"].startswith("[BAD_INPUT] image_dpi"), dpi)
+            engine.assert_not_called()
+            for dpi in (None, 0, 150):
+                self.assertTrue(app.GeneratePDF({**base, "image_dpi": dpi})["success"])
+
+    def test_split_gif_rejects_unsupported_animation_output_formats(self):
+        app = create_app()
+        base = {"action": "convert_animation", "input_path": "a.gif", "output_path": str(Path(self.temp_dir.name) / "out.apng")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for output_format in ("", "mp4", "jpg"):
+                result = app.SplitGIF({**base, "output_format": output_format})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] output_format"), output_format)
+            engine.assert_not_called()
+            self.assertTrue(app.SplitGIF({**base, "output_format": "APNG"})["success"])
+            self.assertTrue(app.SplitGIF({**base, "action": "transcode", "format": "webp"})["success"])
+            self.assertTrue(app.SplitGIF({"action": "reverse", "input_path": "a.gif", "output_path": "b.gif"})["success"])
+
+    def test_split_gif_requires_a_whole_pixel_crop_rectangle(self):
+        app = create_app()
+        base = {"action": "crop", "input_path": "a.gif", "output_path": str(Path(self.temp_dir.name) / "out.gif")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for extra in ({}, {"crop_width": 0, "crop_height": 4}, {"crop_width": 4, "crop_height": 2.5}, {"crop_x": -1, "crop_width": 4, "crop_height": 4}):
+                self.assertTrue(app.SplitGIF({**base, **extra})["error"].startswith("[BAD_INPUT] crop_"), extra)
+            engine.assert_not_called()
+            self.assertTrue(app.SplitGIF({**base, "crop_y": 8, "crop_width": 16, "crop_height": 4})["success"])
+
+    def test_split_gif_requires_a_non_negative_timestamp(self):
+        app = create_app()
+        base = {"action": "export_frames", "input_path": "a.gif", "output_path": str(Path(self.temp_dir.name) / "poster.png")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for timestamp in (-1, 1.5, "1200"):
+                self.assertTrue(app.SplitGIF({**base, "timestamp_ms": timestamp})["error"].startswith("[BAD_INPUT] timestamp_ms"), timestamp)
+            engine.assert_not_called()
+            self.assertTrue(app.SplitGIF({**base, "timestamp_ms": 1200})["success"])
+
+    def test_split_gif_checks_contact_sheet_columns_and_sampling(self):
+        app = create_app()
+        base = {"action": "contact_sheet", "input_path": "a.gif", "output_path": str(Path(self.temp_dir.name) / "sheet.png")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for extra in ({"grid_columns": -1}, {"grid_columns": "4"}, {"max_frames": 2.5}):
+                self.assertTrue(app.SplitGIF({**base, **extra})["error"].startswith("[BAD_INPUT]"), extra)
+            engine.assert_not_called()
+            self.assertTrue(app.SplitGIF({**base, "grid_columns": 4, "max_frames": 12})["success"])
+
+        # The sampling count is not the decode guard, which still comes from the settings.
+        sent = engine.call_args.args[1]
+        self.assertEqual(sent["sheet_max_frames"], 12)
+        self.assertEqual(sent["max_frames"], app.get_settings()["gif_max_frames"])
+
+    def test_split_gif_checks_the_compress_palette_size(self):
+        app = create_app()
+        base = {"action": "compress", "input_path": "a.gif", "output_path": str(Path(self.temp_dir.name) / "out.gif")}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for max_colors in (1, 257, 64.0, "64"):
+                self.assertTrue(app.SplitGIF({**base, "max_colors": max_colors})["error"].startswith("[BAD_INPUT] max_colors"), max_colors)
+            engine.assert_not_called()
+            for max_colors in (None, 2, 256):
+                self.assertTrue(app.SplitGIF({**base, "max_colors": max_colors, "dither": False})["success"])
+
     def test_resolve_file_paths_extracts_absolute_paths_from_runtime_payloads(self):
         app = create_app()
         first = str((Path(self.temp_dir.name) / "first.png").resolve())
@@ -378,6 +548,108 @@ class DesktopAPITests(unittest.TestCase):
             if worker.is_alive():
                 worker.join(timeout=0.5)
 
+    def test_get_info_passes_iptc_and_xmp_groups_through(self):
+        app = create_app()
+        image_path = Path(self.temp_dir.name) / "archival.jpg"
+        image_path.write_bytes(b"jpeg")
+        metadata = {
+            "exifread": {},
+            "piexif": {},
+            "extra": {},
+            "iptc": {"caption": "Harbour at dawn", "keywords": "sea; boats", "byline": "A. Photographer"},
+            "xmp": {"dc:subject": "sea; boats", "dc:rights": "(c) 2026"},
+        }
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True, "metadata": metadata}) as engine:
+            result = app.GetInfo({"input_path": str(image_path)})
+
+        self.assertEqual(engine.call_args.args[0], "info_viewer")
+        self.assertEqual(result["metadata"]["iptc"], metadata["iptc"])
+        self.assertEqual(result["metadata"]["xmp"]["dc:rights"], "(c) 2026")
+
+    def test_get_info_passes_gps_coordinates_through(self):
+        app = create_app()
+        image_path = Path(self.temp_dir.name) / "pinned.jpg"
+        image_path.write_bytes(b"jpeg")
+        engine_result = {"success": True, "latitude": -33.859833, "longitude": 151.208333, "has_gps": True}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value=engine_result):
+            result = app.GetInfo({"input_path": str(image_path)})
+
+        self.assertEqual((result["latitude"], result["longitude"], result["has_gps"]), (-33.859833, 151.208333, True))
+
+    def test_get_info_passes_alpha_flags_through(self):
+        app = create_app()
+        image_path = Path(self.temp_dir.name) / "sticker.png"
+        image_path.write_bytes(b"png")
+        engine_result = {"success": True, "format": "PNG", "has_alpha": True, "has_transparency": False}
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value=engine_result):
+            result = app.GetInfo({"input_path": str(image_path)})
+
+        self.assertIs(result["has_alpha"], True)
+        self.assertIs(result["has_transparency"], False)
+
+    def test_get_info_batch_reads_every_file_in_order_without_taking_the_current_task(self):
+        task_manager = desktop_api.TaskManager()
+        app = desktop_api.DesktopAPI(task_manager)
+        operation_task_id = task_manager.begin_task("operation")
+        paths = [Path(self.temp_dir.name) / f"photo_{index}.jpg" for index in range(3)]
+
+        def fake_batch(module_name, payloads, _settings, manager, cancellable=True):
+            self.assertEqual((module_name, cancellable, manager), ("info_viewer", False, task_manager))
+            return [{"success": True, "input_path": payload["input_path"], "action": payload["action"]} for payload in payloads]
+
+        with mock.patch("backend.application.image_ops.execute_engine_batch", side_effect=fake_batch):
+            results = app.GetInfoBatch([{"input_path": str(path), "action": "edit_exif"} for path in paths])
+
+        self.assertEqual([result["input_path"] for result in results], [str(path.resolve()) for path in paths])
+        self.assertTrue(all(result["action"] == "get_info" for result in results))
+        self.assertEqual(task_manager.current_task_id, operation_task_id)
+        self.assertEqual(app.GetInfoBatch([]), [])
+
+    def test_palette_size_is_checked_before_reading_info(self):
+        app = create_app()
+        paths = [str((Path(self.temp_dir.name) / name).resolve()) for name in ("a.jpg", "b.jpg", "c.jpg")]
+
+        def fake_batch(_module_name, payloads, _settings, _manager, cancellable=True):
+            return [{"success": True, "input_path": payload["input_path"]} for payload in payloads]
+
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            for palette_size in (17, -1, "4", 2.0):
+                self.assertTrue(app.GetInfo({"input_path": paths[0], "palette_size": palette_size})["error"].startswith("[BAD_INPUT] palette_size"))
+            engine.assert_not_called()
+        with mock.patch("backend.application.image_ops.execute_engine_batch", side_effect=fake_batch) as batch:
+            results = app.GetInfoBatch(
+                [{"input_path": paths[0], "palette_size": 5}, {"input_path": paths[1], "palette_size": 40}, {"input_path": paths[2]}]
+            )
+
+        self.assertEqual([payload["input_path"] for payload in batch.call_args.args[1]], [paths[0], paths[2]])
+        self.assertEqual([result["success"] for result in results], [True, False, True])
+        self.assertEqual(results[1]["input_path"], paths[1])
+
+    def test_get_info_batch_fails_only_the_item_with_a_bad_path(self):
+        app = create_app()
+        good = str((Path(self.temp_dir.name) / "a.jpg").resolve())
+
+        def fake_batch(_module_name, payloads, _settings, _manager, cancellable=True):
+            return [{"success": True, "input_path": payload["input_path"]} for payload in payloads]
+
+        with mock.patch("backend.application.image_ops.execute_engine_batch", side_effect=fake_batch) as batch:
+            results = app.GetInfoBatch([{"input_path": "../escape.jpg"}, {"input_path": good}, {"input_path": "bad\x00.jpg"}])
+
+        self.assertEqual([payload["input_path"] for payload in batch.call_args.args[1]], [good])
+        self.assertEqual([result["success"] for result in results], [False, True, False])
+        self.assertTrue(results[0]["error"].startswith("[BAD_INPUT]"))
+        self.assertEqual(results[0]["input_path"], "../escape.jpg")
+        self.assertTrue(results[2]["error"].startswith("[BAD_INPUT]"))
+
+    def test_hamming_distance_reports_bad_hashes_as_bad_input(self):
+        app = create_app()
+
+        self.assertEqual(app.HammingDistance("00000000000000ff", "00000000000000f0"), {"success": True, "distance": 4})
+        self.assertTrue(app.HammingDistance("", "00000000000000ff")["error"].startswith("[BAD_INPUT]"))
+
     def test_get_info_does_not_replace_current_operation_task(self):
         task_manager = desktop_api.TaskManager()
         app = desktop_api.DesktopAPI(task_manager)
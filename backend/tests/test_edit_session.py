@@ -0,0 +1,140 @@
+import errno
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import edit_session
+from backend.application.edit_session import EditSessions
+
+
+def _append_engine(calls):
+    """Stand-in engine: each edit appends its `tag` to the bytes of whatever it was given."""
+
+    def run(module_name, payload):
+        calls.append((module_name, payload))
+        if payload.get("fail"):
+            return {"success": False, "error": "[BAD_INPUT] broken"}
+        data = Path(payload["input_path"]).read_bytes()
+        Path(payload["output_path"]).write_bytes(data + str(payload.get("tag", "")).encode())
+        return {"success": True, "output_path": payload["output_path"]}
+
+    return run
+
+
+class EditSessionTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.addCleanup(self.temp_dir.cleanup)
+        self.source = Path(self.temp_dir.name) / "photo.jpg"
+        self.source.write_bytes(b"src")
+        self.sessions = EditSessions()
+        self.calls = []
+        self.run = _append_engine(self.calls)
+
+    def test_each_edit_builds_on_the_previous_state_and_undo_steps_back(self):
+        begun = self.sessions.begin(str(self.source))
+        session_id = begun["session_id"]
+
+        self.sessions.apply(session_id, {"brightness": 10, "tag": "a"}, self.run)
+        applied = self.sessions.apply(session_id, {"operation": "filter", "tag": "b"}, self.run)
+
+        self.assertEqual([module for module, _payload in self.calls], ["adjuster", "filter"])
+        self.assertEqual(self.calls[1][1]["input_path"], self.calls[0][1]["output_path"])
+        self.assertNotIn("operation", self.calls[1][1])
+        self.assertEqual(Path(applied["working_path"]).read_bytes(), b"srcab")
+        self.assertEqual((applied["edits"], applied["can_undo"]), (2, True))
+
+        undone = self.sessions.undo(session_id)
+        self.assertEqual(Path(undone["working_path"]).read_bytes(), b"srca")
+        self.assertFalse(os.path.exists(applied["working_path"]))
+        self.sessions.undo(session_id)
+        self.assertTrue(self.sessions.undo(session_id)["error"].startswith("[BAD_INPUT] Nothing to undo"))
+        self.assertEqual(self.source.read_bytes(), b"src")
+
+    def test_failed_edit_keeps_the_current_state(self):
+        session_id = self.sessions.begin(str(self.source))["session_id"]
+        self.sessions.apply(session_id, {"tag": "a"}, self.run)
+
+        failed = self.sessions.apply(session_id, {"fail": True}, self.run)
+
+        self.assertFalse(failed["success"])
+        self.assertEqual(failed["error"], "[BAD_INPUT] broken")
+        self.assertEqual(failed["edits"], 1)
+        self.assertTrue(self.sessions.apply(session_id, {"operation": "crop"}, self.run)["error"].startswith("[BAD_INPUT]"))
+
+    def test_history_depth_drops_the_oldest_edits_but_keeps_the_original(self):
+        session_id = self.sessions.begin(str(self.source))["session_id"]
+        for tag in "abc":
+            state = self.sessions.apply(session_id, {"tag": tag}, self.run, depth=2)
+
+        self.assertEqual(state["edits"], 2)
+        self.sessions.undo(session_id)
+        state = self.sessions.undo(session_id)
+        self.assertEqual(Path(state["working_path"]).read_bytes(), b"src")
+        self.assertEqual(len(os.listdir(Path(state["working_path"]).parent)), 1)
+
+    def test_commit_writes_the_current_state_and_removes_the_session_temps(self):
+        session_id = self.sessions.begin(str(self.source))["session_id"]
+        working = self.sessions.apply(session_id, {"tag": "a"}, self.run)["working_path"]
+        output = Path(self.temp_dir.name) / "out" / "photo_edited.jpeg"
+
+        self.assertTrue(self.sessions.commit(session_id, str(output.with_suffix(".png")))["error"].startswith("[BAD_INPUT]"))
+        committed = self.sessions.commit(session_id, str(output))
+
+        self.assertTrue(committed["success"], committed)
+        self.assertEqual((output.read_bytes(), committed["edits"]), (b"srca", 1))
+        self.assertFalse(os.path.exists(Path(working).parent))
+        self.assertEqual(self.sessions.active(), [])
+        self.assertTrue(self.sessions.undo(session_id)["error"].startswith("[BAD_INPUT] Unknown edit session"))
+
+    def test_commit_waits_out_a_briefly_locked_output(self):
+        session_id = self.sessions.begin(str(self.source))["session_id"]
+        self.sessions.apply(session_id, {"tag": "a"}, self.run)
+        output = Path(self.temp_dir.name) / "out" / "photo.jpg"
+        output.parent.mkdir()
+        output.write_bytes(b"old")
+        real_replace = os.replace
+        busy = [OSError(errno.EBUSY, "busy")]
+
+        def replace(src, dst):
+            if busy:
+                raise busy.pop()
+            return real_replace(src, dst)
+
+        with mock.patch.object(os, "replace", side_effect=replace), mock.patch.dict(
+            os.environ, {"IMAGEFLOW_LOCK_RETRY_DELAY_MS": "0"}
+        ):
+            committed = self.sessions.commit(session_id, str(output))
+
+        self.assertTrue(committed["success"], committed)
+        self.assertEqual(busy, [])
+        self.assertEqual(output.read_bytes(), b"srca")
+        self.assertEqual(os.listdir(output.parent), ["photo.jpg"])
+
+    def test_cancel_removes_the_working_directory(self):
+        begun = self.sessions.begin(str(self.source))
+
+        self.assertTrue(self.sessions.cancel(begun["session_id"])["success"])
+
+        self.assertFalse(os.path.exists(Path(begun["working_path"]).parent))
+        self.assertFalse(self.sessions.cancel(begun["session_id"])["success"])
+        self.assertTrue(self.sessions.begin(str(self.source) + ".missing")["error"].startswith("[NOT_FOUND]"))
+
+    def test_host_runs_edits_through_the_engine_with_the_configured_depth(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(edit_session, "_SESSIONS", self.sessions), mock.patch.object(
+            api, "_run_engine", side_effect=self.run
+        ), mock.patch.object(api, "_settings", return_value=mock.Mock(edit_history_depth=1)):
+            session_id = api.BeginEditSession(str(self.source))["session_id"]
+            api.ApplyEdit(session_id, {"tag": "a"})
+            state = api.ApplyEdit(session_id, {"tag": "b"})
+            self.assertEqual(state["edits"], 1)
+            self.assertTrue(api.UndoEdit(session_id)["success"])
+            self.assertTrue(api.CancelEdit(session_id)["success"])
+
+
+if __name__ == "__main__":
+    unittest.main()
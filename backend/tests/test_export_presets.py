@@ -0,0 +1,59 @@
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import export_presets
+from backend.application.export_presets import apply_preset
+from backend.domain.formats import convert_target_error
+
+
+class ExportPresetTests(unittest.TestCase):
+    def test_presets_are_listed_in_order_and_target_convertible_formats(self):
+        presets = export_presets.export_presets()
+
+        self.assertEqual([preset["id"] for preset in presets], ["web", "print", "archive"])
+        for preset in presets:
+            self.assertEqual(convert_target_error(preset["settings"]["format"]), "", preset["id"])
+        presets[0]["settings"]["quality"] = 1
+        self.assertEqual(export_presets.EXPORT_PRESETS[0]["settings"]["quality"], 80)
+
+    def test_web_preset_overrides_request_and_fixes_output_suffix(self):
+        request, error = apply_preset(
+            "Web",
+            {"input_path": "/in/a.png", "output_path": "/out/a.png", "quality": 95, "keep_metadata": True, "pad_color": "#fff"},
+        )
+
+        self.assertEqual(error, "")
+        self.assertEqual((request["format"], request["quality"]), ("webp", 80))
+        self.assertEqual((request["resize_mode"], request["long_edge"]), ("long_edge", 2048))
+        self.assertFalse(request["keep_metadata"])
+        self.assertFalse(request["keep_color_profile"])
+        self.assertEqual(request["pad_color"], "#fff")
+        self.assertEqual(Path(request["output_path"]), Path("/out/a.webp"))
+
+    def test_print_and_archive_keep_source_size_and_metadata(self):
+        for preset_id in ("print", "archive"):
+            request, error = apply_preset(preset_id, {"resize_mode": "fixed", "width": 100, "long_edge": 50})
+
+            self.assertEqual(error, "")
+            self.assertEqual((request["resize_mode"], request["width"], request["long_edge"]), ("", 0, 0))
+            self.assertTrue(request["keep_metadata"] and request["keep_color_profile"])
+            self.assertTrue(request["preserve_bit_depth"])
+
+    def test_unknown_preset_and_bad_request_are_rejected(self):
+        self.assertTrue(apply_preset("poster", {})[1].startswith("[BAD_INPUT]"))
+        self.assertTrue(apply_preset("web", ["a.png"])[1].startswith("[BAD_INPUT]"))
+
+    def test_host_exposes_presets_and_apply(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+
+        self.assertEqual(len(api.GetExportPresets()), len(export_presets.EXPORT_PRESETS))
+        applied = api.ApplyPreset("archive", {"input_path": "/in/a.jpg"})
+        self.assertTrue(applied["success"])
+        self.assertEqual(applied["request"]["format"], "png")
+        self.assertEqual(api.ApplyPreset("nope", {})["success"], False)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,257 @@
+import unittest
+from dataclasses import replace
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.contracts.settings import default_app_settings
+from backend.domain.formats import (
+    CAPABILITY_KEYS,
+    CONVERT_TARGETS,
+    canonical_format,
+    color_profile_error,
+    dpi_error,
+    format_capabilities,
+    hex_color_error,
+    ico_sizes_error,
+    max_megapixels_error,
+    normalize_ico_sizes,
+    orientation_error,
+    subsampling_error,
+    tiff_compression_error,
+    trim_error,
+)
+
+
+class FormatCapabilitiesTests(unittest.TestCase):
+    def test_every_converter_output_format_has_a_capability_entry(self):
+        formats = format_capabilities()["formats"]
+        for name in ("jpg", "jpeg", "png", "webp", "bmp", "tiff", "tif", "ico", "avif"):
+            self.assertIn(canonical_format(name), formats, name)
+
+    def test_entries_expose_every_feature_flag(self):
+        matrix = format_capabilities()
+        self.assertEqual(matrix["features"], list(CAPABILITY_KEYS))
+        for name, entry in matrix["formats"].items():
+            for key in CAPABILITY_KEYS:
+                self.assertIsInstance(entry[key], bool, f"{name}.{key}")
+
+    def test_known_constraints_drive_ui_controls(self):
+        formats = format_capabilities()["formats"]
+        self.assertFalse(formats["jpg"]["alpha"])
+        self.assertTrue(formats["jpg"]["quality"])
+        self.assertFalse(formats["png"]["quality"])
+        self.assertTrue(formats["png"]["lossless"])
+        self.assertTrue(formats["tiff"]["multi_page"])
+
+    def test_result_is_a_copy_not_the_shared_table(self):
+        first = format_capabilities()
+        first["formats"]["jpg"]["extensions"].append(".bogus")
+        self.assertNotIn(".bogus", format_capabilities()["formats"]["jpg"]["extensions"])
+
+    def test_desktop_api_returns_matrix(self):
+        api = desktop_api.DesktopAPI()
+        self.assertEqual(api.GetFormatCapabilities(), format_capabilities())
+
+
+    def test_convert_rejects_unsupported_target_before_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.heic", "format": "heic"})
+
+        engine.assert_not_called()
+        self.assertFalse(result["success"])
+        self.assertTrue(result["error"].startswith("[UNSUPPORTED_FORMAT]"))
+
+    def test_convert_batch_keeps_order_when_some_targets_are_unsupported(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(
+            desktop_api, "execute_engine_batch", return_value=[{"success": True, "output_path": "b.avif"}]
+        ) as batch, mock.patch.object(desktop_api, "notify_batch_done"):
+            results = api.convert_batch(
+                [
+                    {"input_path": "a.png", "output_path": "a.pdf", "format": "pdf"},
+                    {"input_path": "b.png", "output_path": "b.avif", "format": "avif"},
+                ]
+            )
+
+        self.assertEqual([item["format"] for item in batch.call_args[0][1]], ["avif"])
+        self.assertTrue(results[0]["error"].startswith("[UNSUPPORTED_FORMAT]"))
+        self.assertTrue(results[1]["success"])
+
+    def test_convert_targets_have_capability_entries(self):
+        formats = format_capabilities()["formats"]
+        for name in CONVERT_TARGETS:
+            self.assertIn(name, formats)
+        self.assertTrue(formats["avif"]["lossless"])
+
+    def test_convert_rejects_bad_background_color_before_engine(self):
+        self.assertEqual(hex_color_error("", "background_color"), "")
+        self.assertEqual(hex_color_error("#fff", "background_color"), "")
+        self.assertEqual(hex_color_error("#1A2b3C", "background_color"), "")
+        self.assertTrue(hex_color_error("#12345", "background_color").startswith("[BAD_INPUT]"))
+        self.assertTrue(hex_color_error("white", "background_color").startswith("[BAD_INPUT]"))
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "background_color": "#zzz"})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT]"))
+
+        with mock.patch.object(desktop_api, "execute_engine_batch", return_value=[{"success": True}]) as batch:
+            results = api.convert_batch(
+                [
+                    {"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "background_color": "nope"},
+                    {"input_path": "b.png", "output_path": "b.jpg", "format": "jpg", "background_color": "#000000"},
+                ]
+            )
+        self.assertEqual([item["background_color"] for item in batch.call_args[0][1]], ["#000000"])
+        self.assertTrue(results[0]["error"].startswith("[BAD_INPUT]"))
+        self.assertTrue(results[1]["success"])
+
+    def test_convert_rejects_unknown_subsampling_before_engine(self):
+        for value in ("", "4:4:4", "4:2:2", "4:2:0"):
+            self.assertEqual(subsampling_error(value), "", value)
+        self.assertIn("expected one of 4:4:4, 4:2:2, 4:2:0", subsampling_error("4:1:1"))
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "subsampling": "444"})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid subsampling"))
+
+    def test_convert_rejects_unknown_tiff_compression_before_engine(self):
+        for value in ("", None, "none", "LZW", "deflate", "packbits"):
+            self.assertEqual(tiff_compression_error(value), "", value)
+        self.assertIn("expected one of none, lzw, deflate, packbits", tiff_compression_error("jpeg"))
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.tif", "format": "tiff", "tiff_compression": "zip"})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid tiff_compression"))
+
+    def test_convert_rejects_bad_pad_color_before_engine(self):
+        for value in ("", None, "#fff", "00ff00"):
+            self.assertEqual(hex_color_error(value, "pad_color"), "", value)
+        self.assertIn("expected #RRGGBB", hex_color_error("transparent", "pad_color"))
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.png", "format": "png", "resize_mode": "pad", "pad_color": "#12"})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid pad_color"))
+
+    def test_convert_rejects_bad_trim_options_before_engine(self):
+        for borders, tolerance in ((None, None), (True, 0), (False, 255), (True, "")):
+            self.assertEqual(trim_error(borders, tolerance), "", (borders, tolerance))
+        self.assertIn("expected true or false", trim_error("yes", 0))
+        for tolerance in (-1, 256, 3.5, "10", True):
+            self.assertIn("expected an integer 0-255", trim_error(True, tolerance), tolerance)
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "trim_borders": True, "trim_tolerance": 300})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid trim_tolerance"))
+
+    def test_convert_validates_and_normalizes_ico_sizes(self):
+        for value in (None, "", [], [1, 16, 256]):
+            self.assertEqual(ico_sizes_error(value), "", value)
+        self.assertIn("at most 256x256", ico_sizes_error([16, 512]))
+        for value in ([0], [True], [16.0], "16"):
+            self.assertTrue(ico_sizes_error(value).startswith("[BAD_INPUT]"), value)
+        self.assertEqual(normalize_ico_sizes([48, 16, 48, 32]), [16, 32, 48])
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.ico", "format": "ico", "icoSizes": [16, 1024]})
+        engine.assert_not_called()
+        self.assertIn("ICO size 1024", result["error"])
+
+        with mock.patch.object(api, "_run_engine", return_value={"success": True}) as run:
+            api.convert({"input_path": "a.png", "output_path": "a.ico", "format": "ico", "icoSizes": [256, 16, 16]})
+        payload = run.call_args.args[1]
+        self.assertEqual(payload["ico_sizes"], [16, 256])
+        self.assertNotIn("icoSizes", payload)
+
+    def test_convert_applies_max_megapixels_setting_unless_requested(self):
+        for value in (None, "", 1, 256):
+            self.assertEqual(max_megapixels_error(value), "", value)
+        for value in (0, -1, 2.5, "256", True):
+            self.assertTrue(max_megapixels_error(value).startswith("[BAD_INPUT]"), value)
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "max_megapixels": 0})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid max_megapixels"))
+
+        settings = replace(default_app_settings(), max_megapixels=64)
+        with mock.patch.object(api, "_settings", return_value=settings), mock.patch.object(
+            api, "_run_engine_batch", side_effect=lambda _module, items, _settings: [{"success": True} for _ in items]
+        ) as run:
+            api.convert_batch(
+                [
+                    {"input_path": "a.png", "output_path": "a.jpg", "format": "jpg"},
+                    {"input_path": "b.png", "output_path": "b.jpg", "format": "jpg", "max_megapixels": 512},
+                ]
+            )
+        self.assertEqual([item["max_megapixels"] for item in run.call_args.args[1]], [64, 512])
+
+    def test_convert_rejects_out_of_range_dpi_before_engine(self):
+        for value in (None, "", 0, 72, 10000):
+            self.assertEqual(dpi_error(value), "", value)
+        for value in (-1, 10001, 300.5, "300", True):
+            self.assertTrue(dpi_error(value).startswith("[BAD_INPUT]"), value)
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert({"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "dpi": -10})
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid dpi"))
+
+    def test_set_orientation_validates_range_before_engine(self):
+        for value in range(1, 9):
+            self.assertEqual(orientation_error(value), "", value)
+        for value in (None, 0, 9, 6.0, "6", True):
+            self.assertTrue(orientation_error(value).startswith("[BAD_INPUT]"), value)
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            rejected = api.SetOrientation({"input_path": "a.jpg", "overwrite": True, "orientation": 0})
+            engine.assert_not_called()
+            api.SetOrientation({"input_path": "a.jpg", "overwrite": True, "orientation": 8})
+        self.assertTrue(rejected["error"].startswith("[BAD_INPUT] Invalid orientation"))
+        module_name, payload = engine.call_args.args[:2]
+        self.assertEqual((module_name, payload["action"], payload["orientation"]), ("metadata_tool", "set_orientation", 8))
+
+    def test_convert_rejects_non_boolean_profile_flags_before_engine(self):
+        for keep_profile, keep_metadata in ((None, None), (True, False), (False, True), (False, False)):
+            self.assertEqual(color_profile_error(keep_profile, keep_metadata), "")
+        self.assertIn("keep_metadata", color_profile_error(True, "yes"))
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.convert(
+                {"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "keep_color_profile": "false"}
+            )
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[BAD_INPUT] Invalid keep_color_profile"))
+
+    def test_assign_color_profile_validates_before_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            bad_mode = api.assign_color_profile({"input_path": "a.png", "output_path": "b.png", "profile": "sRGB", "mode": "tag"})
+            missing = api.assign_color_profile({"input_path": "a.png", "output_path": "b.png", "profile": "/nope/missing.icc"})
+        engine.assert_not_called()
+        self.assertTrue(bad_mode["error"].startswith("[BAD_INPUT]"))
+        self.assertTrue(missing["error"].startswith("[NOT_FOUND]"))
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            api.assign_color_profile({"input_path": "a.png", "output_path": "b.png", "profile": "AdobeRGB", "mode": "Convert"})
+        request = engine.call_args[0][1]
+        self.assertEqual((request["action"], request["mode"]), ("color_profile", "convert"))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,111 @@
+import csv
+import errno
+import json
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.info_report import INFO_REPORT_COLUMNS
+
+
+def _fake_info_batch(_module_name, payloads, _settings, _manager, cancellable=True):
+    results = []
+    for payload in payloads:
+        name = Path(payload["input_path"]).name
+        if name.startswith("broken"):
+            results.append({"success": False, "error": "[INTERNAL] cannot identify image"})
+            continue
+        results.append(
+            {
+                "success": True,
+                "input_path": payload["input_path"],
+                "file_name": name,
+                "format": "JPEG",
+                "width": 640,
+                "height": 480,
+                "file_size": 2048,
+                "has_gps": name == "pinned.jpg",
+                "latitude": -33.8598 if name == "pinned.jpg" else 0.0,
+                "longitude": 151.2083 if name == "pinned.jpg" else 0.0,
+                "metadata": {"exifread": {"EXIF DateTimeOriginal": "2026:05:01 09:30:00"}},
+            }
+        )
+    return results
+
+
+class InfoReportTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.addCleanup(self.temp_dir.cleanup)
+        self.root = Path(self.temp_dir.name)
+        self.inputs = [str(self.root / name) for name in ("pinned.jpg", "plain.jpg", "broken.jpg")]
+        self.api = desktop_api.DesktopAPI(task_manager=desktop_api.TaskManager())
+        patcher = mock.patch("backend.application.image_ops.execute_engine_batch", side_effect=_fake_info_batch)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_csv_report_has_the_header_and_one_row_per_input(self):
+        output = self.root / "reports" / "folder.csv"
+
+        result = self.api.ExportInfoReport({"input_paths": self.inputs, "output_path": str(output)})
+
+        self.assertTrue(result["success"], result)
+        self.assertEqual((result["format"], result["row_count"], result["failed_count"]), ("csv", 3, 1))
+        with open(output, encoding="utf-8-sig", newline="") as handle:
+            reader = csv.reader(handle)
+            header = next(reader)
+            rows = list(reader)
+        self.assertEqual(tuple(header), INFO_REPORT_COLUMNS)
+        self.assertEqual(len(rows), 3)
+        first = dict(zip(header, rows[0]))
+        self.assertEqual((first["file_name"], first["width"], first["has_gps"]), ("pinned.jpg", "640", "True"))
+        self.assertEqual((first["latitude"], first["datetime"]), ("-33.8598", "2026-05-01T09:30:00"))
+        self.assertEqual(dict(zip(header, rows[1]))["latitude"], "")
+        self.assertTrue(dict(zip(header, rows[2]))["error"].startswith("[INTERNAL]"))
+        self.assertEqual(sorted(path.name for path in output.parent.iterdir()), ["folder.csv"])
+
+    def test_json_report_is_picked_from_the_extension(self):
+        output = self.root / "folder.json"
+
+        result = self.api.ExportInfoReport({"input_paths": self.inputs[:2], "output_path": str(output)})
+
+        self.assertEqual(result["format"], "json")
+        rows = json.loads(output.read_text(encoding="utf-8"))
+        self.assertEqual([row["file_name"] for row in rows], ["pinned.jpg", "plain.jpg"])
+        self.assertEqual(list(rows[0]), list(INFO_REPORT_COLUMNS))
+
+    def test_a_briefly_locked_report_is_replaced_once_the_lock_clears(self):
+        output = self.root / "folder.csv"
+        output.write_text("old", encoding="utf-8")
+        real_replace = os.replace
+        busy = [OSError(errno.EBUSY, "busy")]
+
+        def replace(src, dst):
+            if busy:
+                raise busy.pop()
+            return real_replace(src, dst)
+
+        with mock.patch.object(os, "replace", side_effect=replace), mock.patch.dict(
+            os.environ, {"IMAGEFLOW_LOCK_RETRY_DELAY_MS": "0"}
+        ):
+            result = self.api.ExportInfoReport({"input_paths": self.inputs, "output_path": str(output)})
+
+        self.assertTrue(result["success"], result)
+        self.assertEqual(busy, [])
+        self.assertTrue(output.read_text(encoding="utf-8-sig").startswith("path,"))
+        self.assertEqual(sorted(path.name for path in self.root.iterdir()), ["folder.csv"])
+
+    def test_bad_requests_write_nothing(self):
+        output = self.root / "folder.xlsx"
+
+        self.assertTrue(self.api.ExportInfoReport({"input_paths": self.inputs, "output_path": str(output)})["error"].startswith("[BAD_INPUT] format"))
+        self.assertTrue(self.api.ExportInfoReport({"input_paths": [], "output_path": str(output)})["error"].startswith("[BAD_INPUT]"))
+        self.assertTrue(self.api.ExportInfoReport({"input_paths": self.inputs})["error"].startswith("[BAD_INPUT]"))
+        self.assertFalse(output.exists())
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,87 @@
+import os
+import tempfile
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.domain.formats import input_file_error, sniff_image_format
+
+# SOI, a 16-byte APP0 segment, then a complete SOS header and a few bytes of scan data.
+JPEG_HEADER = b"\xff\xd8" + b"\xff\xe0\x00\x10JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"
+JPEG_SCAN = b"\xff\xda\x00\x08\x01\x01\x00\x00\x3f\x00" + b"\x12\x34\xff\xd9"
+
+
+class InputPrecheckTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _file(self, name, data):
+        path = os.path.join(self.temp_dir.name, name)
+        with open(path, "wb") as handle:
+            handle.write(data)
+        return path
+
+    def test_sniff_image_format_reads_signatures(self):
+        self.assertEqual(sniff_image_format(b"\x89PNG\r\n\x1a\n...."), "png")
+        self.assertEqual(sniff_image_format(JPEG_HEADER), "jpg")
+        self.assertEqual(sniff_image_format(b"RIFF\x00\x00\x00\x00WEBPVP8 "), "webp")
+        self.assertEqual(sniff_image_format(b"\x00\x00\x00\x1cftypavif"), "avif")
+        self.assertEqual(sniff_image_format(b"<svg"), "")
+
+    def test_zero_byte_file_is_empty(self):
+        self.assertTrue(input_file_error(self._file("empty.png", b"")).startswith("[EMPTY_FILE]"))
+
+    def test_truncated_jpeg_header_is_rejected(self):
+        truncated = self._file("cut.jpg", JPEG_HEADER[:12])
+        no_scan = self._file("no-scan.jpg", JPEG_HEADER)
+        complete = self._file("ok.jpg", JPEG_HEADER + JPEG_SCAN)
+
+        self.assertTrue(input_file_error(truncated).startswith("[TRUNCATED_OR_CORRUPT]"))
+        self.assertTrue(input_file_error(no_scan).startswith("[TRUNCATED_OR_CORRUPT]"))
+        self.assertEqual(input_file_error(complete), "")
+
+    def test_short_png_and_webp_headers_are_rejected(self):
+        png = self._file("cut.png", b"\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIH")
+        webp = self._file("cut.webp", b"RIFF\x00\x10\x00\x00WEBPVP8 " + b"\x00" * 8)
+
+        self.assertTrue(input_file_error(png).startswith("[TRUNCATED_OR_CORRUPT]"))
+        self.assertTrue(input_file_error(webp).startswith("[TRUNCATED_OR_CORRUPT]"))
+
+    def test_unknown_or_missing_inputs_are_left_to_the_engine(self):
+        self.assertEqual(input_file_error(self._file("note.svg", b"<svg/>")), "")
+        self.assertEqual(input_file_error(os.path.join(self.temp_dir.name, "missing.png")), "")
+
+    def test_batch_fails_bad_inputs_without_running_them(self):
+        empty = self._file("empty.png", b"")
+        truncated = self._file("cut.jpg", JPEG_HEADER[:12])
+        good = self._file("ok.jpg", JPEG_HEADER + JPEG_SCAN)
+        payloads = [{"input_path": path, "output_path": path + ".out.png"} for path in (empty, truncated, good)]
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+
+        def fake_batch(_module, items, *_args):
+            return [{"success": True, "input_path": item["input_path"]} for item in items]
+
+        with mock.patch.object(desktop_api, "execute_engine_batch", side_effect=fake_batch) as batch:
+            results = api.convert_batch([{**payload, "format": "png"} for payload in payloads])
+
+        self.assertEqual([item["input_path"] for item in batch.call_args.args[1]], [good])
+        self.assertTrue(results[0]["error"].startswith("[EMPTY_FILE]"))
+        self.assertTrue(results[1]["error"].startswith("[TRUNCATED_OR_CORRUPT]"))
+        self.assertTrue(results[2]["success"])
+
+    def test_single_operation_fails_fast(self):
+        empty = self._file("empty.jpg", b"")
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            result = api.compress({"input_path": empty, "output_path": empty + ".out.jpg"})
+
+        engine.assert_not_called()
+        self.assertTrue(result["error"].startswith("[EMPTY_FILE]"))
+
+
+if __name__ == "__main__":
+    unittest.main()
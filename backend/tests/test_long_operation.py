@@ -0,0 +1,72 @@
+import os
+import threading
+import time
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.long_operation import (
+    DEFAULT_LONG_OPERATION_SECONDS,
+    long_operation_seconds,
+    watch_long_operation,
+)
+
+
+class LongOperationNoticeTests(unittest.TestCase):
+    def tearDown(self):
+        os.environ.pop("IMAGEFLOW_LONG_OPERATION_SECONDS", None)
+
+    def test_threshold_reads_env_and_ignores_invalid_values(self):
+        os.environ["IMAGEFLOW_LONG_OPERATION_SECONDS"] = "2.5"
+        self.assertEqual(long_operation_seconds(), 2.5)
+        for raw in ("abc", "0", "-3"):
+            os.environ["IMAGEFLOW_LONG_OPERATION_SECONDS"] = raw
+            self.assertEqual(long_operation_seconds(), DEFAULT_LONG_OPERATION_SECONDS)
+
+    def test_slow_work_gets_one_notice_and_a_done_notice_when_it_ends(self):
+        fired = threading.Event()
+        notices = []
+
+        def notify(detail):
+            notices.append(detail)
+            fired.set()
+
+        with watch_long_operation("pdf_generator", 7, notify, threshold=0.01):
+            self.assertTrue(fired.wait(2))
+            self.assertEqual(len(notices), 1)
+
+        self.assertEqual(notices[0]["type"], "long_operation")
+        self.assertEqual(notices[0]["operation"], "pdf_generator")
+        self.assertEqual(notices[0]["task_id"], 7)
+        self.assertEqual([notice["done"] for notice in notices], [False, True])
+
+    def test_fast_work_never_notifies(self):
+        notices = []
+        with watch_long_operation("converter", 1, notices.append, threshold=0.2):
+            pass
+        time.sleep(0.3)
+        self.assertEqual(notices, [])
+
+    def test_desktop_api_emits_notice_without_failing_the_operation(self):
+        os.environ["IMAGEFLOW_LONG_OPERATION_SECONDS"] = "0.01"
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+
+        def slow_engine(*_args, **_kwargs):
+            time.sleep(0.2)
+            return {"success": True}
+
+        with mock.patch.object(desktop_api, "execute_engine", side_effect=slow_engine), mock.patch.object(
+            desktop_api, "notify_long_operation"
+        ) as notify:
+            result = api.generate_pdf({"images": [], "output_path": "out.pdf"})
+
+        self.assertEqual(result, {"success": True})
+        notices = [call.args[0] for call in notify.call_args_list]
+        self.assertEqual(
+            [(notice["operation"], notice["done"]) for notice in notices],
+            [("pdf_generator", False), ("pdf_generator", True)],
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,173 @@
+import os
+import tempfile
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.manifest import load_manifest, manifest_groups
+
+
+class ManifestTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _write(self, name, text):
+        path = os.path.join(self.temp_dir.name, name)
+        with open(path, "w", encoding="utf-8") as handle:
+            handle.write(text)
+        return path
+
+    def test_json_array_keeps_entry_lines_and_resolves_relative_paths(self):
+        path = self._write(
+            "jobs.json",
+            '[\n  {"type": "convert", "input": "a.png", "output": "out/a.webp", "params": {"quality": 80}},\n'
+            '\n  {"type": "compress", "input": "/abs/b.jpg", "output": "/abs/b.min.jpg"}\n]\n',
+        )
+
+        entries, errors = load_manifest(path)
+
+        self.assertEqual(errors, [])
+        self.assertEqual([entry["line"] for entry in entries], [2, 4])
+        convert = entries[0]["payload"]
+        self.assertEqual(convert["format"], "webp")
+        self.assertEqual(convert["quality"], 80)
+        self.assertEqual(convert["input_path"], os.path.join(os.path.realpath(self.temp_dir.name), "a.png"))
+        self.assertEqual(manifest_groups(entries), [("convert", [0]), ("compress", [1])])
+
+    def test_schema_errors_are_reported_per_line(self):
+        path = self._write(
+            "jobs.jsonl",
+            '{"type": "convert", "input": "a.png", "output": "a.jpg"}\n'
+            '# comments are skipped\n'
+            '{"type": "resize", "input": "b.png", "output": "b.png"}\n'
+            '{"type": "filter", "input": "c.png"}\n'
+            '{"type": "adjust", "input": "d.png", "output": "d.png", "params": [1]}\n',
+        )
+
+        entries, errors = load_manifest(path)
+
+        self.assertEqual(len(entries), 1)
+        self.assertEqual(len(errors), 3)
+        self.assertTrue(errors[0].startswith("[BAD_INPUT] line 3: unknown operation 'resize'"))
+        self.assertIn("line 4: output is required", errors[1])
+        self.assertIn("line 5: params must be an object", errors[2])
+
+    def test_invalid_json_reports_the_manifest_line(self):
+        path = self._write("jobs.jsonl", '{"type": "convert", "input": "a", "output": "b"}\n{"type": \n')
+
+        _entries, errors = load_manifest(path)
+
+        self.assertTrue(errors[0].startswith("[BAD_INPUT] line 2: invalid JSON"))
+
+    def test_csv_extra_columns_become_typed_params(self):
+        path = self._write(
+            "jobs.csv",
+            "type,input,output,quality,keep_metadata,format\n"
+            "convert,a.png,a.jpg,85,true,\n"
+            "watermark,b.png,b.png,,,\n",
+        )
+
+        entries, errors = load_manifest(path)
+
+        self.assertEqual(errors, [])
+        self.assertEqual([entry["line"] for entry in entries], [2, 3])
+        payload = entries[0]["payload"]
+        self.assertEqual((payload["quality"], payload["keep_metadata"], payload["format"]), (85, True, "jpg"))
+        self.assertNotIn("quality", entries[1]["payload"])
+
+    def test_missing_and_unsupported_manifests(self):
+        self.assertTrue(load_manifest(os.path.join(self.temp_dir.name, "none.json"))[1][0].startswith("[NOT_FOUND]"))
+        self.assertTrue(load_manifest(self._write("jobs.txt", "x"))[1][0].startswith("[UNSUPPORTED_FORMAT]"))
+        self.assertTrue(load_manifest(self._write("empty.jsonl", "\n"))[1][0].startswith("[BAD_INPUT]"))
+
+    def test_run_manifest_dispatches_batches_and_keeps_manifest_order(self):
+        path = self._write(
+            "jobs.jsonl",
+            '{"type": "compress", "input": "a.jpg", "output": "a.min.jpg"}\n'
+            '{"type": "convert", "input": "b.png", "output": "b.jpg"}\n'
+            '{"type": "compress", "input": "c.jpg", "output": "c.min.jpg"}\n',
+        )
+        calls = []
+
+        def fake_batch(module_name, payloads, *_args):
+            calls.append((module_name, len(payloads)))
+            return [{"success": True, "output_path": item["output_path"]} for item in payloads]
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine_batch", side_effect=fake_batch):
+            response = api.run_manifest(path)
+
+        self.assertTrue(response["success"])
+        self.assertEqual(calls, [("compressor", 1), ("converter", 1), ("compressor", 1)])
+        self.assertEqual([item["line"] for item in response["results"]], [1, 2, 3])
+        self.assertEqual([item["operation"] for item in response["results"]], ["compress", "convert", "compress"])
+        self.assertEqual(response["succeeded"], 3)
+
+    def test_run_manifest_runs_a_chained_pipeline_in_manifest_order(self):
+        path = self._write(
+            "jobs.jsonl",
+            '{"type": "compress", "input": "a.jpg", "output": "a.min.jpg"}\n'
+            '{"type": "compress", "input": "b.jpg", "output": "b.min.jpg"}\n'
+            '{"type": "convert", "input": "a.min.jpg", "output": "a.png"}\n'
+            '{"type": "compress", "input": "a.png", "output": "a.min.png"}\n',
+        )
+        written: set[str] = set()
+        calls = []
+
+        def fake_batch(module_name, payloads, *_args):
+            calls.append((module_name, [os.path.basename(item["input_path"]) for item in payloads]))
+            results = []
+            for item in payloads:
+                name = os.path.basename(item["input_path"])
+                # Sources a.jpg/b.jpg exist up front; everything else must have been written first.
+                if name not in ("a.jpg", "b.jpg") and item["input_path"] not in written:
+                    results.append({"success": False, "error": f"[NOT_FOUND] {item['input_path']}"})
+                    continue
+                written.add(item["output_path"])
+                results.append({"success": True, "output_path": item["output_path"]})
+            return results
+
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine_batch", side_effect=fake_batch):
+            response = api.run_manifest(path)
+
+        self.assertEqual(
+            calls,
+            [("compressor", ["a.jpg", "b.jpg"]), ("converter", ["a.min.jpg"]), ("compressor", ["a.png"])],
+        )
+        self.assertEqual(response["succeeded"], 4)
+
+    def test_run_manifest_stops_later_batches_after_cancel(self):
+        path = self._write(
+            "jobs.jsonl",
+            '{"type": "compress", "input": "a.jpg", "output": "a.min.jpg"}\n'
+            '{"type": "convert", "input": "b.png", "output": "b.jpg"}\n',
+        )
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(
+            desktop_api,
+            "execute_engine_batch",
+            return_value=[{"success": False, "error": "[PY_CANCELLED] operation cancelled"}],
+        ) as batch:
+            response = api.run_manifest(path)
+
+        self.assertEqual(batch.call_count, 1)
+        self.assertEqual(response["failed"], 2)
+        self.assertTrue(response["results"][1]["error"].startswith("[PY_CANCELLED]"))
+
+    def test_run_manifest_returns_all_errors_without_running(self):
+        path = self._write("jobs.jsonl", '{"type": "nope"}\n{"input": "a"}\n')
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine_batch") as batch:
+            response = api.run_manifest(path)
+
+        batch.assert_not_called()
+        self.assertFalse(response["success"])
+        self.assertEqual(len(response["errors"]), 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
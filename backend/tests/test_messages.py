@@ -0,0 +1,20 @@
+import unittest
+
+from backend.contracts.messages import MESSAGES, SUPPORTED_LANGUAGES, message
+
+
+class MessageCatalogTests(unittest.TestCase):
+    def test_every_message_has_all_supported_languages(self):
+        for key, entry in MESSAGES.items():
+            for lang in SUPPORTED_LANGUAGES:
+                self.assertTrue(entry.get(lang), f"{key} missing {lang}")
+
+    def test_message_resolves_language_and_falls_back(self):
+        self.assertEqual(message("batch_failed", "en"), "Batch processing failed")
+        self.assertEqual(message("batch_failed"), "批处理失败")
+        self.assertEqual(message("batch_failed", "fr"), "批处理失败")
+        self.assertEqual(message("no_such_key", "en"), "no_such_key")
+
+
+if __name__ == "__main__":
+    unittest.main()
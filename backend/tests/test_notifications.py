@@ -0,0 +1,40 @@
+import subprocess
+import unittest
+from unittest import mock
+
+from backend.infrastructure import notifications
+
+
+class NotificationTests(unittest.TestCase):
+    def test_notify_returns_false_when_no_notifier_is_available(self):
+        with mock.patch.object(notifications.sys, "platform", "linux"), mock.patch.object(
+            notifications.shutil, "which", return_value=None
+        ), mock.patch.object(notifications.subprocess, "run") as run:
+            self.assertFalse(notifications.notify("title", "body"))
+        run.assert_not_called()
+
+    def test_notify_swallows_launch_failures(self):
+        with mock.patch.object(notifications.sys, "platform", "linux"), mock.patch.object(
+            notifications.shutil, "which", return_value="/usr/bin/notify-send"
+        ), mock.patch.object(notifications.subprocess, "run", side_effect=subprocess.TimeoutExpired("notify-send", 5)):
+            self.assertFalse(notifications.notify("title", "body"))
+
+    def test_macos_notification_escapes_quotes(self):
+        with mock.patch.object(notifications.sys, "platform", "darwin"):
+            args, _env = notifications._notify_command('say "hi"', "done")
+
+        self.assertEqual(args[:2], ["osascript", "-e"])
+        self.assertIn('\\"hi\\"', args[2])
+
+    def test_windows_toast_passes_text_through_environment(self):
+        with mock.patch.object(notifications.sys, "platform", "win32"), mock.patch.object(
+            notifications.shutil, "which", return_value="powershell.exe"
+        ):
+            args, env = notifications._notify_command("标题", "成功 2 个")
+
+        self.assertNotIn("标题", " ".join(args))
+        self.assertEqual(env, {"IMAGEFLOW_NOTIFY_TITLE": "标题", "IMAGEFLOW_NOTIFY_BODY": "成功 2 个"})
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,172 @@
+import json
+import os
+import tempfile
+import time
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import image_ops
+from backend.application.task_manager import TaskManager
+from backend.contracts.settings import default_app_settings
+from backend.infrastructure import operation_log
+from backend.infrastructure.operation_log import OperationLog, leftover_temp_files
+
+
+class OperationLogTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+        self.path = self.root / operation_log.OPERATION_LOG_NAME
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def _open(self):
+        log = OperationLog(self.path)
+        log.open()
+        self.addCleanup(log.close)
+        return log
+
+    def test_unfinished_intents_survive_a_killed_session(self):
+        log = self._open()
+        done = log.begin("converter", {"input_path": "a.png", "output_path": "a.jpg"})
+        log.begin("converter", {"input_path": "b.png", "output_path": "b.jpg"})
+        log.finish(done, {"success": True})
+        # No close(): the process was killed; a half-written record ends the file.
+        with open(self.path, "a", encoding="utf-8") as handle:
+            handle.write('{"kind": "result", "id": "')
+
+        interrupted = self._open().interrupted()
+
+        self.assertEqual([record["payload"]["input_path"] for record in interrupted], ["b.png"])
+        lines = self.path.read_text(encoding="utf-8").splitlines()
+        self.assertEqual([json.loads(line)["kind"] for line in lines], ["intent"])
+
+    def test_resolved_intents_are_not_offered_again(self):
+        log = self._open()
+        op_id = log.begin("compressor", {"input_path": "a.png", "output_path": "b.png"})
+        log.close()
+
+        reopened = self._open()
+        self.assertEqual([record["id"] for record in reopened.resolve([op_id, "unknown"])], [op_id])
+        self.assertEqual(reopened.interrupted(), [])
+        reopened.close()
+
+        self.assertEqual(self._open().interrupted(), [])
+
+    def test_passwords_are_never_written_to_the_journal(self):
+        log = self._open()
+        log.begin("pdf_generator", {"image_paths": ["a.png"], "output_path": "a.pdf", "user_password": "hunter2", "owner_password": ""})
+        log.close()
+
+        self.assertNotIn("hunter2", self.path.read_text(encoding="utf-8"))
+        record = self._open().interrupted()[0]
+        self.assertEqual(record["redacted"], ["user_password"])
+        self.assertNotIn("owner_password", record["payload"])
+
+    def test_leftover_temp_files_are_limited_to_fresh_files_beside_the_output(self):
+        old = self.root / "imageflow_0ld_name.jpg"
+        old.write_bytes(b"x")
+        os.utime(old, (time.time() - 3600, time.time() - 3600))
+        fresh = self.root / "imageflow_k2x9_q7m.jpg"
+        fresh.write_bytes(b"x")
+        (self.root / "imageflow_k2x9_q7m.png").write_bytes(b"x")
+        (self.root / "photo.jpg").write_bytes(b"x")
+        # User files that only share the prefix are never touched.
+        (self.root / "imageflow_holiday_export.jpg").write_bytes(b"x")
+        (self.root / "imageflow_logo.jpg").write_bytes(b"x")
+        record = {"payload": {"output_path": str(self.root / "out.jpg")}, "started_at": time.time() - 60}
+
+        self.assertEqual(leftover_temp_files(record), [str(fresh)])
+
+    def test_engine_items_are_journaled_only_when_they_write_an_output(self):
+        log = self._open()
+        results = iter([{"success": True}, {"success": False, "error": "boom"}, {"success": True}])
+        payloads = [
+            {"input_path": "a.png", "output_path": "a.jpg"},
+            {"input_path": "b.png", "output_path": "b.jpg"},
+            {"input_path": "c.png"},
+        ]
+        with mock.patch.object(image_ops, "_pool_disabled", True), mock.patch.object(
+            image_ops, "_invoke_engine_job", side_effect=lambda *_args: next(results)
+        ), mock.patch.object(image_ops, "active_operation_log", return_value=log):
+            image_ops.execute_engine_batch("converter", payloads, default_app_settings(), TaskManager())
+
+        records = [json.loads(line) for line in self.path.read_text(encoding="utf-8").splitlines()]
+        self.assertEqual([record["kind"] for record in records], ["intent", "intent", "result", "result"])
+        self.assertEqual([record.get("error") for record in records[2:]], ["", "boom"])
+
+
+class InterruptedOperationsApiTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+        path = self.root / operation_log.OPERATION_LOG_NAME
+        first = OperationLog(path)
+        first.open()
+        self.first_id = first.begin("converter", {"input_path": "a.png", "output_path": str(self.root / "a.jpg")})
+        self.second_id = first.begin("compressor", {"input_path": "b.png", "output_path": str(self.root / "b.png")})
+        first.close()
+        self.log = OperationLog(path)
+        self.log.open()
+        patcher = mock.patch.object(desktop_api, "active_operation_log", return_value=self.log)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+        self.api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+
+    def tearDown(self):
+        self.log.close()
+        self.temp_dir.cleanup()
+
+    def test_lists_interrupted_items(self):
+        listed = self.api.GetInterruptedOperations()
+
+        self.assertEqual([item["id"] for item in listed], [self.first_id, self.second_id])
+        self.assertEqual(listed[0]["module"], "converter")
+        self.assertEqual(listed[0]["input_path"], "a.png")
+
+    def test_resume_reruns_the_original_requests_per_module(self):
+        with mock.patch.object(
+            self.api, "_run_engine_batch", side_effect=lambda _module, items: [{"success": True} for _ in items]
+        ) as run:
+            result = self.api.ResumeInterruptedOperations([self.first_id])
+
+        run.assert_called_once_with("converter", [{"input_path": "a.png", "output_path": str(self.root / "a.jpg")}])
+        self.assertEqual(result["results"], [{"success": True, "id": self.first_id}])
+        self.assertEqual([item["id"] for item in self.api.GetInterruptedOperations()], [self.second_id])
+
+    def test_resume_refuses_items_whose_passwords_were_not_journaled(self):
+        pdf_id = self.log.begin("pdf_generator", {"image_paths": ["a.png"], "output_path": "a.pdf", "owner_password": "x"})
+        self.log.close()
+        self.log = OperationLog(self.log.path)
+        self.log.open()
+        desktop_api.active_operation_log.return_value = self.log
+
+        with mock.patch.object(self.api, "_run_engine_batch") as run:
+            result = self.api.ResumeInterruptedOperations([pdf_id])
+
+        run.assert_not_called()
+        self.assertEqual(result["results"][0]["id"], pdf_id)
+        self.assertTrue(result["results"][0]["error"].startswith("[BAD_INPUT] owner_password"))
+
+    def test_discard_removes_leftover_temp_files(self):
+        leftover = self.root / "imageflow_tmp1_a2b.png"
+        leftover.write_bytes(b"partial")
+
+        result = self.api.DiscardInterruptedOperations()
+
+        self.assertEqual(result["count"], 2)
+        self.assertEqual(result["removed"], [str(leftover)])
+        self.assertFalse(leftover.exists())
+        self.assertEqual(self.api.GetInterruptedOperations(), [])
+
+    def test_without_a_log_nothing_is_interrupted(self):
+        with mock.patch.object(desktop_api, "active_operation_log", return_value=None):
+            self.assertEqual(self.api.GetInterruptedOperations(), [])
+            self.assertEqual(self.api.ResumeInterruptedOperations(), {"success": True, "results": []})
+
+
+if __name__ == "__main__":
+    unittest.main()
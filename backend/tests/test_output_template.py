@@ -0,0 +1,80 @@
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application.output_template import (
+    expand_for_results,
+    expand_metadata_tokens,
+    has_metadata_tokens,
+    sanitize_token_value,
+)
+
+SAMPLE_INFO = {
+    "success": True,
+    "metadata": {
+        "exifread": {
+            "Image Make": "SONY",
+            "Image Model": "ILCE-7M4",
+            "EXIF DateTimeOriginal": "2024:05:06 07:08:09",
+        },
+        "piexif": {"Exif:LensModel": "FE 24-70mm F2.8 GM II"},
+    },
+}
+
+
+class OutputTemplateTests(unittest.TestCase):
+    def test_metadata_tokens_are_resolved_per_file(self):
+        expanded, warnings = expand_metadata_tokens(
+            "{basename}_{exif:Model}_{taken:YYYY-MM-DD}_{make}_{seq:3}", SAMPLE_INFO, "a.jpg"
+        )
+
+        self.assertEqual(expanded, "{basename}_ILCE-7M4_2024-05-06_SONY_{seq:3}")
+        self.assertEqual(warnings, [])
+
+    def test_missing_tokens_expand_empty_with_a_warning(self):
+        expanded, warnings = expand_metadata_tokens("{basename}_{iso}_{taken}", {"success": True}, "a.png")
+
+        self.assertEqual(expanded, "{basename}__")
+        self.assertEqual(len(warnings), 2)
+        self.assertIn("a.png", warnings[0])
+
+    def test_values_are_sanitized_for_file_names(self):
+        self.assertEqual(sanitize_token_value('Canon EOS R5 / "II"?'), "Canon EOS R5 _ _II_")
+        self.assertEqual(sanitize_token_value("../{seq}"), "..__seq_")
+        self.assertEqual(len(sanitize_token_value("x" * 200)), 64)
+        self.assertFalse(has_metadata_tokens("{prefix}{basename}_{date:YYYYMMDD}"))
+
+    def test_unreadable_file_gets_single_warning(self):
+        result = expand_for_results("{camera}_{lens}", ["/in/a.jpg"], [{"success": False, "error": "[IO_ERROR] boom"}])
+
+        self.assertEqual(result["templates"], ["_"])
+        self.assertEqual(len(result["warnings"][0]), 1)
+        self.assertIn("[IO_ERROR] boom", result["warnings"][0][0])
+
+    def test_api_skips_engine_without_metadata_tokens(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine_batch") as batch:
+            response = api.expand_template_tokens({"template": "{basename}", "input_paths": ["/in/a.jpg"]})
+
+        batch.assert_not_called()
+        self.assertEqual(response["templates"], ["{basename}"])
+
+    def test_api_reads_info_for_each_input(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(
+            desktop_api, "execute_engine_batch", return_value=[SAMPLE_INFO, {"success": True}]
+        ) as batch, mock.patch.object(api, "_settings"):
+            response = api.expand_template_tokens(
+                {"template": "{camera}_{basename}", "input_paths": ["/in/a.jpg", "/in/b.png"]}
+            )
+
+        module_name, payloads = batch.call_args.args[:2]
+        self.assertEqual(module_name, "info_viewer")
+        self.assertEqual([item["action"] for item in payloads], ["get_info", "get_info"])
+        self.assertEqual(response["templates"], ["ILCE-7M4_{basename}", "_{basename}"])
+        self.assertEqual(response["warnings"][0], [])
+        self.assertEqual(len(response["warnings"][1]), 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -2,7 +2,7 @@ import tempfile
 import unittest
 from pathlib import Path
 
-from backend.domain.paths import expand_input_paths, resolve_output_path
+from backend.domain.paths import expand_input_paths, resolve_output_path, sanitize_filename
 
 
 class PathServicesTests(unittest.TestCase):
@@ -33,6 +33,15 @@ class PathServicesTests(unittest.TestCase):
                 ["icon.ico", "sample.avif"],
             )
 
+    def test_expand_input_paths_includes_jpeg_xl_files(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            root = Path(temp_dir)
+            (root / "archive.jxl").write_bytes(b"fake-jxl")
+
+            result = expand_input_paths([str(root)])
+
+            self.assertEqual([item["relative_path"] for item in result["files"]], ["archive.jxl"])
+
     def test_resolve_output_path_appends_suffix_for_conflicts(self):
         with tempfile.TemporaryDirectory() as temp_dir:
             root = Path(temp_dir)
@@ -52,6 +61,28 @@ class PathServicesTests(unittest.TestCase):
 
             self.assertEqual(Path(resolved).name, "Logo_01.png")
 
+    def test_sanitize_filename_replaces_windows_illegal_characters(self):
+        self.assertEqual(sanitize_filename('2024:05:06 <cam>?*.jpg', windows=True), "2024_05_06 _cam_.jpg")
+        self.assertEqual(sanitize_filename("a\\b/c|d\x01.png", windows=True), "a_b_c_d_.png")
+        self.assertEqual(sanitize_filename("trailing. . ", windows=True), "trailing")
+
+    def test_sanitize_filename_prefixes_windows_reserved_names(self):
+        for name in ("CON", "nul", "Com1.png", "LPT9.tar.gz", "aux "):
+            self.assertTrue(sanitize_filename(name, windows=True).startswith("_"), name)
+        for name in ("CONSOLE.png", "com10", "nullable"):
+            self.assertFalse(sanitize_filename(name, windows=True).startswith("_"), name)
+
+    def test_sanitize_filename_posix_only_replaces_separators(self):
+        self.assertEqual(sanitize_filename("a:b?/c\x00d", windows=False), "a:b?_c_d")
+        self.assertEqual(sanitize_filename("CON", windows=False), "CON")
+        self.assertEqual(sanitize_filename("..", windows=False), "")
+
+    def test_sanitize_filename_caps_length_and_keeps_extension(self):
+        name = sanitize_filename("x" * 300 + ".jpeg", windows=True)
+
+        self.assertEqual(len(name), 255)
+        self.assertTrue(name.endswith(".jpeg"))
+
 
 if __name__ == "__main__":
     unittest.main()
@@ -0,0 +1,24 @@
+import unittest
+
+from backend.domain.perceptual_hash import hamming_distance
+
+
+class HammingDistanceTests(unittest.TestCase):
+    def test_counts_the_differing_bits(self):
+        self.assertEqual(hamming_distance("ffffffffffffffff", "ffffffffffffffff"), 0)
+        self.assertEqual(hamming_distance("0000000000000000", "0000000000000001"), 1)
+        self.assertEqual(hamming_distance("f0f0f0f0f0f0f0f0", "0f0f0f0f0f0f0f0f"), 64)
+        self.assertEqual(hamming_distance("00FF00ff00ff00ff", "00ff00ff00ff00fe"), 1)
+
+    def test_leading_zero_digits_still_count_toward_the_length(self):
+        self.assertEqual(hamming_distance("000000000000000f", "800000000000000f"), 1)
+
+    def test_rejects_missing_non_hex_and_mismatched_hashes(self):
+        for a, b in (("", "00"), ("zz", "00"), ("00ff", "00ff00"), (None, "00")):
+            with self.subTest(a=a, b=b):
+                with self.assertRaises(ValueError):
+                    hamming_distance(a, b)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -1,3 +1,5 @@
+import base64
+import io
 import os
 import tempfile
 import unittest
@@ -8,6 +10,8 @@ from PIL import Image
 
 from backend.app import create_app
 from backend.application import preview as preview_module
+from backend.application import size_curve
+from backend.application import watermark_preview
 
 
 class PerfPathTests(unittest.TestCase):
@@ -18,6 +22,7 @@ class PerfPathTests(unittest.TestCase):
     def tearDown(self):
         os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None)
         os.environ.pop("IMAGEFLOW_PREVIEW_ISOLATE", None)
+        os.environ.pop("IMAGEFLOW_PREVIEW_MAX_BYTES", None)
         self.temp_dir.cleanup()
 
     def _png(self, name="a.png"):
@@ -64,6 +69,92 @@ class PerfPathTests(unittest.TestCase):
             isolated.assert_called_once()
         self.assertTrue(result.get("success"))
 
+    def test_preview_mode_never_skips_without_decoding(self):
+        path = self._png("never.png")
+        with mock.patch.object(preview_module, "load_engine_module") as loader:
+            result = preview_module.build_image_preview_smart(path, "never")
+            loader.assert_not_called()
+        self.assertEqual(result, {"success": False, "error": "PREVIEW_SKIPPED"})
+
+    def test_preview_mode_always_fast_ignores_byte_budget(self):
+        path = self._png("fast.png")
+        os.environ["IMAGEFLOW_PREVIEW_MAX_BYTES"] = "1"
+        self.assertEqual(preview_module.build_image_preview(path).get("error"), "PREVIEW_SKIPPED")
+        result = preview_module.build_image_preview(path, "always-fast")
+        self.assertTrue(result.get("success"), result)
+
+    def test_oversized_preview_is_decided_from_stat_without_reading_the_file(self):
+        path = self._png("large.png")
+        os.environ["IMAGEFLOW_PREVIEW_MAX_BYTES"] = "16"
+        with mock.patch("builtins.open", side_effect=AssertionError("source was opened")), mock.patch.object(
+            Path, "read_bytes", side_effect=AssertionError("source was read whole")
+        ), mock.patch.object(preview_module, "load_engine_module") as loader:
+            result = preview_module.build_image_preview(path)
+            loader.assert_not_called()
+        self.assertEqual(result, {"success": False, "error": "PREVIEW_SKIPPED"})
+
+        with mock.patch.object(Path, "read_bytes", side_effect=AssertionError("source was read whole")):
+            self.assertTrue(preview_module.build_image_preview(path, "always-fast").get("success"))
+
+    def test_preview_mode_always_full_keeps_source_resolution(self):
+        path = Path(self.temp_dir.name) / "full.png"
+        Image.new("RGB", (preview_module.PREVIEW_MAX_EDGE + 200, 40), (9, 9, 9)).save(path)
+        result = preview_module.build_image_preview(str(path), "always-full")
+        self.assertTrue(result.get("success"), result)
+        encoded = str(result["data_url"]).split(",", 1)[1]
+        with Image.open(io.BytesIO(base64.b64decode(encoded))) as decoded:
+            self.assertEqual(decoded.size, (preview_module.PREVIEW_MAX_EDGE + 200, 40))
+
+    def test_get_image_preview_honors_preview_mode_setting(self):
+        app = create_app()
+        app.save_settings({"preview_mode": "never"})
+        result = app.get_image_preview({"input_path": self._png("setting.png")})
+        self.assertEqual(result.get("error"), "PREVIEW_SKIPPED")
+
+
+    def test_watermark_grid_renders_nine_anchors_in_one_montage(self):
+        path = Path(self.temp_dir.name) / "grid.png"
+        Image.new("RGB", (1200, 800), (10, 10, 10)).save(path)
+        logo = Path(self.temp_dir.name) / "logo.png"
+        Image.new("RGBA", (40, 40), (255, 255, 255, 255)).save(logo)
+
+        result = create_app().preview_watermark_grid(
+            {"input_path": str(path), "watermark_type": "image", "image_path": str(logo), "scale": 0.2, "tiled": True}
+        )
+
+        self.assertTrue(result.get("success"), result)
+        self.assertEqual(result["positions"], list(watermark_preview.WATERMARK_GRID_POSITIONS))
+        self.assertLessEqual(result["cell_width"], watermark_preview.GRID_CELL_EDGE)
+        encoded = str(result["data_url"]).split(",", 1)[1]
+        with Image.open(io.BytesIO(base64.b64decode(encoded))) as decoded:
+            gap = watermark_preview.GRID_GAP
+            self.assertEqual(decoded.size, (result["cell_width"] * 3 + gap * 2, result["cell_height"] * 3 + gap * 2))
+            # Top-left cell carries the logo in its top-left corner; the center cell does not.
+            self.assertGreater(decoded.convert("L").getpixel((2, 2)), 200)
+            self.assertLess(decoded.convert("L").getpixel((result["cell_width"] + gap + 2, result["cell_height"] + gap + 2)), 60)
+
+    def test_watermark_grid_scales_text_size_with_proxy(self):
+        scaled = watermark_preview._scale_pixel_fields({"font_size": 100, "offset_x": 30, "opacity": 0.5}, 0.1)
+
+        self.assertEqual(scaled, {"font_size": 10, "offset_x": 3, "opacity": 0.5})
+
+
+    def test_jpeg_size_curve_grows_with_quality_and_caches_per_file(self):
+        path = Path(self.temp_dir.name) / "curve.png"
+        image = Image.new("RGB", (1600, 1200))
+        image.putdata([((x * 5) % 256, (y * 3) % 256, (x ^ y) % 256) for y in range(1200) for x in range(1600)])
+        image.save(path)
+
+        app = create_app()
+        result = app.jpeg_size_curve({"input_path": str(path), "qualities": [60, 95]})
+
+        self.assertTrue(result.get("success"), result)
+        self.assertLessEqual(max(result["proxy_width"], result["proxy_height"]), size_curve.SIZE_CURVE_PROXY_EDGE)
+        low, high = result["points"]
+        self.assertLess(low["estimated_bytes"], high["estimated_bytes"])
+        self.assertLessEqual(low["ssim"], high["ssim"])
+        self.assertIs(app.jpeg_size_curve({"input_path": str(path), "qualities": [95, 60]}), result)
+
 
 if __name__ == "__main__":
     unittest.main()
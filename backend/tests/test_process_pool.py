@@ -0,0 +1,154 @@
+import threading
+import time
+import unittest
+from unittest import mock
+
+from backend.application import image_ops
+from backend.application.task_manager import TaskManager
+from backend.contracts.settings import AppSettings
+from backend.tests.fake_worker import FakePoolFactory
+
+
+def _engine(module_name, payload):
+    return {"success": True, "module": module_name, "value": payload.get("value")}
+
+
+class ProcessPoolProtocolTests(unittest.TestCase):
+    """Drives `_run_jobs` through the pool path with an in-process fake worker pool."""
+
+    def setUp(self):
+        self.factory = FakePoolFactory()
+        for patcher in (
+            mock.patch.object(image_ops, "_pool_disabled", False),
+            mock.patch.object(image_ops, "ProcessPoolExecutor", self.factory),
+            mock.patch.object(image_ops, "invoke_engine_process", side_effect=_engine),
+            mock.patch.object(image_ops, "active_operation_log", return_value=None),
+        ):
+            patcher.start()
+            self.addCleanup(patcher.stop)
+        image_ops.reset_process_pool_for_tests()
+        self.addCleanup(image_ops.reset_process_pool_for_tests)
+        self.manager = TaskManager()
+
+    def _batch(self, payloads, on_result=None):
+        return image_ops.execute_engine_batch(
+            "converter", payloads, AppSettings(max_concurrency=2), self.manager, on_result=on_result
+        )
+
+    def test_results_come_back_in_request_order_with_engine_timing(self):
+        results = self._batch([{"value": 1}, {"value": 2}, {"value": 3}])
+
+        self.assertEqual([result["value"] for result in results], [1, 2, 3])
+        self.assertTrue(all("elapsed_ms" in result for result in results))
+
+    def test_malformed_and_failed_worker_replies_become_item_errors(self):
+        results = self._batch([{"fake": "bad"}, {"fake": "raise"}, {"value": 3}])
+
+        self.assertEqual(results[0]["error"], image_ops.message("result_bad_format"))
+        self.assertEqual(results[1], {"success": False, "error": "worker failed"})
+        self.assertEqual(results[2]["value"], 3)
+
+    def test_cancel_releases_a_job_that_never_finishes(self):
+        task_id = self.manager.begin_task("batch")
+
+        results = self._batch([{"value": 1}, {"fake": "hang"}], on_result=lambda _index, _result: self.manager.cancel_task(task_id))
+
+        self.assertTrue(results[0]["success"])
+        self.assertEqual(results[1], {"success": False, "error": "[PY_CANCELLED] operation cancelled"})
+        self.assertTrue(self.factory.pools[0].pending[0].cancelled())
+
+    def test_only_max_concurrency_jobs_are_in_flight_and_the_rest_wait_their_turn(self):
+        task_id = self.manager.begin_task("batch")
+        payloads = [{"value": 1}, {"fake": "hang"}, {"fake": "hang"}, {"fake": "hang"}, {"value": 5}]
+
+        results = self._batch(payloads, on_result=lambda _index, _result: self.manager.cancel_task(task_id))
+
+        # Two slots: the first job, one hang, then one more hang once the first settled.
+        pool = self.factory.pools[0]
+        self.assertEqual([payload for _module, payload in pool.submitted], payloads[:3])
+        self.assertEqual(len(pool.pending), 2)
+        self.assertTrue(results[0]["success"])
+        for result in results[1:]:
+            self.assertEqual(result, {"success": False, "error": "[PY_CANCELLED] operation cancelled"})
+
+    def test_a_crashed_worker_fails_its_batch_and_the_next_batch_gets_a_fresh_pool(self):
+        first = self._batch([{"fake": "crash"}, {"value": 2}])
+
+        self.assertEqual([result["success"] for result in first], [False, False])
+        self.assertTrue(self.factory.pools[0].shut_down)
+
+        second = self._batch([{"value": 5}])
+
+        self.assertEqual(len(self.factory.pools), 2)
+        self.assertEqual(second[0]["value"], 5)
+
+    def test_a_batch_moves_to_the_replacement_when_another_batch_grows_the_pool(self):
+        grown = []
+
+        def grow_once(_index, _result):
+            if not grown:
+                grown.append(image_ops._get_pool(8))
+
+        results = self._batch([{"value": 1}, {"value": 2}, {"value": 3}, {"value": 4}], on_result=grow_once)
+
+        self.assertEqual([result["value"] for result in results], [1, 2, 3, 4])
+        self.assertTrue(self.factory.pools[0].shut_down)
+        self.assertEqual([payload["value"] for _module, payload in self.factory.pools[1].submitted], [3, 4])
+
+    def test_growing_the_pool_lets_a_concurrent_batch_finish_its_queued_job(self):
+        first = {}
+        with mock.patch.object(image_ops, "_desired_pool_size", side_effect=lambda requested=None: requested or 1):
+            thread = threading.Thread(target=lambda: first.update(results=self._batch([{"fake": "hang"}])))
+            thread.start()
+            deadline = time.monotonic() + 5
+            while not (self.factory.pools and self.factory.pools[0].pending) and time.monotonic() < deadline:
+                time.sleep(0.01)
+
+            second = image_ops.execute_engine_batch(
+                "converter", [{"value": value} for value in range(4)], AppSettings(max_concurrency=4), self.manager
+            )
+            held = self.factory.pools[0].pending[0]
+            self.assertTrue(held.set_running_or_notify_cancel())
+            held.set_result({"success": True, "value": "held"})
+            thread.join(5)
+
+        self.assertEqual(len(self.factory.pools), 2)
+        self.assertEqual([result["value"] for result in second], [0, 1, 2, 3])
+        self.assertEqual(first["results"], [{"success": True, "value": "held"}])
+
+    def test_disabled_pool_runs_items_in_process_without_creating_a_pool(self):
+        image_ops.disable_process_pool()
+
+        results = self._batch([{"value": 1}, {"value": 2}])
+
+        self.assertEqual([result["value"] for result in results], [1, 2])
+        self.assertEqual(self.factory.pools, [])
+
+    def test_non_cancellable_batch_ignores_a_cancelled_current_task(self):
+        image_ops.disable_process_pool()
+        task_id = self.manager.begin_task("batch")
+
+        results = image_ops.execute_engine_batch(
+            "info_viewer",
+            [{"value": 1}, {"value": 2}],
+            AppSettings(max_concurrency=2),
+            self.manager,
+            on_result=lambda _index, _result: self.manager.cancel_task(task_id),
+            cancellable=False,
+        )
+
+        self.assertEqual([result["value"] for result in results], [1, 2])
+
+    def test_pool_is_reused_and_only_replaced_to_grow(self):
+        with mock.patch.object(image_ops, "_desired_pool_size", side_effect=lambda requested=None: requested or 1):
+            self._batch([{"value": 1}])
+            self._batch([{"value": 2}])
+            self.assertEqual(len(self.factory.pools), 1)
+            self._batch([{"value": 3}, {"value": 4}])
+
+        self.assertEqual(len(self.factory.pools), 2)
+        self.assertTrue(self.factory.pools[0].shut_down)
+
+
+if __name__ == "__main__":
+    unittest.main()
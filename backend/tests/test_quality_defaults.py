@@ -0,0 +1,93 @@
+import os
+import tempfile
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.contracts.settings import AppSettings
+from backend.infrastructure.settings_store import normalize_settings
+
+
+class FormatQualityDefaultsTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        os.environ["IMAGEFLOW_SETTINGS_FILE"] = os.path.join(self.temp_dir.name, "settings.json")
+        self.api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        self.api.save_settings({"format_quality_defaults": {"jpeg": 90, "webp": 80}})
+
+    def tearDown(self):
+        os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None)
+        self.temp_dir.cleanup()
+
+    def _engine_payload(self, method, payload):
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            method(payload)
+        return engine.call_args[0][1]
+
+    def test_normalize_settings_canonicalizes_and_clamps_quality_defaults(self):
+        normalized = normalize_settings(
+            AppSettings(format_quality_defaults={"JPEG": 150, ".webp": "70", "png": 50, "avif": 0, "gif": True, "bogus": 80})
+        )
+
+        self.assertEqual(normalized.format_quality_defaults, {"jpg": 100, "webp": 70})
+
+    def test_convert_fills_zero_quality_from_target_format(self):
+        payload = self._engine_payload(self.api.convert, {"input_path": "a.png", "output_path": "a.webp", "format": "webp", "quality": 0})
+
+        self.assertEqual(payload["quality"], 80)
+
+    def test_zero_quality_without_a_default_reaches_the_engine_unclamped(self):
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            result = self.api.convert({"input_path": "a.png", "output_path": "a.avif", "format": "avif", "quality": 0})
+
+        self.assertEqual(engine.call_args[0][1]["quality"], 0)
+        self.assertNotIn("warning", result)
+
+    def test_explicit_quality_wins_over_defaults(self):
+        payload = self._engine_payload(self.api.convert, {"input_path": "a.png", "output_path": "a.jpg", "format": "jpg", "quality": 55})
+
+        self.assertEqual(payload["quality"], 55)
+
+    def test_compress_uses_output_extension_and_leaves_unknown_formats_alone(self):
+        jpeg = self._engine_payload(self.api.compress, {"input_path": "a.jpg", "output_path": "out.JPEG"})
+        png = self._engine_payload(self.api.compress, {"input_path": "a.png", "output_path": "out.png"})
+
+        self.assertEqual(jpeg["quality"], 90)
+        self.assertNotIn("quality", png)
+
+    def test_a_chosen_compression_level_is_not_overridden_by_the_default(self):
+        single = self._engine_payload(self.api.compress, {"input_path": "a.jpg", "output_path": "out.jpg", "level": 4})
+        with mock.patch.object(desktop_api, "execute_engine_batch", return_value=[]) as batch:
+            self.api.compress_batch(
+                [{"input_path": "a.jpg", "output_path": "a2.jpg", "level": 2}, {"input_path": "b.jpg", "output_path": "b2.jpg"}]
+            )
+
+        self.assertNotIn("quality", single)
+        self.assertEqual([item.get("quality") for item in batch.call_args[0][1]], [None, 90])
+
+    def test_estimate_compression_writes_no_output_and_uses_the_input_format_default(self):
+        payload = self._engine_payload(
+            self.api.EstimateCompression,
+            {"input_path": "C:/in/a.jpg", "output_path": "C:/out/a.jpg", "skip_up_to_date": True},
+        )
+
+        self.assertTrue(payload["estimate"])
+        self.assertEqual((payload["output_path"], payload["skip_up_to_date"]), ("", False))
+        self.assertEqual(payload["quality"], 90)
+        self.assertTrue(self.api.EstimateCompression(["a.jpg"])["error"].startswith("[BAD_INPUT]"))
+
+    def test_batch_convert_applies_defaults_per_item(self):
+        with mock.patch.object(desktop_api, "execute_engine_batch", return_value=[]) as batch:
+            self.api.convert_batch(
+                [
+                    {"input_path": "a.png", "output_path": "a.jpg", "format": "jpg"},
+                    {"input_path": "b.png", "output_path": "b.webp", "format": "webp", "quality": 60},
+                ]
+            )
+
+        payloads = batch.call_args[0][1]
+        self.assertEqual([item["quality"] for item in payloads], [90, 60])
+
+
+if __name__ == "__main__":
+    unittest.main()
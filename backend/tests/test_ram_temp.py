@@ -0,0 +1,65 @@
+import os
+import tempfile
+import unittest
+from collections import namedtuple
+from types import SimpleNamespace
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.infrastructure import ram_temp
+
+DiskUsage = namedtuple("DiskUsage", "total used free")
+
+
+class RamTempDirTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def test_configured_directory_is_used_when_writable_with_space(self):
+        with mock.patch.dict(os.environ, {"IMAGEFLOW_RAM_TEMP_MIN_FREE_MB": "0"}):
+            self.assertEqual(ram_temp.resolve_ram_temp_dir(self.temp_dir.name), os.path.abspath(self.temp_dir.name))
+        self.assertEqual(os.listdir(self.temp_dir.name), [])
+
+    def test_missing_or_full_directory_falls_back(self):
+        missing = os.path.join(self.temp_dir.name, "absent")
+        self.assertIn("目录不存在", ram_temp.ram_temp_dir_error(missing))
+        self.assertEqual(ram_temp.resolve_ram_temp_dir(missing), "")
+
+        with mock.patch.object(ram_temp.shutil, "disk_usage", return_value=DiskUsage(1, 1, 10 * 1024 * 1024)):
+            self.assertIn("可用空间不足", ram_temp.ram_temp_dir_error(self.temp_dir.name))
+            self.assertEqual(ram_temp.resolve_ram_temp_dir(self.temp_dir.name), "")
+
+    def test_unwritable_directory_falls_back(self):
+        with mock.patch.object(ram_temp.tempfile, "mkstemp", side_effect=PermissionError("denied")):
+            self.assertIn("目录不可写", ram_temp.ram_temp_dir_error(self.temp_dir.name))
+
+    def test_default_candidate_is_dev_shm_only_on_linux(self):
+        with mock.patch.object(ram_temp.sys, "platform", "linux"):
+            self.assertEqual(ram_temp.ram_temp_candidates(), ["/dev/shm"])
+        with mock.patch.object(ram_temp.sys, "platform", "win32"):
+            self.assertEqual(ram_temp.ram_temp_candidates(), [])
+            self.assertEqual(ram_temp.ram_temp_candidates("R:\\tmp"), [os.path.abspath("R:\\tmp")])
+
+
+class RamTempPayloadTests(unittest.TestCase):
+    def test_payloads_get_temp_dir_only_when_enabled_and_usable(self):
+        items = [{"input_path": "a.png"}, {"input_path": "b.png", "temp_dir": "/chosen"}]
+        enabled = SimpleNamespace(use_ram_temp=True, ram_temp_dir="")
+
+        with mock.patch.object(desktop_api, "resolve_ram_temp_dir", return_value="/dev/shm") as resolve:
+            routed = desktop_api._with_temp_dir(items, enabled)
+            untouched = desktop_api._with_temp_dir(items, SimpleNamespace(use_ram_temp=False))
+        with mock.patch.object(desktop_api, "resolve_ram_temp_dir", return_value=""):
+            fallback = desktop_api._with_temp_dir(items, enabled)
+
+        resolve.assert_called_once_with("")
+        self.assertEqual([item.get("temp_dir") for item in routed], ["/dev/shm", "/chosen"])
+        self.assertIs(untouched, items)
+        self.assertEqual(fallback, items)
+
+
+if __name__ == "__main__":
+    unittest.main()
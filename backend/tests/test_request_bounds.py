@@ -0,0 +1,72 @@
+import unittest
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.domain.bounds import clamp_request
+
+
+class RequestBoundsTests(unittest.TestCase):
+    def test_out_of_range_inputs_are_clamped(self):
+        cases = [
+            ("converter", "quality", 200, 100),
+            ("converter", "quality", -5, 0),
+            ("converter", "width", -10, 0),
+            ("converter", "target_size_kb", -50, 0),
+            ("compressor", "level", 99, 5),
+            ("compressor", "level", 0, 1),
+            ("watermark", "opacity", 5.0, 1.0),
+            ("watermark", "opacity", -0.5, 0.0),
+            ("watermark", "watermark_scale", 0, 0.01),
+            ("adjuster", "brightness", 250, 100),
+            ("adjuster", "hue", -720, -180),
+            ("filter", "intensity", 1.5, 1.0),
+            ("filter", "grain", -1, 0.0),
+            ("filter", "vignette", 3, 1.0),
+            ("pdf_generator", "margin", -20, 0),
+            ("pdf_generator", "images_per_page", 500, 100),
+            ("pdf_generator", "grid_columns", -3, 0),
+        ]
+        for module_name, field, raw, expected in cases:
+            with self.subTest(module=module_name, field=field, raw=raw):
+                clamped, warnings = clamp_request(module_name, {field: raw})
+                self.assertEqual(clamped[field], expected)
+                self.assertEqual(len(warnings), 1)
+                self.assertIn(field, warnings[0])
+
+    def test_in_range_missing_and_non_numeric_values_pass_through(self):
+        payload = {"quality": 80, "format": "webp", "width": "auto", "maintain_ar": True}
+        clamped, warnings = clamp_request("converter", payload)
+
+        self.assertEqual(clamped, payload)
+        self.assertEqual(warnings, [])
+        self.assertEqual(clamp_request("gif_splitter", {"quality": 500}), ({"quality": 500}, []))
+
+    def test_clamped_kind_matches_field_type(self):
+        clamped, _warnings = clamp_request("compressor", {"level": 7.8})
+        self.assertIsInstance(clamped["level"], int)
+        clamped, _warnings = clamp_request("watermark", {"opacity": 2})
+        self.assertIsInstance(clamped["opacity"], float)
+
+    def test_desktop_api_forwards_clamped_payload_and_reports_warning(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True, "warning": "引擎提示"}) as engine:
+            result = api.add_watermark({"input_path": "a.png", "output_path": "b.png", "opacity": 5})
+
+        self.assertEqual(engine.call_args[0][1]["opacity"], 1.0)
+        self.assertTrue(result["warning"].startswith("参数 opacity 超出范围"))
+        self.assertTrue(result["warning"].endswith("引擎提示"))
+
+    def test_desktop_api_batch_warnings_stay_with_their_item(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(
+            desktop_api, "execute_engine_batch", return_value=[{"success": True}, {"success": True}]
+        ) as batch:
+            results = api.apply_filter_batch([{"input_path": "a.png", "intensity": 0.5}, {"input_path": "b.png", "intensity": 9}])
+
+        self.assertEqual([item["intensity"] for item in batch.call_args[0][1]], [0.5, 1.0])
+        self.assertNotIn("warning", results[0])
+        self.assertIn("intensity", results[1]["warning"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,222 @@
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import startup_status as startup_module
+from backend.application.runtime_status import RuntimeIncomplete, RuntimePreparation, default_runtime_steps
+from backend.application.startup_status import SAFE_MODE_ENV, StartupStatus, safe_mode_requested
+from backend.contracts.settings import AppSettings
+from backend.infrastructure.runtime_files import RUNTIME_MANIFEST_NAME, verify_runtime_files, write_runtime_manifest
+
+
+class RuntimePreparationTests(unittest.TestCase):
+    def test_reports_each_step_and_ends_ready(self):
+        ran = []
+        events = []
+        preparation = RuntimePreparation([("a", lambda: ran.append("a")), ("b", lambda: ran.append("b"))])
+
+        status = preparation.run(events.append)
+
+        self.assertEqual(ran, ["a", "b"])
+        self.assertEqual(status["state"], "ready")
+        self.assertEqual((status["current"], status["total"]), (2, 2))
+        self.assertEqual([event["state"] for event in events], ["preparing", "preparing", "preparing", "ready"])
+        self.assertEqual([event["current"] for event in events], [0, 1, 2, 2])
+        self.assertTrue(all(event["type"] == "runtime_progress" for event in events))
+
+        ran.clear()
+        self.assertEqual(preparation.run()["state"], "ready")
+        self.assertEqual(ran, [])
+
+    def test_failure_stops_at_the_step_and_can_be_retried(self):
+        attempts = []
+
+        def flaky():
+            attempts.append(1)
+            if len(attempts) == 1:
+                raise RuntimeError("pool did not start")
+
+        later = mock.Mock()
+        preparation = RuntimePreparation([("process_pool", flaky), ("converter", later)])
+
+        failed = preparation.run()
+        self.assertEqual(failed["state"], "failed")
+        self.assertEqual(failed["step"], "process_pool")
+        self.assertEqual(failed["error"], "pool did not start")
+        self.assertEqual(failed["current"], 0)
+        later.assert_not_called()
+
+        self.assertEqual(preparation.run()["state"], "ready")
+        later.assert_called_once()
+
+    def test_notify_errors_do_not_break_preparation(self):
+        preparation = RuntimePreparation([("a", lambda: None)])
+
+        def broken(_detail):
+            raise RuntimeError("window closed")
+
+        self.assertEqual(preparation.run(broken)["state"], "ready")
+
+    def test_default_steps_check_every_engine_script_before_warming(self):
+        names = [name for name, _ in default_runtime_steps()]
+
+        self.assertEqual(names[:2], ["runtime_files", "engine:temp_registry"])
+        self.assertIn("engine:converter", names)
+        self.assertEqual(names[-2:], ["process_pool", "converter"])
+        engine_checks = dict(default_runtime_steps()[1:-2])
+        for check in engine_checks.values():
+            check()
+
+    def test_incomplete_files_are_reported_and_repair_runs_again(self):
+        missing = [["_internal/python311.dll", "_internal/PIL/_imaging.pyd"], []]
+
+        def check():
+            if missing[0]:
+                raise RuntimeIncomplete(missing.pop(0))
+
+        preparation = RuntimePreparation([("runtime_files", check), ("converter", lambda: None)])
+
+        status = preparation.run()
+        self.assertEqual(status["state"], "incomplete")
+        self.assertEqual(status["files"], ["_internal/python311.dll", "_internal/PIL/_imaging.pyd"])
+        self.assertEqual(status["file_count"], 2)
+        self.assertTrue(status["error"].startswith("[RUNTIME_INCOMPLETE]"))
+
+        repaired = preparation.repair()
+        self.assertEqual(repaired["state"], "ready")
+        self.assertEqual(repaired["files"], [])
+        self.assertEqual(preparation.repair()["state"], "ready")
+
+    def test_repair_runtime_binding_uses_the_shared_preparation(self):
+        api = desktop_api.DesktopAPI()
+        with mock.patch.object(desktop_api, "repair_runtime_preparation", return_value={"state": "ready"}) as repair:
+            self.assertEqual(api.RepairRuntime(), {"state": "ready"})
+        repair.assert_called_once_with()
+
+    def test_get_runtime_status_reads_the_shared_preparation(self):
+        api = desktop_api.DesktopAPI()
+        snapshot = {"type": "runtime_progress", "state": "preparing", "current": 1, "total": 3, "step": "x", "error": ""}
+        with mock.patch.object(desktop_api, "runtime_status", return_value=snapshot):
+            self.assertEqual(api.GetRuntimeStatus(), snapshot)
+
+
+class StartupStatusTests(unittest.TestCase):
+    def setUp(self):
+        self.status = StartupStatus()
+        patcher = mock.patch.object(startup_module, "_STATUS", self.status)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_safe_mode_is_requested_by_env_or_settings(self):
+        with mock.patch.dict("os.environ", {SAFE_MODE_ENV: "1"}):
+            self.assertEqual(safe_mode_requested(AppSettings()), "env")
+        with mock.patch.dict("os.environ", {SAFE_MODE_ENV: ""}):
+            self.assertEqual(safe_mode_requested(AppSettings(safe_mode=True)), "settings")
+            self.assertEqual(safe_mode_requested(AppSettings()), "")
+            self.assertEqual(safe_mode_requested(None), "")
+
+    def test_safe_mode_disables_the_pool_and_skips_packaged_runtime_steps(self):
+        with mock.patch("backend.application.image_ops.disable_process_pool") as disable:
+            self.status.enter_safe_mode("env")
+
+        disable.assert_called_once()
+        names = [name for name, _ in default_runtime_steps()]
+        self.assertNotIn("runtime_files", names)
+        self.assertNotIn("process_pool", names)
+        self.assertEqual((names[0], names[-1]), ("engine:temp_registry", "converter"))
+        status = self.status.status()
+        self.assertTrue(status["ok"] and status["safe_mode"])
+        self.assertEqual(len(status["decisions"]), 3)
+        self.assertIn("env", status["decisions"][0])
+
+    def test_failed_steps_are_recorded_instead_of_raised(self):
+        with self.status.step("operation_log"):
+            raise OSError("log directory is read-only")
+        with self.status.step("runtime_preparation"):
+            raise RuntimeError("thread could not start")
+        with self.status.step("settings"):
+            pass
+
+        status = desktop_api.DesktopAPI(task_manager=mock.MagicMock()).GetStartupError()
+
+        self.assertFalse(status["ok"])
+        self.assertEqual((status["step"], status["error"]), ("operation_log", "log directory is read-only"))
+        self.assertFalse(status["safe_mode"])
+
+    def test_diagnostics_list_launch_then_runtime_steps_and_name_the_first_failure(self):
+        with self.status.step("settings"):
+            pass
+        preparation = RuntimePreparation(
+            [("engine:converter", lambda: None), ("process_pool", mock.Mock(side_effect=OSError("spawn failed"))), ("converter", lambda: None)]
+        )
+        preparation.run()
+
+        with mock.patch("backend.application.runtime_status._PREPARATION", preparation):
+            diagnostics = desktop_api.DesktopAPI(task_manager=mock.MagicMock()).GetStartupDiagnostics()
+
+            self.assertFalse(diagnostics["ok"])
+            self.assertEqual((diagnostics["phase"], diagnostics["error"]), ("process_pool", "spawn failed"))
+            self.assertEqual(diagnostics["runtime_state"], "failed")
+            self.assertEqual(
+                [(phase["name"], phase["state"], phase["source"]) for phase in diagnostics["phases"]],
+                [
+                    ("settings", "ok", "launch"),
+                    ("engine:converter", "ok", "runtime"),
+                    ("process_pool", "failed", "runtime"),
+                    ("converter", "skipped", "runtime"),
+                ],
+            )
+
+            # A failed launch step is the root cause reported ahead of the runtime failure.
+            with self.status.step("operation_log"):
+                raise PermissionError("log directory is read-only")
+            diagnostics = startup_module.startup_diagnostics()
+            self.assertEqual((diagnostics["phase"], diagnostics["error"]), ("operation_log", "log directory is read-only"))
+
+    def test_diagnostics_before_preparation_ran_are_ok_with_no_runtime_steps(self):
+        with mock.patch("backend.application.runtime_status._PREPARATION", RuntimePreparation()):
+            diagnostics = startup_module.startup_diagnostics()
+
+        self.assertTrue(diagnostics["ok"])
+        self.assertEqual((diagnostics["phase"], diagnostics["runtime_state"], diagnostics["phases"]), ("", "idle", []))
+
+
+class RuntimeManifestTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.root = Path(self.temp_dir.name)
+        (self.root / "_internal").mkdir()
+        (self.root / "ImageFlow.exe").write_bytes(b"exe")
+        (self.root / "_internal" / "python311.dll").write_bytes(b"x" * 64)
+
+    def tearDown(self):
+        self.temp_dir.cleanup()
+
+    def test_complete_tree_passes_and_manifest_skips_itself(self):
+        manifest = write_runtime_manifest(self.root)
+
+        self.assertEqual(manifest.name, RUNTIME_MANIFEST_NAME)
+        self.assertNotIn(RUNTIME_MANIFEST_NAME, manifest.read_text(encoding="utf-8"))
+        self.assertEqual(verify_runtime_files(self.root), [])
+
+    def test_missing_and_truncated_files_are_listed(self):
+        write_runtime_manifest(self.root)
+        (self.root / "ImageFlow.exe").unlink()
+        with open(self.root / "_internal" / "python311.dll", "r+b") as handle:
+            handle.truncate(10)
+
+        self.assertEqual(sorted(verify_runtime_files(self.root)), ["ImageFlow.exe", "_internal/python311.dll"])
+
+    def test_source_runs_and_trees_without_manifest_are_not_checked(self):
+        self.assertEqual(verify_runtime_files(self.root), [])
+        with mock.patch("sys.frozen", False, create=True):
+            self.assertEqual(verify_runtime_files(), [])
+
+        (self.root / RUNTIME_MANIFEST_NAME).write_text("{not json", encoding="utf-8")
+        self.assertEqual(verify_runtime_files(self.root), [RUNTIME_MANIFEST_NAME])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -2,10 +2,21 @@ import json
 import unittest
 import os
 import tempfile
+from dataclasses import replace
 from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
 
 from backend.contracts.settings import AppSettings, default_app_settings
-from backend.infrastructure.settings_store import load_settings, normalize_settings, save_settings
+from backend.infrastructure.settings_store import (
+    load_settings,
+    normalize_settings,
+    reset_settings,
+    save_settings,
+    settings_from_json,
+    settings_to_json,
+)
 
 
 class SettingsStoreTests(unittest.TestCase):
@@ -22,6 +33,40 @@ class SettingsStoreTests(unittest.TestCase):
         self.assertEqual(normalized.default_output_dir, "C:/tmp")
         self.assertEqual(normalized.recent_input_dirs, ["C:/One", "D:/Two"])
 
+    def test_normalize_settings_rejects_unknown_preview_mode(self):
+        self.assertEqual(normalize_settings(AppSettings(preview_mode=" Always-Full ")).preview_mode, "always-full")
+        self.assertEqual(normalize_settings(AppSettings(preview_mode="turbo")).preview_mode, "auto")
+
+    def test_normalize_settings_maps_language_to_supported_catalog(self):
+        self.assertEqual(normalize_settings(AppSettings(language="en-US")).language, "en")
+        self.assertEqual(normalize_settings(AppSettings(language="fr")).language, "zh")
+
+    def test_normalize_settings_coerces_open_folder_flag(self):
+        self.assertTrue(normalize_settings(AppSettings(open_folder_when_done="true")).open_folder_when_done)
+        self.assertFalse(normalize_settings(AppSettings(open_folder_when_done="maybe")).open_folder_when_done)
+
+    def test_normalize_settings_clamps_gif_limits(self):
+        normalized = normalize_settings(AppSettings(gif_max_frames=0, gif_max_megapixels="512", gif_max_export_files=10**9))
+
+        self.assertEqual((normalized.gif_max_frames, normalized.gif_max_megapixels), (1, 512))
+        self.assertEqual(normalized.gif_max_export_files, 100_000)
+
+    def test_normalize_settings_clamps_edit_history_depth(self):
+        self.assertEqual(normalize_settings(AppSettings(edit_history_depth=0)).edit_history_depth, 1)
+        self.assertEqual(normalize_settings(AppSettings(edit_history_depth="500")).edit_history_depth, 100)
+        self.assertEqual(normalize_settings(AppSettings(edit_history_depth="many")).edit_history_depth, 20)
+
+    def test_split_gif_fills_unset_limits_from_settings(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        settings = replace(default_app_settings(), gif_max_frames=50, gif_max_megapixels=8, gif_max_export_files=20)
+        with mock.patch.object(api, "_settings", return_value=settings), mock.patch.object(
+            desktop_api, "execute_engine", return_value={"success": True}
+        ) as engine:
+            api.SplitGIF({"action": "export_frames", "input_path": "a.gif", "output_dir": "out", "max_frames": 500})
+
+        request = engine.call_args.args[1]
+        self.assertEqual((request["max_frames"], request["max_total_megapixels"], request["max_export_files"]), (500, 8, 20))
+
     def test_load_settings_falls_back_to_defaults_for_invalid_json(self):
         with tempfile.TemporaryDirectory() as temp_dir:
             settings_file = Path(temp_dir) / "settings.json"
@@ -135,6 +180,46 @@ class SettingsStoreTests(unittest.TestCase):
 
             self.assertFalse(settings_file.parent.exists())
 
+    def test_settings_json_roundtrip_normalizes_imported_values(self):
+        exported = settings_to_json(AppSettings(max_concurrency=6, output_prefix="MOVE", recent_input_dirs=["D:/In///"]))
+
+        imported = settings_from_json(exported)
+
+        self.assertEqual(imported.max_concurrency, 6)
+        self.assertEqual(imported.output_prefix, "MOVE")
+        self.assertEqual(imported.recent_input_dirs, ["D:/In"])
+        self.assertEqual(settings_from_json('{"max_concurrency": 500, "extra": 1}').max_concurrency, 32)
+
+    def test_settings_from_json_rejects_malformed_payloads(self):
+        for data in ("{ not json", "[1, 2]", ""):
+            with self.assertRaises(ValueError) as ctx:
+                settings_from_json(data)
+            self.assertIn("[BAD_INPUT]", str(ctx.exception))
+
+    def test_reset_settings_writes_defaults_and_backs_up_corrupt_file(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            settings_file = Path(temp_dir) / "settings.json"
+            settings_file.write_text("{ corrupt", encoding="utf-8")
+            os.environ["IMAGEFLOW_SETTINGS_FILE"] = str(settings_file)
+            self.addCleanup(lambda: os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None))
+
+            reset = reset_settings()
+
+            self.assertEqual(reset, normalize_settings(default_app_settings()))
+            self.assertEqual((Path(temp_dir) / "settings.json.bak").read_text(encoding="utf-8"), "{ corrupt")
+            self.assertEqual(load_settings().output_prefix, "IF")
+
+    def test_reset_settings_without_existing_file_skips_backup(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            settings_file = Path(temp_dir) / "settings.json"
+            os.environ["IMAGEFLOW_SETTINGS_FILE"] = str(settings_file)
+            self.addCleanup(lambda: os.environ.pop("IMAGEFLOW_SETTINGS_FILE", None))
+
+            reset_settings()
+
+            self.assertTrue(settings_file.exists())
+            self.assertFalse((Path(temp_dir) / "settings.json.bak").exists())
+
 
 if __name__ == "__main__":
     unittest.main()
@@ -0,0 +1,122 @@
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from backend.api import desktop_api
+from backend.application import size_curve
+
+
+class SizeCurveTests(unittest.TestCase):
+    def test_normalize_curve_qualities_clamps_dedupes_and_sorts(self):
+        self.assertEqual(size_curve.normalize_curve_qualities([95, "70", 150, 0, 70, True, "x"]), [1, 70, 95, 100])
+        self.assertEqual(size_curve.normalize_curve_qualities(None), list(size_curve.DEFAULT_CURVE_QUALITIES))
+        self.assertEqual(len(size_curve.normalize_curve_qualities(list(range(1, 50)))), size_curve.MAX_CURVE_POINTS)
+
+    def test_build_jpeg_size_curve_rejects_missing_input(self):
+        self.assertIn("[BAD_INPUT]", size_curve.build_jpeg_size_curve("")["error"])
+
+    def test_compress_to_quality_rejects_target_outside_unit_range(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            for target in (0, "0.9", True, 1.5, -0.1, "high", ""):
+                result = api.compress_to_quality({"input_path": "a.jpg", "output_path": "b.jpg", "target_ssim": target})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] target_ssim"), target)
+            result = api.compress_to_quality({"input_path": "a.jpg", "output_path": "b.jpg", "target_size_kb": "50"})
+            self.assertTrue(result["error"].startswith("[BAD_INPUT] target_size_kb"))
+        engine.assert_not_called()
+
+    def test_compress_to_quality_leaves_the_search_to_the_compressor(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        engine_result = {"success": True, "compressed_size": 1234, "quality": 83, "ssim": 0.981, "target_reached": True}
+        with mock.patch.object(desktop_api, "execute_engine", return_value=engine_result) as engine:
+            result = api.compress_to_quality(
+                {"input_path": "a.jpg", "output_path": "b.jpg", "target_ssim": 0.98, "level": 1, "target_size_kb": 50}
+            )
+
+        self.assertEqual(engine.call_args[0][0], "compressor")
+        self.assertEqual(engine.call_args[0][1]["target_ssim"], 0.98)
+        self.assertEqual((result["quality"], result["compressed_size"], result["ssim"]), (83, 1234, 0.981))
+
+    def test_compress_with_target_ssim_validates_and_passes_it_to_the_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine") as engine:
+            for target in (0, 1.5, "0.9", True):
+                result = api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "target_ssim": target})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] target_ssim"), target)
+        engine.assert_not_called()
+
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True, "ssim": 0.952}) as engine:
+            result = api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "target_ssim": 0.95, "level": 3})
+
+        self.assertEqual(engine.call_args[0][1]["target_ssim"], 0.95)
+        self.assertEqual(result["ssim"], 0.952)
+
+    def test_compress_batch_sends_valid_target_ssim_items_to_the_pool(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(
+            api, "_run_engine_batch", side_effect=lambda _module, items, _settings: [{"success": True} for _ in items]
+        ) as run:
+            results = api.CompressBatch(
+                [
+                    {"input_path": "a.jpg", "output_path": "a2.jpg", "level": 3},
+                    {"input_path": "b.jpg", "output_path": "b2.jpg", "level": 3, "target_ssim": 0.97},
+                    {"input_path": "c.jpg", "output_path": "c2.jpg", "level": 3, "target_ssim": 2},
+                ]
+            )
+
+        run.assert_called_once()
+        sent = run.call_args.args[1]
+        self.assertEqual([Path(item["input_path"]).name for item in sent], ["a.jpg", "b.jpg"])
+        self.assertEqual(sent[1]["target_ssim"], 0.97)
+        self.assertEqual([result["success"] for result in results], [True, True, False])
+        self.assertTrue(results[2]["error"].startswith("[BAD_INPUT]"))
+
+    def test_target_size_kb_must_be_a_positive_integer_when_set(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for target in (-1, 1.5, "50", True):
+                result = api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "target_size_kb": target})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] target_size_kb"), target)
+            engine.assert_not_called()
+            for target in (None, "", 0, 200):
+                self.assertTrue(api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "target_size_kb": target})["success"])
+
+        with mock.patch.object(
+            api, "_run_engine_batch", side_effect=lambda _module, items, _settings: [{"success": True} for _ in items]
+        ) as run:
+            results = api.CompressBatch(
+                [
+                    {"input_path": "a.jpg", "output_path": "a2.jpg", "target_size_kb": 100},
+                    {"input_path": "b.jpg", "output_path": "b2.jpg", "target_size_kb": -5},
+                ]
+            )
+
+        self.assertEqual([Path(item["input_path"]).name for item in run.call_args.args[1]], ["a.jpg"])
+        self.assertTrue(results[0]["success"])
+        self.assertTrue(results[1]["error"].startswith("[BAD_INPUT] target_size_kb"))
+
+    def test_lossless_is_rejected_with_a_lossy_only_engine(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for name in ("mozjpeg", "PNGQuant", "imagequant"):
+                result = api.Compress({"input_path": "a.png", "output_path": "b.png", "engine": name, "lossless": True})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] lossless"), name)
+            engine.assert_not_called()
+            for name in ("", "auto", "oxipng", "pillow"):
+                self.assertTrue(api.Compress({"input_path": "a.png", "output_path": "b.png", "engine": name, "lossless": True})["success"])
+            self.assertTrue(api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "engine": "mozjpeg", "lossless": False})["success"])
+
+
+    def test_strip_mode_must_be_a_known_mode_when_set(self):
+        api = desktop_api.DesktopAPI(task_manager=mock.MagicMock())
+        with mock.patch.object(desktop_api, "execute_engine", return_value={"success": True}) as engine:
+            for mode in ("gps", "icc", 1, True):
+                result = api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "strip_mode": mode})
+                self.assertTrue(result["error"].startswith("[BAD_INPUT] strip_mode"), mode)
+            engine.assert_not_called()
+            for mode in (None, "", "all", "exif_only", "KEEP_ICC"):
+                self.assertTrue(api.Compress({"input_path": "a.jpg", "output_path": "b.jpg", "strip_mode": mode})["success"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -1,4 +1,6 @@
 import os
+import tempfile
+import time
 import unittest
 from unittest import mock
 
@@ -76,6 +78,89 @@ class TaskManagerTests(unittest.TestCase):
         finally:
             image_ops._invoke_engine_job = original_job
 
+    def test_execute_engine_batch_skips_outputs_newer_than_inputs(self):
+        original_job = image_ops._invoke_engine_job
+        calls: list[str] = []
+
+        def fake_job(_module, payload):
+            calls.append(payload["input_path"])
+            return {"success": True, "input_path": payload["input_path"]}
+
+        with tempfile.TemporaryDirectory() as temp_dir:
+            paths = {name: os.path.join(temp_dir, name) for name in ("a.png", "a.jpg", "b.png", "b.jpg")}
+            for path in paths.values():
+                with open(path, "wb") as handle:
+                    handle.write(b"x")
+            os.utime(paths["a.png"], (1_000, 1_000))
+            os.utime(paths["a.jpg"], (2_000, 2_000))
+            os.utime(paths["b.png"], (2_000, 2_000))
+            os.utime(paths["b.jpg"], (1_000, 1_000))
+            try:
+                image_ops._invoke_engine_job = fake_job
+                manager = TaskManager()
+                results = image_ops.execute_engine_batch(
+                    "converter",
+                    [
+                        {"input_path": paths["a.png"], "output_path": paths["a.jpg"], "skip_up_to_date": True},
+                        {"input_path": paths["b.png"], "output_path": paths["b.jpg"], "skip_up_to_date": True},
+                        {"input_path": paths["a.png"], "output_path": paths["a.jpg"]},
+                    ],
+                    AppSettings(max_concurrency=2),
+                    manager,
+                )
+            finally:
+                image_ops._invoke_engine_job = original_job
+
+        self.assertTrue(results[0]["skipped"])
+        self.assertTrue(results[0]["success"])
+        self.assertNotIn("skipped", results[1])
+        self.assertNotIn("skipped", results[2])
+        self.assertEqual(calls, [paths["b.png"], paths["a.png"]])
+
+    def test_execute_engine_batch_reports_each_settled_item(self):
+        original_job = image_ops._invoke_engine_job
+        reported: list[tuple[int, object]] = []
+
+        with tempfile.TemporaryDirectory() as temp_dir:
+            source = os.path.join(temp_dir, "a.png")
+            output = os.path.join(temp_dir, "a.jpg")
+            for path, stamp in ((source, 1_000), (output, 2_000)):
+                with open(path, "wb") as handle:
+                    handle.write(b"x")
+                os.utime(path, (stamp, stamp))
+            try:
+                image_ops._invoke_engine_job = lambda _module, payload: {"success": True, "value": payload.get("value")}
+                image_ops.execute_engine_batch(
+                    "converter",
+                    [
+                        {"value": 0},
+                        {"input_path": source, "output_path": output, "skip_up_to_date": True},
+                        {"value": 2},
+                    ],
+                    AppSettings(max_concurrency=2),
+                    TaskManager(),
+                    on_result=lambda index, result: reported.append((index, result.get("value", "skipped"))),
+                )
+            finally:
+                image_ops._invoke_engine_job = original_job
+
+        self.assertEqual(reported, [(1, "skipped"), (0, 0), (2, 2)])
+
+    def test_batch_progress_counts_and_finishes(self):
+        from backend.application.progress import BatchProgress
+
+        notices: list[dict] = []
+        progress = BatchProgress("converter", 2, 7, notices.append)
+        progress.report(1, {"success": True, "input_path": "/in/b.png", "output_path": "/out/b.jpg"})
+        progress.report(0, {"success": False, "error": "boom"})
+        progress.finish()
+
+        self.assertEqual([item["current"] for item in notices], [1, 2, 2])
+        self.assertEqual(notices[0]["percentage"], 50.0)
+        self.assertEqual(notices[0]["output_path"], "/out/b.jpg")
+        self.assertFalse(notices[1]["success"])
+        self.assertEqual([item["done"] for item in notices], [False, False, True])
+
     def test_execute_engine_skips_work_for_cancelled_task(self):
         called = {"value": False}
 
@@ -95,6 +180,24 @@ class TaskManagerTests(unittest.TestCase):
         finally:
             image_ops._invoke_engine_job = original_job
 
+    def test_convert_and_compress_results_report_engine_wall_time(self):
+        def slow_engine(_module, _payload):
+            time.sleep(0.02)
+            return {"success": True}
+
+        with mock.patch.object(image_ops, "invoke_engine_process", side_effect=slow_engine):
+            converted = image_ops._invoke_engine_job("converter", {})
+            compressed = image_ops._invoke_engine_job("compressor", {})
+            info = image_ops._invoke_engine_job("info_viewer", {})
+        with mock.patch.object(image_ops, "invoke_engine_process", side_effect=RuntimeError("boom")):
+            failed = image_ops._invoke_engine_job("converter", {})
+
+        self.assertGreaterEqual(converted["elapsed_ms"], 20)
+        self.assertGreaterEqual(compressed["elapsed_ms"], 20)
+        self.assertNotIn("elapsed_ms", info)
+        self.assertEqual(failed["error"], "boom")
+        self.assertIn("elapsed_ms", failed)
+
     def test_execute_engine_batch_marks_remaining_cancelled(self):
         original_job = image_ops._invoke_engine_job
 
@@ -0,0 +1,193 @@
+import errno
+import os
+import tempfile
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+from backend.infrastructure import engine_loader
+
+
+class TempRegistryTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.registry = engine_loader.engine_temp_registry()
+        self.registry.purge()
+
+    def tearDown(self):
+        self.registry.purge()
+        self.temp_dir.cleanup()
+
+    def test_unregister_keeps_file_that_was_moved_into_place(self):
+        temp_path = self.registry.create(suffix=".png", dir=self.temp_dir.name)
+        final_path = os.path.join(self.temp_dir.name, "final.png")
+        os.replace(temp_path, final_path)
+        self.registry.unregister(temp_path)
+
+        self.assertEqual(self.registry.purge(), 0)
+        self.assertTrue(os.path.exists(final_path))
+
+    def test_scope_purges_only_temps_created_inside_it(self):
+        outside = self.registry.create(dir=self.temp_dir.name)
+        with self.registry.scope():
+            inside = self.registry.create(dir=self.temp_dir.name)
+
+        self.assertFalse(os.path.exists(inside))
+        self.assertTrue(os.path.exists(outside))
+        self.assertEqual(self.registry.paths(), [os.path.abspath(outside)])
+
+    def test_cancelled_engine_operation_leaves_no_registered_temps(self):
+        created = []
+
+        def cancelled_process(_payload):
+            created.append(self.registry.create(suffix=".tmp", dir=self.temp_dir.name))
+            raise RuntimeError("[PY_CANCELLED] operation cancelled")
+
+        original_loader = engine_loader.load_engine_module
+        try:
+            engine_loader.load_engine_module = lambda _name: SimpleNamespace(process=cancelled_process)
+            with self.assertRaises(RuntimeError):
+                engine_loader.invoke_engine_process("converter", {})
+        finally:
+            engine_loader.load_engine_module = original_loader
+
+        self.assertEqual(len(created), 1)
+        self.assertFalse(os.path.exists(created[0]))
+        self.assertEqual(self.registry.paths(), [])
+
+    def _invoke_with(self, process, module_name, payload):
+        original_loader = engine_loader.load_engine_module
+        try:
+            engine_loader.load_engine_module = lambda _name: SimpleNamespace(process=process)
+            return engine_loader.invoke_engine_process(module_name, payload)
+        finally:
+            engine_loader.load_engine_module = original_loader
+
+    def test_working_copy_reads_a_registered_copy_and_reports_the_original_path(self):
+        source = os.path.join(self.temp_dir.name, "photo.png")
+        with open(source, "wb") as handle:
+            handle.write(b"pixels")
+        seen = []
+
+        def reading_process(payload):
+            seen.append(payload["input_path"])
+            with open(payload["input_path"], "rb") as handle:
+                self.assertEqual(handle.read(), b"pixels")
+            self.assertIn(os.path.abspath(os.path.dirname(payload["input_path"])), self.registry.paths())
+            return {"success": True, "input_path": payload["input_path"], "error": f"read {payload['input_path']}"}
+
+        result = self._invoke_with(
+            reading_process,
+            "converter",
+            {"input_path": source, "output_path": os.path.join(self.temp_dir.name, "out.jpg"), "working_copy": True},
+        )
+
+        self.assertNotEqual(seen[0], source)
+        self.assertEqual(os.path.basename(seen[0]), "photo.png")
+        self.assertFalse(os.path.exists(seen[0]))
+        self.assertEqual(result["input_path"], source)
+        self.assertEqual(result["error"], f"read {source}")
+        self.assertEqual(self.registry.paths(), [])
+
+    def test_working_copy_is_skipped_for_in_place_writes_and_unlisted_engines(self):
+        source = os.path.join(self.temp_dir.name, "photo.jpg")
+        with open(source, "wb") as handle:
+            handle.write(b"pixels")
+        seen = []
+
+        def process(payload):
+            seen.append(payload["input_path"])
+            return {"success": True}
+
+        self._invoke_with(process, "compressor", {"input_path": source, "output_path": source, "working_copy": True})
+        self._invoke_with(process, "metadata_tool", {"input_path": source, "working_copy": True})
+        self._invoke_with(process, "converter", {"input_path": source, "output_path": source + ".png"})
+
+        self.assertEqual(seen, [source, source, source])
+
+    def test_scope_temp_dir_is_the_default_only_inside_the_scope(self):
+        scratch = os.path.join(self.temp_dir.name, "ram")
+        os.mkdir(scratch)
+        with self.registry.scope(temp_dir=scratch):
+            inside = self.registry.create(suffix=".png")
+            explicit = self.registry.create(dir=self.temp_dir.name)
+            with self.registry.scope():
+                nested = self.registry.create_dir()
+            self.assertEqual(os.path.dirname(inside), scratch)
+            self.assertEqual(os.path.dirname(explicit), self.temp_dir.name)
+            self.assertEqual(os.path.dirname(nested), scratch)
+        self.assertIsNone(self.registry.default_dir())
+        self.assertEqual(os.listdir(scratch), [])
+
+    def test_engine_payload_temp_dir_routes_scratch_files(self):
+        scratch = os.path.join(self.temp_dir.name, "ram")
+        os.mkdir(scratch)
+        seen = []
+
+        def process(_payload):
+            seen.append(self.registry.create(suffix=".tmp"))
+            return {"success": True}
+
+        self._invoke_with(process, "converter", {"temp_dir": scratch})
+
+        self.assertEqual(os.path.dirname(seen[0]), scratch)
+        self.assertFalse(os.path.exists(seen[0]))
+
+    def test_shutdown_purge_removes_remaining_temps(self):
+        leftover = self.registry.create(dir=self.temp_dir.name)
+
+        self.assertEqual(engine_loader.purge_engine_temp_files(), 1)
+        self.assertFalse(os.path.exists(leftover))
+
+
+class LockRetryTests(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.TemporaryDirectory()
+        self.module = engine_loader._load_module_from_engine_file(engine_loader.TEMP_REGISTRY_MODULE)
+        self.env = mock.patch.dict(os.environ, {"IMAGEFLOW_LOCK_RETRY_DELAY_MS": "0"})
+        self.env.start()
+
+    def tearDown(self):
+        self.env.stop()
+        self.temp_dir.cleanup()
+
+    def _sharing_violation(self):
+        exc = PermissionError(errno.EACCES, "The process cannot access the file because it is being used")
+        exc.winerror = 32
+        return exc
+
+    def test_rename_succeeds_after_a_transient_lock(self):
+        src = os.path.join(self.temp_dir.name, "tmp.png")
+        dst = os.path.join(self.temp_dir.name, "final.png")
+        with open(src, "wb") as handle:
+            handle.write(b"pixels")
+        real_replace = os.replace
+        failures = [self._sharing_violation(), OSError(errno.EBUSY, "busy")]
+
+        def flaky_replace(a, b):
+            if failures:
+                raise failures.pop(0)
+            return real_replace(a, b)
+
+        with mock.patch.object(self.module.os, "replace", side_effect=flaky_replace) as replace:
+            self.module.replace_with_retry(src, dst)
+
+        self.assertEqual(replace.call_count, 3)
+        self.assertTrue(os.path.exists(dst))
+
+    def test_retry_is_bounded_and_skips_other_errors(self):
+        with mock.patch.dict(os.environ, {"IMAGEFLOW_LOCK_RETRY_ATTEMPTS": "3"}), mock.patch.object(
+            self.module.os, "replace", side_effect=OSError(errno.EBUSY, "busy")
+        ) as locked:
+            with self.assertRaises(OSError):
+                self.module.replace_with_retry("a", "b")
+        self.assertEqual(locked.call_count, 3)
+
+        with mock.patch.object(self.module.os, "replace", side_effect=FileNotFoundError("gone")) as missing:
+            with self.assertRaises(FileNotFoundError):
+                self.module.replace_with_retry("a", "b")
+        self.assertEqual(missing.call_count, 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -4,6 +4,10 @@ import Dashboard from './components/Dashboard';
 import { WindowControls } from './components/WindowControls';
 import Icon from './components/Icon';
 import ErrorBoundary from './components/ErrorBoundary';
+import ConflictPrompt from './components/ConflictPrompt';
+import InterruptedPrompt from './components/InterruptedPrompt';
+import LongOperationToast from './components/LongOperationToast';
+import RuntimeSetup from './components/RuntimeSetup';
 import { ViewState, Theme, FeatureId } from './types';
 import { FEATURES } from './constants';
 
@@ -356,6 +360,10 @@ const App: React.FC = () => {
                     </div>
                 </main>
             </div>
+            <ConflictPrompt />
+            <RuntimeSetup />
+            <InterruptedPrompt />
+            <LongOperationToast />
         </div>
     );
 };
@@ -0,0 +1,70 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import type { ConflictDecision } from '../types/backend-bindings';
+import { getAppBindings, onConflictPrompt, type ConflictPromptNotice } from '../types/wails-api';
+
+const DECISION_LABELS: Record<ConflictDecision, string> = {
+    overwrite: '覆盖',
+    skip: '跳过',
+    rename: '重命名',
+};
+
+const fileName = (path: string) => path.replace(/\\/g, '/').split('/').pop() || path;
+
+/** Answers the backend's "ask" conflict prompts one at a time, in the order they arrive. */
+const ConflictPrompt: React.FC = () => {
+    const [queue, setQueue] = useState<ConflictPromptNotice[]>([]);
+    const current = queue[0];
+
+    useEffect(() => onConflictPrompt((notice) => {
+        setQueue((previous) => [...previous, notice]);
+    }), []);
+
+    useEffect(() => {
+        if (!current) return undefined;
+        // The backend gives up after timeout_seconds and applies the default; drop the stale prompt too.
+        const timer = window.setTimeout(() => {
+            setQueue((previous) => previous.filter((item) => item !== current));
+        }, Math.max(1, current.timeout_seconds) * 1000);
+        return () => window.clearTimeout(timer);
+    }, [current]);
+
+    const answer = useCallback(async (decision: ConflictDecision) => {
+        if (!current) return;
+        setQueue((previous) => previous.filter((item) => item !== current));
+        try {
+            await getAppBindings()?.ResolveConflict?.(current.operation_id, current.path, decision);
+        } catch (err) {
+            console.error(err);
+        }
+    }, [current]);
+
+    if (!current) return null;
+
+    return (
+        <div className="fixed inset-0 z-[200] flex items-center justify-center bg-black/30 backdrop-blur-[1px]">
+            <div role="alertdialog" aria-label="输出文件已存在" className="w-[360px] rounded-2xl bg-white dark:bg-[#2C2C2E] border border-gray-200 dark:border-white/10 shadow-xl p-5">
+                <div className="text-sm font-semibold text-gray-900 dark:text-white">输出文件已存在</div>
+                <div className="mt-2 text-xs text-gray-500 dark:text-gray-400 break-all" title={current.path}>{fileName(current.path)}</div>
+                {queue.length > 1 && (
+                    <div className="mt-1 text-[11px] text-gray-400">还有 {queue.length - 1} 个冲突待处理</div>
+                )}
+                <div className="mt-4 flex justify-end gap-2">
+                    {current.decisions.map((decision) => (
+                        <button
+                            key={decision}
+                            type="button"
+                            onClick={() => void answer(decision)}
+                            className={`px-3 py-1.5 rounded-lg text-sm transition-colors ${decision === current.default
+                                ? 'bg-[#007AFF] text-white hover:bg-[#0066d6]'
+                                : 'bg-gray-100 dark:bg-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-200 dark:hover:bg-white/20'}`}
+                        >
+                            {DECISION_LABELS[decision] || decision}
+                        </button>
+                    ))}
+                </div>
+            </div>
+        </div>
+    );
+};
+
+export default ConflictPrompt;
@@ -366,7 +366,7 @@ type FileDialogFilter = {
 
 const DEFAULT_IMAGE_FILE_DIALOG_FILTERS: FileDialogFilter[] = [{
     DisplayName: "Images",
-    Pattern: "*.jpg;*.jpeg;*.png;*.webp;*.gif;*.bmp;*.avif;*.ico;*.tiff;*.tif;*.heic;*.heif;*.svg",
+    Pattern: "*.jpg;*.jpeg;*.png;*.webp;*.gif;*.bmp;*.avif;*.jxl;*.ico;*.tiff;*.tif;*.heic;*.heif;*.svg",
 }];
 
 type DroppedFile = {
@@ -702,7 +702,7 @@ export const FileDropZone: React.FC<FileDropZoneProps> = ({
     onPathsExpanded,
     onItemSelect,
     selectedPath,
-    acceptedFormats = "image/*,.svg,.avif,.ico",
+    acceptedFormats = "image/*,.svg,.avif,.jxl,.ico",
     fileDialogFilters,
     allowMultiple = true,
     title = "拖拽图片到这里",
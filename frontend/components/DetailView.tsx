@@ -51,10 +51,13 @@ import {
     summarizeBatchProgress,
 } from './batchHelpers';
 import {
+    CONFLICT_STRATEGIES,
     DEFAULT_APP_SETTINGS,
     getAppBindings,
     loadAppSettings,
+    onBatchProgress,
     updateRecentPaths,
+    type ConflictStrategy,
 } from '../types/wails-api';
 import type { models } from '../types/backend-models';
 import { ConverterSettings } from './detail/ConverterSettingsPanel';
@@ -74,6 +77,7 @@ const DEFAULT_IMAGE_INPUT_EXTENSIONS = [
     '.gif',
     '.bmp',
     '.avif',
+    '.jxl',
     '.ico',
     '.tiff',
     '.tif',
@@ -98,6 +102,12 @@ const COMPRESSOR_INPUT_EXTENSIONS = [
     '.tif',
 ];
 
+// Features whose batches go through the backend "ask" conflict prompt; others keep renaming client-side.
+const CONFLICT_PROMPT_FEATURES = new Set(['converter', 'compressor', 'watermark', 'adjust', 'filter']);
+
+// Keep in sync with METADATA_TOKEN_RE in backend/application/output_template.py.
+const METADATA_TEMPLATE_TOKEN = /\{(?:exif:[^{}]+|taken(?::[^{}]+)?|camera|make|lens|iso)\}/i;
+
 const extensionsToAccept = (extensions: string[]) => extensions.join(',');
 const extensionsToDialogPattern = (extensions: string[]) => extensions.map((ext) => `*${ext}`).join(';');
 
@@ -158,6 +168,8 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
     const [failedRecords, setFailedRecords] = useState<DroppedFile[]>([]);
     const [retryFailedOnly, setRetryFailedOnly] = useState(false);
     const currentRunFailedPathsRef = useRef<Set<string> | null>(null);
+    // Per-file templates with EXIF tokens already expanded by the backend for the current run.
+    const templateOverridesRef = useRef<Map<string, string>>(new Map());
 
     const {
         format: convFormat, setFormat: setConvFormat,
@@ -252,6 +264,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
         imageSize: watermarkImageSize, setImageSize: setWatermarkImageSize,
     } = useWatermarkParams();
 
+    const renamesOnClient = (strategy: string) => strategy !== 'ask' || !CONFLICT_PROMPT_FEATURES.has(id);
     const normalizeOutputSettings = (raw?: Partial<OutputSettings> | null): OutputSettings => {
         if (!raw) return defaultOutputSettings;
         return {
@@ -264,8 +277,8 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
             preserve_folder_structure: typeof raw.preserve_folder_structure === 'boolean'
                 ? raw.preserve_folder_structure
                 : defaultOutputSettings.preserve_folder_structure,
-            conflict_strategy: typeof raw.conflict_strategy === 'string' && raw.conflict_strategy.trim() === 'rename'
-                ? 'rename'
+            conflict_strategy: typeof raw.conflict_strategy === 'string' && CONFLICT_STRATEGIES.includes(raw.conflict_strategy.trim() as ConflictStrategy)
+                ? raw.conflict_strategy.trim()
                 : defaultOutputSettings.conflict_strategy,
         };
     };
@@ -1046,7 +1059,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                             return;
                         }
                         let resolvedPath = normalizePath(rawPath);
-                        if (appAny?.ResolveOutputPath && outputSettings.conflict_strategy === 'rename') {
+                        if (appAny?.ResolveOutputPath && renamesOnClient(outputSettings.conflict_strategy)) {
                             try {
                                 const res = await appAny.ResolveOutputPath({
                                     base_path: normalizePath(rawPath),
@@ -1315,7 +1328,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
         }
         const ext = (options.ext || extname(fileName)).toLowerCase();
         let name = buildOutputName(baseName, {
-            template: options.template,
+            template: templateOverridesRef.current.get(normalizePath(file.input_path)) ?? options.template,
             prefix: options.prefix,
             seq: options.seq,
             op: options.op,
@@ -1629,6 +1642,9 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
             INVALID_ACTION: '操作类型无效',
             INTERNAL: '处理过程中发生内部错误',
             PY_CANCELLED: '已取消当前任务',
+            EMPTY_FILE: '文件为空',
+            TRUNCATED_OR_CORRUPT: '文件不完整或已损坏',
+            IMAGE_TOO_LARGE: '图片像素超过上限',
         };
         const prefix = codeMap[code] || '处理失败';
         if (!detail) return prefix;
@@ -1980,7 +1996,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
         const activeHasDirectory = useOverrideFiles ? true : Boolean(dropResult?.has_directory);
         const resolveUniquePath = async (candidate: string) => {
             const normalized = normalizePath(candidate);
-            if (conflictStrategy !== 'rename') {
+            if (!renamesOnClient(conflictStrategy)) {
                 reservedPaths.add(normalized);
                 return normalized;
             }
@@ -2007,7 +2023,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
         const resolveUniquePathsBatch = async (candidates: string[]) => {
             const normalized = candidates.map((item) => normalizePath(item));
             if (!normalized.length) return [] as string[];
-            if (conflictStrategy !== 'rename' || !appAny?.ResolveOutputPaths) {
+            if (!renamesOnClient(conflictStrategy) || !appAny?.ResolveOutputPaths) {
                 const out: string[] = [];
                 for (const item of normalized) {
                     out.push(await resolveUniquePath(item));
@@ -2041,6 +2057,30 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                 ? normalizedOverrideFiles
                 : (manualRetryOnly ? failedRecords : (dropResult?.files || []));
             currentRunFiles = files;
+            templateOverridesRef.current = new Map();
+            if (METADATA_TEMPLATE_TOKEN.test(outputTemplate) && appAny.ExpandTemplateTokens && files.length) {
+                try {
+                    const expanded = await appAny.ExpandTemplateTokens({
+                        template: outputTemplate,
+                        input_paths: files.map((f) => f.input_path),
+                    });
+                    if (expanded?.success && Array.isArray(expanded.templates)) {
+                        files.forEach((f, index) => {
+                            const item = expanded.templates[index];
+                            if (typeof item === 'string') {
+                                templateOverridesRef.current.set(normalizePath(f.input_path), item);
+                            }
+                        });
+                    }
+                    const warnings = (expanded?.warnings || []).flat();
+                    if (warnings.length) {
+                        warnings.forEach((item) => console.warn(item));
+                        setLastMessage(`${warnings[0]}${warnings.length > 1 ? `（共 ${warnings.length} 条元数据警告）` : ''}`);
+                    }
+                } catch (err) {
+                    console.error(err);
+                }
+            }
             const firstInput = files[0];
             const inputDirForHistory = activeHasDirectory
                 ? (firstInput?.source_root || '')
@@ -2062,6 +2102,8 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                     '原图尺寸': 'original',
                     '按比例': 'percent',
                     '固定宽高': 'fixed',
+                    '裁切填充': 'cover',
+                    '留白填充': 'pad',
                     '最长边': 'long_edge',
                 };
                 const resize_mode = resizeModeMap[convResizeMode] ?? 'original';
@@ -2079,6 +2121,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                         reportBatchTaskFailure,
                         getBatchChunkSize,
                         normalizePath,
+                        onBatchProgress,
                     },
                     format,
                     quality: convQuality,
@@ -2143,6 +2186,7 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                         reportBatchTaskFailure,
                         getBatchChunkSize,
                         normalizePath,
+                        onBatchProgress,
                     },
                     level,
                     engine,
@@ -2205,9 +2249,11 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                         reportBatchTaskFailure,
                         getBatchChunkSize,
                         normalizePath,
+                        onBatchProgress,
                     },
                     taskName: '图片水印',
                     label: '水印',
+                    operation: 'watermark',
                     fallbackError: '水印失败',
                     canBatch: Boolean(appAny.AddWatermarkBatch),
                     runSingle: (item) => appAny.AddWatermark!(item),
@@ -2272,9 +2318,11 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                         reportBatchTaskFailure,
                         getBatchChunkSize,
                         normalizePath,
+                        onBatchProgress,
                     },
                     taskName: '图片调整',
                     label: '调整',
+                    operation: 'adjuster',
                     fallbackError: '调整失败',
                     canBatch: Boolean(appAny.AdjustBatch),
                     runSingle: (item) => appAny.Adjust!(item),
@@ -2342,9 +2390,11 @@ const DetailView: React.FC<DetailViewProps> = ({ id, isActive = true, onTaskFail
                         reportBatchTaskFailure,
                         getBatchChunkSize,
                         normalizePath,
+                        onBatchProgress,
                     },
                     taskName: '图片滤镜',
                     label: '滤镜',
+                    operation: 'filter',
                     fallbackError: '滤镜失败',
                     canBatch: Boolean(appAny.ApplyFilterBatch),
                     runSingle: (item) => appAny.ApplyFilter!(item),
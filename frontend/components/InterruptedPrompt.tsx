@@ -0,0 +1,88 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import type { models } from '../types/backend-models';
+import { getAppBindings } from '../types/wails-api';
+
+const MAX_LISTED = 5;
+
+const fileName = (path: string) => path.replace(/\\/g, '/').split('/').pop() || path;
+
+/** Offers to resume or clean up items the previous session was killed in the middle of. */
+const InterruptedPrompt: React.FC = () => {
+    const [items, setItems] = useState<models.InterruptedOperation[]>([]);
+    const [busy, setBusy] = useState(false);
+
+    useEffect(() => {
+        let active = true;
+        const load = getAppBindings()?.GetInterruptedOperations;
+        if (load) {
+            load()
+                .then((list) => {
+                    if (active && Array.isArray(list)) setItems(list);
+                })
+                .catch((err) => console.error(err));
+        }
+        return () => {
+            active = false;
+        };
+    }, []);
+
+    const settle = useCallback(async (action: 'resume' | 'discard') => {
+        const bindings = getAppBindings();
+        const run = action === 'resume' ? bindings?.ResumeInterruptedOperations : bindings?.DiscardInterruptedOperations;
+        if (!run) return;
+        setBusy(true);
+        try {
+            await run(items.map((item) => item.id));
+            setItems([]);
+        } catch (err) {
+            console.error(err);
+        } finally {
+            setBusy(false);
+        }
+    }, [items]);
+
+    if (items.length === 0) return null;
+
+    return (
+        <div className="fixed inset-0 z-[190] flex items-center justify-center bg-black/30 backdrop-blur-[1px]">
+            <div role="alertdialog" aria-label="上次未完成的任务" className="w-[380px] rounded-2xl bg-white dark:bg-[#2C2C2E] border border-gray-200 dark:border-white/10 shadow-xl p-5">
+                <div className="text-sm font-semibold text-gray-900 dark:text-white">上次有 {items.length} 个任务未完成</div>
+                <div className="mt-1 text-xs text-gray-500 dark:text-gray-400">程序在处理过程中被关闭，可以重新处理这些文件，或清理它们留下的临时文件。</div>
+                <ul className="mt-2 max-h-32 overflow-auto text-[11px] text-gray-500 dark:text-gray-400 break-all">
+                    {items.slice(0, MAX_LISTED).map((item) => (
+                        <li key={item.id} title={item.input_path}>{fileName(item.input_path || item.output_path)}</li>
+                    ))}
+                    {items.length > MAX_LISTED && <li>还有 {items.length - MAX_LISTED} 个文件</li>}
+                </ul>
+                <div className="mt-4 flex justify-end gap-2">
+                    <button
+                        type="button"
+                        disabled={busy}
+                        onClick={() => setItems([])}
+                        className="px-3 py-1.5 rounded-lg text-sm bg-gray-100 dark:bg-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-200 dark:hover:bg-white/20 disabled:opacity-60 transition-colors"
+                    >
+                        稍后
+                    </button>
+                    <button
+                        type="button"
+                        disabled={busy}
+                        onClick={() => void settle('discard')}
+                        className="px-3 py-1.5 rounded-lg text-sm bg-gray-100 dark:bg-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-200 dark:hover:bg-white/20 disabled:opacity-60 transition-colors"
+                    >
+                        清理
+                    </button>
+                    <button
+                        type="button"
+                        disabled={busy}
+                        onClick={() => void settle('resume')}
+                        className="px-3 py-1.5 rounded-lg text-sm bg-[#007AFF] text-white hover:bg-[#0066d6] disabled:opacity-60 transition-colors"
+                    >
+                        {busy ? '处理中…' : '继续处理'}
+                    </button>
+                </div>
+            </div>
+        </div>
+    );
+};
+
+export default InterruptedPrompt;
@@ -0,0 +1,81 @@
+// @vitest-environment jsdom
+
+import React from 'react';
+import { afterEach, beforeAll, describe, expect, it } from 'vitest';
+import { createRoot } from 'react-dom/client';
+import { act } from 'react';
+import LongOperationToast from './LongOperationToast';
+import { LONG_OPERATION_EVENT, type LongOperationNotice } from '../types/wails-api';
+
+const notice = (overrides: Partial<LongOperationNotice> = {}): LongOperationNotice => ({
+    type: 'long_operation',
+    operation: 'pdf_generator',
+    task_id: 3,
+    elapsed_seconds: 15,
+    item_count: 1,
+    done: false,
+    ...overrides,
+});
+
+const emit = async (detail: LongOperationNotice) => {
+    await act(async () => {
+        window.dispatchEvent(new CustomEvent(LONG_OPERATION_EVENT, { detail }));
+    });
+};
+
+describe('LongOperationToast', () => {
+    beforeAll(() => {
+        (globalThis as { IS_REACT_ACT_ENVIRONMENT?: boolean }).IS_REACT_ACT_ENVIRONMENT = true;
+    });
+
+    afterEach(() => {
+        document.body.innerHTML = '';
+    });
+
+    const mount = async () => {
+        const host = document.createElement('div');
+        document.body.appendChild(host);
+        const root = createRoot(host);
+        await act(async () => {
+            root.render(<LongOperationToast />);
+        });
+        return { host, root };
+    };
+
+    it('shows a still-processing notice until the operation reports done', async () => {
+        const { host, root } = await mount();
+        expect(host.textContent).toBe('');
+
+        await emit(notice({ item_count: 4 }));
+        expect(host.textContent).toContain('仍在处理');
+        expect(host.textContent).toContain('已超过 15 秒');
+        expect(host.textContent).toContain('共 4 项');
+
+        await emit(notice({ operation: 'converter', task_id: 9 }));
+        await emit(notice({ done: true }));
+        expect(host.textContent).toContain('仍在处理');
+
+        await emit(notice({ operation: 'converter', task_id: 9, done: true }));
+        expect(host.textContent).toBe('');
+
+        await act(async () => {
+            root.unmount();
+        });
+    });
+
+    it('can be dismissed while the operation keeps running', async () => {
+        const { host, root } = await mount();
+        await emit(notice());
+
+        const button = host.querySelector('button');
+        expect(button).toBeTruthy();
+        await act(async () => {
+            button?.dispatchEvent(new MouseEvent('click', { bubbles: true }));
+        });
+        expect(host.textContent).toBe('');
+
+        await act(async () => {
+            root.unmount();
+        });
+    });
+});
@@ -0,0 +1,50 @@
+import React, { useEffect, useState } from 'react';
+import Icon from './Icon';
+import { onLongOperation, type LongOperationNotice } from '../types/wails-api';
+
+const noticeKey = (notice: LongOperationNotice) => `${notice.operation}:${notice.task_id ?? ''}`;
+
+/** "Still processing" while a backend operation runs past the long-operation threshold; informational only. */
+const LongOperationToast: React.FC = () => {
+    const [running, setRunning] = useState<LongOperationNotice[]>([]);
+
+    useEffect(() => onLongOperation((notice) => {
+        const key = noticeKey(notice);
+        setRunning((previous) => {
+            const others = previous.filter((item) => noticeKey(item) !== key);
+            return notice.done ? others : [...others, notice];
+        });
+    }), []);
+
+    const current = running[running.length - 1];
+    if (!current) return null;
+
+    return (
+        <div
+            role="status"
+            aria-live="polite"
+            className="fixed bottom-4 right-4 z-[180] w-[300px] rounded-xl bg-white dark:bg-[#2C2C2E] border border-gray-200 dark:border-white/10 shadow-lg px-4 py-3"
+        >
+            <div className="flex items-start gap-2">
+                <div className="mt-1 h-3 w-3 shrink-0 rounded-full border-2 border-[#007AFF] border-t-transparent animate-spin" />
+                <div className="min-w-0 flex-1">
+                    <div className="text-sm font-medium text-gray-900 dark:text-white">仍在处理，请稍候</div>
+                    <div className="mt-0.5 text-xs text-gray-500 dark:text-gray-400">
+                        已超过 {Math.round(current.elapsed_seconds)} 秒
+                        {current.item_count > 1 ? `，共 ${current.item_count} 项` : ''}，文件较大时需要更长时间，并未卡住。
+                    </div>
+                </div>
+                <button
+                    type="button"
+                    aria-label="关闭提示"
+                    onClick={() => setRunning((previous) => previous.filter((item) => item !== current))}
+                    className="text-gray-400 hover:text-gray-600 dark:hover:text-gray-200"
+                >
+                    <Icon name="X" size={14} />
+                </button>
+            </div>
+        </div>
+    );
+};
+
+export default LongOperationToast;
@@ -0,0 +1,150 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { getAppBindings, onRuntimeProgress, type RuntimeProgressNotice } from '../types/wails-api';
+import type { StartupPhase } from '../types/backend-models';
+
+// Fast launches finish before this; only a slow first start shows the setup screen.
+const SHOW_DELAY_MS = 400;
+
+/**
+ * Covers the app while the backend checks and warms its engines; stays up with the error if that fails.
+ * A launch step that failed before the UI loaded (settings, operation log, ...) is shown here too.
+ */
+const RuntimeSetup: React.FC = () => {
+    const [status, setStatus] = useState<RuntimeProgressNotice | null>(null);
+    const [visible, setVisible] = useState(false);
+    const [dismissed, setDismissed] = useState(false);
+    const [repairing, setRepairing] = useState(false);
+    const [launchFailure, setLaunchFailure] = useState<StartupPhase | null>(null);
+
+    useEffect(() => {
+        let active = true;
+        const unsubscribe = onRuntimeProgress((notice) => setStatus(notice));
+        const load = getAppBindings()?.GetRuntimeStatus;
+        if (load) {
+            load()
+                .then((current) => {
+                    // A progress event may already be newer than this snapshot.
+                    if (active) setStatus((previous) => previous ?? (current as RuntimeProgressNotice));
+                })
+                .catch((err) => console.error(err));
+        }
+        const diagnose = getAppBindings()?.GetStartupDiagnostics;
+        if (diagnose) {
+            diagnose()
+                .then((diagnostics) => {
+                    const failed = diagnostics.phases?.find((phase) => phase.source === 'launch' && phase.state === 'failed');
+                    if (active && failed) setLaunchFailure(failed);
+                })
+                .catch((err) => console.error(err));
+        }
+        const timer = window.setTimeout(() => {
+            if (active) setVisible(true);
+        }, SHOW_DELAY_MS);
+        return () => {
+            active = false;
+            unsubscribe();
+            window.clearTimeout(timer);
+        };
+    }, []);
+
+    const repair = useCallback(async () => {
+        const run = getAppBindings()?.RepairRuntime;
+        if (!run) return;
+        setRepairing(true);
+        try {
+            setStatus((await run()) as RuntimeProgressNotice);
+        } catch (err) {
+            console.error(err);
+        } finally {
+            setRepairing(false);
+        }
+    }, []);
+
+    if (dismissed) return null;
+    if (!status || status.state === 'ready') {
+        return launchFailure ? <LaunchFailureDialog phase={launchFailure} onClose={() => setDismissed(true)} /> : null;
+    }
+    const incomplete = status.state === 'incomplete';
+    const failed = status.state === 'failed' || incomplete;
+    if (!failed && !visible) return null;
+
+    const percent = status.total > 0 ? Math.round((status.current / status.total) * 100) : 0;
+
+    return (
+        <div className="fixed inset-0 z-[210] flex items-center justify-center bg-black/30 backdrop-blur-[1px]">
+            <div role={failed ? 'alertdialog' : 'status'} aria-label="运行环境" className="w-[360px] rounded-2xl bg-white dark:bg-[#2C2C2E] border border-gray-200 dark:border-white/10 shadow-xl p-5">
+                <div className="text-sm font-semibold text-gray-900 dark:text-white">
+                    {incomplete ? '运行环境不完整' : failed ? '运行环境准备失败' : '正在准备运行环境…'}
+                </div>
+                {failed ? (
+                    <>
+                        <div className="mt-2 text-xs text-red-500 break-all">
+                            {status.step ? `${status.step}：` : ''}{status.error || '未知错误'}
+                        </div>
+                        {launchFailure && (
+                            <div className="mt-1 text-[11px] text-gray-500 dark:text-gray-400 break-all">
+                                启动时 {launchFailure.name} 已失败：{launchFailure.error}
+                            </div>
+                        )}
+                        {incomplete && (status.files?.length ?? 0) > 0 && (
+                            <ul className="mt-2 max-h-32 overflow-auto text-[11px] text-gray-500 dark:text-gray-400 break-all">
+                                {status.files.map((file) => <li key={file}>{file}</li>)}
+                                {status.file_count > status.files.length && (
+                                    <li>还有 {status.file_count - status.files.length} 个文件</li>
+                                )}
+                            </ul>
+                        )}
+                    </>
+                ) : (
+                    <>
+                        <div className="mt-3 h-1.5 rounded-full bg-gray-100 dark:bg-white/10 overflow-hidden">
+                            <div className="h-full bg-[#007AFF] transition-all" style={{ width: `${percent}%` }} />
+                        </div>
+                        <div className="mt-2 text-[11px] text-gray-400">
+                            {status.current}/{status.total}{status.step ? ` · ${status.step}` : ''}
+                        </div>
+                    </>
+                )}
+                {failed && (
+                    <div className="mt-4 flex justify-end gap-2">
+                        <button
+                            type="button"
+                            disabled={repairing}
+                            onClick={() => void repair()}
+                            className="px-3 py-1.5 rounded-lg text-sm bg-[#007AFF] text-white hover:bg-[#0066d6] disabled:opacity-60 transition-colors"
+                        >
+                            {repairing ? '正在检查…' : '重新检查'}
+                        </button>
+                        <button
+                            type="button"
+                            onClick={() => setDismissed(true)}
+                            className="px-3 py-1.5 rounded-lg text-sm bg-gray-100 dark:bg-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-200 dark:hover:bg-white/20 transition-colors"
+                        >
+                            关闭
+                        </button>
+                    </div>
+                )}
+            </div>
+        </div>
+    );
+};
+
+const LaunchFailureDialog: React.FC<{ phase: StartupPhase; onClose: () => void }> = ({ phase, onClose }) => (
+    <div className="fixed inset-0 z-[210] flex items-center justify-center bg-black/30 backdrop-blur-[1px]">
+        <div role="alertdialog" aria-label="启动错误" className="w-[360px] rounded-2xl bg-white dark:bg-[#2C2C2E] border border-gray-200 dark:border-white/10 shadow-xl p-5">
+            <div className="text-sm font-semibold text-gray-900 dark:text-white">启动步骤失败</div>
+            <div className="mt-2 text-xs text-red-500 break-all">{phase.name}：{phase.error || '未知错误'}</div>
+            <div className="mt-4 flex justify-end">
+                <button
+                    type="button"
+                    onClick={onClose}
+                    className="px-3 py-1.5 rounded-lg text-sm bg-gray-100 dark:bg-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-200 dark:hover:bg-white/20 transition-colors"
+                >
+                    关闭
+                </button>
+            </div>
+        </div>
+    </div>
+);
+
+export default RuntimeSetup;
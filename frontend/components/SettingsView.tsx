@@ -5,12 +5,41 @@ import {
     DEFAULT_APP_SETTINGS,
     getAppBindings,
     loadAppSettings,
+    resetAppSettings,
     saveAppSettings,
+    type AppLanguage,
     type AppSettingsSnapshot,
+    type BatchOrder,
+    type ConflictStrategy,
+    type PreviewMode,
 } from '../types/wails-api';
 
 const clamp = (value: number, min: number, max: number) => Math.max(min, Math.min(max, value));
 
+const PREVIEW_MODE_OPTIONS: { value: PreviewMode; label: string }[] = [
+    { value: 'auto', label: '自动（大文件跳过）' },
+    { value: 'always-fast', label: '始终快速缩略图' },
+    { value: 'always-full', label: '始终原尺寸' },
+    { value: 'never', label: '关闭预览' },
+];
+
+const CONFLICT_STRATEGY_OPTIONS: { value: ConflictStrategy; label: string }[] = [
+    { value: 'rename', label: '自动重命名' },
+    { value: 'ask', label: '每次询问' },
+];
+
+const BATCH_ORDER_OPTIONS: { value: BatchOrder; label: string }[] = [
+    { value: 'as-is', label: '按添加顺序' },
+    { value: 'smallest-first', label: '小文件优先' },
+    { value: 'largest-first', label: '大文件优先' },
+    { value: 'name', label: '按文件名' },
+];
+
+const LANGUAGE_OPTIONS: { value: AppLanguage; label: string }[] = [
+    { value: 'zh', label: '简体中文' },
+    { value: 'en', label: 'English' },
+];
+
 const SettingsView: React.FC = () => {
     const [settings, setSettings] = useState<AppSettingsSnapshot>({ ...DEFAULT_APP_SETTINGS });
     const [saving, setSaving] = useState(false);
@@ -61,6 +90,21 @@ const SettingsView: React.FC = () => {
         }
     };
 
+    const handleReset = async () => {
+        setSaving(true);
+        setMessage('');
+        try {
+            setSettings(await resetAppSettings());
+            setMessage('已恢复默认设置');
+            window.setTimeout(() => setMessage(''), 1800);
+        } catch (error: any) {
+            console.error(error);
+            setMessage(error?.message ? `重置失败：${error.message}` : '重置失败');
+        } finally {
+            setSaving(false);
+        }
+    };
+
     const handleSelectDefaultOutputDir = async () => {
         const app = getAppBindings();
         if (!app?.SelectOutputDirectory) {
@@ -99,6 +143,14 @@ const SettingsView: React.FC = () => {
                             ? '读取中...'
                             : (message ? <span className={message.includes('失败') ? 'text-red-500' : 'text-[#007AFF]'}>{message}</span> : '已就绪')}
                     </div>
+                    <button
+                        type="button"
+                        disabled={saving || loading}
+                        onClick={() => void handleReset()}
+                        className="px-4 py-2.5 rounded-xl text-sm border border-gray-200 dark:border-white/10 text-gray-600 dark:text-gray-300 hover:text-red-500 hover:border-red-200 transition-colors disabled:opacity-50 disabled:cursor-not-allowed"
+                    >
+                        恢复默认
+                    </button>
                     <button
                         type="button"
                         disabled={saving || loading}
@@ -224,9 +276,35 @@ const SettingsView: React.FC = () => {
                                         onChange={(checked) => setSettings((previous) => ({ ...previous, preserve_folder_structure: checked }))}
                                         label="保持原文件夹结构"
                                     />
+                                    <Switch
+                                        checked={settings.open_folder_when_done}
+                                        onChange={(checked) => setSettings((previous) => ({ ...previous, open_folder_when_done: checked }))}
+                                        label="批处理完成后打开输出文件夹"
+                                    />
+                                    <Switch
+                                        checked={settings.notify_on_completion}
+                                        onChange={(checked) => setSettings((previous) => ({ ...previous, notify_on_completion: checked }))}
+                                        label="长时间任务完成后发送系统通知"
+                                    />
+                                    <Switch
+                                        checked={settings.use_working_copy}
+                                        onChange={(checked) => setSettings((previous) => ({ ...previous, use_working_copy: checked }))}
+                                        label="处理前复制源文件（避免文件被占用）"
+                                    />
                                     <div className="flex items-center justify-between gap-4 text-sm">
                                         <span className="text-gray-500 dark:text-gray-400">重名文件处理</span>
-                                        <span className="px-2.5 py-1 rounded-full bg-[#007AFF]/10 text-[#007AFF]">自动重命名</span>
+                                        <select
+                                            value={settings.conflict_strategy}
+                                            onChange={(event) => setSettings((previous) => ({
+                                                ...previous,
+                                                conflict_strategy: event.target.value as ConflictStrategy,
+                                            }))}
+                                            className="px-3 py-1.5 rounded-xl bg-gray-100 dark:bg-white/10 text-sm text-gray-700 dark:text-gray-200 outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                        >
+                                            {CONFLICT_STRATEGY_OPTIONS.map((option) => (
+                                                <option key={option.value} value={option.value}>{option.label}</option>
+                                            ))}
+                                        </select>
                                     </div>
                                 </div>
                             </div>
@@ -278,6 +356,154 @@ const SettingsView: React.FC = () => {
                                     className="absolute inset-0 w-full h-full opacity-0 cursor-pointer z-30"
                                 />
                             </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">预览模式</div>
+                                <select
+                                    value={settings.preview_mode}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        preview_mode: event.target.value as PreviewMode,
+                                    }))}
+                                    className="px-3 py-2 rounded-xl bg-gray-100 dark:bg-white/10 text-sm text-gray-700 dark:text-gray-200 outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                >
+                                    {PREVIEW_MODE_OPTIONS.map((option) => (
+                                        <option key={option.value} value={option.value}>{option.label}</option>
+                                    ))}
+                                </select>
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">批处理顺序</div>
+                                <select
+                                    value={settings.batch_order}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        batch_order: event.target.value as BatchOrder,
+                                    }))}
+                                    className="px-3 py-2 rounded-xl bg-gray-100 dark:bg-white/10 text-sm text-gray-700 dark:text-gray-200 outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                >
+                                    {BATCH_ORDER_OPTIONS.map((option) => (
+                                        <option key={option.value} value={option.value}>{option.label}</option>
+                                    ))}
+                                </select>
+                            </div>
+
+                            <div className="mt-4 space-y-2">
+                                <Switch
+                                    checked={settings.use_ram_temp}
+                                    onChange={(checked) => setSettings((previous) => ({ ...previous, use_ram_temp: checked }))}
+                                    label="临时文件放在内存盘（更快，占用内存）"
+                                />
+                                {settings.use_ram_temp && (
+                                    <input
+                                        type="text"
+                                        value={settings.ram_temp_dir}
+                                        onChange={(event) => setSettings((previous) => ({ ...previous, ram_temp_dir: event.target.value }))}
+                                        placeholder="内存盘目录；Linux 留空使用 /dev/shm，不可用时回退到系统临时目录"
+                                        className="w-full px-3 py-2.5 rounded-xl bg-gray-50 dark:bg-white/5 border border-gray-200 dark:border-white/10 text-sm focus:border-[#007AFF] focus:ring-1 focus:ring-[#007AFF] outline-none dark:text-white"
+                                    />
+                                )}
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">单张图片像素上限（百万）</div>
+                                <input
+                                    type="number"
+                                    min={1}
+                                    max={10000}
+                                    value={settings.max_megapixels}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        max_megapixels: clamp(Math.round(Number(event.target.value || 1)), 1, 10000),
+                                    }))}
+                                    className="w-24 text-center text-gray-700 dark:text-gray-200 font-mono text-sm bg-gray-100 dark:bg-white/10 px-3 py-2 rounded-xl outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                />
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">动图帧数上限</div>
+                                <input
+                                    type="number"
+                                    min={1}
+                                    max={100000}
+                                    value={settings.gif_max_frames}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        gif_max_frames: clamp(Math.round(Number(event.target.value || 1)), 1, 100000),
+                                    }))}
+                                    className="w-24 text-center text-gray-700 dark:text-gray-200 font-mono text-sm bg-gray-100 dark:bg-white/10 px-3 py-2 rounded-xl outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                />
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">动图像素总量上限（百万）</div>
+                                <input
+                                    type="number"
+                                    min={1}
+                                    max={100000}
+                                    value={settings.gif_max_megapixels}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        gif_max_megapixels: clamp(Math.round(Number(event.target.value || 1)), 1, 100000),
+                                    }))}
+                                    className="w-24 text-center text-gray-700 dark:text-gray-200 font-mono text-sm bg-gray-100 dark:bg-white/10 px-3 py-2 rounded-xl outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                />
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">导出帧文件数上限</div>
+                                <input
+                                    type="number"
+                                    min={1}
+                                    max={100000}
+                                    value={settings.gif_max_export_files}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        gif_max_export_files: clamp(Math.round(Number(event.target.value || 1)), 1, 100000),
+                                    }))}
+                                    className="w-24 text-center text-gray-700 dark:text-gray-200 font-mono text-sm bg-gray-100 dark:bg-white/10 px-3 py-2 rounded-xl outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                />
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">编辑会话可撤销步数</div>
+                                <input
+                                    type="number"
+                                    min={1}
+                                    max={100}
+                                    value={settings.edit_history_depth}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        edit_history_depth: clamp(Math.round(Number(event.target.value || 1)), 1, 100),
+                                    }))}
+                                    className="w-24 text-center text-gray-700 dark:text-gray-200 font-mono text-sm bg-gray-100 dark:bg-white/10 px-3 py-2 rounded-xl outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                />
+                            </div>
+
+                            <div className="mt-4">
+                                <Switch
+                                    checked={settings.safe_mode}
+                                    onChange={(checked) => setSettings((previous) => ({ ...previous, safe_mode: checked }))}
+                                    label="安全模式（下次启动生效：不使用进程池，跳过运行环境检查）"
+                                />
+                            </div>
+
+                            <div className="flex items-center justify-between gap-3 mt-4">
+                                <div className="text-sm font-medium text-gray-700 dark:text-gray-300">提示语言</div>
+                                <select
+                                    value={settings.language}
+                                    onChange={(event) => setSettings((previous) => ({
+                                        ...previous,
+                                        language: event.target.value as AppLanguage,
+                                    }))}
+                                    className="px-3 py-2 rounded-xl bg-gray-100 dark:bg-white/10 text-sm text-gray-700 dark:text-gray-200 outline-none focus:ring-2 focus:ring-[#007AFF]/30 border border-transparent focus:border-[#007AFF]"
+                                >
+                                    {LANGUAGE_OPTIONS.map((option) => (
+                                        <option key={option.value} value={option.value}>{option.label}</option>
+                                    ))}
+                                </select>
+                            </div>
                         </section>
 
                         <section className="bg-white dark:bg-[#2C2C2E] rounded-3xl border border-gray-200 dark:border-white/10 shadow-sm p-6">
@@ -69,12 +69,12 @@ export const ConverterSettings = memo(({
             <div className="grid grid-cols-[1fr_2fr] gap-4">
                 <CustomSelect 
                     label="目标格式" 
-                    options={['JPG', 'PNG', 'WEBP', 'AVIF', 'TIFF', 'ICO', 'BMP']} 
+                    options={['JPG', 'PNG', 'WEBP', 'AVIF', 'JXL', 'TIFF', 'ICO', 'BMP']} 
                     value={format}
                     onChange={setFormat}
                 />
                 
-                {['JPG', 'WEBP', 'AVIF'].includes(format) && (
+                {['JPG', 'WEBP', 'AVIF', 'JXL'].includes(format) && (
                     <div className="space-y-2">
                         <label className="text-sm font-medium text-gray-700 dark:text-gray-300">输出质量</label>
                         <div className="h-10 flex items-center">
@@ -113,7 +113,7 @@ export const ConverterSettings = memo(({
                     </div>
                 )}
 
-                {!['JPG', 'WEBP', 'AVIF', 'PNG', 'ICO'].includes(format) && (
+                {!['JPG', 'WEBP', 'AVIF', 'JXL', 'PNG', 'ICO'].includes(format) && (
                     <div className="flex items-center justify-center h-full pt-6">
                         <span className="text-xs text-gray-400">无额外设置</span>
                     </div>
@@ -124,9 +124,9 @@ export const ConverterSettings = memo(({
             <div className="space-y-2">
                 <label className="text-sm font-medium text-gray-700 dark:text-gray-300">尺寸调整</label>
                 <SegmentedControl 
-                    options={['原图', '比例', '固定', '长边']}
-                    value={resizeMode === '原图尺寸' ? '原图' : resizeMode === '按比例' ? '比例' : resizeMode === '固定宽高' ? '固定' : '长边'}
-                    onChange={(v) => setResizeMode(v === '原图' ? '原图尺寸' : v === '比例' ? '按比例' : v === '固定' ? '固定宽高' : '最长边')}
+                    options={['原图', '比例', '固定', '裁切', '留白', '长边']}
+                    value={resizeMode === '原图尺寸' ? '原图' : resizeMode === '按比例' ? '比例' : resizeMode === '固定宽高' ? '固定' : resizeMode === '裁切填充' ? '裁切' : resizeMode === '留白填充' ? '留白' : '长边'}
+                    onChange={(v) => setResizeMode(v === '原图' ? '原图尺寸' : v === '比例' ? '按比例' : v === '固定' ? '固定宽高' : v === '裁切' ? '裁切填充' : v === '留白' ? '留白填充' : '最长边')}
                 />
             </div>
 
@@ -134,7 +134,7 @@ export const ConverterSettings = memo(({
                  <StyledSlider label="缩放比例" value={scalePercent} min={1} max={200} unit="%" onChange={setScalePercent} />
             )}
 
-            {resizeMode === '固定宽高' && (
+            {(resizeMode === '固定宽高' || resizeMode === '裁切填充' || resizeMode === '留白填充') && (
                 <div className="flex flex-col gap-3 animate-enter">
                     <div className="flex gap-3">
                         <div className="flex-1 space-y-1">
@@ -146,15 +146,21 @@ export const ConverterSettings = memo(({
                             <input type="number" value={fixedHeight || ''} onChange={e => setFixedHeight(Number(e.target.value || 0))} className="w-full px-3 py-2.5 rounded-xl bg-gray-50 dark:bg-white/5 border border-gray-200 dark:border-white/10 text-sm focus:border-[#007AFF] focus:ring-1 focus:ring-[#007AFF] outline-none dark:text-white" placeholder="自动" />
                         </div>
                     </div>
-                    <div className="flex items-center justify-between">
-                         <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
-                             保持纵横比
-                             <span className="block text-xs font-normal text-gray-500 mt-0.5">
-                                 若关闭则强制拉伸至指定宽高
-                             </span>
-                         </label>
-                         <Switch checked={maintainAR} onChange={setMaintainAR} />
-                    </div>
+                    {resizeMode === '裁切填充' ? (
+                        <span className="text-xs text-gray-500">等比缩放铺满目标尺寸，居中裁掉多余部分</span>
+                    ) : resizeMode === '留白填充' ? (
+                        <span className="text-xs text-gray-500">等比缩放完整放入目标尺寸，空白处透明（不支持透明的格式填充白色）</span>
+                    ) : (
+                        <div className="flex items-center justify-between">
+                             <label className="text-sm font-medium text-gray-700 dark:text-gray-300">
+                                 保持纵横比
+                                 <span className="block text-xs font-normal text-gray-500 mt-0.5">
+                                     若关闭则强制拉伸至指定宽高
+                                 </span>
+                             </label>
+                             <Switch checked={maintainAR} onChange={setMaintainAR} />
+                        </div>
+                    )}
                 </div>
             )}
 
@@ -36,12 +36,34 @@ export type BatchRunnerContext = {
     reportBatchTaskFailure: (taskName: string, files: DroppedFile[], reason: unknown, fallback?: string) => void;
     getBatchChunkSize: (itemCount: number, requestsPerItem?: number) => number;
     normalizePath: (p: string) => string;
+    onBatchProgress?: (callback: (notice: { operation: string; current: number; done: boolean }) => void) => () => void;
 };
 
+/** Move the bar per finished file while one backend batch call is still in flight. */
+async function withLiveProgress<T>(
+    ctx: BatchRunnerContext,
+    operation: string,
+    settledBefore: number,
+    total: number,
+    run: () => Promise<T>,
+): Promise<T> {
+    const unsubscribe = ctx.onBatchProgress?.((notice) => {
+        if (notice.operation !== operation || notice.done || total <= 0) return;
+        ctx.setProgressThrottled(((settledBefore + notice.current) / total) * 100);
+    });
+    try {
+        return await run();
+    } finally {
+        unsubscribe?.();
+    }
+}
+
 export async function runGenericBatch(options: {
     ctx: BatchRunnerContext;
     taskName: string;
     label: string;
+    /** Backend engine module name, used to match progress notices to this run. */
+    operation?: string;
     fallbackError: string;
     buildChunk: (group: DroppedFile[], seqStart: number) => Promise<{ chunk: any[]; nextSeq: number }>;
     runSingle: (item: any) => Promise<any>;
@@ -52,6 +74,7 @@ export async function runGenericBatch(options: {
         ctx,
         taskName,
         label,
+        operation = '',
         fallbackError,
         buildChunk,
         runSingle,
@@ -74,7 +97,7 @@ export async function runGenericBatch(options: {
 
         try {
             const res = (chunk.length > 1 && canBatch && runBatch)
-                ? await runBatch(chunk)
+                ? await withLiveProgress(ctx, operation, completed, total, () => runBatch(chunk))
                 : [await runSingle(chunk[0])];
             const outcome = normalizeBatchResults(res, chunk, fallbackError);
             completed += outcome.settled;
@@ -215,8 +238,8 @@ export async function runConvertBatch(options: {
                     compress_level: compressLevel,
                     ico_sizes: isIcoFormat ? icoSizeGroup : [],
                     icoSizes: isIcoFormat ? icoSizeGroup : [],
-                    width: resizeMode === 'fixed' ? fixedWidth : 0,
-                    height: resizeMode === 'fixed' ? fixedHeight : 0,
+                    width: resizeMode === 'fixed' || resizeMode === 'cover' || resizeMode === 'pad' ? fixedWidth : 0,
+                    height: resizeMode === 'fixed' || resizeMode === 'cover' || resizeMode === 'pad' ? fixedHeight : 0,
                     maintain_ar: maintainAR,
                     resize_mode: resizeMode,
                     scale_percent: resizeMode === 'percent' ? scalePercent : 0,
@@ -230,7 +253,7 @@ export async function runConvertBatch(options: {
         try {
             const res = chunk.length === 1
                 ? [await ctx.app.Convert!(chunk[0])]
-                : await ctx.app.ConvertBatch!(chunk);
+                : await withLiveProgress(ctx, 'converter', completed, totalTasks, () => ctx.app.ConvertBatch!(chunk));
             const outcome = normalizeBatchResults(res, chunk, '转换失败');
             completed += outcome.settled;
             failed += outcome.failed;
@@ -323,7 +346,7 @@ export async function runCompressBatch(options: {
         try {
             const res = chunk.length === 1
                 ? [await ctx.app.Compress!(chunk[0])]
-                : await ctx.app.CompressBatch!(chunk);
+                : await withLiveProgress(ctx, 'compressor', completed, ctx.total, () => ctx.app.CompressBatch!(chunk));
             const outcome = normalizeBatchResults(res, chunk, '压缩失败');
             completed += outcome.settled;
             failed += outcome.failed;
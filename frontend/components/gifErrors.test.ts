@@ -4,6 +4,7 @@ import { resolveGifErrorMessage } from './gifErrors';
 describe('resolveGifErrorMessage', () => {
     it('returns mapped message by code', () => {
         expect(resolveGifErrorMessage('GIF_MEMORY_LIMIT', 'x')).toBe('GIF 体积过大，超出安全处理上限');
+        expect(resolveGifErrorMessage('gif_too_large', 'x')).toBe('动图帧数、像素总量或导出文件数超过设置上限');
     });
 
     it('falls back to backend error text', () => {
@@ -16,6 +16,7 @@ const GIF_ERROR_MESSAGES: Record<string, string> = {
     ANIMATED_CONVERT_BAD_INPUT: '输入不是可互转的动图或参数无效',
     ANIMATED_CONVERT_FAILED: '动图互转失败',
     GIF_MEMORY_LIMIT: 'GIF 体积过大，超出安全处理上限',
+    GIF_TOO_LARGE: '动图帧数、像素总量或导出文件数超过设置上限',
     GIF_INTERNAL_ERROR: 'GIF 处理发生内部错误',
     GIF_INVALID_JSON: '请求数据格式错误',
 };
@@ -1,5 +1,7 @@
 import type { models } from './backend-models';
 
+export type ConflictDecision = 'overwrite' | 'skip' | 'rename';
+
 export type AppBindings = {
     ProbeAnimatedPaths?: (arg1: Array<string>) => Promise<Array<{
         input_path: string;
@@ -12,34 +14,75 @@ export type AppBindings = {
     AddWatermarkBatch: (arg1: Array<models.WatermarkRequest>) => Promise<Array<models.WatermarkResult>>;
     Adjust: (arg1: models.AdjustRequest) => Promise<models.AdjustResult>;
     AdjustBatch: (arg1: Array<models.AdjustRequest>) => Promise<Array<models.AdjustResult>>;
+    ApplyEdit?: (sessionId: string, arg2: Partial<models.AdjustRequest & models.FilterRequest> & { operation?: 'adjust' | 'filter' }) => Promise<models.EditSessionState>;
     ApplyFilter: (arg1: models.FilterRequest) => Promise<models.FilterResult>;
     ApplyFilterBatch: (arg1: Array<models.FilterRequest>) => Promise<Array<models.FilterResult>>;
+    ApplyPreset?: (preset: string, arg2: models.ConvertRequest) => Promise<models.ExportPresetApplyResult>;
+    AssignColorProfile?: (arg1: models.ProfileRequest) => Promise<models.ProfileResult>;
+    BeginEditSession?: (path: string) => Promise<models.EditSessionState>;
+    CancelEdit?: (sessionId: string) => Promise<models.EditSessionState>;
     CancelProcessing: () => Promise<boolean> | boolean;
+    CommitEdit?: (sessionId: string, outputPath: string) => Promise<models.EditCommitResult>;
     Compress: (arg1: models.CompressRequest) => Promise<models.CompressResult>;
     CompressBatch: (arg1: Array<models.CompressRequest>) => Promise<Array<models.CompressResult>>;
+    CompressToQuality?: (arg1: models.QualityTargetRequest) => Promise<models.QualityTargetResult>;
     Convert: (arg1: models.ConvertRequest) => Promise<models.ConvertResult>;
     ConvertBatch: (arg1: Array<models.ConvertRequest>) => Promise<Array<models.ConvertResult>>;
+    ConvertBatchByRule?: (arg1: models.ConvertByRuleRequest) => Promise<Array<models.ConvertResult>>;
+    ConvertByRules?: (arg1: models.RuleConvertRequest) => Promise<models.RuleConvertResult>;
+    DiscardInterruptedOperations?: (operationIds?: Array<string>) => Promise<models.InterruptedDiscardResult>;
     EditMetadata: (arg1: models.MetadataEditRequest) => Promise<models.MetadataEditResult>;
+    EstimateCompression?: (arg1: models.CompressRequest) => Promise<models.CompressResult>;
+    ExpandArchive?: (arg1: string) => Promise<models.ExpandDroppedPathsResult>;
     ExpandDroppedPaths: (arg1: Array<string>) => Promise<models.ExpandDroppedPathsResult>;
+    ExpandTemplateTokens?: (arg1: models.TemplateTokensRequest) => Promise<models.TemplateTokensResult>;
+    ExportInfoReport?: (arg1: { input_paths: Array<string>; output_path: string; format?: string }) => Promise<{ success: boolean; output_path?: string; format?: string; row_count?: number; size?: number; failed_count?: number; error?: string }>;
+    ExportSettings?: () => Promise<string>;
     GeneratePDF: (arg1: models.PDFRequest) => Promise<models.PDFResult>;
     GenerateSubtitleLongImage: (arg1: models.SubtitleStitchRequest) => Promise<models.SubtitleStitchResult>;
+    GetCapabilities?: () => Promise<models.Capabilities>;
+    GetExportPresets?: () => Promise<Array<models.ExportPreset>>;
+    GetFormatCapabilities?: () => Promise<models.FormatMatrix>;
+    GetGIFDetails?: (arg1: models.InfoRequest) => Promise<models.GIFDetails>;
     GetImagePreview: (arg1: models.PreviewRequest) => Promise<models.PreviewResult>;
     GetInfo: (arg1: models.InfoRequest) => Promise<models.InfoResult>;
+    GetInfoBatch?: (arg1: Array<models.InfoRequest>) => Promise<Array<models.InfoResult>>;
+    GetInterruptedOperations?: () => Promise<Array<models.InterruptedOperation>>;
+    GetRuntimeStatus?: () => Promise<models.RuntimeStatus>;
     GetSettings: () => Promise<models.AppSettings>;
+    GetStartupDiagnostics?: () => Promise<models.StartupDiagnostics>;
+    GetStartupError?: () => Promise<models.StartupError>;
+    HammingDistance?: (a: string, b: string) => Promise<{ success: boolean; distance?: number; error?: string }>;
+    ImportSettings?: (arg1: string) => Promise<models.AppSettings | { success: false; error: string }>;
+    JPEGSizeCurve?: (arg1: models.SizeCurveRequest) => Promise<models.SizeCurveResult>;
     ListSystemFonts: () => Promise<Array<string>>;
+    OverlayGuides?: (arg1: models.GuidesRequest) => Promise<models.GuidesResult>;
+    PackResultsZip?: (arg1: models.PackZipRequest) => Promise<models.ZipResult>;
+    PlanBatch?: (operation: string, requests: Array<Record<string, any>> | string) => Promise<models.BatchPlan>;
     Ping: () => Promise<string> | string;
+    PreviewWatermarkGrid?: (arg1: models.WatermarkRequest) => Promise<models.WatermarkGridPreview>;
+    RepairRuntime?: () => Promise<models.RuntimeStatus>;
+    RepackArchive?: (arg1: models.RepackArchiveRequest) => Promise<models.ZipResult>;
+    ResetSettings?: () => Promise<models.AppSettings>;
+    ResolveConflict?: (operationId: string, path: string, decision: ConflictDecision) => Promise<models.ConflictResolveResult>;
     ResolveOutputPath: (arg1: models.ResolveOutputPathRequest) => Promise<models.ResolveOutputPathResult>;
     ResolveOutputPaths?: (arg1: { items: Array<string>; reserved?: Array<string> }) => Promise<{
         success: boolean;
         paths?: Array<string>;
         error?: string;
     }>;
+    ResumeInterruptedOperations?: (operationIds?: Array<string>) => Promise<models.InterruptedResumeResult>;
+    RevealPath?: (path: string) => Promise<{ success: boolean }>;
+    RunManifest?: (manifestPath: string) => Promise<models.ManifestResult>;
     SaveSettings: (arg1: models.AppSettings) => Promise<models.AppSettings>;
     SelectInputDirectory: () => Promise<string>;
     SelectInputFiles: (options?: unknown) => Promise<Array<string>>;
     SelectOutputDirectory: () => Promise<string>;
+    SetOrientation?: (arg1: models.OrientationRequest) => Promise<models.OrientationResult>;
     SplitGIF: (arg1: models.GIFSplitRequest) => Promise<models.GIFSplitResult>;
     StripMetadata: (arg1: models.MetadataStripRequest) => Promise<models.MetadataStripResult>;
+    SummarizeResults?: (operation: string, results: Array<Record<string, any>>, elapsedMs?: number) => Promise<models.BatchSummary>;
+    UndoEdit?: (sessionId: string) => Promise<models.EditSessionState>;
     UpdateRecentPaths: (arg1: models.RecentPathsUpdateRequest) => Promise<models.AppSettings>;
     OpenFileDialog?: (options?: unknown) => Promise<string | string[] | null | undefined>;
     OpenDirectoryDialog?: (options?: unknown) => Promise<string | null | undefined>;
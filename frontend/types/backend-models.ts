@@ -15,6 +15,9 @@ export namespace models {
 	    sharpness: number;
 	    crop_ratio: string;
 	    crop_mode: string;
+	    preserve_16bit?: boolean;
+	    auto_level?: boolean;
+	    skip_up_to_date?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new AdjustRequest(source);
@@ -36,6 +39,9 @@ export namespace models {
 	        this.sharpness = source["sharpness"];
 	        this.crop_ratio = source["crop_ratio"];
 	        this.crop_mode = source["crop_mode"];
+	        this.preserve_16bit = source["preserve_16bit"];
+	        this.auto_level = source["auto_level"];
+	        this.skip_up_to_date = source["skip_up_to_date"];
 	    }
 	}
 	export class AdjustResult {
@@ -43,6 +49,13 @@ export namespace models {
 	    input_path: string;
 	    output_path: string;
 	    error?: string;
+	    file_size?: number;
+	    tone_mapped?: boolean;
+	    warning?: string;
+	    level_roll?: number | null;
+	    auto_level_applied?: boolean;
+	    level_data_missing?: boolean;
+	    skipped?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new AdjustResult(source);
@@ -54,6 +67,13 @@ export namespace models {
 	        this.input_path = source["input_path"];
 	        this.output_path = source["output_path"];
 	        this.error = source["error"];
+	        this.file_size = source["file_size"];
+	        this.tone_mapped = source["tone_mapped"];
+	        this.warning = source["warning"];
+	        this.level_roll = source["level_roll"];
+	        this.auto_level_applied = source["auto_level_applied"];
+	        this.level_data_missing = source["level_data_missing"];
+	        this.skipped = source["skipped"];
 	    }
 	}
 	export class AppSettings {
@@ -65,6 +85,21 @@ export namespace models {
 	    default_output_dir: string;
 	    recent_input_dirs: string[];
 	    recent_output_dirs: string[];
+	    preview_mode: string;
+	    format_quality_defaults: Record<string, number>;
+	    language: string;
+	    open_folder_when_done: boolean;
+	    notify_on_completion: boolean;
+	    use_working_copy: boolean;
+	    batch_order: string;
+	    use_ram_temp: boolean;
+	    ram_temp_dir: string;
+	    max_megapixels: number;
+	    gif_max_frames: number;
+	    gif_max_megapixels: number;
+	    gif_max_export_files: number;
+	    safe_mode: boolean;
+	    edit_history_depth: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new AppSettings(source);
@@ -80,6 +115,57 @@ export namespace models {
 	        this.default_output_dir = source["default_output_dir"];
 	        this.recent_input_dirs = source["recent_input_dirs"];
 	        this.recent_output_dirs = source["recent_output_dirs"];
+	        this.preview_mode = source["preview_mode"];
+	        this.format_quality_defaults = source["format_quality_defaults"];
+	        this.language = source["language"];
+	        this.open_folder_when_done = source["open_folder_when_done"];
+	        this.notify_on_completion = source["notify_on_completion"];
+	        this.use_working_copy = source["use_working_copy"];
+	        this.batch_order = source["batch_order"];
+	        this.use_ram_temp = source["use_ram_temp"];
+	        this.ram_temp_dir = source["ram_temp_dir"];
+	        this.max_megapixels = source["max_megapixels"];
+	        this.gif_max_frames = source["gif_max_frames"];
+	        this.gif_max_megapixels = source["gif_max_megapixels"];
+	        this.gif_max_export_files = source["gif_max_export_files"];
+	        this.safe_mode = source["safe_mode"];
+	        this.edit_history_depth = source["edit_history_depth"];
+	    }
+	}
+	export class BatchSummary {
+	    success: boolean;
+	    operation: string;
+	    total: number;
+	    succeeded: number;
+	    failed: number;
+	    cancelled: number;
+	    input_bytes: number;
+	    output_bytes: number;
+	    total_time_ms: number;
+	    average_compression_rate: number;
+	    error?: string;
+	    skipped?: number;
+	    engines?: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.operation = source["operation"];
+	        this.total = source["total"];
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	        this.cancelled = source["cancelled"];
+	        this.input_bytes = source["input_bytes"];
+	        this.output_bytes = source["output_bytes"];
+	        this.total_time_ms = source["total_time_ms"];
+	        this.average_compression_rate = source["average_compression_rate"];
+	        this.error = source["error"];
+	        this.skipped = source["skipped"];
+	        this.engines = source["engines"];
 	    }
 	}
 	export class CompressRequest {
@@ -89,6 +175,12 @@ export namespace models {
 	    engine?: string;
 	    target_size_kb?: number;
 	    strip_metadata?: boolean;
+	    quality?: number;
+	    skip_up_to_date?: boolean;
+	    target_ssim?: number;
+	    estimate?: boolean;
+	    lossless?: boolean;
+	    strip_mode?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new CompressRequest(source);
@@ -102,6 +194,12 @@ export namespace models {
 	        this.engine = source["engine"];
 	        this.target_size_kb = source["target_size_kb"];
 	        this.strip_metadata = source["strip_metadata"];
+	        this.quality = source["quality"];
+	        this.skip_up_to_date = source["skip_up_to_date"];
+	        this.target_ssim = source["target_ssim"];
+	        this.estimate = source["estimate"];
+	        this.lossless = source["lossless"];
+	        this.strip_mode = source["strip_mode"];
 	    }
 	}
 	export class CompressResult {
@@ -114,6 +212,18 @@ export namespace models {
 	    compression_level: number;
 	    warning?: string;
 	    error?: string;
+	    skipped?: boolean;
+	    elapsed_ms?: number;
+	    quality?: number;
+	    ssim?: number;
+	    target_ssim?: number;
+	    target_reached?: boolean;
+	    estimate?: boolean;
+	    target_size_kb?: number;
+	    target_met?: boolean;
+	    lossless?: boolean;
+	    engine_used?: string;
+	    strip_mode?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new CompressResult(source);
@@ -130,6 +240,32 @@ export namespace models {
 	        this.compression_level = source["compression_level"];
 	        this.warning = source["warning"];
 	        this.error = source["error"];
+	        this.skipped = source["skipped"];
+	        this.elapsed_ms = source["elapsed_ms"];
+	        this.quality = source["quality"];
+	        this.ssim = source["ssim"];
+	        this.target_ssim = source["target_ssim"];
+	        this.target_reached = source["target_reached"];
+	        this.estimate = source["estimate"];
+	        this.target_size_kb = source["target_size_kb"];
+	        this.target_met = source["target_met"];
+	        this.lossless = source["lossless"];
+	        this.engine_used = source["engine_used"];
+	        this.strip_mode = source["strip_mode"];
+	    }
+	}
+	export class ConflictResolveResult {
+	    success: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictResolveResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
 	    }
 	}
 	export class ConvertRequest {
@@ -147,6 +283,24 @@ export namespace models {
 	    compress_level: number;
 	    ico_sizes: number[];
 	    icoSizes?: number[];
+	    preserve_16bit?: boolean;
+	    lossless?: boolean;
+	    background_color?: string;
+	    skip_up_to_date?: boolean;
+	    crop_anchor?: string;
+	    progressive?: boolean;
+	    subsampling?: string;
+	    keep_color_profile?: boolean;
+	    auto_orient?: boolean;
+	    tiff_compression?: string;
+	    preserve_bit_depth?: boolean;
+	    trim_borders?: boolean;
+	    trim_tolerance?: number;
+	    pad_color?: string;
+	    max_megapixels?: number;
+	    dpi?: number;
+	    strip_metadata?: boolean;
+	    target_size_kb?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ConvertRequest(source);
@@ -168,13 +322,58 @@ export namespace models {
 	        this.compress_level = source["compress_level"];
 	        this.ico_sizes = source["ico_sizes"];
 	        this.icoSizes = source["icoSizes"];
+	        this.preserve_16bit = source["preserve_16bit"];
+	        this.lossless = source["lossless"];
+	        this.background_color = source["background_color"];
+	        this.skip_up_to_date = source["skip_up_to_date"];
+	        this.crop_anchor = source["crop_anchor"];
+	        this.progressive = source["progressive"];
+	        this.subsampling = source["subsampling"];
+	        this.keep_color_profile = source["keep_color_profile"];
+	        this.auto_orient = source["auto_orient"];
+	        this.tiff_compression = source["tiff_compression"];
+	        this.preserve_bit_depth = source["preserve_bit_depth"];
+	        this.trim_borders = source["trim_borders"];
+	        this.trim_tolerance = source["trim_tolerance"];
+	        this.pad_color = source["pad_color"];
+	        this.max_megapixels = source["max_megapixels"];
+	        this.dpi = source["dpi"];
+	        this.strip_metadata = source["strip_metadata"];
+	        this.target_size_kb = source["target_size_kb"];
 	    }
 	}
 	export class ConvertResult {
 	    success: boolean;
 	    input_path: string;
 	    output_path: string;
+	    was_cmyk?: boolean;
 	    error?: string;
+	    tone_mapped?: boolean;
+	    warning?: string;
+	    lossless?: boolean;
+	    jxl_distance?: number;
+	    missing_plugin?: string;
+	    copied?: boolean;
+	    rule?: ConvertRule;
+	    skipped?: boolean;
+	    progressive?: boolean;
+	    kept_color_profile?: boolean;
+	    converted_to_srgb?: boolean;
+	    auto_oriented?: boolean;
+	    file_size?: number;
+	    tiff_compression?: string;
+	    bit_depth?: number;
+	    trimmed?: boolean;
+	    trimmed_width?: number;
+	    trimmed_height?: number;
+	    ico_sizes?: number[];
+	    elapsed_ms?: number;
+	    dpi?: number;
+	    color_mode?: string;
+	    stripped_metadata?: boolean;
+	    target_size_kb?: number;
+	    target_met?: boolean;
+	    quality?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new ConvertResult(source);
@@ -185,8 +384,155 @@ export namespace models {
 	        this.success = source["success"];
 	        this.input_path = source["input_path"];
 	        this.output_path = source["output_path"];
+	        this.was_cmyk = source["was_cmyk"];
+	        this.error = source["error"];
+	        this.tone_mapped = source["tone_mapped"];
+	        this.warning = source["warning"];
+	        this.lossless = source["lossless"];
+	        this.jxl_distance = source["jxl_distance"];
+	        this.missing_plugin = source["missing_plugin"];
+	        this.copied = source["copied"];
+	        this.rule = this.convertValues(source["rule"], ConvertRule);
+	        this.skipped = source["skipped"];
+	        this.progressive = source["progressive"];
+	        this.kept_color_profile = source["kept_color_profile"];
+	        this.converted_to_srgb = source["converted_to_srgb"];
+	        this.auto_oriented = source["auto_oriented"];
+	        this.file_size = source["file_size"];
+	        this.tiff_compression = source["tiff_compression"];
+	        this.bit_depth = source["bit_depth"];
+	        this.trimmed = source["trimmed"];
+	        this.trimmed_width = source["trimmed_width"];
+	        this.trimmed_height = source["trimmed_height"];
+	        this.ico_sizes = source["ico_sizes"];
+	        this.elapsed_ms = source["elapsed_ms"];
+	        this.dpi = source["dpi"];
+	        this.color_mode = source["color_mode"];
+	        this.stripped_metadata = source["stripped_metadata"];
+	        this.target_size_kb = source["target_size_kb"];
+	        this.target_met = source["target_met"];
+	        this.quality = source["quality"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConvertByRuleRequest {
+	    input_paths: string[];
+	    formats: Record<string, string | { format: string; quality?: number }>;
+	    output_dir?: string;
+	    options?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConvertByRuleRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_paths = source["input_paths"];
+	        this.formats = source["formats"];
+	        this.output_dir = source["output_dir"];
+	        this.options = source["options"];
+	    }
+	}
+	export class ConvertRule {
+	    index?: number;
+	    source: string[];
+	    format: string;
+	    quality?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConvertRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.source = source["source"];
+	        this.format = source["format"];
+	        this.quality = source["quality"];
+	    }
+	}
+	export class RuleConvertRequest {
+	    rules: ConvertRule[];
+	    requests: ConvertRequest[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleConvertRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rules = this.convertValues(source["rules"], ConvertRule);
+	        this.requests = this.convertValues(source["requests"], ConvertRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RuleConvertResult {
+	    success: boolean;
+	    error?: string;
+	    results: ConvertResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RuleConvertResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
 	        this.error = source["error"];
+	        this.results = this.convertValues(source["results"], ConvertResult);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class DroppedFile {
 	    input_path: string;
@@ -195,6 +541,8 @@ export namespace models {
 	    is_from_dir_drop: boolean;
 	    size: number;
 	    mod_time: number;
+	    archive_path?: string;
+	    archive_member?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DroppedFile(source);
@@ -208,11 +556,60 @@ export namespace models {
 	        this.is_from_dir_drop = source["is_from_dir_drop"];
 	        this.size = source["size"];
 	        this.mod_time = source["mod_time"];
+	        this.archive_path = source["archive_path"];
+	        this.archive_member = source["archive_member"];
+	    }
+	}
+	export class EditCommitResult {
+	    success: boolean;
+	    error?: string;
+	    session_id?: string;
+	    input_path?: string;
+	    output_path?: string;
+	    edits?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditCommitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.session_id = source["session_id"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.edits = source["edits"];
+	    }
+	}
+	export class EditSessionState {
+	    success: boolean;
+	    error?: string;
+	    session_id?: string;
+	    input_path?: string;
+	    working_path?: string;
+	    edits?: number;
+	    can_undo?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditSessionState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.session_id = source["session_id"];
+	        this.input_path = source["input_path"];
+	        this.working_path = source["working_path"];
+	        this.edits = source["edits"];
+	        this.can_undo = source["can_undo"];
 	    }
 	}
 	export class ExpandDroppedPathsResult {
 	    files: DroppedFile[];
 	    has_directory: boolean;
+	    warnings?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new ExpandDroppedPathsResult(source);
@@ -222,6 +619,59 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.files = this.convertValues(source["files"], DroppedFile);
 	        this.has_directory = source["has_directory"];
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ExportPreset {
+	    id: string;
+	    label: string;
+	    description: string;
+	    settings: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.description = source["description"];
+	        this.settings = source["settings"];
+	    }
+	}
+	export class ExportPresetApplyResult {
+	    success: boolean;
+	    request?: ConvertRequest;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportPresetApplyResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.request = this.convertValues(source["request"], ConvertRequest);
+	        this.error = source["error"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -249,6 +699,8 @@ export namespace models {
 	    intensity: number;
 	    grain: number;
 	    vignette: number;
+	    colorblind_type?: string;
+	    skip_up_to_date?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new FilterRequest(source);
@@ -262,6 +714,8 @@ export namespace models {
 	        this.intensity = source["intensity"];
 	        this.grain = source["grain"];
 	        this.vignette = source["vignette"];
+	        this.colorblind_type = source["colorblind_type"];
+	        this.skip_up_to_date = source["skip_up_to_date"];
 	    }
 	}
 	export class FilterResult {
@@ -269,6 +723,7 @@ export namespace models {
 	    input_path: string;
 	    output_path: string;
 	    error?: string;
+	    skipped?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new FilterResult(source);
@@ -280,54 +735,267 @@ export namespace models {
 	        this.input_path = source["input_path"];
 	        this.output_path = source["output_path"];
 	        this.error = source["error"];
+	        this.skipped = source["skipped"];
 	    }
 	}
-	export class GIFSplitRequest {
-	    action?: string;
-	    input_path?: string;
-	    input_paths?: string[];
-	    output_dir?: string;
-	    output_path?: string;
-	    output_format?: string;
-	    frame_range?: string;
-	    start_frame?: number;
-	    end_frame?: number;
-	    format?: string;
-	    speed_factor?: number;
-	    fps?: number;
-	    quality?: number;
-	    width?: number;
-	    height?: number;
-	    maintain_aspect: boolean;
-	    loop?: number;
+	export class FormatCapabilities {
+	    extensions: string[];
+	    alpha: boolean;
+	    animation: boolean;
+	    lossless: boolean;
+	    quality: boolean;
+	    progressive: boolean;
+	    metadata: boolean;
+	    multi_page: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new GIFSplitRequest(source);
+	        return new FormatCapabilities(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.action = source["action"];
-	        this.input_path = source["input_path"];
-	        this.input_paths = source["input_paths"];
-	        this.output_dir = source["output_dir"];
-	        this.output_path = source["output_path"];
-	        this.output_format = source["output_format"];
-	        this.frame_range = source["frame_range"];
-	        this.start_frame = source["start_frame"];
-	        this.end_frame = source["end_frame"];
-	        this.format = source["format"];
-	        this.speed_factor = source["speed_factor"];
-	        this.fps = source["fps"];
+	        this.extensions = source["extensions"];
+	        this.alpha = source["alpha"];
+	        this.animation = source["animation"];
+	        this.lossless = source["lossless"];
 	        this.quality = source["quality"];
-	        this.width = source["width"];
-	        this.height = source["height"];
-	        this.maintain_aspect = source["maintain_aspect"];
-	        this.loop = source["loop"];
+	        this.progressive = source["progressive"];
+	        this.metadata = source["metadata"];
+	        this.multi_page = source["multi_page"];
 	    }
 	}
-	export class GIFSplitResult {
-	    success: boolean;
+	export class Capabilities {
+	    app_version: string;
+	    python: Record<string, string>;
+	    compression_engines: Record<string, boolean>;
+	    codecs: Record<string, boolean>;
+	    pdf: Record<string, boolean>;
+	    models: Record<string, boolean>;
+	    formats: FormatMatrix;
+	    generated_at: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Capabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app_version = source["app_version"];
+	        this.python = source["python"];
+	        this.compression_engines = source["compression_engines"];
+	        this.codecs = source["codecs"];
+	        this.pdf = source["pdf"];
+	        this.models = source["models"];
+	        this.formats = this.convertValues(source["formats"], FormatMatrix);
+	        this.generated_at = source["generated_at"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FormatMatrix {
+	    features: string[];
+	    aliases: Record<string, string>;
+	    formats: Record<string, FormatCapabilities>;
+	
+	    static createFrom(source: any = {}) {
+	        return new FormatMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.features = source["features"];
+	        this.aliases = source["aliases"];
+	        this.formats = this.convertValues(source["formats"], FormatCapabilities, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GIFDetails {
+	    success: boolean;
+	    input_path?: string;
+	    file_size?: number;
+	    width?: number;
+	    height?: number;
+	    frame_count?: number;
+	    palette_size?: number;
+	    loop?: number;
+	    total_duration_ms?: number;
+	    largest_frame?: number;
+	    frames?: GIFFrameDetail[];
+	    error?: string;
+	    error_code?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GIFDetails(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.file_size = source["file_size"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.frame_count = source["frame_count"];
+	        this.palette_size = source["palette_size"];
+	        this.loop = source["loop"];
+	        this.total_duration_ms = source["total_duration_ms"];
+	        this.largest_frame = source["largest_frame"];
+	        this.frames = this.convertValues(source["frames"], GIFFrameDetail);
+	        this.error = source["error"];
+	        this.error_code = source["error_code"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GIFFrameDetail {
+	    index: number;
+	    delay_ms: number;
+	    disposal: number;
+	    bytes: number;
+	    cumulative_ms: number;
+	    left?: number;
+	    top?: number;
+	    width?: number;
+	    height?: number;
+	    palette_size?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GIFFrameDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.delay_ms = source["delay_ms"];
+	        this.disposal = source["disposal"];
+	        this.bytes = source["bytes"];
+	        this.cumulative_ms = source["cumulative_ms"];
+	        this.left = source["left"];
+	        this.top = source["top"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.palette_size = source["palette_size"];
+	    }
+	}
+	export class GIFSplitRequest {
+	    action?: string;
+	    input_path?: string;
+	    input_paths?: string[];
+	    output_dir?: string;
+	    output_path?: string;
+	    output_format?: string;
+	    frame_range?: string;
+	    start_frame?: number;
+	    end_frame?: number;
+	    format?: string;
+	    speed_factor?: number;
+	    fps?: number;
+	    quality?: number;
+	    width?: number;
+	    height?: number;
+	    maintain_aspect: boolean;
+	    loop?: number;
+	    max_frames?: number;
+	    max_total_megapixels?: number;
+	    max_export_files?: number;
+	    maintain_ar?: boolean;
+	    crop_x?: number;
+	    crop_y?: number;
+	    crop_width?: number;
+	    crop_height?: number;
+	    max_colors?: number;
+	    dither?: boolean;
+	    timestamp_ms?: number;
+	    grid_columns?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GIFSplitRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.input_path = source["input_path"];
+	        this.input_paths = source["input_paths"];
+	        this.output_dir = source["output_dir"];
+	        this.output_path = source["output_path"];
+	        this.output_format = source["output_format"];
+	        this.frame_range = source["frame_range"];
+	        this.start_frame = source["start_frame"];
+	        this.end_frame = source["end_frame"];
+	        this.format = source["format"];
+	        this.speed_factor = source["speed_factor"];
+	        this.fps = source["fps"];
+	        this.quality = source["quality"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.maintain_aspect = source["maintain_aspect"];
+	        this.loop = source["loop"];
+	        this.max_frames = source["max_frames"];
+	        this.max_total_megapixels = source["max_total_megapixels"];
+	        this.max_export_files = source["max_export_files"];
+	        this.maintain_ar = source["maintain_ar"];
+	        this.crop_x = source["crop_x"];
+	        this.crop_y = source["crop_y"];
+	        this.crop_width = source["crop_width"];
+	        this.crop_height = source["crop_height"];
+	        this.max_colors = source["max_colors"];
+	        this.dither = source["dither"];
+	        this.timestamp_ms = source["timestamp_ms"];
+	        this.grid_columns = source["grid_columns"];
+	    }
+	}
+	export class GIFSplitResult {
+	    success: boolean;
 	    input_path?: string;
 	    input_paths?: string[];
 	    output_dir?: string;
@@ -346,6 +1014,26 @@ export namespace models {
 	    error_detail?: string;
 	    warning?: string;
 	    error?: string;
+	    maintain_aspect?: boolean;
+	    output_format?: string;
+	    source_format?: string;
+	    loop?: number;
+	    crop_x?: number;
+	    crop_y?: number;
+	    max_colors?: number;
+	    dither?: boolean;
+	    color_count?: number;
+	    input_size?: number;
+	    output_size?: number;
+	    source_frame_count?: number;
+	    frame_index?: number;
+	    timestamp_ms?: number;
+	    frame_start_ms?: number;
+	    total_duration_ms?: number;
+	    sheet_frame_count?: number;
+	    frame_indices?: number[];
+	    grid_columns?: number;
+	    grid_rows?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new GIFSplitResult(source);
@@ -372,6 +1060,68 @@ export namespace models {
 	        this.error_detail = source["error_detail"];
 	        this.warning = source["warning"];
 	        this.error = source["error"];
+	        this.maintain_aspect = source["maintain_aspect"];
+	        this.output_format = source["output_format"];
+	        this.source_format = source["source_format"];
+	        this.loop = source["loop"];
+	        this.crop_x = source["crop_x"];
+	        this.crop_y = source["crop_y"];
+	        this.max_colors = source["max_colors"];
+	        this.dither = source["dither"];
+	        this.color_count = source["color_count"];
+	        this.input_size = source["input_size"];
+	        this.output_size = source["output_size"];
+	        this.source_frame_count = source["source_frame_count"];
+	        this.frame_index = source["frame_index"];
+	        this.timestamp_ms = source["timestamp_ms"];
+	        this.frame_start_ms = source["frame_start_ms"];
+	        this.total_duration_ms = source["total_duration_ms"];
+	        this.sheet_frame_count = source["sheet_frame_count"];
+	        this.frame_indices = source["frame_indices"];
+	        this.grid_columns = source["grid_columns"];
+	        this.grid_rows = source["grid_rows"];
+	    }
+	}
+	export class GuidesRequest {
+	    input_path: string;
+	    output_path: string;
+	    guide_type: string;
+	    guide_color?: string;
+	    opacity?: number;
+	    line_width?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GuidesRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.guide_type = source["guide_type"];
+	        this.guide_color = source["guide_color"];
+	        this.opacity = source["opacity"];
+	        this.line_width = source["line_width"];
+	    }
+	}
+	export class GuidesResult {
+	    success: boolean;
+	    input_path: string;
+	    output_path: string;
+	    file_size?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GuidesResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.file_size = source["file_size"];
+	        this.error = source["error"];
 	    }
 	}
 	export class InfoBasic {
@@ -388,6 +1138,7 @@ export namespace models {
 	    modified?: number;
 	    orientation?: string;
 	    has_alpha?: boolean;
+	    is_cmyk?: boolean;
 	    is_animated?: boolean;
 	    frame_count?: number;
 	    duration_ms?: number;
@@ -414,6 +1165,7 @@ export namespace models {
 	        this.modified = source["modified"];
 	        this.orientation = source["orientation"];
 	        this.has_alpha = source["has_alpha"];
+	        this.is_cmyk = source["is_cmyk"];
 	        this.is_animated = source["is_animated"];
 	        this.frame_count = source["frame_count"];
 	        this.duration_ms = source["duration_ms"];
@@ -446,6 +1198,8 @@ export namespace models {
 	}
 	export class InfoRequest {
 	    input_path: string;
+	    include_hash?: boolean;
+	    palette_size?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new InfoRequest(source);
@@ -454,6 +1208,8 @@ export namespace models {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.input_path = source["input_path"];
+	        this.include_hash = source["include_hash"];
+	        this.palette_size = source["palette_size"];
 	    }
 	}
 	export class InfoWarning {
@@ -489,6 +1245,15 @@ export namespace models {
 	    warnings?: InfoWarning[];
 	    histogram?: Record<string, Array<number>>;
 	    error?: string;
+	    level_roll?: number | null;
+	    has_level_data?: boolean;
+	    latitude?: number;
+	    longitude?: number;
+	    has_gps?: boolean;
+	    perceptual_hash?: string;
+	    dominant_colors?: string[];
+	    has_alpha: boolean;
+	    has_transparency: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new InfoResult(source);
@@ -514,6 +1279,15 @@ export namespace models {
 	        this.warnings = this.convertValues(source["warnings"], InfoWarning);
 	        this.histogram = source["histogram"];
 	        this.error = source["error"];
+	        this.level_roll = source["level_roll"];
+	        this.has_level_data = source["has_level_data"];
+	        this.latitude = source["latitude"];
+	        this.longitude = source["longitude"];
+	        this.has_gps = source["has_gps"];
+	        this.perceptual_hash = source["perceptual_hash"];
+	        this.dominant_colors = source["dominant_colors"];
+	        this.has_alpha = source["has_alpha"];
+	        this.has_transparency = source["has_transparency"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -535,6 +1309,56 @@ export namespace models {
 		}
 	}
 	
+	export class InterruptedDiscardResult {
+	    success: boolean;
+	    count: number;
+	    removed: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InterruptedDiscardResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.count = source["count"];
+	        this.removed = source["removed"];
+	    }
+	}
+	export class InterruptedOperation {
+	    id: string;
+	    module: string;
+	    input_path: string;
+	    output_path: string;
+	    started_at: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new InterruptedOperation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.module = source["module"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.started_at = source["started_at"];
+	    }
+	}
+	export class InterruptedResumeResult {
+	    success: boolean;
+	    results: Array<Record<string, any>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new InterruptedResumeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.results = source["results"];
+	    }
+	}
 	export class MetadataEditRequest {
 	    input_path: string;
 	    output_path: string;
@@ -605,6 +1429,46 @@ export namespace models {
 	        this.error = source["error"];
 	    }
 	}
+	export class OrientationRequest {
+	    input_path: string;
+	    output_path?: string;
+	    overwrite?: boolean;
+	    orientation: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrientationRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.overwrite = source["overwrite"];
+	        this.orientation = source["orientation"];
+	    }
+	}
+	export class OrientationResult {
+	    success: boolean;
+	    input_path?: string;
+	    output_path?: string;
+	    orientation?: number;
+	    previous_orientation?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrientationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.orientation = source["orientation"];
+	        this.previous_orientation = source["previous_orientation"];
+	        this.error = source["error"];
+	    }
+	}
 	export class PDFRequest {
 	    image_paths: string[];
 	    output_path: string;
@@ -615,6 +1479,20 @@ export namespace models {
 	    fit_mode?: string;
 	    title: string;
 	    author: string;
+	    images_per_page?: number;
+	    grid_columns?: number;
+	    user_password?: string;
+	    owner_password?: string;
+	    permissions?: string[];
+	    page_numbers?: boolean;
+	    header_text?: string;
+	    footer_text?: string;
+	    bookmarks?: string[];
+	    auto_bookmark_from_filename?: boolean;
+	    append_to_path?: string;
+	    image_dpi?: number;
+	    subject?: string;
+	    keywords?: string[];
 	
 	    static createFrom(source: any = {}) {
 	        return new PDFRequest(source);
@@ -631,6 +1509,20 @@ export namespace models {
 	        this.fit_mode = source["fit_mode"];
 	        this.title = source["title"];
 	        this.author = source["author"];
+	        this.images_per_page = source["images_per_page"];
+	        this.grid_columns = source["grid_columns"];
+	        this.user_password = source["user_password"];
+	        this.owner_password = source["owner_password"];
+	        this.permissions = source["permissions"];
+	        this.page_numbers = source["page_numbers"];
+	        this.header_text = source["header_text"];
+	        this.footer_text = source["footer_text"];
+	        this.bookmarks = source["bookmarks"];
+	        this.auto_bookmark_from_filename = source["auto_bookmark_from_filename"];
+	        this.append_to_path = source["append_to_path"];
+	        this.image_dpi = source["image_dpi"];
+	        this.subject = source["subject"];
+	        this.keywords = source["keywords"];
 	    }
 	}
 	export class PDFResult {
@@ -639,7 +1531,15 @@ export namespace models {
 	    page_count: number;
 	    file_size: number;
 	    error?: string;
-	
+	    fit_mode?: string;
+	    compression_level?: number;
+	    encryption?: string;
+	    warning?: string;
+	    bookmark_count?: number;
+	    appended_to?: string;
+	    image_dpi?: number;
+	    downsampled_count?: number;
+	
 	    static createFrom(source: any = {}) {
 	        return new PDFResult(source);
 	    }
@@ -651,6 +1551,14 @@ export namespace models {
 	        this.page_count = source["page_count"];
 	        this.file_size = source["file_size"];
 	        this.error = source["error"];
+	        this.fit_mode = source["fit_mode"];
+	        this.compression_level = source["compression_level"];
+	        this.encryption = source["encryption"];
+	        this.warning = source["warning"];
+	        this.bookmark_count = source["bookmark_count"];
+	        this.appended_to = source["appended_to"];
+	        this.image_dpi = source["image_dpi"];
+	        this.downsampled_count = source["downsampled_count"];
 	    }
 	}
 	export class PreviewRequest {
@@ -668,6 +1576,7 @@ export namespace models {
 	export class PreviewResult {
 	    success: boolean;
 	    data_url?: string;
+	    was_cmyk?: boolean;
 	    error?: string;
 	
 	    static createFrom(source: any = {}) {
@@ -678,6 +1587,109 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.success = source["success"];
 	        this.data_url = source["data_url"];
+	        this.was_cmyk = source["was_cmyk"];
+	        this.error = source["error"];
+	    }
+	}
+	export class ProfileRequest {
+	    input_path: string;
+	    output_path: string;
+	    mode: string;
+	    profile: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.mode = source["mode"];
+	        this.profile = source["profile"];
+	    }
+	}
+	export class ProfileResult {
+	    success: boolean;
+	    input_path?: string;
+	    output_path?: string;
+	    mode?: string;
+	    converted?: boolean;
+	    profile?: string;
+	    had_embedded_profile?: boolean;
+	    warning?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.mode = source["mode"];
+	        this.converted = source["converted"];
+	        this.profile = source["profile"];
+	        this.had_embedded_profile = source["had_embedded_profile"];
+	        this.warning = source["warning"];
+	        this.error = source["error"];
+	    }
+	}
+	export class QualityTargetRequest {
+	    input_path: string;
+	    output_path: string;
+	    target_ssim: number;
+	    level?: number;
+	    engine?: string;
+	    strip_metadata?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new QualityTargetRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.target_ssim = source["target_ssim"];
+	        this.level = source["level"];
+	        this.engine = source["engine"];
+	        this.strip_metadata = source["strip_metadata"];
+	    }
+	}
+	export class QualityTargetResult {
+	    success: boolean;
+	    input_path?: string;
+	    output_path?: string;
+	    original_size?: number;
+	    compressed_size?: number;
+	    compression_rate?: number;
+	    quality?: number;
+	    ssim?: number;
+	    target_ssim?: number;
+	    target_reached?: boolean;
+	    warning?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QualityTargetResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.original_size = source["original_size"];
+	        this.compressed_size = source["compressed_size"];
+	        this.compression_rate = source["compression_rate"];
+	        this.quality = source["quality"];
+	        this.ssim = source["ssim"];
+	        this.target_ssim = source["target_ssim"];
+	        this.target_reached = source["target_reached"];
+	        this.warning = source["warning"];
 	        this.error = source["error"];
 	    }
 	}
@@ -727,6 +1739,186 @@ export namespace models {
 	        this.error = source["error"];
 	    }
 	}
+	export class RuntimeStatus {
+	    type?: string;
+	    state: string;
+	    current: number;
+	    total: number;
+	    step: string;
+	    error: string;
+	    files?: string[];
+	    file_count?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RuntimeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.state = source["state"];
+	        this.current = source["current"];
+	        this.total = source["total"];
+	        this.step = source["step"];
+	        this.error = source["error"];
+	        this.files = source["files"];
+	        this.file_count = source["file_count"];
+	    }
+	}
+	export class SizeCurvePoint {
+	    quality: number;
+	    estimated_bytes: number;
+	    ssim: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeCurvePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.quality = source["quality"];
+	        this.estimated_bytes = source["estimated_bytes"];
+	        this.ssim = source["ssim"];
+	    }
+	}
+	export class SizeCurveRequest {
+	    input_path: string;
+	    qualities?: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeCurveRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input_path = source["input_path"];
+	        this.qualities = source["qualities"];
+	    }
+	}
+	export class SizeCurveResult {
+	    success: boolean;
+	    input_path?: string;
+	    width?: number;
+	    height?: number;
+	    proxy_width?: number;
+	    proxy_height?: number;
+	    points?: SizeCurvePoint[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeCurveResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.proxy_width = source["proxy_width"];
+	        this.proxy_height = source["proxy_height"];
+	        this.points = this.convertValues(source["points"], SizeCurvePoint);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StartupPhase {
+	    name: string;
+	    state: string;
+	    error: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupPhase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.source = source["source"];
+	    }
+	}
+	export class StartupDiagnostics {
+	    ok: boolean;
+	    phase: string;
+	    error: string;
+	    safe_mode: boolean;
+	    runtime_state: string;
+	    phases: StartupPhase[];
+	    decisions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupDiagnostics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.phase = source["phase"];
+	        this.error = source["error"];
+	        this.safe_mode = source["safe_mode"];
+	        this.runtime_state = source["runtime_state"];
+	        this.phases = this.convertValues(source["phases"], StartupPhase);
+	        this.decisions = source["decisions"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StartupError {
+	    ok: boolean;
+	    step: string;
+	    error: string;
+	    safe_mode: boolean;
+	    decisions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.step = source["step"];
+	        this.error = source["error"];
+	        this.safe_mode = source["safe_mode"];
+	        this.decisions = source["decisions"];
+	    }
+	}
 	export class SubtitleStitchRequest {
 	    input_paths: string[];
 	    output_path: string;
@@ -779,6 +1971,60 @@ export namespace models {
 	        this.error = source["error"];
 	    }
 	}
+	export class TemplateTokensRequest {
+	    template: string;
+	    input_paths: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateTokensRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.template = source["template"];
+	        this.input_paths = source["input_paths"];
+	    }
+	}
+	export class TemplateTokensResult {
+	    success: boolean;
+	    templates: string[];
+	    warnings: string[][];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TemplateTokensResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.templates = source["templates"];
+	        this.warnings = source["warnings"];
+	        this.error = source["error"];
+	    }
+	}
+	export class WatermarkGridPreview {
+	    success: boolean;
+	    data_url?: string;
+	    positions?: string[];
+	    cell_width?: number;
+	    cell_height?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatermarkGridPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.data_url = source["data_url"];
+	        this.positions = source["positions"];
+	        this.cell_width = source["cell_width"];
+	        this.cell_height = source["cell_height"];
+	        this.error = source["error"];
+	    }
+	}
 	export class WatermarkRequest {
 	    input_path: string;
 	    output_path: string;
@@ -797,6 +2043,7 @@ export namespace models {
 	    shadow: boolean;
 	    offset_x: number;
 	    offset_y: number;
+	    skip_up_to_date?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new WatermarkRequest(source);
@@ -821,6 +2068,7 @@ export namespace models {
 	        this.shadow = source["shadow"];
 	        this.offset_x = source["offset_x"];
 	        this.offset_y = source["offset_y"];
+	        this.skip_up_to_date = source["skip_up_to_date"];
 	    }
 	}
 	export class WatermarkResult {
@@ -828,6 +2076,7 @@ export namespace models {
 	    input_path: string;
 	    output_path: string;
 	    error?: string;
+	    skipped?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new WatermarkResult(source);
@@ -839,8 +2088,231 @@ export namespace models {
 	        this.input_path = source["input_path"];
 	        this.output_path = source["output_path"];
 	        this.error = source["error"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class RepackArchiveItem {
+	    output_path: string;
+	    relative_path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepackArchiveItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output_path = source["output_path"];
+	        this.relative_path = source["relative_path"];
 	    }
 	}
+	export class RepackArchiveRequest {
+	    output_path: string;
+	    items: RepackArchiveItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepackArchiveRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output_path = source["output_path"];
+	        this.items = this.convertValues(source["items"], RepackArchiveItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PackZipRequest {
+	    output_path: string;
+	    paths?: string[];
+	    directory?: string;
+	    root?: string;
+	    preserve_structure?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PackZipRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output_path = source["output_path"];
+	        this.paths = source["paths"];
+	        this.directory = source["directory"];
+	        this.root = source["root"];
+	        this.preserve_structure = source["preserve_structure"];
+	    }
+	}
+	export class ZipResult {
+	    success: boolean;
+	    output_path?: string;
+	    file_count?: number;
+	    size?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ZipResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.output_path = source["output_path"];
+	        this.file_count = source["file_count"];
+	        this.size = source["size"];
+	        this.error = source["error"];
+	    }
+	}
+	export class BatchPlanItem {
+	    index: number;
+	    input_path: string;
+	    output_path: string;
+	    format: string;
+	    skip: boolean;
+	    warnings: string[];
+	    error?: string;
+	    up_to_date?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchPlanItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.format = source["format"];
+	        this.skip = source["skip"];
+	        this.warnings = source["warnings"];
+	        this.error = source["error"];
+	        this.up_to_date = source["up_to_date"];
+	    }
+	}
+	export class BatchPlan {
+	    success: boolean;
+	    operation?: string;
+	    total?: number;
+	    skipped?: number;
+	    items?: BatchPlanItem[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.operation = source["operation"];
+	        this.total = source["total"];
+	        this.skipped = source["skipped"];
+	        this.items = this.convertValues(source["items"], BatchPlanItem);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ManifestEntryResult {
+	    line: number;
+	    operation: string;
+	    success: boolean;
+	    input_path?: string;
+	    output_path?: string;
+	    error?: string;
+	    warning?: string;
+	    skipped?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ManifestEntryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.operation = source["operation"];
+	        this.success = source["success"];
+	        this.input_path = source["input_path"];
+	        this.output_path = source["output_path"];
+	        this.error = source["error"];
+	        this.warning = source["warning"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class ManifestResult {
+	    success: boolean;
+	    error?: string;
+	    errors?: string[];
+	    manifest_path?: string;
+	    total?: number;
+	    succeeded?: number;
+	    failed?: number;
+	    results: ManifestEntryResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ManifestResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.errors = source["errors"];
+	        this.manifest_path = source["manifest_path"];
+	        this.total = source["total"];
+	        this.succeeded = source["succeeded"];
+	        this.failed = source["failed"];
+	        this.results = this.convertValues(source["results"], ManifestEntryResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -1,4 +1,4 @@
-import type { AppBindings } from './backend-bindings';
+import type { AppBindings, ConflictDecision } from './backend-bindings';
 import { getDesktopBindings } from './desktop-api';
 
 type FilePathRuntime = {
@@ -19,8 +19,39 @@ export type AppSettingsSnapshot = {
     default_output_dir: string;
     recent_input_dirs: string[];
     recent_output_dirs: string[];
+    preview_mode: PreviewMode;
+    format_quality_defaults: Record<string, number>;
+    language: AppLanguage;
+    open_folder_when_done: boolean;
+    notify_on_completion: boolean;
+    use_working_copy: boolean;
+    batch_order: BatchOrder;
+    use_ram_temp: boolean;
+    ram_temp_dir: string;
+    max_megapixels: number;
+    gif_max_frames: number;
+    gif_max_megapixels: number;
+    gif_max_export_files: number;
+    safe_mode: boolean;
+    edit_history_depth: number;
 };
 
+export type AppLanguage = 'zh' | 'en';
+
+export const APP_LANGUAGES: AppLanguage[] = ['zh', 'en'];
+
+export type PreviewMode = 'auto' | 'always-fast' | 'always-full' | 'never';
+
+export const PREVIEW_MODES: PreviewMode[] = ['auto', 'always-fast', 'always-full', 'never'];
+
+export type ConflictStrategy = 'rename' | 'ask';
+
+export const CONFLICT_STRATEGIES: ConflictStrategy[] = ['rename', 'ask'];
+
+export type BatchOrder = 'as-is' | 'smallest-first' | 'largest-first' | 'name';
+
+export const BATCH_ORDERS: BatchOrder[] = ['as-is', 'smallest-first', 'largest-first', 'name'];
+
 const clamp = (value: number, min: number, max: number) => Math.max(min, Math.min(max, value));
 const MAX_RECENT_PATHS = 4;
 const DEFAULT_FILE_PATH_RESOLVE_TIMEOUT_MS = 1500;
@@ -34,6 +65,21 @@ export const DEFAULT_APP_SETTINGS: AppSettingsSnapshot = {
     default_output_dir: '',
     recent_input_dirs: [],
     recent_output_dirs: [],
+    preview_mode: 'auto',
+    format_quality_defaults: {},
+    language: 'zh',
+    open_folder_when_done: false,
+    notify_on_completion: false,
+    use_working_copy: false,
+    batch_order: 'as-is',
+    use_ram_temp: false,
+    ram_temp_dir: '',
+    max_megapixels: 256,
+    gif_max_frames: 10000,
+    gif_max_megapixels: 2048,
+    gif_max_export_files: 2000,
+    safe_mode: false,
+    edit_history_depth: 20,
 };
 
 const normalizeSavedPath = (value: unknown) => {
@@ -55,6 +101,23 @@ const finiteNumberOr = (value: unknown, fallback: number) => {
     return Number.isFinite(numeric) ? numeric : fallback;
 };
 
+const QUALITY_FORMAT_ALIASES: Record<string, string> = { jpeg: 'jpg' };
+const QUALITY_FORMATS = new Set(['jpg', 'webp', 'avif', 'jxl', 'pdf']);
+
+const normalizeFormatQualityDefaults = (value: unknown) => {
+    const defaults: Record<string, number> = {};
+    if (!value || typeof value !== 'object' || Array.isArray(value)) return defaults;
+    for (const [rawFormat, rawQuality] of Object.entries(value as Record<string, unknown>)) {
+        const lowered = rawFormat.trim().toLowerCase().replace(/^\./, '');
+        const format = QUALITY_FORMAT_ALIASES[lowered] || lowered;
+        if (!QUALITY_FORMATS.has(format) || typeof rawQuality === 'boolean') continue;
+        const quality = Math.round(finiteNumberOr(rawQuality, 0));
+        if (quality <= 0) continue;
+        defaults[format] = clamp(quality, 1, 100);
+    }
+    return defaults;
+};
+
 const normalizeRecentPaths = (value: unknown) => {
     if (!Array.isArray(value)) return [] as string[];
     const seen = new Set<string>();
@@ -88,12 +151,63 @@ export function normalizeAppSettings(raw?: Partial<AppSettingsSnapshot> | null):
         preserve_folder_structure: typeof raw.preserve_folder_structure === 'boolean'
             ? raw.preserve_folder_structure
             : DEFAULT_APP_SETTINGS.preserve_folder_structure,
-        conflict_strategy: typeof raw.conflict_strategy === 'string' && raw.conflict_strategy.trim() === 'rename'
-            ? 'rename'
+        conflict_strategy: CONFLICT_STRATEGIES.includes(raw.conflict_strategy as ConflictStrategy)
+            ? (raw.conflict_strategy as ConflictStrategy)
             : DEFAULT_APP_SETTINGS.conflict_strategy,
         default_output_dir: normalizeSavedPath(raw.default_output_dir),
         recent_input_dirs: normalizeRecentPaths(raw.recent_input_dirs),
         recent_output_dirs: normalizeRecentPaths(raw.recent_output_dirs),
+        preview_mode: PREVIEW_MODES.includes(raw.preview_mode as PreviewMode)
+            ? (raw.preview_mode as PreviewMode)
+            : DEFAULT_APP_SETTINGS.preview_mode,
+        format_quality_defaults: normalizeFormatQualityDefaults(raw.format_quality_defaults),
+        language: APP_LANGUAGES.includes(raw.language as AppLanguage)
+            ? (raw.language as AppLanguage)
+            : DEFAULT_APP_SETTINGS.language,
+        open_folder_when_done: typeof raw.open_folder_when_done === 'boolean'
+            ? raw.open_folder_when_done
+            : DEFAULT_APP_SETTINGS.open_folder_when_done,
+        notify_on_completion: typeof raw.notify_on_completion === 'boolean'
+            ? raw.notify_on_completion
+            : DEFAULT_APP_SETTINGS.notify_on_completion,
+        use_working_copy: typeof raw.use_working_copy === 'boolean'
+            ? raw.use_working_copy
+            : DEFAULT_APP_SETTINGS.use_working_copy,
+        batch_order: BATCH_ORDERS.includes(raw.batch_order as BatchOrder)
+            ? (raw.batch_order as BatchOrder)
+            : DEFAULT_APP_SETTINGS.batch_order,
+        use_ram_temp: typeof raw.use_ram_temp === 'boolean'
+            ? raw.use_ram_temp
+            : DEFAULT_APP_SETTINGS.use_ram_temp,
+        ram_temp_dir: normalizeSavedPath(raw.ram_temp_dir),
+        max_megapixels: clamp(
+            Math.round(finiteNumberOr(raw.max_megapixels, DEFAULT_APP_SETTINGS.max_megapixels)),
+            1,
+            10000,
+        ),
+        gif_max_frames: clamp(
+            Math.round(finiteNumberOr(raw.gif_max_frames, DEFAULT_APP_SETTINGS.gif_max_frames)),
+            1,
+            100000,
+        ),
+        gif_max_megapixels: clamp(
+            Math.round(finiteNumberOr(raw.gif_max_megapixels, DEFAULT_APP_SETTINGS.gif_max_megapixels)),
+            1,
+            100000,
+        ),
+        gif_max_export_files: clamp(
+            Math.round(finiteNumberOr(raw.gif_max_export_files, DEFAULT_APP_SETTINGS.gif_max_export_files)),
+            1,
+            100000,
+        ),
+        safe_mode: typeof raw.safe_mode === 'boolean'
+            ? raw.safe_mode
+            : DEFAULT_APP_SETTINGS.safe_mode,
+        edit_history_depth: clamp(
+            Math.round(finiteNumberOr(raw.edit_history_depth, DEFAULT_APP_SETTINGS.edit_history_depth)),
+            1,
+            100,
+        ),
     };
 }
 
@@ -124,6 +238,35 @@ export async function saveAppSettings(settings: AppSettingsSnapshot): Promise<Ap
     return normalizeAppSettings((saved as Partial<AppSettingsSnapshot>) || normalized);
 }
 
+export async function exportAppSettings(): Promise<string> {
+    const app = getAppBindings();
+    if (!app?.ExportSettings) {
+        return JSON.stringify(normalizeAppSettings(await loadAppSettings()), null, 2);
+    }
+    return app.ExportSettings();
+}
+
+export async function importAppSettings(data: string): Promise<AppSettingsSnapshot> {
+    const app = getAppBindings();
+    if (!app?.ImportSettings) {
+        throw new Error('当前运行环境不支持导入设置');
+    }
+    const saved = await app.ImportSettings(data);
+    if ((saved as { success?: boolean }).success === false) {
+        throw new Error((saved as { error?: string }).error || '导入设置失败');
+    }
+    return normalizeAppSettings(saved as Partial<AppSettingsSnapshot>);
+}
+
+export async function resetAppSettings(): Promise<AppSettingsSnapshot> {
+    const app = getAppBindings();
+    if (!app?.ResetSettings) {
+        return { ...DEFAULT_APP_SETTINGS };
+    }
+    const saved = await app.ResetSettings();
+    return normalizeAppSettings(saved as Partial<AppSettingsSnapshot>);
+}
+
 export function pushRecentPath(paths: string[], nextPath: string): string[] {
     const normalized = normalizeSavedPath(nextPath);
     if (!normalized) return normalizeRecentPaths(paths);
@@ -142,6 +285,133 @@ export async function updateRecentPaths(payload: { inputDir?: string; outputDir?
     return normalizeAppSettings(saved as Partial<AppSettingsSnapshot>);
 }
 
+export const LONG_OPERATION_EVENT = '__imageflow_long_operation__';
+
+export type LongOperationNotice = {
+    type: 'long_operation';
+    operation: string;
+    task_id: number | null;
+    elapsed_seconds: number;
+    item_count: number;
+    /** False when the operation passed the threshold, true once it has ended. */
+    done: boolean;
+};
+
+/** Informational "still processing" notice; not progress and not a failure. */
+export function onLongOperation(callback: (notice: LongOperationNotice) => void): () => void {
+    const listener = (event: Event) => {
+        const detail = (event as CustomEvent<LongOperationNotice>).detail;
+        if (detail?.type === 'long_operation') {
+            callback(detail);
+        }
+    };
+    window.addEventListener(LONG_OPERATION_EVENT, listener);
+    return () => window.removeEventListener(LONG_OPERATION_EVENT, listener);
+}
+
+export const BATCH_DONE_EVENT = '__imageflow_batch_done__';
+
+export type BatchDoneNotice = {
+    type: 'batch_done';
+    operation: string;
+    total: number;
+    succeeded: number;
+    failed: number;
+    output_dir: string;
+    message: string;
+};
+
+/** Fired once per batch that wrote at least one file. */
+export function onBatchDone(callback: (notice: BatchDoneNotice) => void): () => void {
+    const listener = (event: Event) => {
+        const detail = (event as CustomEvent<BatchDoneNotice>).detail;
+        if (detail?.type === 'batch_done') {
+            callback(detail);
+        }
+    };
+    window.addEventListener(BATCH_DONE_EVENT, listener);
+    return () => window.removeEventListener(BATCH_DONE_EVENT, listener);
+}
+
+export const BATCH_PROGRESS_EVENT = '__imageflow_batch_progress__';
+
+export type BatchProgressNotice = {
+    type: 'batch_progress';
+    operation: string;
+    task_id: number | null;
+    current: number;
+    total: number;
+    percentage: number;
+    input_path: string;
+    output_path: string;
+    success: boolean;
+    done: boolean;
+};
+
+/** Fired as each item of a backend batch settles, then once more with `done: true`. */
+export function onBatchProgress(callback: (notice: BatchProgressNotice) => void): () => void {
+    const listener = (event: Event) => {
+        const detail = (event as CustomEvent<BatchProgressNotice>).detail;
+        if (detail?.type === 'batch_progress') {
+            callback(detail);
+        }
+    };
+    window.addEventListener(BATCH_PROGRESS_EVENT, listener);
+    return () => window.removeEventListener(BATCH_PROGRESS_EVENT, listener);
+}
+
+export const CONFLICT_PROMPT_EVENT = '__imageflow_conflict_prompt__';
+
+export type ConflictPromptNotice = {
+    type: 'conflict_prompt';
+    operation_id: string;
+    operation: string;
+    path: string;
+    decisions: ConflictDecision[];
+    default: ConflictDecision;
+    timeout_seconds: number;
+};
+
+/** The backend is waiting for `ResolveConflict`; unanswered prompts fall back to `default`. */
+export function onConflictPrompt(callback: (notice: ConflictPromptNotice) => void): () => void {
+    const listener = (event: Event) => {
+        const detail = (event as CustomEvent<ConflictPromptNotice>).detail;
+        if (detail?.type === 'conflict_prompt') {
+            callback(detail);
+        }
+    };
+    window.addEventListener(CONFLICT_PROMPT_EVENT, listener);
+    return () => window.removeEventListener(CONFLICT_PROMPT_EVENT, listener);
+}
+
+export const RUNTIME_PROGRESS_EVENT = '__imageflow_runtime_progress__';
+
+export type RuntimeState = 'idle' | 'preparing' | 'ready' | 'failed' | 'incomplete';
+
+export type RuntimeProgressNotice = {
+    type: 'runtime_progress';
+    state: RuntimeState;
+    current: number;
+    total: number;
+    step: string;
+    error: string;
+    /** Missing or truncated runtime files (first few) when `state` is `incomplete`. */
+    files: string[];
+    file_count: number;
+};
+
+/** Launch-time engine checks and warm-up; `GetRuntimeStatus` returns the same shape for late subscribers. */
+export function onRuntimeProgress(callback: (notice: RuntimeProgressNotice) => void): () => void {
+    const listener = (event: Event) => {
+        const detail = (event as CustomEvent<RuntimeProgressNotice>).detail;
+        if (detail?.type === 'runtime_progress') {
+            callback(detail);
+        }
+    };
+    window.addEventListener(RUNTIME_PROGRESS_EVENT, listener);
+    return () => window.removeEventListener(RUNTIME_PROGRESS_EVENT, listener);
+}
+
 export function getAppBindings(): Partial<AppBindings> | null {
     const app = getDesktopBindings();
     if (!app) return null;